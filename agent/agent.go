@@ -24,6 +24,7 @@ import (
 	"github.com/armon/go-metrics/prometheus"
 	"github.com/hashicorp/consul/agent/rpcclient"
 	"github.com/hashicorp/consul/agent/rpcclient/configentry"
+	"github.com/hashicorp/consul/agent/rpcclient/kvs"
 	"github.com/hashicorp/go-connlimit"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
@@ -266,6 +267,12 @@ type Agent struct {
 	// checkUDPs maps the check ID to an associated UDP check
 	checkUDPs map[structs.CheckID]*checks.CheckUDP
 
+	// checkDNSs maps the check ID to an associated DNS check
+	checkDNSs map[structs.CheckID]*checks.CheckDNS
+
+	// checkTLSs maps the check ID to an associated TLS check
+	checkTLSs map[structs.CheckID]*checks.CheckTLS
+
 	// checkGRPCs maps the check ID to an associated GRPC check
 	checkGRPCs map[structs.CheckID]*checks.CheckGRPC
 
@@ -278,6 +285,9 @@ type Agent struct {
 	// checkAliases maps the check ID to an associated Alias checks
 	checkAliases map[structs.CheckID]*checks.CheckAlias
 
+	// checkComposites maps the check ID to an associated Composite check
+	checkComposites map[structs.CheckID]*checks.CheckComposite
+
 	// checkOSServices maps the check ID to an associated OS Service check
 	checkOSServices map[structs.CheckID]*checks.CheckOSService
 
@@ -325,6 +335,14 @@ type Agent struct {
 	// dnsServer provides the DNS API
 	dnsServers []*DNSServer
 
+	// localDNSServer serves the DNS queries received over gRPC and HTTPS. It
+	// doesn't listen itself.
+	localDNSServer *DNSServer
+
+	// dnsZones keep the last versions of the zone of the DNS domain served
+	// by the zone transfers, for each TSIG key.
+	dnsZones *dnsZones
+
 	// apiServers listening for connections. If any of these server goroutines
 	// fail, the agent will be shutdown.
 	apiServers *apiServers
@@ -388,6 +406,7 @@ type Agent struct {
 	// into Agent, which will allow us to remove this field.
 	rpcClientHealth      *health.Client
 	rpcClientConfigEntry *configentry.Client
+	rpcClientKVS         *kvs.Client
 
 	rpcClientPeering pbpeering.PeeringServiceClient
 
@@ -436,15 +455,19 @@ func New(bd BaseDeps) (*Agent, error) {
 		checkH2PINGs:    make(map[structs.CheckID]*checks.CheckH2PING),
 		checkTCPs:       make(map[structs.CheckID]*checks.CheckTCP),
 		checkUDPs:       make(map[structs.CheckID]*checks.CheckUDP),
+		checkDNSs:       make(map[structs.CheckID]*checks.CheckDNS),
+		checkTLSs:       make(map[structs.CheckID]*checks.CheckTLS),
 		checkGRPCs:      make(map[structs.CheckID]*checks.CheckGRPC),
 		checkDockers:    make(map[structs.CheckID]*checks.CheckDocker),
 		checkAliases:    make(map[structs.CheckID]*checks.CheckAlias),
+		checkComposites: make(map[structs.CheckID]*checks.CheckComposite),
 		checkOSServices: make(map[structs.CheckID]*checks.CheckOSService),
 		eventCh:         make(chan serf.UserEvent, 1024),
 		eventBuf:        make([]*UserEvent, 256),
 		joinLANNotifier: &systemd.Notifier{},
 		retryJoinCh:     make(chan error),
 		shutdownCh:      make(chan struct{}),
+		dnsZones:        new(dnsZones),
 		endpoints:       make(map[string]string),
 		stateLock:       mutex.New(),
 
@@ -497,6 +520,20 @@ func New(bd BaseDeps) (*Agent, error) {
 		},
 	}
 
+	a.rpcClientKVS = &kvs.Client{
+		Client: rpcclient.Client{
+			NetRPC:    &a,
+			ViewStore: bd.ViewStore,
+			MaterializerDeps: rpcclient.MaterializerDeps{
+				Conn:   conn,
+				Logger: bd.Logger.Named("rpcclient.kvs"),
+			},
+			UseStreamingBackend: a.config.UseStreamingBackend,
+			QueryOptionDefaults: config.ApplyDefaultQueryOptions(a.config),
+		},
+		EnforceKeyListPolicy: a.config.ACLEnableKeyListPolicy,
+	}
+
 	// We used to do this in the Start method. However it doesn't need to go
 	// there any longer. Originally it did because we passed the agent
 	// delegate to some of the cache registrations. Now we just
@@ -947,8 +984,9 @@ func (a *Agent) listenAndServeGRPC() error {
 }
 
 func (a *Agent) listenAndServeDNS() error {
-	notif := make(chan net.Addr, len(a.config.DNSAddrs))
-	errCh := make(chan error, len(a.config.DNSAddrs))
+	total := len(a.config.DNSAddrs) + len(a.config.DNSTLSAddrs)
+	notif := make(chan net.Addr, total)
+	errCh := make(chan error, total)
 	for _, addr := range a.config.DNSAddrs {
 		// create server
 		s, err := NewDNSServer(a)
@@ -967,6 +1005,23 @@ func (a *Agent) listenAndServeDNS() error {
 			}
 		}(addr)
 	}
+	for _, addr := range a.config.DNSTLSAddrs {
+		s, err := NewDNSServer(a)
+		if err != nil {
+			return err
+		}
+		a.dnsServers = append(a.dnsServers, s)
+
+		a.wgServers.Add(1)
+		go func(addr net.Addr) {
+			defer a.wgServers.Done()
+			tlsConfig := a.tlsConfigurator.IncomingDNSConfig()
+			err := s.ListenAndServeTLS(addr.String(), tlsConfig, func() { notif <- addr })
+			if err != nil && !strings.Contains(err.Error(), "accept") {
+				errCh <- err
+			}
+		}(addr)
+	}
 	s, _ := NewDNSServer(a)
 
 	grpcDNS.NewServer(grpcDNS.Config{
@@ -976,11 +1031,13 @@ func (a *Agent) listenAndServeDNS() error {
 	}).Register(a.externalGRPCServer)
 
 	a.dnsServers = append(a.dnsServers, s)
+	a.localDNSServer = s
+	go s.notifySecondaries(a.shutdownCh)
 
 	// wait for servers to be up
 	timeout := time.After(time.Second)
 	var merr *multierror.Error
-	for range a.config.DNSAddrs {
+	for i := 0; i < total; i++ {
 		select {
 		case addr := <-notif:
 			a.logger.Info("Started DNS server",
@@ -1227,7 +1284,7 @@ func (a *Agent) reloadWatches(cfg *config.RuntimeConfig) error {
 	for _, params := range cfg.Watches {
 		if handlerType, ok := params["handler_type"]; !ok {
 			params["handler_type"] = "script"
-		} else if handlerType != "http" && handlerType != "script" {
+		} else if handlerType != "http" && handlerType != "script" && handlerType != "file" {
 			return fmt.Errorf("Handler type '%s' not recognized", params["handler_type"])
 		}
 
@@ -1256,12 +1313,27 @@ func (a *Agent) reloadWatches(cfg *config.RuntimeConfig) error {
 			continue
 		}
 
+		// The file handler opens its file upfront so that it can be closed
+		// once the watch is stopped.
+		var fileHandler watch.HandlerFunc
+		var file io.Closer
+		if fileConfig, ok := wp.Exempt["file_handler_config"].(*watch.FileHandlerConfig); ok {
+			fileHandler, file, err = makeFileWatchHandler(a.logger, fileConfig)
+			if err != nil {
+				a.logger.Error("Failed to run watch", "error", err)
+				continue
+			}
+		}
+
 		a.watchPlans = append(a.watchPlans, wp)
 		go func(wp *watch.Plan) {
 			if h, ok := wp.Exempt["handler"]; ok {
 				wp.Handler = makeWatchHandler(a.logger, h)
 			} else if h, ok := wp.Exempt["args"]; ok {
 				wp.Handler = makeWatchHandler(a.logger, h)
+			} else if fileHandler != nil {
+				wp.Handler = fileHandler
+				defer file.Close()
 			} else {
 				httpConfig := wp.Exempt["http_handler_config"].(*watch.HttpHandlerConfig)
 				wp.Handler = makeHTTPWatchHandler(a.logger, httpConfig)
@@ -1291,6 +1363,7 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 
 	// Apply dev mode
 	cfg.DevMode = runtimeCfg.DevMode
+	cfg.DevResourceStorage = runtimeCfg.DevResourceStorage
 
 	// Override with our runtimeCfg
 	// todo(fs): these are now always set in the runtime runtimeCfg so we can simplify this
@@ -1406,6 +1479,8 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	if runtimeCfg.ReadReplica {
 		cfg.ReadReplica = runtimeCfg.ReadReplica
 	}
+	cfg.KVHistoryMaxRevisions = runtimeCfg.KVHistoryMaxRevisions
+	cfg.KVHistoryMaxAge = runtimeCfg.KVHistoryMaxAge
 
 	// These are fully specified in the agent defaults, so we can simply
 	// copy them over.
@@ -1504,6 +1579,7 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 
 	cfg.ConfigEntryBootstrap = runtimeCfg.ConfigEntryBootstrap
 	cfg.LogStoreConfig = runtimeCfg.RaftLogStoreConfig
+	cfg.SnapshotAgent = runtimeCfg.SnapshotSchedule
 
 	// Duplicate our own serf config once to make sure that the duplication
 	// function does not drift.
@@ -1653,6 +1729,12 @@ func (a *Agent) ShutdownAgent() error {
 	for _, chk := range a.checkUDPs {
 		chk.Stop()
 	}
+	for _, chk := range a.checkDNSs {
+		chk.Stop()
+	}
+	for _, chk := range a.checkTLSs {
+		chk.Stop()
+	}
 	for _, chk := range a.checkGRPCs {
 		chk.Stop()
 	}
@@ -1662,6 +1744,9 @@ func (a *Agent) ShutdownAgent() error {
 	for _, chk := range a.checkAliases {
 		chk.Stop()
 	}
+	for _, chk := range a.checkComposites {
+		chk.Stop()
+	}
 	for _, chk := range a.checkH2PINGs {
 		chk.Stop()
 	}
@@ -1683,6 +1768,7 @@ func (a *Agent) ShutdownAgent() error {
 
 	a.rpcClientHealth.Close()
 	a.rpcClientConfigEntry.Close()
+	a.rpcClientKVS.Close()
 
 	// Shutdown SCADA provider
 	if a.scadaProvider != nil {
@@ -2871,6 +2957,10 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 		}
 
 		statusHandler := checks.NewStatusHandler(a.State, a.logger, chkType.SuccessBeforePassing, chkType.FailuresBeforeWarning, chkType.FailuresBeforeCritical)
+		if chkType.FlapDetection {
+			low, high := chkType.FlapThresholds()
+			statusHandler.EnableFlapDetection(low, high, chkType.Type(), check.ServiceName)
+		}
 		sid := check.CompoundServiceID()
 
 		cid := check.CompoundCheckID()
@@ -2926,6 +3016,7 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 				Method:           chkType.Method,
 				Body:             chkType.Body,
 				DisableRedirects: chkType.DisableRedirects,
+				Assertions:       chkType.HTTPAssertions,
 				Interval:         chkType.Interval,
 				Timeout:          chkType.Timeout,
 				Logger:           a.logger,
@@ -3000,6 +3091,66 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			udp.Start()
 			a.checkUDPs[cid] = udp
 
+		case chkType.IsDNS():
+			if existing, ok := a.checkDNSs[cid]; ok {
+				existing.Stop()
+				delete(a.checkDNSs, cid)
+			}
+			if chkType.Interval < checks.MinInterval {
+				a.logger.Warn("check has interval below minimum",
+					"check", cid.String(),
+					"minimum_interval", checks.MinInterval,
+				)
+				chkType.Interval = checks.MinInterval
+			}
+
+			dnsCheck := &checks.CheckDNS{
+				CheckID:        cid,
+				ServiceID:      sid,
+				DNS:            chkType.DNS,
+				QueryName:      chkType.DNSQueryName,
+				QueryType:      chkType.DNSQueryType,
+				Protocol:       chkType.DNSProtocol,
+				ExpectedAnswer: chkType.DNSExpectedAnswer,
+				ExpectedRcode:  chkType.DNSExpectedRcode,
+				Interval:       chkType.Interval,
+				Timeout:        chkType.Timeout,
+				Logger:         a.logger,
+				StatusHandler:  statusHandler,
+			}
+			dnsCheck.Start()
+			a.checkDNSs[cid] = dnsCheck
+
+		case chkType.IsTLS():
+			if existing, ok := a.checkTLSs[cid]; ok {
+				existing.Stop()
+				delete(a.checkTLSs, cid)
+			}
+			if chkType.Interval < checks.MinInterval {
+				a.logger.Warn("check has interval below minimum",
+					"check", cid.String(),
+					"minimum_interval", checks.MinInterval,
+				)
+				chkType.Interval = checks.MinInterval
+			}
+
+			warningDays, criticalDays := chkType.TLSExpiryThresholds()
+			tlsCheck := &checks.CheckTLS{
+				CheckID:            cid,
+				ServiceID:          sid,
+				TLS:                chkType.TLS,
+				TLSClientConfig:    a.tlsConfigurator.OutgoingTLSConfigForCheck(chkType.TLSSkipVerify, chkType.TLSServerName),
+				CAFile:             chkType.TLSCAFile,
+				ExpiryWarningDays:  warningDays,
+				ExpiryCriticalDays: criticalDays,
+				Interval:           chkType.Interval,
+				Timeout:            chkType.Timeout,
+				Logger:             a.logger,
+				StatusHandler:      statusHandler,
+			}
+			tlsCheck.Start()
+			a.checkTLSs[cid] = tlsCheck
+
 		case chkType.IsGRPC():
 			if existing, ok := a.checkGRPCs[cid]; ok {
 				existing.Stop()
@@ -3208,6 +3359,39 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			chkImpl.Start()
 			a.checkAliases[cid] = chkImpl
 
+		case chkType.IsComposite():
+			if a.compositeCycle(cid, chkType.CompositeChecks) {
+				return fmt.Errorf("Check is not valid: composite check %q references itself, directly or through other composite checks", cid.ID)
+			}
+
+			if existing, ok := a.checkComposites[cid]; ok {
+				existing.Stop()
+				delete(a.checkComposites, cid)
+			}
+
+			// The token follows the same rules as for alias checks.
+			var rpcReq structs.NodeSpecificRequest
+			rpcReq.Datacenter = a.config.Datacenter
+			rpcReq.EnterpriseMeta = *a.AgentEnterpriseMeta()
+			rpcReq.Token = a.tokens.UserToken()
+			if token != "" {
+				rpcReq.Token = token
+			}
+
+			chkImpl := &checks.CheckComposite{
+				Notify:            a.State,
+				RPC:               a.delegate,
+				RPCReq:            rpcReq,
+				CheckID:           cid,
+				Checks:            chkType.CompositeChecks,
+				Mode:              chkType.CompositeMode,
+				CriticalThreshold: chkType.CompositeCriticalThreshold,
+				WarningThreshold:  chkType.CompositeWarningThreshold,
+				EnterpriseMeta:    check.EnterpriseMeta,
+			}
+			chkImpl.Start()
+			a.checkComposites[cid] = chkImpl
+
 		default:
 			return fmt.Errorf("Check type is not valid")
 		}
@@ -3240,6 +3424,37 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 	return nil
 }
 
+// compositeCycle returns whether a composite check with the given ID and
+// references would be part of a cycle of local composite checks, including
+// by referencing itself. Such a check would latch at its first non-passing
+// status and never recover. Callers must hold stateLock.
+func (a *Agent) compositeCycle(cid structs.CheckID, refs []structs.CompositeCheckRef) bool {
+	visited := make(map[structs.CheckID]bool)
+
+	var visit func(refs []structs.CompositeCheckRef) bool
+	visit = func(refs []structs.CompositeCheckRef) bool {
+		for _, ref := range refs {
+			if ref.Node != "" && !strings.EqualFold(ref.Node, a.config.NodeName) {
+				continue
+			}
+			id := structs.NewCheckID(ref.CheckID, &cid.EnterpriseMeta)
+			if id == cid {
+				return true
+			}
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+
+			if chk, ok := a.checkComposites[id]; ok && visit(chk.Checks) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(refs)
+}
+
 // RemoveCheck is used to remove a health check.
 // The agent will make a best effort to ensure it is deregistered
 func (a *Agent) RemoveCheck(checkID structs.CheckID, persist bool) error {
@@ -3336,6 +3551,14 @@ func (a *Agent) cancelCheckMonitors(checkID structs.CheckID) {
 		check.Stop()
 		delete(a.checkUDPs, checkID)
 	}
+	if check, ok := a.checkDNSs[checkID]; ok {
+		check.Stop()
+		delete(a.checkDNSs, checkID)
+	}
+	if check, ok := a.checkTLSs[checkID]; ok {
+		check.Stop()
+		delete(a.checkTLSs, checkID)
+	}
 	if check, ok := a.checkGRPCs[checkID]; ok {
 		check.Stop()
 		delete(a.checkGRPCs, checkID)
@@ -3356,6 +3579,10 @@ func (a *Agent) cancelCheckMonitors(checkID structs.CheckID) {
 		check.Stop()
 		delete(a.checkAliases, checkID)
 	}
+	if check, ok := a.checkComposites[checkID]; ok {
+		check.Stop()
+		delete(a.checkComposites, checkID)
+	}
 }
 
 // updateTTLCheck is used to update the status of a TTL check via the Agent API.
@@ -4340,6 +4567,10 @@ func (a *Agent) registerCache() {
 
 	a.cache.RegisterType(cachetype.NodeServicesName, &cachetype.NodeServices{RPC: a})
 
+	a.cache.RegisterType(cachetype.KVSGetName, &cachetype.KVSGet{RPC: a})
+
+	a.cache.RegisterType(cachetype.CoordinateListNodesName, &cachetype.CoordinateListNodes{RPC: a})
+
 	a.cache.RegisterType(cachetype.ResolvedServiceConfigName, &cachetype.ResolvedServiceConfig{RPC: a})
 
 	a.cache.RegisterType(cachetype.CatalogListServicesName, &cachetype.CatalogListServices{RPC: a})
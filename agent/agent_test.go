@@ -1598,6 +1598,51 @@ func TestAgent_AddCheck_MinInterval(t *testing.T) {
 	}
 }
 
+func TestAgent_AddCheck_CompositeCycle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	addComposite := func(id string, refs ...types.CheckID) error {
+		health := &structs.HealthCheck{
+			Node:    a.Config.NodeName,
+			CheckID: types.CheckID(id),
+			Name:    id,
+			Status:  api.HealthCritical,
+		}
+		chk := &structs.CheckType{}
+		for _, ref := range refs {
+			chk.CompositeChecks = append(chk.CompositeChecks, structs.CompositeCheckRef{CheckID: ref})
+		}
+		return a.AddCheck(health, chk, false, "", ConfigSourceLocal)
+	}
+
+	err := addComposite("self", "self")
+	require.ErrorContains(t, err, "references itself")
+
+	require.NoError(t, addComposite("a", "b"))
+	require.NoError(t, addComposite("b", "c"))
+
+	err = addComposite("c", "a")
+	require.ErrorContains(t, err, "references itself")
+
+	// A remote check with the same ID isn't part of the cycle.
+	health := &structs.HealthCheck{
+		Node:    a.Config.NodeName,
+		CheckID: "c",
+		Name:    "c",
+		Status:  api.HealthCritical,
+	}
+	chk := &structs.CheckType{
+		CompositeChecks: []structs.CompositeCheckRef{{Node: "other", CheckID: "a"}},
+	}
+	require.NoError(t, a.AddCheck(health, chk, false, "", ConfigSourceLocal))
+}
+
 func TestAgent_AddCheck_MissingService(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
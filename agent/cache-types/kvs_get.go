@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cachetype
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// Recommended name for registration.
+const KVSGetName = "kvs-get"
+
+// kvsGetLastGetTTL is how long an entry is kept after its last read. Every
+// key looked up gets an entry, they don't outlive the lookups for long.
+const kvsGetLastGetTTL = 10 * time.Minute
+
+// KVSGet supports fetching a single KV entry. The entries are not refreshed,
+// they are fetched again when older than the MaxAge of the request.
+type KVSGet struct {
+	RPC RPC
+}
+
+func (c *KVSGet) RegisterOptions() cache.RegisterOptions {
+	return cache.RegisterOptions{
+		Refresh:          false,
+		SupportsBlocking: false,
+		LastGetTTL:       kvsGetLastGetTTL,
+	}
+}
+
+func (c *KVSGet) Fetch(_ cache.FetchOptions, req cache.Request) (cache.FetchResult, error) {
+	var result cache.FetchResult
+
+	// The request should be a KeyRequest.
+	reqReal, ok := req.(*structs.KeyRequest)
+	if !ok {
+		return result, fmt.Errorf(
+			"Internal cache failure: request wrong type: %T", req)
+	}
+
+	// Lightweight copy this object so that manipulating QueryOptions doesn't race.
+	dup := *reqReal
+	reqReal = &dup
+
+	// Always allow stale - there's no point in hitting leader if the request is
+	// going to be served from cache and endup arbitrarily stale anyway. This
+	// allows cached reads to automatically read scale across all servers too.
+	reqReal.AllowStale = true
+
+	// Fetch
+	var reply structs.IndexedDirEntries
+	if err := c.RPC.RPC(context.Background(), "KVS.Get", reqReal, &reply); err != nil {
+		return result, err
+	}
+
+	result.Value = &reply
+	result.Index = reply.QueryMeta.Index
+	return result, nil
+}
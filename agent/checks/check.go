@@ -5,6 +5,7 @@ package checks
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/tls"
 	"errors"
@@ -25,6 +26,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/armon/circbuf"
+	"github.com/armon/go-metrics"
 	"github.com/hashicorp/consul/agent/exec"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
@@ -351,6 +353,7 @@ type CheckHTTP struct {
 	OutputMaxSize    int
 	StatusHandler    *StatusHandler
 	DisableRedirects bool
+	Assertions       []structs.HTTPAssertion
 
 	httpClient *http.Client
 	stop       bool
@@ -358,6 +361,11 @@ type CheckHTTP struct {
 	stopLock   sync.Mutex
 	stopWg     sync.WaitGroup
 
+	// assertions are the compiled Assertions. assertionsErr is set if they
+	// failed to compile, in which case the check is critical.
+	assertions    []*structs.CompiledHTTPAssertion
+	assertionsErr error
+
 	// Set if checks are exposed through Connect proxies
 	// If set, this is the target of check()
 	ProxyHTTP string
@@ -365,15 +373,16 @@ type CheckHTTP struct {
 
 func (c *CheckHTTP) CheckType() structs.CheckType {
 	return structs.CheckType{
-		CheckID:       c.CheckID.ID,
-		HTTP:          c.HTTP,
-		Method:        c.Method,
-		Body:          c.Body,
-		Header:        c.Header,
-		Interval:      c.Interval,
-		ProxyHTTP:     c.ProxyHTTP,
-		Timeout:       c.Timeout,
-		OutputMaxSize: c.OutputMaxSize,
+		CheckID:        c.CheckID.ID,
+		HTTP:           c.HTTP,
+		Method:         c.Method,
+		Body:           c.Body,
+		Header:         c.Header,
+		Interval:       c.Interval,
+		ProxyHTTP:      c.ProxyHTTP,
+		Timeout:        c.Timeout,
+		OutputMaxSize:  c.OutputMaxSize,
+		HTTPAssertions: c.Assertions,
 	}
 }
 
@@ -409,6 +418,15 @@ func (c *CheckHTTP) Start() {
 		if c.OutputMaxSize < 1 {
 			c.OutputMaxSize = DefaultBufSize
 		}
+
+		for i := range c.Assertions {
+			a, err := c.Assertions[i].Compile()
+			if err != nil {
+				c.assertionsErr = fmt.Errorf("HTTPAssertions[%d]: %w", i, err)
+				break
+			}
+			c.assertions = append(c.assertions, a)
+		}
 	}
 
 	c.stop = false
@@ -449,6 +467,11 @@ func (c *CheckHTTP) run() {
 
 // check is invoked periodically to perform the HTTP check
 func (c *CheckHTTP) check() {
+	if c.assertionsErr != nil {
+		c.StatusHandler.updateCheck(c.CheckID, api.HealthCritical, c.assertionsErr.Error())
+		return
+	}
+
 	method := c.Method
 	if method == "" {
 		method = "GET"
@@ -491,30 +514,104 @@ func (c *CheckHTTP) check() {
 	}
 	defer resp.Body.Close()
 
-	// Read the response into a circular buffer to limit the size
+	// Read the response into a circular buffer to limit the size. When there
+	// are assertions the body is kept, up to a limit, to evaluate them.
 	output, _ := circbuf.NewBuffer(int64(c.OutputMaxSize))
-	if _, err := io.Copy(output, resp.Body); err != nil {
+	var body bytes.Buffer
+	var dst io.Writer = output
+	if len(c.Assertions) > 0 {
+		dst = io.MultiWriter(output, &limitedWriter{w: &body, n: HTTPAssertionBodyMaxSize})
+	}
+	if _, err := io.Copy(dst, resp.Body); err != nil {
 		c.Logger.Warn("Check error while reading body",
 			"check", c.CheckID.String(),
 			"error", err,
 		)
 	}
 
+	status := httpStatus(resp.StatusCode)
+	for _, a := range c.Assertions {
+		if a.StatusRange != "" {
+			// Status range assertions replace the default mapping of
+			// status codes, so the response is passing unless one fails.
+			status = api.HealthPassing
+			break
+		}
+	}
+	var failed []string
+	for _, a := range c.assertions {
+		if ok, desc := a.Evaluate(resp.StatusCode, body.Bytes()); !ok {
+			failed = append(failed, desc)
+			status = worstStatus(status, a.FailureStatus())
+		}
+	}
+
 	// Format the response body
 	result := fmt.Sprintf("HTTP %s %s: %s Output: %s", method, target, resp.Status, output.String())
+	if len(failed) > 0 {
+		result = fmt.Sprintf("HTTP %s %s: %s Assertions failed: %s Output: %s",
+			method, target, resp.Status, strings.Join(failed, "; "), output.String())
+	}
+
+	c.StatusHandler.updateCheck(c.CheckID, status, result)
+}
+
+// HTTPAssertionBodyMaxSize is the maximum number of bytes of an HTTP check
+// response body that are used to evaluate its assertions.
+const HTTPAssertionBodyMaxSize = 1024 * 1024
 
-	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+// httpStatus returns the status of an HTTP check for a response status code,
+// when there are no status range assertions.
+func httpStatus(code int) string {
+	if code >= 200 && code <= 299 {
 		// PASSING (2xx)
-		c.StatusHandler.updateCheck(c.CheckID, api.HealthPassing, result)
-	} else if resp.StatusCode == 429 {
+		return api.HealthPassing
+	} else if code == 429 {
 		// WARNING
 		// 429 Too Many Requests (RFC 6585)
 		// The user has sent too many requests in a given amount of time.
-		c.StatusHandler.updateCheck(c.CheckID, api.HealthWarning, result)
-	} else {
-		// CRITICAL
-		c.StatusHandler.updateCheck(c.CheckID, api.HealthCritical, result)
+		return api.HealthWarning
 	}
+	// CRITICAL
+	return api.HealthCritical
+}
+
+// worstStatus returns the most severe of two check statuses.
+func worstStatus(a, b string) string {
+	rank := func(s string) int {
+		switch s {
+		case api.HealthPassing:
+			return 0
+		case api.HealthWarning:
+			return 1
+		}
+		return 2
+	}
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
+// limitedWriter writes up to n bytes to w and silently discards the rest.
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		b := p
+		if int64(len(b)) > l.n {
+			b = b[:l.n]
+		}
+		n, err := l.w.Write(b)
+		l.n -= int64(n)
+		if err != nil {
+			return n, err
+		}
+	}
+	return len(p), nil
 }
 
 type CheckH2PING struct {
@@ -1180,6 +1277,13 @@ type StatusHandler struct {
 	failuresBeforeWarning  int
 	failuresBeforeCritical int
 	failuresCounter        int
+
+	// flap is set when flap detection is enabled, lastStatus is the last
+	// status reported to inner. flapLabels are the labels of the flapping
+	// metrics.
+	flap       *flapDetector
+	flapLabels []metrics.Label
+	lastStatus string
 }
 
 // NewStatusHandler set counters values to threshold in order to immediatly update status after first check.
@@ -1195,6 +1299,17 @@ func NewStatusHandler(inner CheckNotifier, logger hclog.Logger, successBeforePas
 	}
 }
 
+// EnableFlapDetection enables the detection of flapping checks. While a check
+// is flapping its status changes are suppressed, and the check keeps the last
+// status it had before it started flapping. The thresholds are percentages of
+// state changes in the recent results of the check. The metrics are labelled
+// with the type of the check and the name of its service, the ID of the check
+// is only logged.
+func (s *StatusHandler) EnableFlapDetection(lowThreshold, highThreshold int, checkType, serviceName string) {
+	s.flap = newFlapDetector(lowThreshold, highThreshold)
+	s.flapLabels = flapLabels(checkType, serviceName)
+}
+
 func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output string) {
 
 	if status == api.HealthPassing || status == api.HealthWarning {
@@ -1205,7 +1320,7 @@ func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output stri
 				"check", checkID.String(),
 				"status", status,
 			)
-			s.inner.UpdateCheck(checkID, status, output)
+			s.notify(checkID, status, output)
 			return
 		}
 		s.logger.Warn("Check passed but has not reached success threshold",
@@ -1219,13 +1334,13 @@ func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output stri
 		s.successCounter = 0
 		if s.failuresCounter >= s.failuresBeforeCritical {
 			s.logger.Warn("Check is now critical", "check", checkID.String())
-			s.inner.UpdateCheck(checkID, status, output)
+			s.notify(checkID, status, output)
 			return
 		}
 		// Defaults to same value as failuresBeforeCritical if not set.
 		if s.failuresCounter >= s.failuresBeforeWarning {
 			s.logger.Warn("Check is now warning", "check", checkID.String())
-			s.inner.UpdateCheck(checkID, api.HealthWarning, output)
+			s.notify(checkID, api.HealthWarning, output)
 			return
 		}
 		s.logger.Warn("Check failed but has not reached warning/failure threshold",
@@ -1237,3 +1352,44 @@ func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output stri
 		)
 	}
 }
+
+// notify reports the status to inner, unless flap detection is enabled and
+// the check is flapping.
+func (s *StatusHandler) notify(checkID structs.CheckID, status, output string) {
+	if s.flap == nil {
+		s.inner.UpdateCheck(checkID, status, output)
+		return
+	}
+
+	flapping, changed := s.flap.record(status)
+	if changed {
+		if flapping {
+			s.logger.Warn("Check is flapping, suppressing status changes",
+				"check", checkID.String(),
+				"status", s.lastStatus,
+			)
+			metrics.IncrCounterWithLabels([]string{"agent", "check", "flapping", "start"}, 1, s.flapLabels)
+		} else {
+			s.logger.Info("Check stopped flapping", "check", checkID.String())
+			metrics.IncrCounterWithLabels([]string{"agent", "check", "flapping", "stop"}, 1, s.flapLabels)
+		}
+		if n, ok := s.inner.(FlapNotifier); ok {
+			n.UpdateCheckFlapping(checkID, flapping)
+		}
+	}
+
+	if flapping && s.lastStatus != "" {
+		if status != s.lastStatus {
+			s.logger.Debug("Suppressed status change of flapping check",
+				"check", checkID.String(),
+				"status", status,
+			)
+			metrics.IncrCounterWithLabels([]string{"agent", "check", "flapping", "suppressed"}, 1, s.flapLabels)
+		}
+		// Keep the output up to date, it explains why the check is failing.
+		s.inner.UpdateCheck(checkID, s.lastStatus, output)
+		return
+	}
+	s.lastStatus = status
+	s.inner.UpdateCheck(checkID, status, output)
+}
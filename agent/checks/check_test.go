@@ -506,6 +506,133 @@ func TestCheckHTTP_DisableRedirects(t *testing.T) {
 	})
 }
 
+func TestCheckHTTP_Assertions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc       string
+		code       int
+		body       string
+		assertions []structs.HTTPAssertion
+		status     string
+		output     string
+	}{
+		{
+			desc:   "no assertions",
+			code:   200,
+			body:   `{"status":"degraded"}`,
+			status: api.HealthPassing,
+		},
+		{
+			desc:       "json value",
+			code:       200,
+			body:       `{"status":"ok"}`,
+			assertions: []structs.HTTPAssertion{{JSONPath: "$.status", JSONValue: "ok"}},
+			status:     api.HealthPassing,
+		},
+		{
+			desc: "json value mismatch",
+			code: 200,
+			body: `{"status":"degraded"}`,
+			assertions: []structs.HTTPAssertion{
+				{JSONPath: "$.status", JSONValue: "ok", Status: api.HealthWarning},
+			},
+			status: api.HealthWarning,
+			output: `Assertions failed: $.status is "degraded", expected "ok"`,
+		},
+		{
+			desc: "worst failure wins",
+			code: 200,
+			body: `{"status":"down"}`,
+			assertions: []structs.HTTPAssertion{
+				{JSONPath: "$.status", JSONValue: "ok", Status: api.HealthWarning},
+				{BodyContains: "down", Negate: true},
+			},
+			status: api.HealthCritical,
+			output: `body contains "down" (negated)`,
+		},
+		{
+			desc:       "body regex",
+			code:       200,
+			body:       "version: 1.2.3",
+			assertions: []structs.HTTPAssertion{{BodyRegex: `version: 1\.\d+`}},
+			status:     api.HealthPassing,
+		},
+		{
+			desc:       "assertions don't override failing status",
+			code:       500,
+			body:       "ok",
+			assertions: []structs.HTTPAssertion{{BodyContains: "ok"}},
+			status:     api.HealthCritical,
+		},
+		{
+			desc:       "status range replaces default rule",
+			code:       404,
+			assertions: []structs.HTTPAssertion{{StatusRange: "200-299,404"}},
+			status:     api.HealthPassing,
+		},
+		{
+			desc: "status range mapped to warning",
+			code: 503,
+			assertions: []structs.HTTPAssertion{
+				{StatusRange: "200-299,503"},
+				{StatusRange: "503", Negate: true, Status: api.HealthWarning},
+			},
+			status: api.HealthWarning,
+			output: `status 503 is in "503" (negated)`,
+		},
+		{
+			desc:       "status out of range",
+			code:       200,
+			assertions: []structs.HTTPAssertion{{StatusRange: "204"}},
+			status:     api.HealthCritical,
+			output:     `status 200 is not in "204"`,
+		},
+		{
+			desc:       "invalid body regex",
+			code:       200,
+			assertions: []structs.HTTPAssertion{{BodyRegex: `version: (`}},
+			status:     api.HealthCritical,
+			output:     "HTTPAssertions[0]: invalid BodyRegex",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.code)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			notif := mock.NewNotify()
+			logger := testutil.Logger(t)
+			statusHandler := NewStatusHandler(notif, logger, 0, 0, 0)
+			cid := structs.NewCheckID("foo", nil)
+
+			check := &CheckHTTP{
+				CheckID:       cid,
+				HTTP:          server.URL,
+				Assertions:    tt.assertions,
+				Interval:      10 * time.Millisecond,
+				Logger:        logger,
+				StatusHandler: statusHandler,
+			}
+			check.Start()
+			defer check.Stop()
+
+			retry.Run(t, func(r *retry.R) {
+				if got, want := notif.State(cid), tt.status; got != want {
+					r.Fatalf("got state %q want %q", got, want)
+				}
+				if output := notif.Output(cid); !strings.Contains(output, tt.output) {
+					r.Fatalf("got output %q want it to contain %q", output, tt.output)
+				}
+			})
+		})
+	}
+}
+
 func TestCheckHTTPTCP_BigTimeout(t *testing.T) {
 	testCases := []struct {
 		timeoutIn, intervalIn, timeoutWant time.Duration
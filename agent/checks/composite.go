@@ -0,0 +1,377 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+// CheckComposite is a check type that combines the status of other checks
+// using one of the structs.CompositeMode* modes. Checks registered on this
+// agent are watched using the local state, like CheckAlias does for local
+// services, and checks on other nodes are watched using blocking queries.
+type CheckComposite struct {
+	CheckID           structs.CheckID             // ID of this check
+	Checks            []structs.CompositeCheckRef // Checks to combine
+	Mode              string                      // One of structs.CompositeMode*
+	CriticalThreshold int                         // Used by the threshold mode
+	WarningThreshold  int                         // Used by the threshold mode
+	RPC               RPC                         // Used to query remote servers
+	RPCReq            structs.NodeSpecificRequest // Base request
+	Notify            AliasNotifier               // For updating the check state
+
+	// updateCh is notified when the checks of a remote node are updated.
+	updateCh chan struct{}
+
+	// remote holds the last health checks retrieved for each remote node,
+	// and remoteErr the last error querying them. Both are protected by
+	// remoteLock.
+	remote     map[string][]*structs.HealthCheck
+	remoteErr  map[string]error
+	remoteLock sync.Mutex
+
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+	stopWg   sync.WaitGroup
+
+	acl.EnterpriseMeta
+}
+
+// Start is used to start the check, runs until Stop()
+func (c *CheckComposite) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+
+	c.updateCh = make(chan struct{}, 1)
+	c.remoteLock.Lock()
+	c.remote = make(map[string][]*structs.HealthCheck)
+	c.remoteErr = make(map[string]error)
+	c.remoteLock.Unlock()
+
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	for _, node := range c.remoteNodes() {
+		c.stopWg.Add(1)
+		go c.runQuery(node, c.stopCh)
+	}
+	c.stopWg.Add(1)
+	go c.run(c.stopCh)
+}
+
+// Stop is used to stop the check.
+func (c *CheckComposite) Stop() {
+	c.stopLock.Lock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+	c.stopLock.Unlock()
+
+	// Wait until the associated goroutines are complete before returning, so
+	// that a replacement check is the only one updating the status.
+	c.stopWg.Wait()
+}
+
+// remoteNodes returns the nodes of the checks that aren't local.
+func (c *CheckComposite) remoteNodes() []string {
+	var nodes []string
+	seen := make(map[string]bool)
+	for _, ref := range c.Checks {
+		key := strings.ToLower(ref.Node)
+		if ref.Node == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		nodes = append(nodes, ref.Node)
+	}
+	return nodes
+}
+
+// run is invoked in a goroutine until Stop() is called. It recomputes the
+// status whenever a local or remote check changes.
+func (c *CheckComposite) run(stopCh chan struct{}) {
+	defer c.stopWg.Done()
+
+	// Very important this is buffered as 1 so that we do not lose any
+	// queued updates. Like for CheckAlias, any notification triggers a full
+	// recomputation.
+	notifyCh := make(chan struct{}, 1)
+	watched := make(map[structs.ServiceID]struct{})
+	watch := func(sid structs.ServiceID) {
+		if _, ok := watched[sid]; ok {
+			return
+		}
+		if err := c.Notify.AddAliasCheck(c.CheckID, sid, notifyCh); err == nil {
+			watched[sid] = struct{}{}
+		}
+	}
+	defer func() {
+		for sid := range watched {
+			c.Notify.RemoveAliasCheck(c.CheckID, sid)
+		}
+	}()
+
+	// Node checks are notified under the empty service ID.
+	watch(structs.NewServiceID("", &c.EnterpriseMeta))
+
+	// maxDurationBetweenUpdates is the maximum time we go between
+	// recomputations, which also picks up local checks registered after
+	// this one.
+	const maxDurationBetweenUpdates = 1 * time.Minute
+	refreshTimer := time.NewTimer(maxDurationBetweenUpdates)
+	defer refreshTimer.Stop()
+
+	updateStatus := func() {
+		local := c.Notify.Checks(c.WithWildcardNamespace())
+		for _, chk := range local {
+			for _, ref := range c.Checks {
+				if ref.Node == "" && c.localCheckID(ref) == chk.CompoundCheckID() {
+					watch(chk.CompoundServiceID())
+				}
+			}
+		}
+		c.processChecks(local)
+
+		if !refreshTimer.Stop() {
+			select {
+			case <-refreshTimer.C:
+			default:
+			}
+		}
+		refreshTimer.Reset(maxDurationBetweenUpdates)
+	}
+
+	updateStatus()
+	for {
+		select {
+		case <-refreshTimer.C:
+			updateStatus()
+		case <-notifyCh:
+			updateStatus()
+		case <-c.updateCh:
+			updateStatus()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// runQuery watches the health checks of a remote node with blocking queries,
+// using the same backoff as CheckAlias.
+func (c *CheckComposite) runQuery(node string, stopCh chan struct{}) {
+	defer c.stopWg.Done()
+
+	args := c.RPCReq
+	args.Node = node
+	args.AllowStale = true
+	args.MaxQueryTime = 1 * time.Minute
+	args.EnterpriseMeta = c.EnterpriseMeta
+	// We are late at maximum of 15s compared to leader
+	args.MaxStaleDuration = 15 * time.Second
+
+	var attempt uint
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		if attempt > checkAliasBackoffMin {
+			shift := attempt - checkAliasBackoffMin
+			if shift > 31 {
+				shift = 31 // so we don't overflow to 0
+			}
+			waitTime := (1 << shift) * time.Second
+			if waitTime > checkAliasBackoffMaxWait {
+				waitTime = checkAliasBackoffMaxWait
+			}
+			select {
+			case <-time.After(waitTime):
+			case <-stopCh:
+				return
+			}
+		}
+
+		var out structs.IndexedHealthChecks
+		if err := c.RPC.RPC(context.Background(), "Health.NodeChecks", &args, &out); err != nil {
+			attempt++
+			if attempt > 1 {
+				c.setRemote(node, nil, err)
+			}
+			continue
+		}
+		attempt = 0
+
+		// Always block on subsequent requests to avoid hot loops, see
+		// CheckAlias.runQuery.
+		args.MinQueryIndex = out.Index
+		if args.MinQueryIndex < 1 {
+			args.MinQueryIndex = 1
+		}
+		c.setRemote(node, out.HealthChecks, nil)
+	}
+}
+
+func (c *CheckComposite) setRemote(node string, checks []*structs.HealthCheck, err error) {
+	c.remoteLock.Lock()
+	if err != nil {
+		c.remoteErr[node] = err
+	} else {
+		delete(c.remoteErr, node)
+		if checks == nil {
+			checks = []*structs.HealthCheck{}
+		}
+		c.remote[node] = checks
+	}
+	c.remoteLock.Unlock()
+
+	select {
+	case c.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+// compositeResult is the status of one of the checks of a composite check.
+type compositeResult struct {
+	ref    structs.CompositeCheckRef
+	status string
+	reason string
+}
+
+// processChecks computes and updates the status of the composite check from
+// the local checks and the last known checks of remote nodes. The status
+// isn't updated until every remote node has been queried at least once.
+func (c *CheckComposite) processChecks(local map[structs.CheckID]*structs.HealthCheck) {
+	c.remoteLock.Lock()
+	defer c.remoteLock.Unlock()
+
+	results := make([]compositeResult, 0, len(c.Checks))
+	for _, ref := range c.Checks {
+		result := compositeResult{ref: ref, status: api.HealthCritical}
+		if ref.Node == "" {
+			if chk, ok := local[c.localCheckID(ref)]; ok {
+				result.status = chk.Status
+			} else {
+				result.reason = "check not found"
+			}
+			results = append(results, result)
+			continue
+		}
+
+		checks, ok := c.remote[ref.Node]
+		if err := c.remoteErr[ref.Node]; err != nil {
+			result.reason = fmt.Sprintf("failed to query node: %s", err)
+		} else if !ok {
+			// Wait for the first response of the node.
+			return
+		} else {
+			result.reason = "check not found"
+			for _, chk := range checks {
+				if chk.CheckID == ref.CheckID && strings.EqualFold(chk.Node, ref.Node) {
+					result.status, result.reason = chk.Status, ""
+					break
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	status, output := evaluateComposite(c.Mode, c.CriticalThreshold, c.WarningThreshold, results)
+	c.Notify.UpdateCheck(c.CheckID, status, output)
+}
+
+// localCheckID returns the ID of a referenced local check, which lives in the
+// same partition and namespace as the composite check.
+func (c *CheckComposite) localCheckID(ref structs.CompositeCheckRef) structs.CheckID {
+	return structs.NewCheckID(ref.CheckID, &c.EnterpriseMeta)
+}
+
+// evaluateComposite returns the status of a composite check and an output
+// listing the contributing checks.
+func evaluateComposite(mode string, criticalThreshold, warningThreshold int, results []compositeResult) (string, string) {
+	var passing, warning, critical int
+	for _, r := range results {
+		switch r.status {
+		case api.HealthPassing:
+			passing++
+		case api.HealthWarning:
+			warning++
+		default:
+			critical++
+		}
+	}
+	total := len(results)
+
+	var status, desc string
+	switch mode {
+	case structs.CompositeModeAny:
+		desc = "any check must be passing"
+		switch {
+		case passing > 0:
+			status = api.HealthPassing
+		case warning > 0:
+			status = api.HealthWarning
+		default:
+			status = api.HealthCritical
+		}
+
+	case structs.CompositeModeQuorum:
+		quorum := total/2 + 1
+		desc = fmt.Sprintf("a quorum of %d checks must be passing", quorum)
+		switch {
+		case passing >= quorum:
+			status = api.HealthPassing
+		case passing+warning >= quorum:
+			status = api.HealthWarning
+		default:
+			status = api.HealthCritical
+		}
+
+	case structs.CompositeModeThreshold:
+		desc = fmt.Sprintf("critical at %d critical checks", criticalThreshold)
+		if warningThreshold > 0 {
+			desc += fmt.Sprintf(", warning at %d failing checks", warningThreshold)
+		}
+		switch {
+		case critical >= criticalThreshold:
+			status = api.HealthCritical
+		case warningThreshold > 0 && warning+critical >= warningThreshold:
+			status = api.HealthWarning
+		default:
+			status = api.HealthPassing
+		}
+
+	default:
+		desc = "all checks must be passing"
+		switch {
+		case critical > 0:
+			status = api.HealthCritical
+		case warning > 0:
+			status = api.HealthWarning
+		default:
+			status = api.HealthPassing
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Composite check %s (%s): %d passing, %d warning, %d critical",
+		status, desc, passing, warning, critical)
+	for _, r := range results {
+		fmt.Fprintf(&b, "\n- %s: %s", r.ref, r.status)
+		if r.reason != "" {
+			fmt.Fprintf(&b, " (%s)", r.reason)
+		}
+	}
+	return status, b.String()
+}
@@ -0,0 +1,285 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/types"
+)
+
+func TestEvaluateComposite(t *testing.T) {
+	results := func(statuses ...string) []compositeResult {
+		var out []compositeResult
+		for i, status := range statuses {
+			out = append(out, compositeResult{
+				ref:    structs.CompositeCheckRef{CheckID: types.CheckID(fmt.Sprintf("check-%d", i))},
+				status: status,
+			})
+		}
+		return out
+	}
+	const (
+		pass = api.HealthPassing
+		warn = api.HealthWarning
+		crit = api.HealthCritical
+	)
+
+	cases := []struct {
+		mode      string
+		critical  int
+		warning   int
+		statuses  []string
+		expected  string
+		outputHas string
+	}{
+		{mode: "", statuses: []string{pass, pass}, expected: pass, outputHas: "all checks must be passing"},
+		{mode: structs.CompositeModeAll, statuses: []string{pass, warn}, expected: warn},
+		{mode: structs.CompositeModeAll, statuses: []string{warn, crit, pass}, expected: crit},
+		{mode: structs.CompositeModeAny, statuses: []string{crit, pass}, expected: pass},
+		{mode: structs.CompositeModeAny, statuses: []string{crit, warn}, expected: warn},
+		{mode: structs.CompositeModeAny, statuses: []string{crit, crit}, expected: crit},
+		{mode: structs.CompositeModeQuorum, statuses: []string{pass, pass, crit}, expected: pass, outputHas: "a quorum of 2 checks"},
+		{mode: structs.CompositeModeQuorum, statuses: []string{pass, warn, crit}, expected: warn},
+		{mode: structs.CompositeModeQuorum, statuses: []string{pass, crit, crit}, expected: crit},
+		{mode: structs.CompositeModeQuorum, statuses: []string{pass, pass, crit, crit}, expected: crit},
+		{mode: structs.CompositeModeThreshold, critical: 2, warning: 1, statuses: []string{pass, pass, pass}, expected: pass},
+		{mode: structs.CompositeModeThreshold, critical: 2, warning: 1, statuses: []string{crit, pass, pass}, expected: warn},
+		{mode: structs.CompositeModeThreshold, critical: 2, warning: 1, statuses: []string{crit, crit, pass}, expected: crit, outputHas: "critical at 2 critical checks, warning at 1 failing checks"},
+		{mode: structs.CompositeModeThreshold, critical: 2, statuses: []string{crit, warn, pass}, expected: pass},
+	}
+
+	for _, tc := range cases {
+		name := fmt.Sprintf("%s %d/%d %s", tc.mode, tc.critical, tc.warning, strings.Join(tc.statuses, ","))
+		t.Run(name, func(t *testing.T) {
+			status, output := evaluateComposite(tc.mode, tc.critical, tc.warning, results(tc.statuses...))
+			require.Equal(t, tc.expected, status)
+			require.Contains(t, output, tc.outputHas)
+			for i, s := range tc.statuses {
+				require.Contains(t, output, fmt.Sprintf("- check-%d: %s", i, s))
+			}
+		})
+	}
+}
+
+// mockCompositeNotify is an AliasNotifier that returns the given local
+// checks.
+type mockCompositeNotify struct {
+	*mockAliasNotify
+
+	lock    sync.Mutex
+	checks  map[structs.CheckID]*structs.HealthCheck
+	watched map[structs.ServiceID]chan<- struct{}
+}
+
+func newMockCompositeNotify(checks ...*structs.HealthCheck) *mockCompositeNotify {
+	m := &mockCompositeNotify{
+		mockAliasNotify: newMockAliasNotify(),
+		checks:          make(map[structs.CheckID]*structs.HealthCheck),
+		watched:         make(map[structs.ServiceID]chan<- struct{}),
+	}
+	for _, chk := range checks {
+		m.checks[chk.CompoundCheckID()] = chk
+	}
+	return m
+}
+
+func (m *mockCompositeNotify) AddAliasCheck(chkID structs.CheckID, serviceID structs.ServiceID, ch chan<- struct{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.watched[serviceID] = ch
+	return nil
+}
+
+func (m *mockCompositeNotify) Checks(*acl.EnterpriseMeta) map[structs.CheckID]*structs.HealthCheck {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	out := make(map[structs.CheckID]*structs.HealthCheck, len(m.checks))
+	for id, chk := range m.checks {
+		out[id] = chk.Clone()
+	}
+	return out
+}
+
+// setStatus updates the status of a local check and notifies the watchers
+// of its service.
+func (m *mockCompositeNotify) setStatus(id types.CheckID, status string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	chk := m.checks[structs.NewCheckID(id, nil)]
+	chk.Status = status
+	if ch, ok := m.watched[chk.CompoundServiceID()]; ok {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func TestCheckComposite_local(t *testing.T) {
+	t.Parallel()
+
+	notify := newMockCompositeNotify(
+		&structs.HealthCheck{CheckID: "cache-1", ServiceID: "cache", Status: api.HealthPassing},
+		&structs.HealthCheck{CheckID: "cache-2", ServiceID: "cache", Status: api.HealthPassing},
+		&structs.HealthCheck{CheckID: "mem", Status: api.HealthPassing},
+	)
+	chkID := structs.NewCheckID("composite", nil)
+	chk := &CheckComposite{
+		CheckID: chkID,
+		Checks: []structs.CompositeCheckRef{
+			{CheckID: "cache-1"},
+			{CheckID: "cache-2"},
+			{CheckID: "mem"},
+		},
+		Notify: notify,
+		RPC:    &mockRPC{},
+	}
+	chk.Start()
+	defer chk.Stop()
+
+	expect := func(status string, output ...string) {
+		t.Helper()
+		retry.Run(t, func(r *retry.R) {
+			if got := notify.State(chkID); got != status {
+				r.Fatalf("got state %q want %q", got, status)
+			}
+			for _, o := range output {
+				if got := notify.Output(chkID); !strings.Contains(got, o) {
+					r.Fatalf("got output %q want it to contain %q", got, o)
+				}
+			}
+		})
+	}
+
+	expect(api.HealthPassing, "3 passing, 0 warning, 0 critical")
+
+	notify.setStatus("cache-2", api.HealthWarning)
+	expect(api.HealthWarning, "- cache-2: warning")
+
+	notify.setStatus("mem", api.HealthCritical)
+	expect(api.HealthCritical, "- mem: critical")
+}
+
+func TestCheckComposite_localMissing(t *testing.T) {
+	t.Parallel()
+
+	notify := newMockCompositeNotify(
+		&structs.HealthCheck{CheckID: "db", Status: api.HealthPassing},
+	)
+	chkID := structs.NewCheckID("composite", nil)
+	chk := &CheckComposite{
+		CheckID: chkID,
+		Checks:  []structs.CompositeCheckRef{{CheckID: "db"}, {CheckID: "missing"}},
+		Mode:    structs.CompositeModeAny,
+		Notify:  notify,
+		RPC:     &mockRPC{},
+	}
+	chk.Start()
+	defer chk.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notify.State(chkID), api.HealthPassing; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+		if got, want := notify.Output(chkID), "- missing: critical (check not found)"; !strings.Contains(got, want) {
+			r.Fatalf("got output %q want it to contain %q", got, want)
+		}
+	})
+}
+
+func TestCheckComposite_remoteThreshold(t *testing.T) {
+	t.Parallel()
+
+	notify := newMockCompositeNotify()
+	chkID := structs.NewCheckID("composite", nil)
+	rpc := &mockRPC{}
+	chk := &CheckComposite{
+		CheckID: chkID,
+		Checks: []structs.CompositeCheckRef{
+			{Node: "db-1", CheckID: "db"},
+			{Node: "db-2", CheckID: "db"},
+			{Node: "db-3", CheckID: "db"},
+		},
+		Mode:              structs.CompositeModeThreshold,
+		CriticalThreshold: 2,
+		WarningThreshold:  1,
+		Notify:            notify,
+		RPC:               rpc,
+	}
+
+	// The mock returns the same reply for every node, the check only uses
+	// the checks of the node it queried for.
+	rpc.AddReply("Health.NodeChecks", structs.IndexedHealthChecks{
+		HealthChecks: []*structs.HealthCheck{
+			{Node: "db-1", CheckID: "db", Status: api.HealthCritical},
+			{Node: "db-2", CheckID: "db", Status: api.HealthPassing},
+			{Node: "db-3", CheckID: "db", Status: api.HealthPassing},
+		},
+	})
+	chk.Start()
+	defer chk.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notify.State(chkID), api.HealthWarning; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+		if got, want := notify.Output(chkID), "- db-1/db: critical\n- db-2/db: passing\n- db-3/db: passing"; !strings.Contains(got, want) {
+			r.Fatalf("got output %q want it to contain %q", got, want)
+		}
+	})
+
+	rpc.Replies["Health.NodeChecks"].Store(structs.IndexedHealthChecks{
+		HealthChecks: []*structs.HealthCheck{
+			{Node: "db-1", CheckID: "db", Status: api.HealthCritical},
+			{Node: "db-2", CheckID: "db", Status: api.HealthCritical},
+			{Node: "db-3", CheckID: "db", Status: api.HealthPassing},
+		},
+	})
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notify.State(chkID), api.HealthCritical; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+	})
+}
+
+func TestCheckComposite_remoteError(t *testing.T) {
+	t.Parallel()
+
+	notify := newMockCompositeNotify(
+		&structs.HealthCheck{CheckID: "local", Status: api.HealthPassing},
+	)
+	chkID := structs.NewCheckID("composite", nil)
+	rpc := &mockRPC{}
+	chk := &CheckComposite{
+		CheckID: chkID,
+		Checks: []structs.CompositeCheckRef{
+			{CheckID: "local"},
+			{Node: "remote", CheckID: "db"},
+		},
+		Notify: notify,
+		RPC:    rpc,
+	}
+	rpc.AddReply("Health.NodeChecks", fmt.Errorf("failure"))
+	chk.Start()
+	defer chk.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notify.State(chkID), api.HealthCritical; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+		if got, want := notify.Output(chkID), "- remote/db: critical (failed to query node: failure)"; !strings.Contains(got, want) {
+			r.Fatalf("got output %q want it to contain %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checks
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/miekg/dns"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib"
+)
+
+// CheckDNS is used to periodically query a DNS server and verify its answer.
+// The check is passing if the response code matches the expected one
+// (NOERROR by default) and, for NOERROR responses, the answer section holds
+// at least one record of the queried type and every expected value.
+type CheckDNS struct {
+	CheckID        structs.CheckID
+	ServiceID      structs.ServiceID
+	DNS            string
+	QueryName      string
+	QueryType      string
+	Protocol       string
+	ExpectedAnswer []string
+	ExpectedRcode  string
+	Interval       time.Duration
+	Timeout        time.Duration
+	Logger         hclog.Logger
+	StatusHandler  *StatusHandler
+
+	client *dns.Client
+	stop   bool
+	stopCh chan struct{}
+	// stopLock protects stop and stopCh
+	stopLock sync.Mutex
+}
+
+func (c *CheckDNS) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+
+	if c.client == nil {
+		c.client = c.newClient()
+	}
+
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	go c.run()
+}
+
+func (c *CheckDNS) newClient() *dns.Client {
+	client := &dns.Client{
+		Net:     strings.ToLower(c.Protocol),
+		Timeout: 10 * time.Second,
+	}
+	if c.Timeout > 0 {
+		client.Timeout = c.Timeout
+	}
+	return client
+}
+
+func (c *CheckDNS) Stop() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+}
+
+func (c *CheckDNS) run() {
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+func (c *CheckDNS) check() {
+	status, output := c.query()
+	if status != api.HealthPassing {
+		c.Logger.Warn("DNS check failed",
+			"check", c.CheckID.String(),
+			"error", output,
+		)
+	}
+	c.StatusHandler.updateCheck(c.CheckID, status, output)
+}
+
+// query runs the DNS query and returns the resulting check status and output.
+func (c *CheckDNS) query() (string, string) {
+	qtype := dns.TypeA
+	if c.QueryType != "" {
+		qtype = dns.StringToType[strings.ToUpper(c.QueryType)]
+	}
+	expectedRcode := dns.RcodeSuccess
+	if c.ExpectedRcode != "" {
+		expectedRcode = dns.StringToRcode[strings.ToUpper(c.ExpectedRcode)]
+	}
+
+	server := dnsServerAddr(c.DNS)
+	desc := fmt.Sprintf("DNS query %s %s @%s", dns.Fqdn(c.QueryName), dns.TypeToString[qtype], server)
+
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(c.QueryName), qtype)
+	resp, rtt, err := c.client.Exchange(m, server)
+	if err != nil {
+		return api.HealthCritical, fmt.Sprintf("%s: %s", desc, err)
+	}
+
+	if resp.Rcode != expectedRcode {
+		return api.HealthCritical, fmt.Sprintf("%s: got %s, expected %s",
+			desc, dns.RcodeToString[resp.Rcode], dns.RcodeToString[expectedRcode])
+	}
+
+	var values []string
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == qtype {
+			values = append(values, dnsAnswerValue(rr))
+		}
+	}
+	if expectedRcode == dns.RcodeSuccess && len(values) == 0 {
+		return api.HealthCritical, fmt.Sprintf("%s: %s with no %s answers",
+			desc, dns.RcodeToString[resp.Rcode], dns.TypeToString[qtype])
+	}
+
+	var missing []string
+	for _, want := range c.ExpectedAnswer {
+		if !dnsAnswerContains(values, want) {
+			missing = append(missing, want)
+		}
+	}
+	if len(missing) > 0 {
+		return api.HealthCritical, fmt.Sprintf("%s: answer [%s] is missing [%s]",
+			desc, strings.Join(values, ", "), strings.Join(missing, ", "))
+	}
+
+	return api.HealthPassing, fmt.Sprintf("%s: Success, %s with answer [%s] in %s",
+		desc, dns.RcodeToString[resp.Rcode], strings.Join(values, ", "), rtt)
+}
+
+// dnsServerAddr adds the default DNS port to the server address if it
+// doesn't include one.
+func dnsServerAddr(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(strings.Trim(addr, "[]"), "53")
+}
+
+// dnsAnswerValue returns the data of a resource record, without its header.
+func dnsAnswerValue(rr dns.RR) string {
+	switch v := rr.(type) {
+	case *dns.A:
+		return v.A.String()
+	case *dns.AAAA:
+		return v.AAAA.String()
+	case *dns.CNAME:
+		return v.Target
+	case *dns.PTR:
+		return v.Ptr
+	case *dns.NS:
+		return v.Ns
+	case *dns.MX:
+		return v.Mx
+	case *dns.SRV:
+		return v.Target
+	case *dns.TXT:
+		return strings.Join(v.Txt, "")
+	default:
+		return strings.TrimPrefix(rr.String(), rr.Header().String())
+	}
+}
+
+// dnsAnswerContains returns whether the expected value is among the answer
+// values. IP addresses are compared by value, and names are compared case
+// insensitively with or without a trailing dot.
+func dnsAnswerContains(values []string, want string) bool {
+	wantIP := net.ParseIP(want)
+	for _, v := range values {
+		if wantIP != nil {
+			if ip := net.ParseIP(v); ip != nil && ip.Equal(wantIP) {
+				return true
+			}
+			continue
+		}
+		if v == want || strings.EqualFold(dns.Fqdn(v), dns.Fqdn(want)) {
+			return true
+		}
+	}
+	return false
+}
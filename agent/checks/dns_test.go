@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checks
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+// mockDNSServer starts a DNS server on the given network that answers for a
+// few fixed names, and returns its address.
+func mockDNSServer(t *testing.T, network string) string {
+	t.Helper()
+
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+		q := req.Question[0]
+		hdr := dns.RR_Header{Name: q.Name, Rrtype: q.Qtype, Class: dns.ClassINET, Ttl: 30}
+		switch {
+		case q.Name == "web.example.com." && q.Qtype == dns.TypeA:
+			m.Answer = append(m.Answer,
+				&dns.A{Hdr: hdr, A: net.ParseIP("10.0.0.1")},
+				&dns.A{Hdr: hdr, A: net.ParseIP("10.0.0.2")},
+			)
+		case q.Name == "alias.example.com." && q.Qtype == dns.TypeCNAME:
+			m.Answer = append(m.Answer, &dns.CNAME{Hdr: hdr, Target: "web.example.com."})
+		case q.Name == "web.example.com.":
+			// NOERROR with an empty answer section.
+		default:
+			m.Rcode = dns.RcodeNameError
+		}
+		require.NoError(t, w.WriteMsg(m))
+	})
+
+	up := make(chan struct{})
+	server := &dns.Server{
+		Addr:              "127.0.0.1:0",
+		Net:               network,
+		Handler:           mux,
+		NotifyStartedFunc: func() { close(up) },
+	}
+	go server.ListenAndServe()
+	<-up
+	t.Cleanup(func() { server.Shutdown() })
+
+	if network == "tcp" {
+		return server.Listener.Addr().String()
+	}
+	return server.PacketConn.LocalAddr().String()
+}
+
+func TestCheckDNS_Query(t *testing.T) {
+	t.Parallel()
+	udpAddr := mockDNSServer(t, "udp")
+	tcpAddr := mockDNSServer(t, "tcp")
+
+	cases := map[string]struct {
+		check  *CheckDNS
+		status string
+		output string
+	}{
+		"passing": {
+			check:  &CheckDNS{DNS: udpAddr, QueryName: "web.example.com"},
+			status: api.HealthPassing,
+			output: "Success, NOERROR with answer [10.0.0.1, 10.0.0.2]",
+		},
+		"passing over tcp": {
+			check:  &CheckDNS{DNS: tcpAddr, QueryName: "web.example.com", Protocol: "TCP"},
+			status: api.HealthPassing,
+		},
+		"expected answer": {
+			check:  &CheckDNS{DNS: udpAddr, QueryName: "web.example.com", ExpectedAnswer: []string{"10.0.0.2"}},
+			status: api.HealthPassing,
+		},
+		"expected answer missing": {
+			check:  &CheckDNS{DNS: udpAddr, QueryName: "web.example.com", ExpectedAnswer: []string{"10.0.0.2", "10.0.0.3"}},
+			status: api.HealthCritical,
+			output: "answer [10.0.0.1, 10.0.0.2] is missing [10.0.0.3]",
+		},
+		"expected name": {
+			check:  &CheckDNS{DNS: udpAddr, QueryName: "alias.example.com", QueryType: "cname", ExpectedAnswer: []string{"WEB.example.com"}},
+			status: api.HealthPassing,
+		},
+		"no answers": {
+			check:  &CheckDNS{DNS: udpAddr, QueryName: "web.example.com", QueryType: "AAAA"},
+			status: api.HealthCritical,
+			output: "NOERROR with no AAAA answers",
+		},
+		"unexpected rcode": {
+			check:  &CheckDNS{DNS: udpAddr, QueryName: "db.example.com"},
+			status: api.HealthCritical,
+			output: "got NXDOMAIN, expected NOERROR",
+		},
+		"expected rcode": {
+			check:  &CheckDNS{DNS: udpAddr, QueryName: "db.example.com", ExpectedRcode: "nxdomain"},
+			status: api.HealthPassing,
+		},
+		"unreachable": {
+			check:  &CheckDNS{DNS: "127.0.0.1:1", QueryName: "web.example.com", Protocol: "tcp"},
+			status: api.HealthCritical,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.check.Timeout = time.Second
+			tc.check.client = tc.check.newClient()
+
+			status, output := tc.check.query()
+			require.Equal(t, tc.status, status, output)
+			require.Contains(t, output, tc.output)
+		})
+	}
+}
+
+func TestCheckDNS(t *testing.T) {
+	t.Parallel()
+	addr := mockDNSServer(t, "udp")
+
+	notif := mock.NewNotify()
+	logger := testutil.Logger(t)
+	statusHandler := NewStatusHandler(notif, logger, 0, 0, 0)
+	cid := structs.NewCheckID("foo", nil)
+
+	check := &CheckDNS{
+		CheckID:        cid,
+		DNS:            addr,
+		QueryName:      "web.example.com",
+		ExpectedAnswer: []string{"10.0.0.1"},
+		Interval:       10 * time.Millisecond,
+		Logger:         logger,
+		StatusHandler:  statusHandler,
+	}
+	check.Start()
+	defer check.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notif.State(cid), api.HealthPassing; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+	})
+}
+
+func TestDNSServerAddr(t *testing.T) {
+	require.Equal(t, "127.0.0.1:53", dnsServerAddr("127.0.0.1"))
+	require.Equal(t, "127.0.0.1:8600", dnsServerAddr("127.0.0.1:8600"))
+	require.Equal(t, "[::1]:53", dnsServerAddr("::1"))
+	require.Equal(t, "[::1]:53", dnsServerAddr("[::1]"))
+}
@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checks
+
+import (
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// flapHistorySize is the number of check results kept to detect flapping.
+// Like Nagios, the last 21 results give 20 possible state changes.
+const flapHistorySize = 21
+
+// The weights given to the oldest and the newest state changes of the
+// history, so that recent changes count more than older ones.
+const (
+	flapLowWeight  = 0.8
+	flapHighWeight = 1.2
+)
+
+var FlapCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"agent", "check", "flapping", "start"},
+		Help: "Increments whenever a check with flap detection starts flapping.",
+	},
+	{
+		Name: []string{"agent", "check", "flapping", "stop"},
+		Help: "Increments whenever a check with flap detection stops flapping.",
+	},
+	{
+		Name: []string{"agent", "check", "flapping", "suppressed"},
+		Help: "Increments whenever a status change of a flapping check is suppressed.",
+	},
+}
+
+// FlapNotifier is implemented by a CheckNotifier that keeps track of the
+// checks that are flapping.
+type FlapNotifier interface {
+	UpdateCheckFlapping(checkID structs.CheckID, flapping bool)
+}
+
+// flapDetector detects checks oscillating between statuses, using the
+// weighted percentage of state changes in the last results of the check.
+// A check starts flapping when the percentage reaches the high threshold
+// and stops flapping when it goes below the low threshold.
+type flapDetector struct {
+	lowThreshold  float64
+	highThreshold float64
+
+	// history is a ring buffer of the last statuses, next is the position
+	// of the next status and full is set once history has wrapped.
+	history []string
+	next    int
+	full    bool
+
+	flapping bool
+}
+
+func newFlapDetector(lowThreshold, highThreshold int) *flapDetector {
+	return &flapDetector{
+		lowThreshold:  float64(lowThreshold),
+		highThreshold: float64(highThreshold),
+		history:       make([]string, flapHistorySize),
+	}
+}
+
+// record adds a status to the history and returns whether the check is
+// flapping, and whether that changed with this status.
+func (d *flapDetector) record(status string) (flapping, changed bool) {
+	d.history[d.next] = status
+	d.next = (d.next + 1) % len(d.history)
+	if d.next == 0 {
+		d.full = true
+	}
+
+	percent := d.percentStateChange()
+	switch {
+	case !d.flapping && percent >= d.highThreshold:
+		d.flapping = true
+		return true, true
+	case d.flapping && percent < d.lowThreshold:
+		d.flapping = false
+		return false, true
+	}
+	return d.flapping, false
+}
+
+// percentStateChange returns the weighted percentage of state changes in the
+// history. Like Nagios, it is computed over the possible changes of a full
+// history even while the history is filling up, so that a new check doesn't
+// start flapping on its first changes.
+func (d *flapDetector) percentStateChange() float64 {
+	statuses := d.statuses()
+	changes := len(d.history) - 1
+	step := (flapHighWeight - flapLowWeight) / float64(changes-1)
+
+	// The newest change has the highest weight, so the weights are
+	// aligned as if the history was full.
+	offset := changes - (len(statuses) - 1)
+	var total float64
+	for i := 1; i < len(statuses); i++ {
+		if statuses[i] != statuses[i-1] {
+			total += flapLowWeight + float64(offset+i-1)*step
+		}
+	}
+	return total * 100 / float64(changes)
+}
+
+// statuses returns the statuses of the history, from the oldest to the newest.
+func (d *flapDetector) statuses() []string {
+	if !d.full {
+		return d.history[:d.next]
+	}
+	out := make([]string, 0, len(d.history))
+	out = append(out, d.history[d.next:]...)
+	return append(out, d.history[:d.next]...)
+}
+
+// flapLabels returns the labels of the flapping metrics. They don't include
+// the check ID so that their cardinality stays bounded on large clusters.
+func flapLabels(checkType, serviceName string) []metrics.Label {
+	return []metrics.Label{
+		{Name: "type", Value: checkType},
+		{Name: "service", Value: serviceName},
+	}
+}
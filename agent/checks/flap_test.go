@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestFlapDetector_percentStateChange(t *testing.T) {
+	d := newFlapDetector(5, 20)
+	require.Equal(t, 0.0, d.percentStateChange())
+
+	// A single change is weighted as the newest change of a full history.
+	d.record(api.HealthPassing)
+	d.record(api.HealthCritical)
+	require.InDelta(t, 6.0, d.percentStateChange(), 0.001)
+
+	// Once the change is the oldest of the history, it has the lowest weight.
+	for i := 0; i < flapHistorySize-2; i++ {
+		d.record(api.HealthCritical)
+	}
+	require.InDelta(t, 4.0, d.percentStateChange(), 0.001)
+
+	// And it is dropped once it leaves the history.
+	d.record(api.HealthCritical)
+	require.Equal(t, 0.0, d.percentStateChange())
+
+	// A check changing status on every result has 100% of state changes.
+	status := api.HealthPassing
+	for i := 0; i < flapHistorySize; i++ {
+		if status == api.HealthPassing {
+			status = api.HealthCritical
+		} else {
+			status = api.HealthPassing
+		}
+		d.record(status)
+	}
+	require.InDelta(t, 100.0, d.percentStateChange(), 0.001)
+}
+
+func TestFlapDetector_hysteresis(t *testing.T) {
+	d := newFlapDetector(5, 20)
+
+	statuses := []string{api.HealthPassing, api.HealthCritical, api.HealthPassing, api.HealthCritical}
+	for _, status := range statuses {
+		flapping, changed := d.record(status)
+		require.False(t, flapping)
+		require.False(t, changed)
+	}
+
+	// The 4th change reaches the high threshold.
+	flapping, changed := d.record(api.HealthPassing)
+	require.True(t, flapping)
+	require.True(t, changed)
+
+	// The check keeps flapping while the percentage is above the low
+	// threshold, even though it is below the high threshold.
+	for i := 0; i < flapHistorySize-5; i++ {
+		flapping, changed = d.record(api.HealthPassing)
+		require.True(t, flapping)
+		require.False(t, changed)
+	}
+	require.Less(t, d.percentStateChange(), 20.0)
+
+	var stopped int
+	for i := 0; i < flapHistorySize; i++ {
+		if flapping, changed = d.record(api.HealthPassing); changed {
+			require.False(t, flapping)
+			stopped++
+		}
+	}
+	require.Equal(t, 1, stopped)
+}
+
+func TestStatusHandler_flapDetection(t *testing.T) {
+	t.Parallel()
+	cid := structs.NewCheckID("foo", nil)
+	notif := mock.NewNotify()
+	statusHandler := NewStatusHandler(notif, testutil.Logger(t), 0, 0, 0)
+	statusHandler.EnableFlapDetection(5, 20, "ttl", "web")
+
+	statusHandler.updateCheck(cid, api.HealthPassing, "ok")
+	statusHandler.updateCheck(cid, api.HealthCritical, "failed 1")
+	statusHandler.updateCheck(cid, api.HealthPassing, "ok")
+	statusHandler.updateCheck(cid, api.HealthCritical, "failed 2")
+	require.False(t, notif.Flapping(cid))
+	require.Equal(t, api.HealthCritical, notif.State(cid))
+
+	// The check starts flapping and keeps its last status, with the latest
+	// output.
+	statusHandler.updateCheck(cid, api.HealthPassing, "ok")
+	require.True(t, notif.Flapping(cid))
+	require.Equal(t, api.HealthCritical, notif.State(cid))
+	require.Equal(t, "ok", notif.Output(cid))
+
+	statusHandler.updateCheck(cid, api.HealthWarning, "failed 3")
+	require.Equal(t, api.HealthCritical, notif.State(cid))
+	require.Equal(t, "failed 3", notif.Output(cid))
+
+	// The status is updated again once the check is stable.
+	for i := 0; i < flapHistorySize; i++ {
+		statusHandler.updateCheck(cid, api.HealthPassing, "ok")
+	}
+	require.False(t, notif.Flapping(cid))
+	require.Equal(t, api.HealthPassing, notif.State(cid))
+}
+
+func TestStatusHandler_flapDetectionDisabled(t *testing.T) {
+	t.Parallel()
+	cid := structs.NewCheckID("foo", nil)
+	notif := mock.NewNotify()
+	statusHandler := NewStatusHandler(notif, testutil.Logger(t), 0, 0, 0)
+
+	for i := 0; i < flapHistorySize; i++ {
+		statusHandler.updateCheck(cid, api.HealthPassing, "ok")
+		statusHandler.updateCheck(cid, api.HealthCritical, "failed")
+	}
+	require.False(t, notif.Flapping(cid))
+	require.Equal(t, api.HealthCritical, notif.State(cid))
+}
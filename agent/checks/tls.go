@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checks
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib"
+)
+
+// CheckTLS is used to periodically make a TLS connection to an address and
+// inspect the certificate presented by the peer. The check is critical if
+// the certificate chain fails verification, or if the leaf certificate
+// expires within ExpiryCriticalDays. It is warning if the leaf certificate
+// expires within ExpiryWarningDays, and passing otherwise. The thresholds are
+// the resolved ones of the check definition, see
+// structs.CheckType.TLSExpiryThresholds.
+type CheckTLS struct {
+	CheckID            structs.CheckID
+	ServiceID          structs.ServiceID
+	TLS                string
+	TLSClientConfig    *tls.Config
+	CAFile             string
+	ExpiryWarningDays  int
+	ExpiryCriticalDays int
+	Interval           time.Duration
+	Timeout            time.Duration
+	Logger             hclog.Logger
+	StatusHandler      *StatusHandler
+
+	dialer   *net.Dialer
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+
+	// now is a shim for testing.
+	now func() time.Time
+}
+
+// Start is used to start a TLS check.
+// The check runs until stop is called
+func (c *CheckTLS) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+
+	c.init()
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	go c.run()
+}
+
+func (c *CheckTLS) init() {
+	if c.dialer == nil {
+		c.dialer = &net.Dialer{
+			Timeout: 10 * time.Second,
+		}
+		if c.Timeout > 0 {
+			c.dialer.Timeout = c.Timeout
+		}
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+}
+
+// Stop is used to stop a TLS check.
+func (c *CheckTLS) Stop() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+}
+
+// run is invoked by a goroutine to run until Stop() is called
+func (c *CheckTLS) run() {
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// check is invoked periodically to perform the TLS check
+func (c *CheckTLS) check() {
+	status, output := c.inspect()
+	if status != api.HealthPassing {
+		c.Logger.Warn("TLS check is not passing",
+			"check", c.CheckID.String(),
+			"status", status,
+			"output", output,
+		)
+	}
+	c.StatusHandler.updateCheck(c.CheckID, status, output)
+}
+
+// inspect connects to the address and returns the check status and output
+// for the certificate presented by the peer.
+func (c *CheckTLS) inspect() (string, string) {
+	config, err := c.tlsConfig()
+	if err != nil {
+		return api.HealthCritical, err.Error()
+	}
+	serverName := config.ServerName
+	if serverName == "" {
+		serverName, _, _ = net.SplitHostPort(c.TLS)
+	}
+
+	// The chain is verified below rather than during the handshake, so that
+	// the certificate can be reported on even when it isn't trusted.
+	skipVerify := config.InsecureSkipVerify
+	config.InsecureSkipVerify = true
+
+	conn, err := tls.DialWithDialer(c.dialer, "tcp", c.TLS, config)
+	if err != nil {
+		return api.HealthCritical, fmt.Sprintf("TLS connect %s: %s", c.TLS, err)
+	}
+	state := conn.ConnectionState()
+	conn.Close()
+
+	if len(state.PeerCertificates) == 0 {
+		return api.HealthCritical, fmt.Sprintf("TLS connect %s: no certificate presented", c.TLS)
+	}
+	leaf := state.PeerCertificates[0]
+	now := c.now()
+	desc := fmt.Sprintf("TLS connect %s: certificate %q issued by %q expires at %s",
+		c.TLS, leaf.Subject, leaf.Issuer, leaf.NotAfter.UTC().Format(time.RFC3339))
+
+	if !now.Before(leaf.NotAfter) {
+		return api.HealthCritical, desc + ", certificate has expired"
+	}
+	if !skipVerify {
+		intermediates := x509.NewCertPool()
+		for _, cert := range state.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			Roots:         config.RootCAs,
+			Intermediates: intermediates,
+			DNSName:       serverName,
+			CurrentTime:   now,
+		})
+		if err != nil {
+			return api.HealthCritical, fmt.Sprintf("%s, certificate verification failed: %s", desc, err)
+		}
+	}
+
+	warningDays, criticalDays := c.ExpiryWarningDays, c.ExpiryCriticalDays
+	remaining := leaf.NotAfter.Sub(now)
+	days := int(remaining / (24 * time.Hour))
+	switch {
+	case remaining < time.Duration(criticalDays)*24*time.Hour:
+		return api.HealthCritical, fmt.Sprintf("%s, in %d days (critical threshold is %d days)", desc, days, criticalDays)
+	case remaining < time.Duration(warningDays)*24*time.Hour:
+		return api.HealthWarning, fmt.Sprintf("%s, in %d days (warning threshold is %d days)", desc, days, warningDays)
+	}
+	return api.HealthPassing, fmt.Sprintf("%s, in %d days", desc, days)
+}
+
+// tlsConfig returns the TLS configuration used for the connection, using the
+// roots from CAFile if it is set. The file is read on each run so that CA
+// rotation is picked up.
+func (c *CheckTLS) tlsConfig() (*tls.Config, error) {
+	var config *tls.Config
+	if c.TLSClientConfig != nil {
+		config = c.TLSClientConfig.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	if c.CAFile == "" {
+		return config, nil
+	}
+
+	pem, err := os.ReadFile(c.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("failed to parse CA file %q: no certificates found", c.CAFile)
+	}
+	config.RootCAs = pool
+	return config, nil
+}
@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checks
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/tlsutil"
+)
+
+// mockTLSServer starts a TLS listener that serves a certificate for
+// web.example.com valid for the given number of days, signed by a new CA. It
+// returns the listener address and the path of the CA file.
+func mockTLSServer(t *testing.T, days int) (string, string) {
+	t.Helper()
+
+	signer, _, err := tlsutil.GeneratePrivateKey()
+	require.NoError(t, err)
+	ca, _, err := tlsutil.GenerateCA(tlsutil.CAOpts{Signer: signer, Days: 365})
+	require.NoError(t, err)
+
+	serial, err := tlsutil.GenerateSerialNumber()
+	require.NoError(t, err)
+	certPEM, keyPEM, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+		Signer:      signer,
+		CA:          ca,
+		Serial:      serial,
+		Name:        "web.example.com",
+		Days:        days,
+		DNSNames:    []string{"web.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	})
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	require.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte(ca), 0600))
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}()
+		}
+	}()
+
+	return ln.Addr().String(), caFile
+}
+
+func TestCheckTLS_Inspect(t *testing.T) {
+	t.Parallel()
+	addr, caFile := mockTLSServer(t, 40)
+
+	cases := map[string]struct {
+		check  *CheckTLS
+		after  time.Duration
+		status string
+		output string
+	}{
+		"passing": {
+			check:  &CheckTLS{CAFile: caFile},
+			status: api.HealthPassing,
+			output: `certificate "CN=web.example.com" issued by`,
+		},
+		"warning": {
+			check:  &CheckTLS{CAFile: caFile},
+			after:  15 * 24 * time.Hour,
+			status: api.HealthWarning,
+			output: "(warning threshold is 30 days)",
+		},
+		"custom warning": {
+			check:  &CheckTLS{CAFile: caFile, ExpiryWarningDays: 50, ExpiryCriticalDays: 7},
+			status: api.HealthWarning,
+			output: "(warning threshold is 50 days)",
+		},
+		"critical": {
+			check:  &CheckTLS{CAFile: caFile},
+			after:  35 * 24 * time.Hour,
+			status: api.HealthCritical,
+			output: "(critical threshold is 7 days)",
+		},
+		"custom critical": {
+			check:  &CheckTLS{CAFile: caFile, ExpiryWarningDays: 60, ExpiryCriticalDays: 50},
+			status: api.HealthCritical,
+			output: "(critical threshold is 50 days)",
+		},
+		"expired": {
+			check:  &CheckTLS{CAFile: caFile},
+			after:  41 * 24 * time.Hour,
+			status: api.HealthCritical,
+			output: "certificate has expired",
+		},
+		"unknown authority": {
+			check:  &CheckTLS{},
+			status: api.HealthCritical,
+			output: "certificate verification failed",
+		},
+		"skip verify": {
+			check:  &CheckTLS{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+			status: api.HealthPassing,
+		},
+		"server name": {
+			check:  &CheckTLS{CAFile: caFile, TLSClientConfig: &tls.Config{ServerName: "web.example.com"}},
+			status: api.HealthPassing,
+		},
+		"server name mismatch": {
+			check:  &CheckTLS{CAFile: caFile, TLSClientConfig: &tls.Config{ServerName: "db.example.com"}},
+			status: api.HealthCritical,
+			output: "certificate is valid for web.example.com, not db.example.com",
+		},
+		"missing CA file": {
+			check:  &CheckTLS{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			status: api.HealthCritical,
+			output: "failed to read CA file",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.check.TLS = addr
+			tc.check.Timeout = time.Second
+			if tc.check.ExpiryWarningDays == 0 && tc.check.ExpiryCriticalDays == 0 {
+				tc.check.ExpiryWarningDays, tc.check.ExpiryCriticalDays = (&structs.CheckType{}).TLSExpiryThresholds()
+			}
+			tc.check.init()
+			tc.check.now = func() time.Time { return time.Now().Add(tc.after) }
+
+			status, output := tc.check.inspect()
+			require.Equal(t, tc.status, status, output)
+			require.Contains(t, output, tc.output)
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		check := &CheckTLS{TLS: "127.0.0.1:1", Timeout: time.Second}
+		check.init()
+		status, output := check.inspect()
+		require.Equal(t, api.HealthCritical, status)
+		require.Contains(t, output, "TLS connect 127.0.0.1:1")
+	})
+}
+
+func TestCheckTLS(t *testing.T) {
+	t.Parallel()
+	addr, caFile := mockTLSServer(t, 10)
+
+	notif := mock.NewNotify()
+	logger := testutil.Logger(t)
+	statusHandler := NewStatusHandler(notif, logger, 0, 0, 0)
+	cid := structs.NewCheckID("foo", nil)
+
+	check := &CheckTLS{
+		CheckID:            cid,
+		TLS:                addr,
+		CAFile:             caFile,
+		ExpiryWarningDays:  30,
+		ExpiryCriticalDays: 7,
+		Interval:           10 * time.Millisecond,
+		Logger:             logger,
+		StatusHandler:      statusHandler,
+	}
+	check.Start()
+	defer check.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notif.State(cid), api.HealthWarning; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+	})
+}
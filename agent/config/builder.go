@@ -405,6 +405,27 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		}
 	}
 
+	var dnssec RuntimeDNSSECConfig
+	if c.DNS.DNSSEC != nil {
+		dnssec.KeySigningKeys = c.DNS.DNSSEC.KeySigningKeys
+		dnssec.ZoneSigningKeys = c.DNS.DNSSEC.ZoneSigningKeys
+		dnssec.SignatureValidity = b.durationValWithDefault("dns_config.dnssec.signature_validity", c.DNS.DNSSEC.SignatureValidity, 7*24*time.Hour)
+	}
+
+	var zoneTransfer RuntimeDNSZoneTransferConfig
+	if c.DNS.ZoneTransfer != nil {
+		zoneTransfer.AllowFrom = b.cidrsVal("dns_config.zone_transfer.allow_from", c.DNS.ZoneTransfer.AllowFrom)
+		zoneTransfer.Secondaries = c.DNS.ZoneTransfer.Secondaries
+		for _, k := range c.DNS.ZoneTransfer.TSIGKeys {
+			zoneTransfer.TSIGKeys = append(zoneTransfer.TSIGKeys, RuntimeDNSTSIGKey{
+				Name:      dnsFQDN(strings.ToLower(stringVal(k.Name))),
+				Algorithm: dnsFQDN(strings.ToLower(stringValWithDefault(k.Algorithm, "hmac-sha256"))),
+				Secret:    stringVal(k.Secret),
+				Token:     stringVal(k.Token),
+			})
+		}
+	}
+
 	leaveOnTerm := !boolVal(c.ServerMode)
 	if c.LeaveOnTerm != nil {
 		leaveOnTerm = boolVal(c.LeaveOnTerm)
@@ -441,6 +462,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 
 	// determine port values and replace values <= 0 and > 65535 with -1
 	dnsPort := b.portVal("ports.dns", c.Ports.DNS)
+	dnsTLSPort := b.portVal("ports.dns_tls", c.Ports.DNSTLS)
 	httpPort := b.portVal("ports.http", c.Ports.HTTP)
 	httpsPort := b.portVal("ports.https", c.Ports.HTTPS)
 	serverPort := b.portVal("ports.server", c.Ports.Server)
@@ -585,6 +607,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		b.warn("client_addr is empty, client services (DNS, HTTP, HTTPS, GRPC) will not be listening for connections")
 	}
 	dnsAddrs := b.makeAddrs(b.expandAddrs("addresses.dns", c.Addresses.DNS), clientAddrs, dnsPort)
+	dnsTLSAddrs := b.makeAddrs(b.expandAddrs("addresses.dns_tls", c.Addresses.DNSTLS), clientAddrs, dnsTLSPort)
 	httpAddrs := b.makeAddrs(b.expandAddrs("addresses.http", c.Addresses.HTTP), clientAddrs, httpPort)
 	httpsAddrs := b.makeAddrs(b.expandAddrs("addresses.https", c.Addresses.HTTPS), clientAddrs, httpsPort)
 	grpcAddrs := b.makeAddrs(b.expandAddrs("addresses.grpc", c.Addresses.GRPC), clientAddrs, grpcPort)
@@ -901,7 +924,11 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		DNSDisableCompression: boolVal(c.DNS.DisableCompression),
 		DNSDomain:             stringVal(c.DNSDomain),
 		DNSAltDomain:          altDomain,
+		DNSAnswerOrdering:     b.dnsAnswerOrderingVal(stringVal(c.DNS.AnswerOrdering)),
+		DNSAnswerPreferNear:   boolVal(c.DNS.AnswerPreferNear),
+		DNSEnableDoH:          boolVal(c.DNS.EnableDoH),
 		DNSEnableTruncate:     boolVal(c.DNS.EnableTruncate),
+		DNSKVPrefix:           stringVal(c.DNS.KVPrefix),
 		DNSMaxStale:           b.durationVal("dns_config.max_stale", c.DNS.MaxStale),
 		DNSNodeTTL:            b.durationVal("dns_config.node_ttl", c.DNS.NodeTTL),
 		DNSOnlyPassing:        boolVal(c.DNS.OnlyPassing),
@@ -911,6 +938,10 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		DNSRecursors:          dnsRecursors,
 		DNSServiceTTL:         dnsServiceTTL,
 		DNSSOA:                soa,
+		DNSSEC:                dnssec,
+		DNSZoneTransfer:       zoneTransfer,
+		DNSTLSAddrs:           dnsTLSAddrs,
+		DNSTLSPort:            dnsTLSPort,
 		DNSUDPAnswerLimit:     intVal(c.DNS.UDPAnswerLimit),
 		DNSNodeMetaTXT:        boolValWithDefault(c.DNS.NodeMetaTXT, true),
 		DNSUseCache:           boolVal(c.DNS.UseCache),
@@ -998,6 +1029,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		Datacenter:                             datacenter,
 		DefaultQueryTime:                       b.durationVal("default_query_time", c.DefaultQueryTime),
 		DevMode:                                boolVal(b.opts.DevMode),
+		DevResourceStorage:                     stringVal(c.DevResourceStorage),
 		DisableAnonymousSignature:              boolVal(c.DisableAnonymousSignature),
 		DisableCoordinates:                     boolVal(c.DisableCoordinates),
 		DisableHostNodeID:                      boolVal(c.DisableHostNodeID),
@@ -1020,6 +1052,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		GRPCTLSPort:                grpcTlsPort,
 		HTTPMaxConnsPerClient:      intVal(c.Limits.HTTPMaxConnsPerClient),
 		HTTPSHandshakeTimeout:      b.durationVal("limits.https_handshake_timeout", c.Limits.HTTPSHandshakeTimeout),
+		KVHistoryMaxAge:            b.durationVal("kv_history_max_age", c.KVHistoryMaxAge),
+		KVHistoryMaxRevisions:      intVal(c.KVHistoryMaxRevisions),
 		KVMaxValueSize:             uint64Val(c.Limits.KVMaxValueSize),
 		LeaveDrainTime:             b.durationVal("performance.leave_drain_time", c.Performance.LeaveDrainTime),
 		LeaveOnTerm:                leaveOnTerm,
@@ -1064,6 +1098,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		RaftSnapshotInterval:              b.durationVal("raft_snapshot_interval", c.RaftSnapshotInterval),
 		RaftTrailingLogs:                  intVal(c.RaftTrailingLogs),
 		RaftLogStoreConfig:                b.raftLogStoreConfigVal(&c.RaftLogStore),
+		SnapshotSchedule:                  b.snapshotScheduleVal(c.SnapshotSchedule),
 		ReconnectTimeoutLAN:               b.durationVal("reconnect_timeout", c.ReconnectTimeoutLAN),
 		ReconnectTimeoutWAN:               b.durationVal("reconnect_timeout_wan", c.ReconnectTimeoutWAN),
 		RejoinAfterLeave:                  boolVal(c.RejoinAfterLeave),
@@ -1095,6 +1130,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		SkipLeaveOnInt:                    skipLeaveOnInt,
 		TaggedAddresses:                   c.TaggedAddresses,
 		TranslateWANAddrs:                 boolVal(c.TranslateWANAddrs),
+		TxnMaxOps:                         intVal(c.Limits.TxnMaxOps),
 		TxnMaxReqLen:                      uint64Val(c.Limits.TxnMaxReqLen),
 		UIConfig:                          b.uiConfigVal(c.UIConfig),
 		UnixSocketGroup:                   stringVal(c.UnixSocket.Group),
@@ -1300,6 +1336,40 @@ func (b *builder) validate(rt RuntimeConfig) error {
 			return fmt.Errorf("DNS address cannot be a unix socket")
 		}
 	}
+	for _, a := range rt.DNSTLSAddrs {
+		if _, ok := a.(*net.UnixAddr); ok {
+			return fmt.Errorf("DNS TLS address cannot be a unix socket")
+		}
+	}
+	if rt.DNSEnableDoH && len(rt.HTTPSAddrs) == 0 {
+		return fmt.Errorf("dns_config.enable_doh requires the HTTPS endpoint to be enabled")
+	}
+	// DNS over TLS and HTTPS use the HTTPS certificate, or the automatically
+	// provisioned one.
+	httpsCert := rt.TLS.HTTPS.CertFile != "" && rt.TLS.HTTPS.KeyFile != ""
+	if len(rt.DNSTLSAddrs) > 0 && !httpsCert && !rt.TLS.AutoTLS {
+		return fmt.Errorf("ports.dns_tls requires tls.https.cert_file and tls.https.key_file to be set")
+	}
+	if rt.DNSEnableDoH && !httpsCert && !rt.TLS.AutoTLS {
+		return fmt.Errorf("dns_config.enable_doh requires tls.https.cert_file and tls.https.key_file to be set")
+	}
+	if rt.DNSAnswerPreferNear && rt.DNSAnswerOrdering != dns.AnswerOrderingWeighted {
+		return fmt.Errorf("dns_config.answer_prefer_near requires dns_config.answer_ordering to be %q", dns.AnswerOrderingWeighted)
+	}
+	if (len(rt.DNSSEC.KeySigningKeys) == 0) != (len(rt.DNSSEC.ZoneSigningKeys) == 0) {
+		return fmt.Errorf("dns_config.dnssec requires both key_signing_keys and zone_signing_keys")
+	}
+	if len(rt.DNSSEC.ZoneSigningKeys) > 0 && rt.DNSSEC.SignatureValidity <= 0 {
+		return fmt.Errorf("dns_config.dnssec.signature_validity cannot be %s. Must be positive", rt.DNSSEC.SignatureValidity)
+	}
+	for _, a := range rt.DNSZoneTransfer.Secondaries {
+		if ipaddr.IsAny(a) {
+			return fmt.Errorf("DNS zone transfer secondary address cannot be 0.0.0.0, :: or [::]")
+		}
+	}
+	if err := validateDNSTSIGKeys(rt.DNSZoneTransfer); err != nil {
+		return err
+	}
 	for _, a := range rt.DNSRecursors {
 		if ipaddr.IsAny(a) {
 			return fmt.Errorf("DNS recursor address cannot be 0.0.0.0, :: or [::]")
@@ -1348,6 +1418,27 @@ func (b *builder) validate(rt RuntimeConfig) error {
 	if rt.DNSARecordLimit < 0 {
 		return fmt.Errorf("dns_config.a_record_limit cannot be %d. Must be greater than or equal to zero", rt.DNSARecordLimit)
 	}
+	if rt.TxnMaxOps <= 0 {
+		return fmt.Errorf("limits.txn_max_ops cannot be %d. Must be greater than zero", rt.TxnMaxOps)
+	}
+	switch rt.DevResourceStorage {
+	case "", "raft":
+	case "bolt":
+		if !rt.DevMode {
+			return fmt.Errorf("dev_resource_storage = \"bolt\" is only supported in dev mode")
+		}
+		if rt.DataDir == "" {
+			return fmt.Errorf("dev_resource_storage = \"bolt\" requires data_dir to be set")
+		}
+	default:
+		return fmt.Errorf("dev_resource_storage must be \"raft\" or \"bolt\", got %q", rt.DevResourceStorage)
+	}
+	if rt.KVHistoryMaxRevisions < 0 {
+		return fmt.Errorf("kv_history_max_revisions cannot be %d. Must be greater than or equal to zero", rt.KVHistoryMaxRevisions)
+	}
+	if rt.KVHistoryMaxAge < 0 {
+		return fmt.Errorf("kv_history_max_age cannot be %s. Must be greater than or equal to zero", rt.KVHistoryMaxAge)
+	}
 	if err := structs.ValidateNodeMetadata(rt.NodeMeta, false); err != nil {
 		return fmt.Errorf("node_meta invalid: %v", err)
 	}
@@ -1368,6 +1459,26 @@ func (b *builder) validate(rt RuntimeConfig) error {
 			return fmt.Errorf("'retry_join_wan' is incompatible with 'connect.enable_mesh_gateway_wan_federation = true'")
 		}
 	}
+	if rt.SnapshotSchedule.Enabled {
+		if !rt.ServerMode {
+			return fmt.Errorf("'snapshot_schedule.enabled = true' requires 'server = true'")
+		}
+		if rt.SnapshotSchedule.LocalPath == "" {
+			return fmt.Errorf("'snapshot_schedule.local_path' is required when the snapshot agent is enabled")
+		}
+		if rt.SnapshotSchedule.Interval < time.Minute {
+			return fmt.Errorf("'snapshot_schedule.interval' must be at least 1m")
+		}
+		if rt.SnapshotSchedule.Retain < 0 {
+			return fmt.Errorf("'snapshot_schedule.retain' cannot be negative")
+		}
+		if rt.SnapshotSchedule.RetainAge < 0 {
+			return fmt.Errorf("'snapshot_schedule.retain_age' cannot be negative")
+		}
+		if rt.SnapshotSchedule.FilePrefix == "" || strings.ContainsAny(rt.SnapshotSchedule.FilePrefix, `/\`) {
+			return fmt.Errorf("'snapshot_schedule.file_prefix' must be a non-empty file name")
+		}
+	}
 	if len(rt.PrimaryGateways) > 0 {
 		if !rt.ServerMode {
 			return fmt.Errorf("'primary_gateways' requires 'server = true'")
@@ -1414,6 +1525,9 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		// we leave this for consistency
 		return err
 	}
+	if err := addrsUnique(inuse, "DNS TLS", rt.DNSTLSAddrs); err != nil {
+		return err
+	}
 	if err := addrsUnique(inuse, "HTTP", rt.HTTPAddrs); err != nil {
 		return err
 	}
@@ -1609,6 +1723,7 @@ func (b *builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 		Method:                         stringVal(v.Method),
 		Body:                           stringVal(v.Body),
 		DisableRedirects:               boolVal(v.DisableRedirects),
+		HTTPAssertions:                 httpAssertionsVal(v.HTTPAssertions),
 		TCP:                            stringVal(v.TCP),
 		UDP:                            stringVal(v.UDP),
 		Interval:                       b.durationVal(fmt.Sprintf("check[%s].interval", id), v.Interval),
@@ -1620,20 +1735,72 @@ func (b *builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 		TLSSkipVerify:                  boolVal(v.TLSSkipVerify),
 		AliasNode:                      stringVal(v.AliasNode),
 		AliasService:                   stringVal(v.AliasService),
+		CompositeChecks:                compositeChecksVal(v.CompositeChecks),
+		CompositeMode:                  stringVal(v.CompositeMode),
+		CompositeCriticalThreshold:     intVal(v.CompositeCriticalThreshold),
+		CompositeWarningThreshold:      intVal(v.CompositeWarningThreshold),
 		Timeout:                        b.durationVal(fmt.Sprintf("check[%s].timeout", id), v.Timeout),
 		TTL:                            b.durationVal(fmt.Sprintf("check[%s].ttl", id), v.TTL),
 		SuccessBeforePassing:           intVal(v.SuccessBeforePassing),
 		FailuresBeforeCritical:         intVal(v.FailuresBeforeCritical),
 		FailuresBeforeWarning:          intValWithDefault(v.FailuresBeforeWarning, intVal(v.FailuresBeforeCritical)),
+		FlapDetection:                  boolVal(v.FlapDetection),
+		FlapLowThreshold:               intVal(v.FlapLowThreshold),
+		FlapHighThreshold:              intVal(v.FlapHighThreshold),
 		H2PING:                         stringVal(v.H2PING),
 		H2PingUseTLS:                   H2PingUseTLSVal,
 		OSService:                      stringVal(v.OSService),
+		DNS:                            stringVal(v.DNS),
+		DNSQueryName:                   stringVal(v.DNSQueryName),
+		DNSQueryType:                   stringVal(v.DNSQueryType),
+		DNSProtocol:                    stringVal(v.DNSProtocol),
+		DNSExpectedAnswer:              v.DNSExpectedAnswer,
+		DNSExpectedRcode:               stringVal(v.DNSExpectedRcode),
+		TLS:                            stringVal(v.TLS),
+		TLSCAFile:                      stringVal(v.TLSCAFile),
+		TLSExpiryWarningDays:           intVal(v.TLSExpiryWarningDays),
+		TLSExpiryCriticalDays:          intVal(v.TLSExpiryCriticalDays),
 		DeregisterCriticalServiceAfter: b.durationVal(fmt.Sprintf("check[%s].deregister_critical_service_after", id), v.DeregisterCriticalServiceAfter),
 		OutputMaxSize:                  intValWithDefault(v.OutputMaxSize, checks.DefaultBufSize),
 		EnterpriseMeta:                 v.EnterpriseMeta.ToStructs(),
 	}
 }
 
+func httpAssertionsVal(v []HTTPAssertion) []structs.HTTPAssertion {
+	if len(v) == 0 {
+		return nil
+	}
+
+	assertions := make([]structs.HTTPAssertion, 0, len(v))
+	for _, a := range v {
+		assertions = append(assertions, structs.HTTPAssertion{
+			StatusRange:  stringVal(a.StatusRange),
+			BodyContains: stringVal(a.BodyContains),
+			BodyRegex:    stringVal(a.BodyRegex),
+			JSONPath:     stringVal(a.JSONPath),
+			JSONValue:    stringVal(a.JSONValue),
+			Negate:       boolVal(a.Negate),
+			Status:       stringVal(a.Status),
+		})
+	}
+	return assertions
+}
+
+func compositeChecksVal(v []CompositeCheckRef) []structs.CompositeCheckRef {
+	if len(v) == 0 {
+		return nil
+	}
+
+	refs := make([]structs.CompositeCheckRef, 0, len(v))
+	for _, ref := range v {
+		refs = append(refs, structs.CompositeCheckRef{
+			Node:    stringVal(ref.Node),
+			CheckID: types.CheckID(stringVal(ref.CheckID)),
+		})
+	}
+	return refs
+}
+
 func (b *builder) svcTaggedAddresses(v map[string]ServiceAddress) map[string]structs.ServiceAddress {
 	if len(v) <= 0 {
 		return nil
@@ -1810,6 +1977,20 @@ func (b *builder) dnsRecursorStrategyVal(v string) dns.RecursorStrategy {
 	return out
 }
 
+func (b *builder) dnsAnswerOrderingVal(v string) dns.AnswerOrdering {
+	var out dns.AnswerOrdering
+
+	switch dns.AnswerOrdering(v) {
+	case dns.AnswerOrderingWeighted:
+		out = dns.AnswerOrderingWeighted
+	case dns.AnswerOrderingRandom, "":
+		out = dns.AnswerOrderingRandom
+	default:
+		b.err = multierror.Append(b.err, fmt.Errorf("dns_config.answer_ordering: invalid ordering: %q", v))
+	}
+	return out
+}
+
 func (b *builder) requestsLimitsModeVal(v string) consulrate.Mode {
 	var out consulrate.Mode
 
@@ -2574,6 +2755,53 @@ func isValidAltDomain(domain, datacenter string) bool {
 	return !reAltDomain.MatchString(domain)
 }
 
+// dnsFQDN returns a DNS name with the trailing dot of the root.
+func dnsFQDN(name string) string {
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// dnsTSIGAlgorithms are the HMAC algorithms of the TSIG keys.
+var dnsTSIGAlgorithms = map[string]bool{
+	"hmac-sha1.":   true,
+	"hmac-sha224.": true,
+	"hmac-sha256.": true,
+	"hmac-sha384.": true,
+	"hmac-sha512.": true,
+}
+
+// validateDNSTSIGKeys checks the TSIG keys of the zone transfers, which are
+// required to allow any client.
+func validateDNSTSIGKeys(zt RuntimeDNSZoneTransferConfig) error {
+	if len(zt.TSIGKeys) == 0 {
+		if len(zt.AllowFrom) > 0 || len(zt.Secondaries) > 0 {
+			return fmt.Errorf("dns_config.zone_transfer requires tsig_keys to allow zone transfers")
+		}
+		return nil
+	}
+
+	names := make(map[string]bool, len(zt.TSIGKeys))
+	for i, k := range zt.TSIGKeys {
+		switch {
+		case k.Name == "":
+			return fmt.Errorf("dns_config.zone_transfer.tsig_keys[%d].name cannot be empty", i)
+		case names[k.Name]:
+			return fmt.Errorf("dns_config.zone_transfer.tsig_keys[%d].name %q is not unique", i, k.Name)
+		case !dnsTSIGAlgorithms[k.Algorithm]:
+			return fmt.Errorf("dns_config.zone_transfer.tsig_keys[%d].algorithm %q is not supported", i, k.Algorithm)
+		case k.Token == "":
+			return fmt.Errorf("dns_config.zone_transfer.tsig_keys[%d].token cannot be empty", i)
+		}
+		if secret, err := base64.StdEncoding.DecodeString(k.Secret); err != nil || len(secret) == 0 {
+			return fmt.Errorf("dns_config.zone_transfer.tsig_keys[%d].secret must be a base64 encoded key", i)
+		}
+		names[k.Name] = true
+	}
+	return nil
+}
+
 // UIPathBuilder checks to see if there was a path set
 // If so, adds beginning and trailing slashes to UI path
 func UIPathBuilder(UIContentString string) string {
@@ -2742,6 +2970,17 @@ func (b *builder) parsePrefixFilter(telemetry *Telemetry) ([]string, []string) {
 	return telemetryAllowedPrefixes, telemetryBlockedPrefixes
 }
 
+func (b *builder) snapshotScheduleVal(raw SnapshotScheduleRaw) consul.SnapshotAgentConfig {
+	return consul.SnapshotAgentConfig{
+		Enabled:    boolVal(raw.Enabled),
+		Interval:   b.durationVal("snapshot_schedule.interval", raw.Interval),
+		Retain:     intVal(raw.Retain),
+		RetainAge:  b.durationVal("snapshot_schedule.retain_age", raw.RetainAge),
+		FilePrefix: stringVal(raw.FilePrefix),
+		LocalPath:  stringVal(raw.LocalPath),
+	}
+}
+
 func (b *builder) raftLogStoreConfigVal(raw *RaftLogStoreRaw) consul.RaftLogStoreConfig {
 	var cfg consul.RaftLogStoreConfig
 	if raw != nil {
@@ -165,6 +165,7 @@ type Config struct {
 	DataDir                          *string             `mapstructure:"data_dir" json:"data_dir,omitempty"`
 	Datacenter                       *string             `mapstructure:"datacenter" json:"datacenter,omitempty"`
 	DefaultQueryTime                 *string             `mapstructure:"default_query_time" json:"default_query_time,omitempty"`
+	DevResourceStorage               *string             `mapstructure:"dev_resource_storage" json:"dev_resource_storage,omitempty"`
 	DisableAnonymousSignature        *bool               `mapstructure:"disable_anonymous_signature" json:"disable_anonymous_signature,omitempty"`
 	DisableCoordinates               *bool               `mapstructure:"disable_coordinates" json:"disable_coordinates,omitempty"`
 	DisableHostNodeID                *bool               `mapstructure:"disable_host_node_id" json:"disable_host_node_id,omitempty"`
@@ -186,6 +187,8 @@ type Config struct {
 	GossipLAN                        GossipLANConfig     `mapstructure:"gossip_lan" json:"-"`
 	GossipWAN                        GossipWANConfig     `mapstructure:"gossip_wan" json:"-"`
 	HTTPConfig                       HTTPConfig          `mapstructure:"http_config" json:"-"`
+	KVHistoryMaxAge                  *string             `mapstructure:"kv_history_max_age" json:"kv_history_max_age,omitempty"`
+	KVHistoryMaxRevisions            *int                `mapstructure:"kv_history_max_revisions" json:"kv_history_max_revisions,omitempty"`
 	LeaveOnTerm                      *bool               `mapstructure:"leave_on_terminate" json:"leave_on_terminate,omitempty"`
 	LicensePath                      *string             `mapstructure:"license_path" json:"license_path,omitempty"`
 	Limits                           Limits              `mapstructure:"limits" json:"-"`
@@ -254,6 +257,10 @@ type Config struct {
 
 	RaftLogStore RaftLogStoreRaw `mapstructure:"raft_logstore" json:"raft_logstore,omitempty"`
 
+	// SnapshotSchedule configures the server's built-in snapshot agent. Note
+	// that snapshot_agent is reserved for the standalone snapshot agent below.
+	SnapshotSchedule SnapshotScheduleRaw `mapstructure:"snapshot_schedule" json:"snapshot_schedule,omitempty"`
+
 	// UseStreamingBackend instead of blocking queries for service health and
 	// any other endpoints which support streaming.
 	UseStreamingBackend *bool `mapstructure:"use_streaming_backend" json:"-"`
@@ -347,6 +354,7 @@ type Consul struct {
 
 type Addresses struct {
 	DNS     *string `mapstructure:"dns"`
+	DNSTLS  *string `mapstructure:"dns_tls"`
 	HTTP    *string `mapstructure:"http"`
 	HTTPS   *string `mapstructure:"https"`
 	GRPC    *string `mapstructure:"grpc"`
@@ -419,6 +427,7 @@ type CheckDefinition struct {
 	Method                         *string             `mapstructure:"method"`
 	Body                           *string             `mapstructure:"body"`
 	DisableRedirects               *bool               `mapstructure:"disable_redirects"`
+	HTTPAssertions                 []HTTPAssertion     `mapstructure:"http_assertions"`
 	OutputMaxSize                  *int                `mapstructure:"output_max_size"`
 	TCP                            *string             `mapstructure:"tcp"`
 	UDP                            *string             `mapstructure:"udp"`
@@ -431,19 +440,54 @@ type CheckDefinition struct {
 	TLSSkipVerify                  *bool               `mapstructure:"tls_skip_verify" alias:"tlsskipverify"`
 	AliasNode                      *string             `mapstructure:"alias_node"`
 	AliasService                   *string             `mapstructure:"alias_service"`
+	CompositeChecks                []CompositeCheckRef `mapstructure:"composite_checks"`
+	CompositeMode                  *string             `mapstructure:"composite_mode"`
+	CompositeCriticalThreshold     *int                `mapstructure:"composite_critical_threshold"`
+	CompositeWarningThreshold      *int                `mapstructure:"composite_warning_threshold"`
 	Timeout                        *string             `mapstructure:"timeout"`
 	TTL                            *string             `mapstructure:"ttl"`
 	H2PING                         *string             `mapstructure:"h2ping"`
 	H2PingUseTLS                   *bool               `mapstructure:"h2ping_use_tls"`
 	OSService                      *string             `mapstructure:"os_service"`
+	DNS                            *string             `mapstructure:"dns"`
+	DNSQueryName                   *string             `mapstructure:"dns_query_name"`
+	DNSQueryType                   *string             `mapstructure:"dns_query_type"`
+	DNSProtocol                    *string             `mapstructure:"dns_protocol"`
+	DNSExpectedAnswer              []string            `mapstructure:"dns_expected_answer"`
+	DNSExpectedRcode               *string             `mapstructure:"dns_expected_rcode"`
+	TLS                            *string             `mapstructure:"tls"`
+	TLSCAFile                      *string             `mapstructure:"tls_ca_file"`
+	TLSExpiryWarningDays           *int                `mapstructure:"tls_expiry_warning_days"`
+	TLSExpiryCriticalDays          *int                `mapstructure:"tls_expiry_critical_days"`
 	SuccessBeforePassing           *int                `mapstructure:"success_before_passing"`
 	FailuresBeforeWarning          *int                `mapstructure:"failures_before_warning"`
 	FailuresBeforeCritical         *int                `mapstructure:"failures_before_critical"`
+	FlapDetection                  *bool               `mapstructure:"flap_detection"`
+	FlapLowThreshold               *int                `mapstructure:"flap_low_threshold"`
+	FlapHighThreshold              *int                `mapstructure:"flap_high_threshold"`
 	DeregisterCriticalServiceAfter *string             `mapstructure:"deregister_critical_service_after" alias:"deregistercriticalserviceafter"`
 
 	EnterpriseMeta `mapstructure:",squash"`
 }
 
+// HTTPAssertion is a condition on the response of an HTTP check.
+type HTTPAssertion struct {
+	StatusRange  *string `mapstructure:"status_range"`
+	BodyContains *string `mapstructure:"body_contains"`
+	BodyRegex    *string `mapstructure:"body_regex"`
+	JSONPath     *string `mapstructure:"json_path"`
+	JSONValue    *string `mapstructure:"json_value"`
+	Negate       *bool   `mapstructure:"negate"`
+	Status       *string `mapstructure:"status"`
+}
+
+// CompositeCheckRef references a check whose status contributes to a
+// composite check.
+type CompositeCheckRef struct {
+	Node    *string `mapstructure:"node"`
+	CheckID *string `mapstructure:"check_id"`
+}
+
 // ServiceConnect is the connect block within a service registration
 type ServiceConnect struct {
 	// Native is true when this service can natively understand Connect.
@@ -640,11 +684,41 @@ type SOA struct {
 	Minttl  *uint32 `mapstructure:"min_ttl"`
 }
 
+// DNSSEC configures the online signing of the responses of the Consul DNS
+// domain.
+type DNSSEC struct {
+	KeySigningKeys    []string `mapstructure:"key_signing_keys"`
+	ZoneSigningKeys   []string `mapstructure:"zone_signing_keys"`
+	SignatureValidity *string  `mapstructure:"signature_validity"`
+}
+
+// DNSZoneTransfer configures the transfer of the Consul DNS domain to
+// secondary DNS servers. The clients sign their queries with one of the TSIG
+// keys.
+type DNSZoneTransfer struct {
+	AllowFrom   []string     `mapstructure:"allow_from"`
+	Secondaries []string     `mapstructure:"secondaries"`
+	TSIGKeys    []DNSTSIGKey `mapstructure:"tsig_keys"`
+}
+
+// DNSTSIGKey is a TSIG key allowed to transfer the zone, with the ACL token
+// the zone is read with.
+type DNSTSIGKey struct {
+	Name      *string `mapstructure:"name"`
+	Algorithm *string `mapstructure:"algorithm"`
+	Secret    *string `mapstructure:"secret"`
+	Token     *string `mapstructure:"token"`
+}
+
 type DNS struct {
 	AllowStale         *bool             `mapstructure:"allow_stale"`
 	ARecordLimit       *int              `mapstructure:"a_record_limit"`
 	DisableCompression *bool             `mapstructure:"disable_compression"`
+	AnswerOrdering     *string           `mapstructure:"answer_ordering"`
+	AnswerPreferNear   *bool             `mapstructure:"answer_prefer_near"`
+	EnableDoH          *bool             `mapstructure:"enable_doh"`
 	EnableTruncate     *bool             `mapstructure:"enable_truncate"`
+	KVPrefix           *string           `mapstructure:"kv_prefix"`
 	MaxStale           *string           `mapstructure:"max_stale"`
 	NodeTTL            *string           `mapstructure:"node_ttl"`
 	OnlyPassing        *bool             `mapstructure:"only_passing"`
@@ -654,6 +728,8 @@ type DNS struct {
 	UDPAnswerLimit     *int              `mapstructure:"udp_answer_limit"`
 	NodeMetaTXT        *bool             `mapstructure:"enable_additional_node_meta_txt"`
 	SOA                *SOA              `mapstructure:"soa"`
+	DNSSEC             *DNSSEC           `mapstructure:"dnssec"`
+	ZoneTransfer       *DNSZoneTransfer  `mapstructure:"zone_transfer"`
 	UseCache           *bool             `mapstructure:"use_cache"`
 	CacheMaxAge        *string           `mapstructure:"cache_max_age"`
 
@@ -703,6 +779,7 @@ type Telemetry struct {
 
 type Ports struct {
 	DNS            *int `mapstructure:"dns" json:"dns,omitempty"`
+	DNSTLS         *int `mapstructure:"dns_tls" json:"dns_tls,omitempty"`
 	HTTP           *int `mapstructure:"http" json:"http,omitempty"`
 	HTTPS          *int `mapstructure:"https" json:"https,omitempty"`
 	SerfLAN        *int `mapstructure:"serf_lan" json:"serf_lan,omitempty"`
@@ -741,6 +818,7 @@ type Limits struct {
 	RPCRate               *float64      `mapstructure:"rpc_rate"`
 	KVMaxValueSize        *uint64       `mapstructure:"kv_max_value_size"`
 	TxnMaxReqLen          *uint64       `mapstructure:"txn_max_req_len"`
+	TxnMaxOps             *int          `mapstructure:"txn_max_ops"`
 }
 
 type Segment struct {
@@ -952,6 +1030,15 @@ type RaftLogStoreVerificationRaw struct {
 	Interval *string `mapstructure:"interval" json:"interval,omitempty"`
 }
 
+type SnapshotScheduleRaw struct {
+	Enabled    *bool   `mapstructure:"enabled" json:"enabled,omitempty"`
+	Interval   *string `mapstructure:"interval" json:"interval,omitempty"`
+	Retain     *int    `mapstructure:"retain" json:"retain,omitempty"`
+	RetainAge  *string `mapstructure:"retain_age" json:"retain_age,omitempty"`
+	FilePrefix *string `mapstructure:"file_prefix" json:"file_prefix,omitempty"`
+	LocalPath  *string `mapstructure:"local_path" json:"local_path,omitempty"`
+}
+
 type RaftBoltDBConfigRaw struct {
 	NoFreelistSync *bool `mapstructure:"no_freelist_sync" json:"no_freelist_sync,omitempty"`
 }
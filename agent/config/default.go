@@ -112,6 +112,7 @@ func DefaultSource() Source {
 			rpc_max_conns_per_client = 100
 			kv_max_value_size = ` + strconv.FormatInt(raft.SuggestedMaxDataSize, 10) + `
 			txn_max_req_len = ` + strconv.FormatInt(raft.SuggestedMaxDataSize, 10) + `
+			txn_max_ops = 128
 		}
 		performance = {
 			leave_drain_time = "5s"
@@ -149,6 +150,11 @@ func DefaultSource() Source {
 				segment_size_mb = 64
 			}
 		}
+		snapshot_schedule {
+			interval = "1h"
+			retain = 30
+			file_prefix = "consul"
+		}
 		xds {
 			update_max_per_second = 250
 		}
@@ -34,6 +34,61 @@ type RuntimeSOAConfig struct {
 	Minttl  uint32 // 0,
 }
 
+// RuntimeDNSSECConfig configures the online signing of the DNS responses.
+// Signing is disabled when no zone signing key is configured.
+type RuntimeDNSSECConfig struct {
+	// KeySigningKeys are the paths of the key signing keys, without the
+	// .key and .private extensions of the files. All of them are published
+	// and sign the DNSKEY record set.
+	KeySigningKeys []string
+
+	// ZoneSigningKeys are the paths of the zone signing keys, without the
+	// .key and .private extensions of the files. All of them are published
+	// but only the first one signs the responses, so that a new key can be
+	// published before it becomes active.
+	ZoneSigningKeys []string
+
+	// SignatureValidity is how long the signatures are valid for.
+	SignatureValidity time.Duration
+}
+
+// RuntimeDNSZoneTransferConfig configures the AXFR and IXFR transfers of the
+// DNS domain. Transfers are disabled when no TSIG key is configured. The
+// clients must sign their queries with one of the keys, and the zone holds
+// what the token of the key can read.
+type RuntimeDNSZoneTransferConfig struct {
+	// AllowFrom restricts the zone transfers to the clients in the given
+	// networks, in addition to the secondaries. The clients are not
+	// restricted by their address when neither AllowFrom nor Secondaries
+	// are set.
+	AllowFrom []*net.IPNet
+
+	// Secondaries are the addresses of the secondary DNS servers notified
+	// when the zone changes, they are allowed to transfer the zone. The
+	// hostnames are resolved when a client transfers the zone.
+	Secondaries []string
+
+	// TSIGKeys are the keys the zone transfer queries are signed with. The
+	// secrets are loaded when the DNS servers start.
+	TSIGKeys []RuntimeDNSTSIGKey
+}
+
+// RuntimeDNSTSIGKey is a TSIG key allowed to transfer the zone.
+type RuntimeDNSTSIGKey struct {
+	// Name is the name of the key, as a fully qualified domain name.
+	Name string
+
+	// Algorithm is the HMAC algorithm of the key, hmac-sha256 by default.
+	Algorithm string
+
+	// Secret is the base64 encoded secret of the key.
+	Secret string
+
+	// Token is the ACL token the zone is read with for the clients of the
+	// key, it needs node:read and service:read on what the zone holds.
+	Token string
+}
+
 // StaticRuntimeConfig specifies the subset of configuration the consul agent actually
 // uses and that are not reloadable by configuration auto reload.
 type StaticRuntimeConfig struct {
@@ -216,6 +271,34 @@ type RuntimeConfig struct {
 	// flag: -alt-domain string
 	DNSAltDomain string
 
+	// DNSAnswerOrdering controls the order of the answers of the service
+	// lookups. 'random' shuffles them. 'weighted' orders them randomly
+	// proportionally to the passing or warning weights of the service
+	// instances, so that clients using the first answer honor the weights.
+	//
+	// hcl: dns_config { answer_ordering = "(random|weighted)" }
+	DNSAnswerOrdering dns.AnswerOrdering
+
+	// DNSAnswerPreferNear biases the weighted ordering of the answers towards
+	// the instances with the lowest estimated round trip time from the agent,
+	// computed from the network coordinates of the nodes.
+	//
+	// hcl: dns_config { answer_prefer_near = (true|false) }
+	DNSAnswerPreferNear bool
+
+	// DNSKVPrefix enables the TXT lookups of the KV entries under the
+	// "kv" label, for the keys under this prefix: "flags" serves the keys
+	// under "flags/". The lookups are disabled when it is empty.
+	//
+	// hcl: dns_config { kv_prefix = string }
+	DNSKVPrefix string
+
+	// DNSEnableDoH enables serving DNS over HTTPS (RFC 8484) queries on the
+	// /dns-query path of the HTTPS endpoint.
+	//
+	// hcl: dns_config { enable_doh = (true|false) }
+	DNSEnableDoH bool
+
 	// DNSEnableTruncate is used to enable setting the truncate
 	// flag for UDP DNS queries.  This allows unmodified
 	// clients to re-query the consul server using TCP
@@ -559,6 +642,40 @@ type RuntimeConfig struct {
 	// hcl: soa {}
 	DNSSOA RuntimeSOAConfig
 
+	// DNSSEC configures the online signing of the responses of the DNS
+	// domains with ECDSA P-256 keys.
+	//
+	// hcl: dns_config { dnssec { ... } }
+	DNSSEC RuntimeDNSSECConfig
+
+	// DNSZoneTransfer configures the transfer of the DNS domain to secondary
+	// DNS servers. The clients sign their queries with a TSIG key, and the
+	// zone contains the nodes and the healthy services readable with the
+	// token of the key.
+	//
+	// hcl: dns_config { zone_transfer { ... } }
+	DNSZoneTransfer RuntimeDNSZoneTransferConfig
+
+	// DNSTLSAddrs contains the list of TCP addresses the DNS over TLS server
+	// will bind to. If the endpoint is disabled (ports.dns_tls <= 0) the list
+	// is empty.
+	//
+	// The ip addresses are taken from 'addresses.dns_tls' which should
+	// contain a space separated list of ip addresses and/or go-sockaddr
+	// templates.
+	//
+	// If 'addresses.dns_tls' was not provided the 'client_addr' addresses
+	// are used.
+	//
+	// hcl: client_addr = string addresses { dns_tls = string } ports { dns_tls = int }
+	DNSTLSAddrs []net.Addr
+
+	// DNSTLSPort is the port the DNS over TLS server listens on, using the
+	// certificates of the HTTPS endpoint. It is disabled by default.
+	//
+	// hcl: ports { dns_tls = int }
+	DNSTLSPort int
+
 	// DataDir is the path to the directory where the local state is stored.
 	//
 	// hcl: data_dir = string
@@ -579,6 +696,14 @@ type RuntimeConfig struct {
 	// flag: -dev
 	DevMode bool
 
+	// DevResourceStorage selects the storage backend of the resource service
+	// in development mode. It is either "raft" (the default) or "bolt", which
+	// persists resources to a local database in the data directory so that
+	// they survive restarts.
+	//
+	// hcl: dev_resource_storage = string
+	DevResourceStorage string
+
 	// DisableAnonymousSignature is used to turn off the anonymous signature
 	// send with the update check. This is used to deduplicate messages.
 	//
@@ -781,6 +906,19 @@ type RuntimeConfig struct {
 	// flags: -https-port int
 	HTTPSPort int
 
+	// KVHistoryMaxAge controls how long the revisions of the KV history are
+	// retained. Revisions are only bounded by their number when it is zero.
+	//
+	// hcl: kv_history_max_age = "duration"
+	KVHistoryMaxAge time.Duration
+
+	// KVHistoryMaxRevisions is the number of revisions kept in the history
+	// of each KV entry. The history is disabled when it is zero. The value
+	// of the leader applies to every server.
+	//
+	// hcl: kv_history_max_revisions = int
+	KVHistoryMaxRevisions int
+
 	// KVMaxValueSize controls the max allowed value size. If not set defaults
 	// to raft's suggested max value size.
 	//
@@ -1387,6 +1525,13 @@ type RuntimeConfig struct {
 	// hcl: skip_leave_on_interrupt = (true|false)
 	SkipLeaveOnInt bool
 
+	// SnapshotSchedule configures the snapshot agent, which periodically
+	// saves snapshots of the server state to a local directory while the
+	// server is the leader.
+	//
+	// hcl: snapshot_schedule { enabled = (true|false) interval = "duration" retain = int retain_age = "duration" file_prefix = string local_path = string }
+	SnapshotSchedule consul.SnapshotAgentConfig
+
 	// AutoReloadConfig indicate if the config will be
 	// auto reloaded bases on config file modification
 	// hcl: auto_reload_config = (true|false)
@@ -1415,6 +1560,13 @@ type RuntimeConfig struct {
 	// hcl: translate_wan_addrs = (true|false)
 	TranslateWANAddrs bool
 
+	// TxnMaxOps configures the upper limit for the number of operations in a
+	// transaction to the /txn endpoint. Transactions larger than the raft
+	// entry size are applied in chunks, and remain atomic.
+	//
+	// hcl: limits { txn_max_ops = int }
+	TxnMaxOps int
+
 	// TxnMaxReqLen configures the upper limit for the size (in bytes) of the
 	// incoming request bodies for transactions to the /txn endpoint.
 	//
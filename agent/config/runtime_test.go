@@ -2075,6 +2075,141 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl:         []string{`dns_config = { udp_answer_limit = -1 }`},
 		expectedErr: "dns_config.udp_answer_limit cannot be -1. Must be greater than or equal to zero",
 	})
+	run(t, testCase{
+		desc: "dns_config.enable_doh without https",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "enable_doh": true } }`},
+		hcl:         []string{`dns_config = { enable_doh = true }`},
+		expectedErr: "dns_config.enable_doh requires the HTTPS endpoint to be enabled",
+	})
+	run(t, testCase{
+		desc: "dns_config.zone_transfer without tsig_keys",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "zone_transfer": { "allow_from": ["10.0.0.0/8"] } } }`},
+		hcl:         []string{`dns_config = { zone_transfer = { allow_from = ["10.0.0.0/8"] } }`},
+		expectedErr: "dns_config.zone_transfer requires tsig_keys to allow zone transfers",
+	})
+	run(t, testCase{
+		desc: "dns_config.zone_transfer.tsig_keys without token",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "zone_transfer": { "tsig_keys": [{ "name": "transfer", "secret": "c2VjcmV0" }] } } }`},
+		hcl:         []string{`dns_config = { zone_transfer = { tsig_keys = [{ name = "transfer", secret = "c2VjcmV0" }] } }`},
+		expectedErr: "dns_config.zone_transfer.tsig_keys[0].token cannot be empty",
+	})
+	run(t, testCase{
+		desc: "dns_config.zone_transfer.tsig_keys invalid secret",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "zone_transfer": { "tsig_keys": [{ "name": "transfer", "secret": "not base64!", "token": "foo" }] } } }`},
+		hcl:         []string{`dns_config = { zone_transfer = { tsig_keys = [{ name = "transfer", secret = "not base64!", token = "foo" }] } }`},
+		expectedErr: "dns_config.zone_transfer.tsig_keys[0].secret must be a base64 encoded key",
+	})
+	run(t, testCase{
+		desc: "dns_config.zone_transfer.tsig_keys invalid algorithm",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "zone_transfer": { "tsig_keys": [{ "name": "transfer", "algorithm": "hmac-md5", "secret": "c2VjcmV0", "token": "foo" }] } } }`},
+		hcl:         []string{`dns_config = { zone_transfer = { tsig_keys = [{ name = "transfer", algorithm = "hmac-md5", secret = "c2VjcmV0", token = "foo" }] } }`},
+		expectedErr: `dns_config.zone_transfer.tsig_keys[0].algorithm "hmac-md5." is not supported`,
+	})
+	run(t, testCase{
+		desc: "dns_config.answer_ordering invalid",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "answer_ordering": "nearest" } }`},
+		hcl:         []string{`dns_config = { answer_ordering = "nearest" }`},
+		expectedErr: `dns_config.answer_ordering: invalid ordering: "nearest"`,
+	})
+	run(t, testCase{
+		desc: "dns_config.answer_prefer_near without weighted ordering",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "answer_prefer_near": true } }`},
+		hcl:         []string{`dns_config = { answer_prefer_near = true }`},
+		expectedErr: `dns_config.answer_prefer_near requires dns_config.answer_ordering to be "weighted"`,
+	})
+	run(t, testCase{
+		desc: "dns_config.dnssec without key signing keys",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "dnssec": { "zone_signing_keys": ["Kconsul.+013+16209"] } } }`},
+		hcl:         []string{`dns_config = { dnssec = { zone_signing_keys = ["Kconsul.+013+16209"] } }`},
+		expectedErr: "dns_config.dnssec requires both key_signing_keys and zone_signing_keys",
+	})
+	run(t, testCase{
+		desc: "dns_config.dnssec defaults",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "dns_config": { "dnssec": { "key_signing_keys": ["Kconsul.+013+39437"], "zone_signing_keys": ["Kconsul.+013+16209"] } } }`},
+		hcl:  []string{`dns_config = { dnssec = { key_signing_keys = ["Kconsul.+013+39437"] zone_signing_keys = ["Kconsul.+013+16209"] } }`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.DNSSEC = RuntimeDNSSECConfig{
+				KeySigningKeys:    []string{"Kconsul.+013+39437"},
+				ZoneSigningKeys:   []string{"Kconsul.+013+16209"},
+				SignatureValidity: 7 * 24 * time.Hour,
+			}
+		},
+	})
+	run(t, testCase{
+		desc: "dns_config.dnssec invalid signature validity",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "dnssec": { "key_signing_keys": ["Kconsul.+013+39437"], "zone_signing_keys": ["Kconsul.+013+16209"], "signature_validity": "-1h" } } }`},
+		hcl:         []string{`dns_config = { dnssec = { key_signing_keys = ["Kconsul.+013+39437"] zone_signing_keys = ["Kconsul.+013+16209"] signature_validity = "-1h" } }`},
+		expectedErr: "dns_config.dnssec.signature_validity cannot be -1h0m0s. Must be positive",
+	})
+	run(t, testCase{
+		desc: "dns tls address defaults to client addr",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{
+					"client_addr":"1.2.3.4",
+					"ports": { "dns_tls": 853 },
+					"tls": { "https": { "cert_file": "dns_cert_file", "key_file": "dns_key_file" } }
+				}`},
+		hcl: []string{`
+					client_addr = "1.2.3.4"
+					ports { dns_tls = 853 }
+					tls { https { cert_file = "dns_cert_file" key_file = "dns_key_file" } }
+				`},
+		expected: func(rt *RuntimeConfig) {
+			rt.TLS.HTTPS.CertFile = "dns_cert_file"
+			rt.TLS.HTTPS.KeyFile = "dns_key_file"
+			rt.ClientAddrs = []*net.IPAddr{ipAddr("1.2.3.4")}
+			rt.DNSAddrs = []net.Addr{tcpAddr("1.2.3.4:8600"), udpAddr("1.2.3.4:8600")}
+			rt.HTTPAddrs = []net.Addr{tcpAddr("1.2.3.4:8500")}
+			rt.DNSTLSPort = 853
+			rt.DNSTLSAddrs = []net.Addr{tcpAddr("1.2.3.4:853")}
+			rt.DataDir = dataDir
+		},
+	})
+	run(t, testCase{
+		desc:        "dns tls without https certificate",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "ports": { "dns_tls": 853 } }`},
+		hcl:         []string{`ports { dns_tls = 853 }`},
+		expectedErr: "ports.dns_tls requires tls.https.cert_file and tls.https.key_file to be set",
+	})
+	run(t, testCase{
+		desc:        "dns_config.enable_doh without https certificate",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "ports": { "https": 8501 }, "dns_config": { "enable_doh": true } }`},
+		hcl:         []string{`ports { https = 8501 } dns_config = { enable_doh = true }`},
+		expectedErr: "dns_config.enable_doh requires tls.https.cert_file and tls.https.key_file to be set",
+	})
 	run(t, testCase{
 		desc: "dns_config.a_record_limit invalid",
 		args: []string{
@@ -2084,6 +2219,33 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl:         []string{`dns_config = { a_record_limit = -1 }`},
 		expectedErr: "dns_config.a_record_limit cannot be -1. Must be greater than or equal to zero",
 	})
+	run(t, testCase{
+		desc: "dev_resource_storage invalid",
+		args: []string{
+			`-dev`,
+		},
+		json:        []string{`{ "dev_resource_storage": "etcd" }`},
+		hcl:         []string{`dev_resource_storage = "etcd"`},
+		expectedErr: `dev_resource_storage must be "raft" or "bolt", got "etcd"`,
+	})
+	run(t, testCase{
+		desc: "dev_resource_storage bolt outside of dev mode",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dev_resource_storage": "bolt" }`},
+		hcl:         []string{`dev_resource_storage = "bolt"`},
+		expectedErr: `dev_resource_storage = "bolt" is only supported in dev mode`,
+	})
+	run(t, testCase{
+		desc: "dev_resource_storage bolt without data dir",
+		args: []string{
+			`-dev`,
+		},
+		json:        []string{`{ "dev_resource_storage": "bolt" }`},
+		hcl:         []string{`dev_resource_storage = "bolt"`},
+		expectedErr: `dev_resource_storage = "bolt" requires data_dir to be set`,
+	})
 	run(t, testCase{
 		desc: "performance.raft_multiplier < 0",
 		args: []string{
@@ -2579,7 +2741,7 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl: []string{
 			`check = { name = "a", os_service = "foo" }`,
 		},
-		expectedErr: `Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP or OSService checks`,
+		expectedErr: `Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP, OSService, DNS or TLS checks`,
 	})
 	run(t, testCase{
 		desc: "os_service check",
@@ -2602,6 +2764,245 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			}
 			rt.DataDir = dataDir
 		}})
+	run(t, testCase{
+		desc: "dns check",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "dns": "127.0.0.1:8600", "dns_query_name": "web.service.consul", "dns_query_type": "SRV", "dns_protocol": "tcp", "dns_expected_answer": ["node1.node.dc1.consul."], "dns_expected_rcode": "NOERROR", "interval": "30s" } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", dns = "127.0.0.1:8600", dns_query_name = "web.service.consul", dns_query_type = "SRV", dns_protocol = "tcp", dns_expected_answer = ["node1.node.dc1.consul."], dns_expected_rcode = "NOERROR", interval = "30s" }`,
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.Checks = []*structs.CheckDefinition{
+				{Name: "a",
+					DNS:               "127.0.0.1:8600",
+					DNSQueryName:      "web.service.consul",
+					DNSQueryType:      "SRV",
+					DNSProtocol:       "tcp",
+					DNSExpectedAnswer: []string{"node1.node.dc1.consul."},
+					DNSExpectedRcode:  "NOERROR",
+					Interval:          30 * time.Second,
+					OutputMaxSize:     checks.DefaultBufSize,
+				},
+			}
+			rt.DataDir = dataDir
+		}})
+	run(t, testCase{
+		desc: "dns check invalid query type",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "dns": "127.0.0.1", "dns_query_name": "example.com", "dns_query_type": "BOGUS", "interval": "30s" } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", dns = "127.0.0.1", dns_query_name = "example.com", dns_query_type = "BOGUS", interval = "30s" }`,
+		},
+		expectedErr: `DNSQueryType "BOGUS" is not a valid DNS record type`,
+	})
+	run(t, testCase{
+		desc: "tls check",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "tls": "127.0.0.1:443", "tls_server_name": "web.example.com", "tls_ca_file": "/etc/ca.pem", "tls_expiry_warning_days": 20, "tls_expiry_critical_days": 5, "interval": "1h" } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", tls = "127.0.0.1:443", tls_server_name = "web.example.com", tls_ca_file = "/etc/ca.pem", tls_expiry_warning_days = 20, tls_expiry_critical_days = 5, interval = "1h" }`,
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.Checks = []*structs.CheckDefinition{
+				{Name: "a",
+					TLS:                   "127.0.0.1:443",
+					TLSServerName:         "web.example.com",
+					TLSCAFile:             "/etc/ca.pem",
+					TLSExpiryWarningDays:  20,
+					TLSExpiryCriticalDays: 5,
+					Interval:              time.Hour,
+					OutputMaxSize:         checks.DefaultBufSize,
+				},
+			}
+			rt.DataDir = dataDir
+		}})
+	run(t, testCase{
+		desc: "tls check invalid expiry thresholds",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "tls": "127.0.0.1:443", "tls_expiry_warning_days": 5, "tls_expiry_critical_days": 20, "interval": "1h" } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", tls = "127.0.0.1:443", tls_expiry_warning_days = 5, tls_expiry_critical_days = 20, interval = "1h" }`,
+		},
+		expectedErr: `TLSExpiryWarningDays (5) can't be lower than TLSExpiryCriticalDays (20)`,
+	})
+	run(t, testCase{
+		desc: "tls check critical threshold above default warning threshold",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "tls": "127.0.0.1:443", "tls_expiry_critical_days": 60, "interval": "1h" } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", tls = "127.0.0.1:443", tls_expiry_critical_days = 60, interval = "1h" }`,
+		},
+		expectedErr: `TLSExpiryWarningDays (30) can't be lower than TLSExpiryCriticalDays (60)`,
+	})
+	run(t, testCase{
+		desc: "http check with assertions",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "http": "http://localhost/health", "interval": "10s", "http_assertions": [ { "status_range": "200-299,404" }, { "json_path": "$.status", "json_value": "ok", "status": "warning" }, { "body_contains": "panic", "negate": true } ] } }`,
+		},
+		hcl: []string{
+			`check = {
+				name = "a"
+				http = "http://localhost/health"
+				interval = "10s"
+				http_assertions = [
+					{ status_range = "200-299,404" },
+					{ json_path = "$.status", json_value = "ok", status = "warning" },
+					{ body_contains = "panic", negate = true },
+				]
+			}`,
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.Checks = []*structs.CheckDefinition{
+				{Name: "a",
+					HTTP: "http://localhost/health",
+					HTTPAssertions: []structs.HTTPAssertion{
+						{StatusRange: "200-299,404"},
+						{JSONPath: "$.status", JSONValue: "ok", Status: "warning"},
+						{BodyContains: "panic", Negate: true},
+					},
+					Interval:      10 * time.Second,
+					OutputMaxSize: checks.DefaultBufSize,
+				},
+			}
+			rt.DataDir = dataDir
+		}})
+	run(t, testCase{
+		desc: "http check with invalid assertion",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "http": "http://localhost/health", "interval": "10s", "http_assertions": [ { "body_regex": "(" } ] } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", http = "http://localhost/health", interval = "10s", http_assertions = [ { body_regex = "(" } ] }`,
+		},
+		expectedErr: `HTTPAssertions[0]: invalid BodyRegex`,
+	})
+	run(t, testCase{
+		desc: "composite check",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "composite_checks": [ { "node": "db-1", "check_id": "db" }, { "node": "db-2", "check_id": "db" }, { "check_id": "mem" } ], "composite_mode": "threshold", "composite_critical_threshold": 2, "composite_warning_threshold": 1 } }`,
+		},
+		hcl: []string{
+			`check = {
+				name = "a"
+				composite_checks = [
+					{ node = "db-1", check_id = "db" },
+					{ node = "db-2", check_id = "db" },
+					{ check_id = "mem" },
+				]
+				composite_mode = "threshold"
+				composite_critical_threshold = 2
+				composite_warning_threshold = 1
+			}`,
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.Checks = []*structs.CheckDefinition{
+				{Name: "a",
+					CompositeChecks: []structs.CompositeCheckRef{
+						{Node: "db-1", CheckID: "db"},
+						{Node: "db-2", CheckID: "db"},
+						{CheckID: "mem"},
+					},
+					CompositeMode:              "threshold",
+					CompositeCriticalThreshold: 2,
+					CompositeWarningThreshold:  1,
+					OutputMaxSize:              checks.DefaultBufSize,
+				},
+			}
+			rt.DataDir = dataDir
+		}})
+	run(t, testCase{
+		desc: "composite check threshold out of range",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "composite_checks": [ { "check_id": "db" } ], "composite_mode": "threshold", "composite_critical_threshold": 2 } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", composite_checks = [ { check_id = "db" } ], composite_mode = "threshold", composite_critical_threshold = 2 }`,
+		},
+		expectedErr: `CompositeCriticalThreshold must be between 1 and the number of CompositeChecks`,
+	})
+	run(t, testCase{
+		desc: "composite check with interval",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "composite_checks": [ { "check_id": "db" } ], "ttl": "10s" } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", composite_checks = [ { check_id = "db" } ], ttl = "10s" }`,
+		},
+		expectedErr: `Composite checks can't be combined with another check type`,
+	})
+	run(t, testCase{
+		desc: "check with flap detection",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "tcp": "localhost:55555", "interval": "5s", "flap_detection": true, "flap_low_threshold": 10, "flap_high_threshold": 30 } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", tcp = "localhost:55555", interval = "5s", flap_detection = true, flap_low_threshold = 10, flap_high_threshold = 30 }`,
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.Checks = []*structs.CheckDefinition{
+				{
+					Name:              "a",
+					TCP:               "localhost:55555",
+					Interval:          5 * time.Second,
+					FlapDetection:     true,
+					FlapLowThreshold:  10,
+					FlapHighThreshold: 30,
+					OutputMaxSize:     checks.DefaultBufSize,
+				},
+			}
+			rt.DataDir = dataDir
+		}})
+	run(t, testCase{
+		desc: "check with flap detection thresholds inverted",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "tcp": "localhost:55555", "interval": "5s", "flap_detection": true, "flap_low_threshold": 30 } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", tcp = "localhost:55555", interval = "5s", flap_detection = true, flap_low_threshold = 30 }`,
+		},
+		expectedErr: `FlapLowThreshold must be lower than FlapHighThreshold`,
+	})
 	run(t, testCase{
 		desc: "multiple service files",
 		args: []string{
@@ -5964,6 +6365,104 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.EnableDebug = true
 		},
 	})
+	run(t, testCase{
+		desc: "snapshot_schedule defaults",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`
+			{
+				"server": true,
+				"snapshot_schedule": {
+					"enabled": true,
+					"local_path": "/snapshots"
+				}
+			}`},
+		hcl: []string{`
+			server = true
+			snapshot_schedule {
+				enabled = true
+				local_path = "/snapshots"
+			}`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.ServerMode = true
+			rt.LeaveOnTerm = false
+			rt.SkipLeaveOnInt = true
+			rt.TLS.ServerMode = true
+			rt.RPCConfig.EnableStreaming = true
+			rt.GRPCTLSPort = 8503
+			rt.GRPCTLSAddrs = []net.Addr{defaultGrpcTlsAddr}
+			rt.SnapshotSchedule = consul.SnapshotAgentConfig{
+				Enabled:    true,
+				Interval:   time.Hour,
+				Retain:     30,
+				FilePrefix: "consul",
+				LocalPath:  "/snapshots",
+			}
+		},
+	})
+	run(t, testCase{
+		desc: "snapshot_schedule requires server mode",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`
+			{
+				"snapshot_schedule": {
+					"enabled": true,
+					"local_path": "/snapshots"
+				}
+			}`},
+		hcl: []string{`
+			snapshot_schedule {
+				enabled = true
+				local_path = "/snapshots"
+			}`},
+		expectedErr: "'snapshot_schedule.enabled = true' requires 'server = true'",
+	})
+	run(t, testCase{
+		desc: "snapshot_schedule requires local_path",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`
+			{
+				"server": true,
+				"snapshot_schedule": {
+					"enabled": true
+				}
+			}`},
+		hcl: []string{`
+			server = true
+			snapshot_schedule {
+				enabled = true
+			}`},
+		expectedErr: "'snapshot_schedule.local_path' is required",
+	})
+	run(t, testCase{
+		desc: "snapshot_schedule interval lower bound",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`
+			{
+				"server": true,
+				"snapshot_schedule": {
+					"enabled": true,
+					"interval": "10s",
+					"local_path": "/snapshots"
+				}
+			}`},
+		hcl: []string{`
+			server = true
+			snapshot_schedule {
+				enabled = true
+				interval = "10s"
+				local_path = "/snapshots"
+			}`},
+		expectedErr: "'snapshot_schedule.interval' must be at least 1m",
+	})
 }
 
 func (tc testCase) run(format string, dataDir string) func(t *testing.T) {
@@ -6312,23 +6811,35 @@ func TestLoad_FullConfig(t *testing.T) {
 			AuthURL:      "332nCdR2",
 			ScadaAddress: "aoeusth232",
 		},
-		DNSAddrs:                         []net.Addr{tcpAddr("93.95.95.81:7001"), udpAddr("93.95.95.81:7001")},
-		DNSARecordLimit:                  29907,
-		DNSAllowStale:                    true,
-		DNSDisableCompression:            true,
-		DNSDomain:                        "7W1xXSqd",
-		DNSAltDomain:                     "1789hsd",
-		DNSEnableTruncate:                true,
-		DNSMaxStale:                      29685 * time.Second,
-		DNSNodeTTL:                       7084 * time.Second,
-		DNSOnlyPassing:                   true,
-		DNSPort:                          7001,
-		DNSRecursorStrategy:              "sequential",
-		DNSRecursorTimeout:               4427 * time.Second,
-		DNSRecursors:                     []string{"63.38.39.58", "92.49.18.18"},
-		DNSSOA:                           RuntimeSOAConfig{Refresh: 3600, Retry: 600, Expire: 86400, Minttl: 0},
+		DNSAddrs:              []net.Addr{tcpAddr("93.95.95.81:7001"), udpAddr("93.95.95.81:7001")},
+		DNSARecordLimit:       29907,
+		DNSAnswerOrdering:     "weighted",
+		DNSAnswerPreferNear:   true,
+		DNSAllowStale:         true,
+		DNSDisableCompression: true,
+		DNSDomain:             "7W1xXSqd",
+		DNSAltDomain:          "1789hsd",
+		DNSEnableDoH:          true,
+		DNSEnableTruncate:     true,
+		DNSKVPrefix:           "appliances/flags/",
+		DNSMaxStale:           29685 * time.Second,
+		DNSNodeTTL:            7084 * time.Second,
+		DNSOnlyPassing:        true,
+		DNSPort:               7001,
+		DNSTLSAddrs:           []net.Addr{tcpAddr("18.53.85.31:7853")},
+		DNSTLSPort:            7853,
+		DNSRecursorStrategy:   "sequential",
+		DNSRecursorTimeout:    4427 * time.Second,
+		DNSRecursors:          []string{"63.38.39.58", "92.49.18.18"},
+		DNSSOA:                RuntimeSOAConfig{Refresh: 3600, Retry: 600, Expire: 86400, Minttl: 0},
+		DNSSEC: RuntimeDNSSECConfig{
+			KeySigningKeys:    []string{"/etc/consul.d/Kconsul.+013+39437"},
+			ZoneSigningKeys:   []string{"/etc/consul.d/Kconsul.+013+16209", "/etc/consul.d/Kconsul.+013+51003"},
+			SignatureValidity: 51 * time.Hour,
+		},
 		DNSServiceTTL:                    map[string]time.Duration{"*": 32030 * time.Second},
 		DNSUDPAnswerLimit:                29909,
+		DNSZoneTransfer:                  RuntimeDNSZoneTransferConfig{AllowFrom: []*net.IPNet{cidr("10.42.0.0/16")}, Secondaries: []string{"10.42.7.3", "10.42.7.4:5353"}, TSIGKeys: []RuntimeDNSTSIGKey{{Name: "transfer.consul.", Algorithm: "hmac-sha512.", Secret: "ZZ6ccyl3dQpiYZ1zLLjP5A==", Token: "5ab3f0dc-e4e7-4e6e-9a1c-d2e3b5cd4e51"}}},
 		DNSNodeMetaTXT:                   true,
 		DNSUseCache:                      true,
 		DNSCacheMaxAge:                   5 * time.Minute,
@@ -6370,6 +6881,8 @@ func TestLoad_FullConfig(t *testing.T) {
 		HTTPSHandshakeTimeout: 2391 * time.Millisecond,
 		HTTPSPort:             15127,
 		HTTPUseCache:          false,
+		KVHistoryMaxAge:       3610 * time.Second,
+		KVHistoryMaxRevisions: 17,
 		KVMaxValueSize:        1234567800,
 		LeaveDrainTime:        8265 * time.Second,
 		LeaveOnTerm:           true,
@@ -6726,6 +7239,14 @@ func TestLoad_FullConfig(t *testing.T) {
 		SerfAllowedCIDRsWAN:  []net.IPNet{},
 		SessionTTLMin:        26627 * time.Second,
 		SkipLeaveOnInt:       true,
+		SnapshotSchedule: consul.SnapshotAgentConfig{
+			Enabled:    true,
+			Interval:   6437 * time.Second,
+			Retain:     57,
+			RetainAge:  31536 * time.Second,
+			FilePrefix: "aXtq9Fsm",
+			LocalPath:  "/snapshots/Ub7rvsJn",
+		},
 		Telemetry: lib.TelemetryConfig{
 			CirconusAPIApp:                     "p4QOTe9j",
 			CirconusAPIToken:                   "E3j35V23",
@@ -6802,6 +7323,7 @@ func TestLoad_FullConfig(t *testing.T) {
 			"wan_ipv4": "78.63.37.19",
 		},
 		TranslateWANAddrs: true,
+		TxnMaxOps:         1500,
 		TxnMaxReqLen:      567800000,
 		UIConfig: UIConfig{
 			Dir:                        "pVncV4Ey",
@@ -7101,6 +7623,11 @@ func TestRuntimeConfig_Sanitize(t *testing.T) {
 			&net.UDPAddr{IP: net.ParseIP("1.2.3.4"), Port: 5678},
 		},
 		DNSSOA: RuntimeSOAConfig{Refresh: 3600, Retry: 600, Expire: 86400, Minttl: 0},
+		DNSZoneTransfer: RuntimeDNSZoneTransferConfig{
+			TSIGKeys: []RuntimeDNSTSIGKey{
+				{Name: "transfer.", Algorithm: "hmac-sha256.", Secret: "c2VjcmV0", Token: "zone-token"},
+			},
+		},
 		AllowWriteHTTPFrom: []*net.IPNet{
 			parseCIDR(t, "127.0.0.0/8"),
 			parseCIDR(t, "::1/128"),
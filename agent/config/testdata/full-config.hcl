@@ -44,6 +44,7 @@ acl = {
 }
 addresses = {
     dns = "93.95.95.81"
+    dns_tls = "18.53.85.31"
     http = "83.39.91.39"
     https = "95.17.17.19"
     grpc = "32.31.61.91"
@@ -261,8 +262,17 @@ alt_domain = "1789hsd"
 dns_config {
     allow_stale = true
     a_record_limit = 29907
+    answer_ordering = "weighted"
+    answer_prefer_near = true
     disable_compression = true
+    dnssec {
+        key_signing_keys = ["/etc/consul.d/Kconsul.+013+39437"]
+        zone_signing_keys = ["/etc/consul.d/Kconsul.+013+16209", "/etc/consul.d/Kconsul.+013+51003"]
+        signature_validity = "51h"
+    }
+    enable_doh = true
     enable_truncate = true
+    kv_prefix = "appliances/flags/"
     max_stale = "29685s"
     node_ttl = "7084s"
     only_passing = true
@@ -271,6 +281,18 @@ dns_config {
         "*" = "32030s"
     }
     udp_answer_limit = 29909
+    zone_transfer {
+        allow_from = ["10.42.0.0/16"]
+        secondaries = ["10.42.7.3", "10.42.7.4:5353"]
+        tsig_keys = [
+            {
+                name = "transfer.consul"
+                algorithm = "HMAC-SHA512"
+                secret = "ZZ6ccyl3dQpiYZ1zLLjP5A=="
+                token = "5ab3f0dc-e4e7-4e6e-9a1c-d2e3b5cd4e51"
+            }
+        ]
+    }
     use_cache = true
     cache_max_age = "5m"
     prefer_namespace = true
@@ -296,6 +318,8 @@ http_config {
     max_header_bytes = 10
 }
 key_file = "IEkkwgIA"
+kv_history_max_age = "3610s"
+kv_history_max_revisions = 17
 leave_on_terminate = true
 license_path = "/path/to/license.lic"
 limits {
@@ -308,6 +332,7 @@ limits {
     rpc_max_conns_per_client = 2954
     kv_max_value_size = 1234567800
     txn_max_req_len = 567800000
+    txn_max_ops = 1500
     request_limits {
         mode = "permissive"
         read_rate = 99.0
@@ -336,6 +361,7 @@ performance {
 pid_file = "43xN80Km"
 ports {
     dns = 7001
+    dns_tls = 7853
     http = 7999
     https = 15127
     server = 3757
@@ -394,6 +420,14 @@ serf_lan = "99.43.63.15"
 serf_wan = "67.88.33.19"
 server = true
 server_name = "Oerr9n1G"
+snapshot_schedule {
+    enabled = true
+    interval = "6437s"
+    retain = 57
+    retain_age = "31536s"
+    file_prefix = "aXtq9Fsm"
+    local_path = "/snapshots/Ub7rvsJn"
+}
 service = {
     id = "dLOXpSCI"
     name = "o1ynPkp0"
@@ -107,6 +107,11 @@ type Config struct {
 	// DevMode is used to enable a development server mode.
 	DevMode bool
 
+	// DevResourceStorage selects the storage backend of the resource service
+	// in development mode. When it is "bolt", resources are persisted to a
+	// bbolt database in the DataDir rather than written through Raft.
+	DevResourceStorage string
+
 	// NodeID is a unique identifier for this node across space and time.
 	NodeID types.NodeID
 
@@ -260,6 +265,16 @@ type Config struct {
 	// to reduce overhead. It is unlikely a user would ever need to tune this.
 	TombstoneTTLGranularity time.Duration
 
+	// KVHistoryMaxRevisions is the number of revisions kept in the history
+	// of each KV entry. The history is disabled when it is zero. The value
+	// of the leader is replicated to the other servers.
+	KVHistoryMaxRevisions int
+
+	// KVHistoryMaxAge is used to control how long the revisions of the KV
+	// history are retained. Revisions are only bounded by their number when
+	// it is zero.
+	KVHistoryMaxAge time.Duration
+
 	// Minimum Session TTL
 	SessionTTLMin time.Duration
 
@@ -434,6 +449,9 @@ type Config struct {
 
 	LogStoreConfig RaftLogStoreConfig
 
+	// SnapshotAgent configures scheduled snapshots of the server state.
+	SnapshotAgent SnapshotAgentConfig
+
 	// PeeringEnabled enables cluster peering.
 	PeeringEnabled bool
 
@@ -698,6 +716,28 @@ type RaftLogStoreVerificationConfig struct {
 	Interval time.Duration
 }
 
+// SnapshotAgentConfig configures the snapshot agent, which periodically saves
+// snapshots of the server state while this server is the leader.
+type SnapshotAgentConfig struct {
+	Enabled bool
+
+	// Interval is the time between snapshots.
+	Interval time.Duration
+
+	// Retain is the number of snapshots to keep. Zero keeps them all.
+	Retain int
+
+	// RetainAge is the age after which snapshots are deleted. Zero keeps them
+	// regardless of age.
+	RetainAge time.Duration
+
+	// FilePrefix is the prefix of the snapshot file names.
+	FilePrefix string
+
+	// LocalPath is the directory snapshots are saved in.
+	LocalPath string
+}
+
 type RaftBoltDBConfig struct {
 	NoFreelistSync bool
 }
@@ -41,6 +41,10 @@ var CommandsSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"fsm", "tombstone"},
 		Help: "Measures the time it takes to apply the given tombstone operation to the FSM.",
 	},
+	{
+		Name: []string{"fsm", "kvs_revision_reap"},
+		Help: "Measures the time it takes to reap the revisions of the KV history in the FSM.",
+	},
 	{
 		Name: []string{"fsm", "coordinate", "batch-update"},
 		Help: "Measures the time it takes to apply the given batch coordinate update to the FSM.",
@@ -147,6 +151,7 @@ func init() {
 	registerCommand(structs.PeeringSecretsWriteType, (*FSM).applyPeeringSecretsWrite)
 	registerCommand(structs.ResourceOperationType, (*FSM).applyResourceOperation)
 	registerCommand(structs.UpdateVirtualIPRequestType, (*FSM).applyManualVirtualIPs)
+	registerCommand(structs.KVSRevisionReapRequestType, (*FSM).applyKVSRevisionReap)
 }
 
 func (c *FSM) applyRegister(buf []byte, index uint64) interface{} {
@@ -279,6 +284,15 @@ func (c *FSM) applyTombstoneOperation(buf []byte, index uint64) interface{} {
 	}
 }
 
+func (c *FSM) applyKVSRevisionReap(buf []byte, index uint64) interface{} {
+	var req structs.KVSRevisionReapRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"fsm", "kvs_revision_reap"}, time.Now())
+	return c.state.KVSRevisionsReap(index, req.ReapIndex)
+}
+
 // applyCoordinateBatchUpdate processes a batch of coordinate updates and applies
 // them in a single underlying transaction. This interface isn't 1:1 with the outer
 // update interface that the coordinate endpoint exposes, so we made it single
@@ -1003,6 +1003,39 @@ func TestFSM_TombstoneReap(t *testing.T) {
 	}
 }
 
+func TestFSM_KVSRevisionReap(t *testing.T) {
+	t.Parallel()
+	logger := testutil.Logger(t)
+	fsm, err := New(nil, logger)
+	require.NoError(t, err)
+	require.NoError(t, fsm.state.SystemMetadataSet(10, &structs.SystemMetadataEntry{
+		Key:   structs.SystemMetadataKVHistoryMaxRevisions,
+		Value: "10",
+	}))
+
+	// Create some revisions
+	require.NoError(t, fsm.state.KVSSet(11, &structs.DirEntry{Key: "foo", Value: []byte("one")}))
+	require.NoError(t, fsm.state.KVSSet(12, &structs.DirEntry{Key: "foo", Value: []byte("two")}))
+
+	// Create a new reap request
+	req := structs.KVSRevisionReapRequest{
+		Datacenter: "dc1",
+		ReapIndex:  11,
+	}
+	buf, err := structs.Encode(structs.KVSRevisionReapRequestType, req)
+	require.NoError(t, err)
+	resp := fsm.Apply(makeLog(buf))
+	if err, ok := resp.(error); ok {
+		t.Fatalf("resp: %v", err)
+	}
+
+	// Verify only the newest revision is left
+	_, revs, err := fsm.state.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Len(t, revs, 1)
+	require.Equal(t, uint64(12), revs[0].ModifyIndex)
+}
+
 func TestFSM_Txn(t *testing.T) {
 	t.Parallel()
 	logger := testutil.Logger(t)
@@ -159,6 +159,10 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 	}
 	require.NoError(t, fsm.state.ACLBindingRuleSet(1, bindingRule))
 
+	require.NoError(t, fsm.state.SystemMetadataSet(10, &structs.SystemMetadataEntry{
+		Key:   structs.SystemMetadataKVHistoryMaxRevisions,
+		Value: "10",
+	}))
 	fsm.state.KVSSet(11, &structs.DirEntry{
 		Key:   "/remove",
 		Value: []byte("foo"),
@@ -754,6 +758,14 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 		require.Nil(t, stones.Next())
 	}()
 
+	// Verify KV revisions are restored
+	idx, revs, err := fsm2.state.KVSRevisions(nil, "/remove", nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 12, idx)
+	require.Len(t, revs, 2)
+	require.True(t, revs[0].Deleted)
+	require.Equal(t, []byte("foo"), revs[1].Value)
+
 	// Verify coordinates are restored
 	_, coords, err := fsm2.state.Coordinates(nil, nil)
 	require.NoError(t, err)
@@ -830,8 +842,8 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 	// Verify system metadata is restored.
 	_, systemMetadataLoaded, err := fsm2.state.SystemMetadataList(nil)
 	require.NoError(t, err)
-	require.Len(t, systemMetadataLoaded, 2)
-	require.Equal(t, systemMetadataEntry, systemMetadataLoaded[1])
+	require.Len(t, systemMetadataLoaded, 3)
+	require.Equal(t, systemMetadataEntry, systemMetadataLoaded[2])
 
 	// Verify service-intentions is restored
 	_, serviceIxnEntry, err := fsm2.state.ConfigEntry(nil, structs.ServiceIntentions, "foo", structs.DefaultEnterpriseMetaInDefaultPartition())
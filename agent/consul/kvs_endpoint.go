@@ -55,7 +55,7 @@ func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api
 	case api.KVGet, api.KVGetTree, api.KVGetOrEmpty:
 		// Filtering for GETs is done on the output side.
 
-	case api.KVCheckSession, api.KVCheckIndex:
+	case api.KVCheckSession, api.KVCheckIndex, api.KVCheckValue, api.KVCheckValuePrefix:
 		// These could reveal information based on the outcome
 		// of the transaction, and they operate on individual
 		// keys so we check them here.
@@ -93,6 +93,21 @@ func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api
 		}
 	}
 
+	// Like the lock-delay, the expiration time is based on wall-time so it
+	// is computed before commit using the wall-time of the leader.
+	dirEnt.Expires = nil
+	if dirEnt.TTL != "" {
+		ttl, err := time.ParseDuration(dirEnt.TTL)
+		if err != nil {
+			return false, fmt.Errorf("Invalid KV TTL '%s': %v", dirEnt.TTL, err)
+		}
+		if ttl <= 0 {
+			return false, fmt.Errorf("Invalid KV TTL '%s': must be positive", dirEnt.TTL)
+		}
+		expires := time.Now().Add(ttl)
+		dirEnt.Expires = &expires
+	}
+
 	return true, nil
 }
 
@@ -129,8 +144,13 @@ func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 	}
 
 	// Check if the return type is a bool.
+	applied := true
 	if respBool, ok := resp.(bool); ok {
 		*reply = respBool
+		applied = respBool
+	}
+	if applied {
+		k.srv.updateKVSTimer(args.Op, &args.DirEnt)
 	}
 	return nil
 }
@@ -175,6 +195,88 @@ func (k *KVS) Get(args *structs.KeyRequest, reply *structs.IndexedDirEntries) er
 		})
 }
 
+// GetAtIndex is used to lookup a single key as it was at a given index,
+// using the history of the key.
+func (k *KVS) GetAtIndex(args *structs.KeyRevisionRequest, reply *structs.IndexedDirEntries) error {
+	if done, err := k.srv.ForwardRPC("KVS.GetAtIndex", args, reply); done {
+		return err
+	}
+
+	var authzContext acl.AuthorizerContext
+	authz, err := k.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+
+	if err := k.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	// The history of a key is only visible to the tokens which can read
+	// it, even the errors telling how much of it is retained.
+	if err := authz.ToAllowAuthorizer().KeyReadAllowed(args.Key, &authzContext); err != nil {
+		return err
+	}
+
+	return k.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, ent, err := state.KVSGetAtIndex(ws, args.Key, args.AtIndex, &args.EnterpriseMeta)
+			if err != nil {
+				return err
+			}
+
+			reply.Index = index
+			if ent == nil {
+				reply.Entries = nil
+				return errNotFound
+			}
+			reply.Entries = structs.DirEntries{ent}
+			return nil
+		})
+}
+
+// Revisions is used to list the revisions of a key kept in its history.
+func (k *KVS) Revisions(args *structs.KeyRequest, reply *structs.IndexedDirEntryRevisions) error {
+	if done, err := k.srv.ForwardRPC("KVS.Revisions", args, reply); done {
+		return err
+	}
+
+	var authzContext acl.AuthorizerContext
+	authz, err := k.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+
+	if err := k.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	// The history of a key is only visible to the tokens which can read
+	// it, even the errors telling how much of it is retained.
+	if err := authz.ToAllowAuthorizer().KeyReadAllowed(args.Key, &authzContext); err != nil {
+		return err
+	}
+
+	return k.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, revs, err := state.KVSRevisions(ws, args.Key, &args.EnterpriseMeta)
+			if err != nil {
+				return err
+			}
+
+			reply.Index = index
+			reply.Revisions = revs
+			if len(revs) == 0 {
+				return errNotFound
+			}
+			return nil
+		})
+}
+
 // List is used to list all keys with a given prefix.
 func (k *KVS) List(args *structs.KeyRequest, reply *structs.IndexedDirEntries) error {
 	if done, err := k.srv.ForwardRPC("KVS.List", args, reply); done {
@@ -4,6 +4,7 @@
 package consul
 
 import (
+	"math"
 	"os"
 	"testing"
 	"time"
@@ -229,6 +230,127 @@ func TestKVS_Get_ACLDeny(t *testing.T) {
 
 }
 
+func TestKVS_Revisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.KVHistoryMaxRevisions = 2
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	getR := structs.KeyRequest{
+		Datacenter: "dc1",
+		Key:        "test",
+	}
+	var revs structs.IndexedDirEntryRevisions
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Revisions", &getR, &revs))
+	require.Empty(t, revs.Revisions)
+
+	var indexes []uint64
+	for _, value := range []string{"one", "two", "three"} {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:   "test",
+				Value: []byte(value),
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+
+		var dirent structs.IndexedDirEntries
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Get", &getR, &dirent))
+		indexes = append(indexes, dirent.Entries[0].ModifyIndex)
+	}
+
+	// Only the two newest revisions are kept.
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Revisions", &getR, &revs))
+	require.Len(t, revs.Revisions, 2)
+	require.Equal(t, "three", string(revs.Revisions[0].Value))
+	require.Equal(t, "two", string(revs.Revisions[1].Value))
+	require.Equal(t, indexes[2], revs.Index)
+
+	atR := structs.KeyRevisionRequest{
+		Datacenter: "dc1",
+		Key:        "test",
+		AtIndex:    indexes[1],
+	}
+	var dirent structs.IndexedDirEntries
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.GetAtIndex", &atR, &dirent))
+	require.Len(t, dirent.Entries, 1)
+	require.Equal(t, "two", string(dirent.Entries[0].Value))
+
+	// The first revision was dropped from the history.
+	atR.AtIndex = indexes[0]
+	dirent = structs.IndexedDirEntries{}
+	err := msgpackrpc.CallWithCodec(codec, "KVS.GetAtIndex", &atR, &dirent)
+	require.True(t, structs.IsErrKVSRevisionNotRetained(err), "err: %v", err)
+}
+
+func TestKVS_Revisions_ACLDeny(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+		c.KVHistoryMaxRevisions = 10
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1", testrpc.WithToken("root"))
+
+	arg := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVSet,
+		DirEnt: structs.DirEntry{
+			Key:   "zip",
+			Value: []byte("test"),
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var out bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+
+	getR := structs.KeyRequest{
+		Datacenter: "dc1",
+		Key:        "zip",
+	}
+	var revs structs.IndexedDirEntryRevisions
+	err := msgpackrpc.CallWithCodec(codec, "KVS.Revisions", &getR, &revs)
+	require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+
+	atR := structs.KeyRevisionRequest{
+		Datacenter: "dc1",
+		Key:        "zip",
+		AtIndex:    math.MaxUint64,
+	}
+	var dirent structs.IndexedDirEntries
+	err = msgpackrpc.CallWithCodec(codec, "KVS.GetAtIndex", &atR, &dirent)
+	require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+
+	// A read before the retained history doesn't tell the history apart.
+	atR.AtIndex = 1
+	err = msgpackrpc.CallWithCodec(codec, "KVS.GetAtIndex", &atR, &dirent)
+	require.True(t, acl.IsErrPermissionDenied(err), "err: %v", err)
+}
+
 func TestKVSEndpoint_List(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+var KVSTTLGauges = []prometheus.GaugeDefinition{
+	{
+		Name: []string{"kvs_ttl", "active"},
+		Help: "Tracks the active number of KV entries with a TTL being tracked.",
+	},
+}
+
+var KVSTTLSummaries = []prometheus.SummaryDefinition{
+	{
+		Name: []string{"kvs_ttl", "reap"},
+		Help: "Measures the time spent deleting an expired KV entry.",
+	},
+}
+
+// initializeKVSTimers is used when a leader is newly elected to create the
+// timers of every KV entry with an expiration time.
+func (s *Server) initializeKVSTimers() error {
+	entries, err := s.fsm.State().KVSListExpiring()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		s.resetKVSTimer(entry)
+	}
+	return nil
+}
+
+// kvsTimerID returns the ID of the timer of a KV entry, which is unique
+// across partitions and namespaces.
+func kvsTimerID(key string, entMeta *acl.EnterpriseMeta) string {
+	return entMeta.PartitionOrDefault() + "/" + entMeta.NamespaceOrDefault() + "/" + key
+}
+
+// resetKVSTimer is used to create, reset or stop the timer of a KV entry
+// after it was written. Entries without an expiration time have their timer
+// stopped.
+func (s *Server) resetKVSTimer(entry *structs.DirEntry) {
+	id := kvsTimerID(entry.Key, &entry.EnterpriseMeta)
+	if entry.Expires == nil {
+		s.kvsTimers.Stop(id)
+		return
+	}
+
+	key, entMeta := entry.Key, entry.EnterpriseMeta
+	s.kvsTimers.ResetOrCreate(id, time.Until(*entry.Expires), func() { s.reapKVS(key, &entMeta) })
+}
+
+// updateKVSTimer updates the timer of the entry of a KV operation which was
+// applied.
+func (s *Server) updateKVSTimer(op api.KVOp, entry *structs.DirEntry) {
+	switch op {
+	case api.KVSet, api.KVCAS, api.KVLock, api.KVUnlock:
+		s.resetKVSTimer(entry)
+	case api.KVDelete, api.KVDeleteCAS:
+		s.kvsTimers.Stop(kvsTimerID(entry.Key, &entry.EnterpriseMeta))
+	}
+}
+
+// reapKVS is invoked when the expiration time of a KV entry is reached and
+// we need to delete it. The entry is only deleted if it still is expired, so
+// that an entry refreshed concurrently is kept.
+func (s *Server) reapKVS(key string, entMeta *acl.EnterpriseMeta) {
+	defer metrics.MeasureSince([]string{"kvs_ttl", "reap"}, time.Now())
+
+	id := kvsTimerID(key, entMeta)
+	for attempt := uint(0); attempt < maxInvalidateAttempts; attempt++ {
+		_, entry, err := s.fsm.State().KVSGet(nil, key, entMeta)
+		if err != nil {
+			s.logger.Error("failed to lookup expired KV entry", "key", key, "error", err)
+			time.Sleep((1 << attempt) * invalidateRetryBase)
+			continue
+		}
+		if entry == nil || entry.Expires == nil {
+			s.kvsTimers.Del(id)
+			return
+		}
+		if entry.Expires.After(time.Now()) {
+			// The entry was refreshed, the write reset the timer already
+			// but make sure it will be reaped even if it didn't.
+			s.resetKVSTimer(entry)
+			return
+		}
+
+		// Use a delete-cas so a concurrent write isn't lost.
+		args := structs.KVSRequest{
+			Datacenter: s.config.Datacenter,
+			Op:         api.KVDeleteCAS,
+			DirEnt: structs.DirEntry{
+				Key:            key,
+				EnterpriseMeta: *entMeta,
+				RaftIndex: structs.RaftIndex{
+					ModifyIndex: entry.ModifyIndex,
+				},
+			},
+		}
+		resp, err := s.leaderRaftApply("KVS.Apply", structs.KVSRequestType, args)
+		if err != nil {
+			s.logger.Error("failed to delete expired KV entry", "key", key, "error", err)
+			time.Sleep((1 << attempt) * invalidateRetryBase)
+			continue
+		}
+		if ok, _ := resp.(bool); ok {
+			s.logger.Debug("KV entry TTL expired", "key", key)
+			s.kvsTimers.Del(id)
+			return
+		}
+		// The entry was modified between the lookup and the delete, check it
+		// again.
+	}
+	s.logger.Error("maximum delete attempts reached for expired KV entry", "key", key)
+}
+
+// clearAllKVSTimers is used when a leader is stepping down and we no longer
+// need to track any KV timers.
+func (s *Server) clearAllKVSTimers() {
+	s.kvsTimers.StopAll()
+}
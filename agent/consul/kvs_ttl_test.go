@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/consul-net-rpc/net-rpc-msgpackrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestInitializeKVSTimers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	expires := time.Now().Add(time.Hour)
+	require.NoError(t, state.KVSSet(100, &structs.DirEntry{Key: "expiring", TTL: "1h", Expires: &expires}))
+	require.NoError(t, state.KVSSet(101, &structs.DirEntry{Key: "permanent"}))
+
+	require.NoError(t, s1.initializeKVSTimers())
+
+	require.NotNil(t, s1.kvsTimers.Get(kvsTimerID("expiring", structs.DefaultEnterpriseMetaInDefaultPartition())))
+	require.Nil(t, s1.kvsTimers.Get(kvsTimerID("permanent", structs.DefaultEnterpriseMetaInDefaultPartition())))
+
+	s1.clearAllKVSTimers()
+	require.Equal(t, 0, s1.kvsTimers.Len())
+}
+
+func TestKVSTTL_Reap(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	apply := func(key, ttl string) {
+		t.Helper()
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:   key,
+				Value: []byte("test"),
+				TTL:   ttl,
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	}
+	get := func(key string) *structs.DirEntry {
+		t.Helper()
+		_, entry, err := s1.fsm.State().KVSGet(nil, key, nil)
+		require.NoError(t, err)
+		return entry
+	}
+
+	apply("expiring", "100ms")
+	apply("refreshed", "100ms")
+	apply("permanent", "")
+
+	entry := get("expiring")
+	require.NotNil(t, entry)
+	require.Equal(t, "100ms", entry.TTL)
+	require.NotNil(t, entry.Expires)
+	require.Nil(t, get("permanent").Expires)
+
+	// Writing the key again without a TTL clears its expiration.
+	apply("refreshed", "")
+	require.Nil(t, get("refreshed").Expires)
+
+	retry.Run(t, func(r *retry.R) {
+		_, entry, err := s1.fsm.State().KVSGet(nil, "expiring", nil)
+		if err != nil {
+			r.Fatalf("err: %v", err)
+		}
+		if entry != nil {
+			r.Fatalf("entry should be reaped")
+		}
+	})
+	require.NotNil(t, get("refreshed"))
+	require.NotNil(t, get("permanent"))
+	require.Equal(t, 0, s1.kvsTimers.Len())
+}
+
+func TestKVSTTL_InvalidTTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	for _, ttl := range []string{"foo", "-1s", "0s"} {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key: "test",
+				TTL: ttl,
+			},
+		}
+		var out bool
+		err := msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out)
+		require.ErrorContains(t, err, "Invalid KV TTL")
+	}
+}
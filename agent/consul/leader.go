@@ -46,6 +46,10 @@ var LeaderSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"leader", "reapTombstones"},
 		Help: "Measures the time spent clearing tombstones.",
 	},
+	{
+		Name: []string{"leader", "reapKVSRevisions"},
+		Help: "Measures the time spent clearing the revisions of the KV history.",
+	},
 }
 
 const (
@@ -215,6 +219,12 @@ WAIT:
 	default:
 	}
 
+	// The revisions of the KV history only expire if they have a max age.
+	var kvHistoryExpireCh <-chan uint64
+	if s.kvHistory.GC != nil {
+		kvHistoryExpireCh = s.kvHistory.GC.ExpireCh()
+	}
+
 	// Periodically reconcile as long as we are the leader,
 	// or when Serf events arrive
 	for {
@@ -229,6 +239,8 @@ WAIT:
 			s.reconcileMember(member)
 		case index := <-s.tombstoneGC.ExpireCh():
 			go s.reapTombstones(index)
+		case index := <-kvHistoryExpireCh:
+			go s.reapKVSRevisions(index)
 		case errCh := <-s.reassertLeaderCh:
 			// we can get into this state when the initial
 			// establishLeadership has failed as well as the follow
@@ -292,6 +304,16 @@ func (s *Server) establishLeadership(ctx context.Context) error {
 	lastIndex := s.raft.LastIndex()
 	s.tombstoneGC.Hint(lastIndex)
 
+	// Same for the revisions of the KV history.
+	if s.kvHistory.GC != nil {
+		s.kvHistory.GC.SetEnabled(true)
+		s.kvHistory.GC.Hint(lastIndex)
+	}
+
+	if err := s.setKVHistoryMaxRevisions(); err != nil {
+		return err
+	}
+
 	// Setup the session timers. This is done both when starting up or when
 	// a leader fail over happens. Since the timers are maintained by the leader
 	// node along, effectively this means all the timers are renewed at the
@@ -305,6 +327,12 @@ func (s *Server) establishLeadership(ctx context.Context) error {
 		return err
 	}
 
+	// Setup the KV timers the same way, the expiration times are stored so
+	// entries are expired at the same time after a leader fail over.
+	if err := s.initializeKVSTimers(); err != nil {
+		return err
+	}
+
 	if err := s.establishEnterpriseLeadership(ctx); err != nil {
 		return err
 	}
@@ -341,6 +369,12 @@ func (s *Server) establishLeadership(ctx context.Context) error {
 		s.startLogVerification(ctx)
 	}
 
+	if s.config.SnapshotAgent.Enabled {
+		if err := s.startSnapshotAgent(ctx); err != nil {
+			s.logger.Error("failed to start the snapshot agent", "error", err)
+		}
+	}
+
 	if s.config.Reporting.License.Enabled && s.reportingManager != nil {
 		s.reportingManager.StartReportingAgent()
 	}
@@ -355,12 +389,18 @@ func (s *Server) revokeLeadership() {
 
 	s.stopLogVerification()
 
+	s.stopSnapshotAgent()
+
 	// Disable the tombstone GC, since it is only useful as a leader
 	s.tombstoneGC.SetEnabled(false)
+	if s.kvHistory.GC != nil {
+		s.kvHistory.GC.SetEnabled(false)
+	}
 
 	// Clear the session timers on either shutdown or step down, since we
 	// are no longer responsible for session expirations.
 	s.clearAllSessionTimers()
+	s.clearAllKVSTimers()
 
 	s.revokeEnterpriseLeadership()
 
@@ -1301,6 +1341,48 @@ func (s *Server) reapTombstones(index uint64) {
 	}
 }
 
+// reapKVSRevisions is invoked by the current leader to manage garbage
+// collection of the revisions of the KV history older than their max age.
+// We issue a Raft request to the followers so that the history is the same
+// on every server.
+func (s *Server) reapKVSRevisions(index uint64) {
+	defer metrics.MeasureSince([]string{"leader", "reapKVSRevisions"}, time.Now())
+	req := structs.KVSRevisionReapRequest{
+		Datacenter: s.config.Datacenter,
+		ReapIndex:  index,
+	}
+	_, err := s.raftApply(structs.KVSRevisionReapRequestType|structs.IgnoreUnknownTypeFlag, &req)
+	if err != nil {
+		s.logger.Error("failed to reap kvs revisions up to index",
+			"index", index,
+			"error", err,
+		)
+	}
+}
+
+// setKVHistoryMaxRevisions replicates the max number of revisions of the KV
+// history configured on the leader, so that every server records and trims
+// the history the same way.
+func (s *Server) setKVHistoryMaxRevisions() error {
+	val, err := s.GetSystemMetadata(structs.SystemMetadataKVHistoryMaxRevisions)
+	if err != nil {
+		return err
+	}
+
+	var want string
+	if s.config.KVHistoryMaxRevisions > 0 {
+		want = strconv.Itoa(s.config.KVHistoryMaxRevisions)
+	}
+	switch {
+	case val == want:
+		return nil
+	case want == "":
+		return s.deleteSystemMetadataKey(structs.SystemMetadataKVHistoryMaxRevisions)
+	default:
+		return s.SetSystemMetadataKey(structs.SystemMetadataKVHistoryMaxRevisions, want)
+	}
+}
+
 func (s *Server) setDatacenterSupportsFederationStates() {
 	atomic.StoreInt32(&s.dcSupportsFederationStates, 1)
 }
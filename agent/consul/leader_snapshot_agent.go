@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"context"
+
+	"github.com/hashicorp/consul/agent/consul/snapshotagent"
+	"github.com/hashicorp/consul/logging"
+	"github.com/hashicorp/consul/snapshot"
+)
+
+func (s *Server) startSnapshotAgent(ctx context.Context) error {
+	return s.leaderRoutineManager.Start(ctx, snapshotAgentRoutineName, s.runSnapshotAgent)
+}
+
+func (s *Server) stopSnapshotAgent() {
+	s.leaderRoutineManager.Stop(snapshotAgentRoutineName)
+}
+
+func (s *Server) runSnapshotAgent(ctx context.Context) error {
+	conf := s.config.SnapshotAgent
+	// This shouldn't be possible but bit of a safety check
+	if !conf.Enabled || conf.Interval == 0 {
+		return nil
+	}
+
+	store, err := snapshotagent.NewLocalStore(conf.LocalPath)
+	if err != nil {
+		return err
+	}
+
+	logger := s.logger.Named(logging.SnapshotAgent)
+	agent := snapshotagent.New(snapshotagent.Config{
+		Interval:   conf.Interval,
+		Retain:     conf.Retain,
+		RetainAge:  conf.RetainAge,
+		FilePrefix: conf.FilePrefix,
+	}, store, func() (snapshotagent.Snapshot, error) {
+		snap, err := snapshot.New(logger, s.raft)
+		if err != nil {
+			return nil, err
+		}
+		return snap, nil
+	}, logger)
+	return agent.Run(ctx)
+}
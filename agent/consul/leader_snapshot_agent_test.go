@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/snapshot"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestLeader_SnapshotAgent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.SnapshotAgent = SnapshotAgentConfig{
+			Enabled:    true,
+			Interval:   100 * time.Millisecond,
+			Retain:     2,
+			FilePrefix: "test",
+			LocalPath:  dir,
+		}
+	})
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	var names []string
+	retry.Run(t, func(r *retry.R) {
+		entries, err := os.ReadDir(dir)
+		require.NoError(r, err)
+
+		names = names[:0]
+		for _, e := range entries {
+			if filepath.Ext(e.Name()) == ".snap" {
+				names = append(names, e.Name())
+			}
+		}
+		require.NotEmpty(r, names)
+		require.LessOrEqual(r, len(names), 2)
+		require.True(r, s1.leaderRoutineManager.IsRunning(snapshotAgentRoutineName))
+	})
+
+	f, err := os.Open(filepath.Join(dir, names[0]))
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = snapshot.Verify(f)
+	require.NoError(t, err)
+}
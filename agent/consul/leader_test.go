@@ -1039,6 +1039,89 @@ func TestLeader_ReapTombstones(t *testing.T) {
 	})
 }
 
+func TestLeader_ReapKVSRevisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.KVHistoryMaxRevisions = 10
+		c.KVHistoryMaxAge = 500 * time.Millisecond
+		c.TombstoneTTLGranularity = 10 * time.Millisecond
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	// Create a KV entry
+	arg := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVSet,
+		DirEnt: structs.DirEntry{
+			Key:   "test",
+			Value: []byte("test"),
+		},
+	}
+	var out bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+
+	// Make sure there's a revision.
+	state := s1.fsm.State()
+	_, revs, err := state.KVSRevisions(nil, "test", nil)
+	require.NoError(t, err)
+	require.Len(t, revs, 1)
+
+	// Watch for the revision to get removed once it's older than the max age,
+	// while the entry itself is kept.
+	retry.Run(t, func(r *retry.R) {
+		_, revs, err := state.KVSRevisions(nil, "test", nil)
+		if err != nil {
+			r.Fatal(err)
+		}
+		if len(revs) != 0 {
+			r.Fatal("should have no revisions")
+		}
+	})
+	_, entry, err := state.KVSGet(nil, "test", nil)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+}
+
+func TestLeader_KVHistoryMaxRevisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.KVHistoryMaxRevisions = 5
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Bootstrap = false
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	joinLAN(t, s2, s1)
+
+	// The max number of revisions of the leader is replicated to the other
+	// servers, whatever their own configuration.
+	retry.Run(t, func(r *retry.R) {
+		for _, s := range []*Server{s1, s2} {
+			val, err := s.GetSystemMetadata(structs.SystemMetadataKVHistoryMaxRevisions)
+			require.NoError(r, err)
+			require.Equal(r, "5", val)
+		}
+	})
+}
+
 func TestLeader_RollRaftServer(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -962,7 +962,7 @@ func (s *Server) raftApplyEncoded(t structs.MessageType, buf []byte) (any, error
 	var chunked bool
 	var future raft.ApplyFuture
 	switch {
-	case len(buf) <= raft.SuggestedMaxDataSize || t != structs.KVSRequestType:
+	case len(buf) <= raft.SuggestedMaxDataSize || (t != structs.KVSRequestType && t != structs.TxnRequestType):
 		future = s.raft.Apply(buf, enqueueLimit)
 	default:
 		chunked = true
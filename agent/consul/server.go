@@ -74,6 +74,8 @@ import (
 	"github.com/hashicorp/consul/internal/resource"
 	"github.com/hashicorp/consul/internal/resource/demo"
 	"github.com/hashicorp/consul/internal/resource/reaper"
+	"github.com/hashicorp/consul/internal/storage"
+	boltstorage "github.com/hashicorp/consul/internal/storage/bolt"
 	raftstorage "github.com/hashicorp/consul/internal/storage/raft"
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/lib/routine"
@@ -109,6 +111,7 @@ const (
 	serfLANSnapshot   = "serf/local.snapshot"
 	serfWANSnapshot   = "serf/remote.snapshot"
 	raftState         = "raft/"
+	boltStorageFile   = "resources.db"
 	snapshotsRetained = 2
 
 	// raftLogCacheSize is the maximum number of logs to cache in-memory.
@@ -153,6 +156,7 @@ const (
 	peeringDeletionRoutineName            = "peering deferred deletion"
 	peeringStreamsMetricsRoutineName      = "metrics for streaming peering resources"
 	raftLogVerifierRoutineName            = "raft log verifier"
+	snapshotAgentRoutineName              = "snapshot agent"
 )
 
 var (
@@ -265,6 +269,10 @@ type Server struct {
 	// raftStorageBackend is the Raft-backed storage backend for resources.
 	raftStorageBackend *raftstorage.Backend
 
+	// boltStorageBackend is the local bbolt storage backend for resources,
+	// it is only set in dev mode when dev_resource_storage = "bolt".
+	boltStorageBackend *boltstorage.Backend
+
 	// reconcileCh is used to pass events from the serf handler
 	// into the leader manager, so that the strong state can be
 	// updated
@@ -362,6 +370,10 @@ type Server struct {
 	// destroy the session via standard session destroy processing
 	sessionTimers *SessionTimers
 
+	// kvsTimers track the expiration time of each KV entry that has a TTL.
+	// On expiration, the entry is deleted if it wasn't updated since.
+	kvsTimers *SessionTimers
+
 	// statsFetcher is used by autopilot to check the status of the other
 	// Consul router.
 	statsFetcher *StatsFetcher
@@ -378,6 +390,10 @@ type Server struct {
 	// for the KV tombstones
 	tombstoneGC *state.TombstoneGC
 
+	// kvHistory bounds the history of the KV entries, its GC is used to
+	// track the pending reaps of the revisions older than the max age.
+	kvHistory state.KVHistoryConfig
+
 	// aclReplicationStatus (and its associated lock) provide information
 	// about the health of the ACL replication goroutine.
 	aclReplicationStatus     structs.ACLReplicationStatus
@@ -473,6 +489,16 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 		return nil, err
 	}
 
+	// Create the KV history GC, revisions are only reaped by age if a max
+	// age is configured.
+	var kvHistory state.KVHistoryConfig
+	if config.KVHistoryMaxRevisions > 0 && config.KVHistoryMaxAge > 0 {
+		kvHistory.GC, err = state.NewTombstoneGC(config.KVHistoryMaxAge, config.TombstoneTTLGranularity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create the shutdown channel - this is closed but never written to.
 	shutdownCh := make(chan struct{})
 
@@ -499,7 +525,9 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 		externalGRPCServer:      externalGRPCServer,
 		reassertLeaderCh:        make(chan chan error),
 		sessionTimers:           NewSessionTimers(),
+		kvsTimers:               NewSessionTimers(),
 		tombstoneGC:             gc,
+		kvHistory:               kvHistory,
 		serverLookup:            NewServerLookup(),
 		shutdownCh:              shutdownCh,
 		leaderRoutineManager:    routine.NewManager(logger.Named(logging.Leader)),
@@ -517,10 +545,23 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 	}
 	go s.raftStorageBackend.Run(&lib.StopChannelContext{StopCh: shutdownCh})
 
+	if config.DevMode && config.DevResourceStorage == "bolt" {
+		path := filepath.Join(config.DataDir, boltStorageFile)
+		if err := lib.EnsurePath(path, false); err != nil {
+			return nil, err
+		}
+		s.boltStorageBackend, err = boltstorage.NewBackend(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bolt storage backend: %w", err)
+		}
+	}
+
 	s.fsm = fsm.NewFromDeps(fsm.Deps{
 		Logger: flat.Logger,
 		NewStateStore: func() *state.Store {
-			return state.NewStateStoreWithEventPublisher(gc, flat.EventPublisher)
+			store := state.NewStateStoreWithEventPublisher(gc, flat.EventPublisher)
+			store.SetKVHistoryConfig(kvHistory)
+			return store
 		},
 		Publisher:      flat.EventPublisher,
 		StorageBackend: s.raftStorageBackend,
@@ -845,6 +886,7 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server, incom
 func (s *Server) registerResources() {
 	catalog.RegisterTypes(s.typeRegistry)
 	mesh.RegisterTypes(s.typeRegistry)
+	catalog.RegisterControllers(s.controllerManager)
 	reaper.RegisterControllers(s.controllerManager)
 
 	if s.config.DevMode {
@@ -1110,7 +1152,9 @@ func (s *Server) setupRaft() error {
 			tmpFsm := fsm.NewFromDeps(fsm.Deps{
 				Logger: s.logger,
 				NewStateStore: func() *state.Store {
-					return state.NewStateStore(s.tombstoneGC)
+					store := state.NewStateStore(s.tombstoneGC)
+					store.SetKVHistoryConfig(s.kvHistory)
+					return store
 				},
 				StorageBackend: backend,
 			})
@@ -1311,7 +1355,7 @@ func (s *Server) setupExternalGRPC(config *Config, logger hclog.Logger) {
 
 	resourcegrpc.NewServer(resourcegrpc.Config{
 		Registry:    s.typeRegistry,
-		Backend:     s.raftStorageBackend,
+		Backend:     s.resourceStorageBackend(),
 		ACLResolver: s.ACLResolver,
 		Logger:      logger.Named("grpc-api.resource"),
 	}).Register(s.externalGRPCServer)
@@ -1322,7 +1366,7 @@ func (s *Server) setupInternalResourceService(logger hclog.Logger) error {
 
 	resourcegrpc.NewServer(resourcegrpc.Config{
 		Registry:    s.typeRegistry,
-		Backend:     s.raftStorageBackend,
+		Backend:     s.resourceStorageBackend(),
 		ACLResolver: resolver.DANGER_NO_AUTH{},
 		Logger:      logger.Named("grpc-api.resource"),
 	}).Register(server)
@@ -1418,9 +1462,23 @@ func (s *Server) Shutdown() error {
 		s.fsm.State().Abandon()
 	}
 
+	if s.boltStorageBackend != nil {
+		s.boltStorageBackend.Close()
+	}
+
 	return nil
 }
 
+// resourceStorageBackend returns the storage backend used by the resource
+// service: the bolt backend in dev mode when it was selected, and the Raft
+// backend otherwise.
+func (s *Server) resourceStorageBackend() storage.Backend {
+	if s.boltStorageBackend != nil {
+		return s.boltStorageBackend
+	}
+	return s.raftStorageBackend
+}
+
 func (s *Server) attemptLeadershipTransfer(id raft.ServerID) (err error) {
 	var addr raft.ServerAddress
 	if id != "" {
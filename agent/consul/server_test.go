@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
@@ -374,6 +375,25 @@ func TestServer_StartStop(t *testing.T) {
 	}
 }
 
+func TestServer_DevResourceStorage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir, s1 := testServerWithConfig(t, func(c *Config) {
+		c.DevMode = true
+		c.DevResourceStorage = "bolt"
+	})
+
+	require.Same(t, s1.boltStorageBackend, s1.resourceStorageBackend())
+	require.FileExists(t, filepath.Join(dir, boltStorageFile))
+
+	_, s2 := testServer(t)
+	require.Same(t, s2.raftStorageBackend, s2.resourceStorageBackend())
+}
+
 func TestServer_fixupACLDatacenter(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -153,6 +153,7 @@ func (s *Server) updateMetrics() {
 		select {
 		case <-time.After(time.Second):
 			metrics.SetGauge([]string{"session_ttl", "active"}, float32(s.sessionTimers.Len()))
+			metrics.SetGauge([]string{"kvs_ttl", "active"}, float32(s.kvsTimers.Len()))
 
 			metrics.SetGauge([]string{"raft", "applied_index"}, float32(s.raft.AppliedIndex()))
 			metrics.SetGauge([]string{"raft", "last_index"}, float32(s.raft.LastIndex()))
@@ -12,16 +12,20 @@ package consul
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"time"
 
 	"github.com/hashicorp/consul-net-rpc/go-msgpack/codec"
 
+	"github.com/hashicorp/consul/agent/consul/fsm"
 	"github.com/hashicorp/consul/agent/pool"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/snapshot"
 )
 
@@ -142,11 +146,151 @@ func (s *Server) dispatchSnapshotRequest(args *structs.SnapshotRequest, in io.Re
 		// stream back.
 		return io.NopCloser(bytes.NewReader([]byte(""))), nil
 
+	case structs.SnapshotRestoreFiltered:
+		if args.AllowStale {
+			return nil, fmt.Errorf("stale not allowed for restore")
+		}
+		if args.Filter.IsEmpty() {
+			return nil, fmt.Errorf("filtered restore must select at least one kind of record")
+		}
+
+		summary, err := s.restoreFilteredSnapshot(in, &args.Filter)
+		if err != nil {
+			return nil, err
+		}
+
+		// Stream the summary back so the caller can report what was restored.
+		buf, err := json.Marshal(summary)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(buf)), nil
+
 	default:
 		return nil, fmt.Errorf("unrecognized snapshot op %q", args.Op)
 	}
 }
 
+// restoreFilteredSnapshot reads a snapshot archive and replays the records
+// selected by the given filter through the same Raft applies used by the KV,
+// config entry and ACL endpoints. Unlike a full restore, the rest of the state
+// is left untouched and the cluster stays available throughout.
+//
+// Records are applied one at a time, so if an apply fails the records before
+// it will already have been restored.
+func (s *Server) restoreFilteredSnapshot(in io.Reader, filter *structs.SnapshotRestoreFilter) (*structs.SnapshotRestoreSummary, error) {
+	snap, _, err := snapshot.Read(s.logger, in)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := snap.Close(); err != nil {
+			s.logger.Error("Failed to close temp snapshot", "error", err)
+		}
+		if err := os.Remove(snap.Name()); err != nil {
+			s.logger.Error("Failed to clean up temp snapshot", "error", err)
+		}
+	}()
+
+	apply := func(method string, t structs.MessageType, msg interface{}) error {
+		resp, err := s.leaderRaftApply(method, t, msg)
+		if err != nil {
+			return err
+		}
+		if respErr, ok := resp.(error); ok {
+			return respErr
+		}
+		return nil
+	}
+
+	var summary structs.SnapshotRestoreSummary
+	handler := func(_ *fsm.SnapshotHeader, msg structs.MessageType, dec *codec.Decoder) error {
+		switch {
+		case msg == structs.KVSRequestType && len(filter.KVPrefixes) != 0:
+			var entry structs.DirEntry
+			if err := dec.Decode(&entry); err != nil {
+				return err
+			}
+			if !filter.MatchesKey(entry.Key) {
+				return nil
+			}
+			entry.RaftIndex = structs.RaftIndex{}
+
+			req := structs.KVSRequest{Op: api.KVSet, DirEnt: entry}
+			if err := apply("KVS.Apply", structs.KVSRequestType, &req); err != nil {
+				return fmt.Errorf("failed to restore key %q: %w", entry.Key, err)
+			}
+			summary.KV++
+
+		case msg == structs.ConfigEntryRequestType && len(filter.ConfigEntries) != 0:
+			var req structs.ConfigEntryRequest
+			if err := dec.Decode(&req); err != nil {
+				return err
+			}
+			if req.Entry == nil || !filter.MatchesConfigEntry(req.Entry) {
+				return nil
+			}
+
+			req.Op = structs.ConfigEntryUpsert
+			if err := apply("ConfigEntry.Apply", structs.ConfigEntryRequestType, &req); err != nil {
+				return fmt.Errorf("failed to restore config entry %s/%s: %w", req.Entry.GetKind(), req.Entry.GetName(), err)
+			}
+			summary.ConfigEntries++
+
+		case msg == structs.ACLTokenSetRequestType && filter.ACLs:
+			var token structs.ACLToken
+			if err := dec.Decode(&token); err != nil {
+				return err
+			}
+			token.SetHash(false)
+
+			req := structs.ACLTokenBatchSetRequest{
+				Tokens:            structs.ACLTokens{&token},
+				CAS:               false,
+				AllowMissingLinks: true,
+			}
+			if err := apply("ACL.TokenSet", structs.ACLTokenSetRequestType, &req); err != nil {
+				return fmt.Errorf("failed to restore token %q: %w", token.AccessorID, err)
+			}
+			summary.ACLTokens++
+
+		case msg == structs.ACLPolicySetRequestType && filter.ACLs:
+			var policy structs.ACLPolicy
+			if err := dec.Decode(&policy); err != nil {
+				return err
+			}
+
+			req := structs.ACLPolicyBatchSetRequest{
+				Policies: structs.ACLPolicies{&policy},
+			}
+			if err := apply("ACL.PolicySet", structs.ACLPolicySetRequestType, &req); err != nil {
+				return fmt.Errorf("failed to restore policy %q: %w", policy.Name, err)
+			}
+			summary.ACLPolicies++
+
+		default:
+			// Skip over records that weren't selected.
+			var val interface{}
+			if err := dec.Decode(&val); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := fsm.ReadSnapshot(snap, handler); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("restored records from snapshot",
+		"kv", summary.KV,
+		"config_entries", summary.ConfigEntries,
+		"acl_tokens", summary.ACLTokens,
+		"acl_policies", summary.ACLPolicies,
+	)
+	return &summary, nil
+}
+
 // handleSnapshotRequest reads the request from the conn and dispatches it. This
 // will be called from a goroutine after an incoming stream is determined to be
 // a snapshot request.
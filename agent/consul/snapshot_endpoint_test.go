@@ -5,6 +5,7 @@ package consul
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -447,3 +448,128 @@ func TestSnapshot_AllowStale(t *testing.T) {
 		}
 	}
 }
+
+func TestSnapshot_RestoreFiltered(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	setKey := func(key, value string) {
+		args := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt:     structs.DirEntry{Key: key, Value: []byte(value)},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &args, &out))
+	}
+	setConfigEntry := func(name string) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceConfigEntry{
+				Kind:     structs.ServiceDefaults,
+				Name:     name,
+				Protocol: "http",
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out))
+	}
+
+	setKey("app/a", "1")
+	setKey("app/b", "1")
+	setKey("other/c", "1")
+	setConfigEntry("web")
+	setConfigEntry("db")
+
+	// Take a snapshot.
+	args := structs.SnapshotRequest{
+		Datacenter: "dc1",
+		Op:         structs.SnapshotSave,
+	}
+	var reply structs.SnapshotResponse
+	snap, err := SnapshotRPC(s1.connPool, s1.config.Datacenter, s1.config.NodeName, s1.config.RPCAddr,
+		&args, bytes.NewReader([]byte("")), &reply)
+	require.NoError(t, err)
+	defer snap.Close()
+
+	// Make some changes.
+	{
+		args := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVDelete,
+			DirEnt:     structs.DirEntry{Key: "app/a"},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &args, &out))
+	}
+	setKey("app/b", "2")
+	setKey("other/c", "2")
+	{
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry:      &structs.ServiceConfigEntry{Kind: structs.ServiceDefaults, Name: "web"},
+		}
+		var out structs.ConfigEntryDeleteResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Delete", &args, &out))
+		args.Entry = &structs.ServiceConfigEntry{Kind: structs.ServiceDefaults, Name: "db"}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Delete", &args, &out))
+	}
+
+	t.Run("empty filter", func(t *testing.T) {
+		args := structs.SnapshotRequest{
+			Datacenter: "dc1",
+			Op:         structs.SnapshotRestoreFiltered,
+		}
+		_, err := SnapshotRPC(s1.connPool, s1.config.Datacenter, s1.config.NodeName, s1.config.RPCAddr,
+			&args, bytes.NewReader([]byte("")), &reply)
+		require.ErrorContains(t, err, "must select at least one kind of record")
+	})
+
+	// Restore the app/ KV tree and only the "web" service-defaults entry.
+	args = structs.SnapshotRequest{
+		Datacenter: "dc1",
+		Op:         structs.SnapshotRestoreFiltered,
+		Filter: structs.SnapshotRestoreFilter{
+			KVPrefixes:    []string{"app/"},
+			ConfigEntries: []string{"service-defaults/web"},
+		},
+	}
+	restore, err := SnapshotRPC(s1.connPool, s1.config.Datacenter, s1.config.NodeName, s1.config.RPCAddr,
+		&args, snap, &reply)
+	require.NoError(t, err)
+	defer restore.Close()
+
+	var summary structs.SnapshotRestoreSummary
+	require.NoError(t, json.NewDecoder(restore).Decode(&summary))
+	require.Equal(t, structs.SnapshotRestoreSummary{KV: 2, ConfigEntries: 1}, summary)
+
+	state := s1.fsm.State()
+	for key, value := range map[string]string{
+		"app/a":   "1",
+		"app/b":   "1",
+		"other/c": "2",
+	} {
+		_, entry, err := state.KVSGet(nil, key, nil)
+		require.NoError(t, err)
+		require.NotNil(t, entry, key)
+		require.Equal(t, value, string(entry.Value), key)
+	}
+
+	_, entry, err := state.ConfigEntry(nil, structs.ServiceDefaults, "web", nil)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+
+	_, entry, err = state.ConfigEntry(nil, structs.ServiceDefaults, "db", nil)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+}
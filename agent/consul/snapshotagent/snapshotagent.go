@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package snapshotagent takes snapshots of the server's state on a schedule
+// and stores them in a SnapshotStore, pruning old snapshots as it goes.
+package snapshotagent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/go-hclog"
+)
+
+var Gauges = []prometheus.GaugeDefinition{
+	{
+		Name: []string{"snapshot_agent", "last_success"},
+		Help: "The Unix time, in seconds, of the last snapshot that was successfully taken and stored by the snapshot agent.",
+	},
+	{
+		Name: []string{"snapshot_agent", "size"},
+		Help: "The size, in bytes, of the last snapshot that was successfully taken and stored by the snapshot agent.",
+	},
+}
+
+var Summaries = []prometheus.SummaryDefinition{
+	{
+		Name: []string{"snapshot_agent", "duration"},
+		Help: "Measures the time spent taking and storing a snapshot, in milliseconds.",
+	},
+}
+
+var Counters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"snapshot_agent", "failure"},
+		Help: "Counts the number of times the snapshot agent failed to take or store a snapshot.",
+	},
+}
+
+// Snapshot is a snapshot archive of the server's state, as returned by
+// snapshot.New.
+type Snapshot interface {
+	io.ReadCloser
+	Index() uint64
+}
+
+// Config holds the settings of the snapshot agent.
+type Config struct {
+	// Interval is the time between snapshots.
+	Interval time.Duration
+
+	// Retain is the number of snapshots to keep. Zero keeps them all.
+	Retain int
+
+	// RetainAge is the age after which snapshots are deleted. Zero keeps
+	// them regardless of age.
+	RetainAge time.Duration
+
+	// FilePrefix is used to name snapshots, and to identify the snapshots
+	// that are managed by the agent when pruning.
+	FilePrefix string
+}
+
+// Agent periodically takes snapshots and stores them. It's meant to run on
+// the leader only.
+type Agent struct {
+	config   Config
+	store    SnapshotStore
+	snapshot func() (Snapshot, error)
+	logger   hclog.Logger
+
+	// now is a shim for testing.
+	now func() time.Time
+}
+
+// New returns an agent that takes snapshots with the given function and
+// stores them in store.
+func New(config Config, store SnapshotStore, snapshot func() (Snapshot, error), logger hclog.Logger) *Agent {
+	return &Agent{
+		config:   config,
+		store:    store,
+		snapshot: snapshot,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Run takes a snapshot every interval until the context is cancelled. The
+// first snapshot is taken one interval after starting, so that leadership
+// changes don't cause a burst of snapshots.
+func (a *Agent) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.SnapshotOnce(ctx); err != nil {
+				a.logger.Error("failed to take snapshot", "error", err)
+			}
+		}
+	}
+}
+
+// SnapshotOnce takes and stores a single snapshot, then prunes the snapshots
+// that are no longer retained.
+func (a *Agent) SnapshotOnce(ctx context.Context) error {
+	start := time.Now()
+
+	name, size, err := a.takeSnapshot(ctx)
+	if err != nil {
+		metrics.IncrCounter([]string{"snapshot_agent", "failure"}, 1)
+		return err
+	}
+
+	metrics.MeasureSince([]string{"snapshot_agent", "duration"}, start)
+	metrics.SetGauge([]string{"snapshot_agent", "last_success"}, float32(a.now().Unix()))
+	metrics.SetGauge([]string{"snapshot_agent", "size"}, float32(size))
+	a.logger.Info("saved snapshot", "name", name, "size", size, "duration", time.Since(start))
+
+	// Failing to prune isn't fatal, the next run will try again.
+	if err := a.prune(ctx); err != nil {
+		a.logger.Error("failed to prune old snapshots", "error", err)
+	}
+	return nil
+}
+
+func (a *Agent) takeSnapshot(ctx context.Context) (string, int64, error) {
+	snap, err := a.snapshot()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer snap.Close()
+
+	name := fmt.Sprintf("%s-%d-%d%s", a.config.FilePrefix, a.now().UnixMilli(), snap.Index(), snapshotExt)
+	cr := &countingReader{r: snap}
+	if err := a.store.Put(ctx, name, cr); err != nil {
+		return "", 0, fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return name, cr.n, nil
+}
+
+// prune deletes the snapshots that exceed the retention count or age. Only
+// snapshots named with the agent's file prefix are considered.
+func (a *Agent) prune(ctx context.Context) error {
+	if a.config.Retain <= 0 && a.config.RetainAge <= 0 {
+		return nil
+	}
+
+	all, err := a.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	var snaps []SnapshotInfo
+	for _, snap := range all {
+		if strings.HasPrefix(snap.Name, a.config.FilePrefix+"-") {
+			snaps = append(snaps, snap)
+		}
+	}
+	sortByCreated(snaps)
+
+	cutoff := a.now().Add(-a.config.RetainAge)
+	for i, snap := range snaps {
+		tooMany := a.config.Retain > 0 && i >= a.config.Retain
+		tooOld := a.config.RetainAge > 0 && snap.Created.Before(cutoff)
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := a.store.Delete(ctx, snap.Name); err != nil {
+			return err
+		}
+		a.logger.Debug("deleted old snapshot", "name", snap.Name)
+	}
+	return nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshotagent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+type testSnapshot struct {
+	*bytes.Reader
+	index uint64
+}
+
+func (s *testSnapshot) Index() uint64 { return s.index }
+func (s *testSnapshot) Close() error  { return nil }
+
+// testAgent returns an agent storing snapshots in a temporary directory, with
+// a clock that advances by a minute on each snapshot.
+func testAgent(t *testing.T, config Config) (*Agent, *LocalStore) {
+	t.Helper()
+
+	store, err := NewLocalStore(filepath.Join(t.TempDir(), "snapshots"))
+	require.NoError(t, err)
+
+	var index uint64
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := New(config, store, func() (Snapshot, error) {
+		index++
+		now = now.Add(time.Minute)
+		return &testSnapshot{Reader: bytes.NewReader([]byte("snapshot data")), index: index}, nil
+	}, hclog.NewNullLogger())
+	a.now = func() time.Time { return now }
+	return a, store
+}
+
+func listNames(t *testing.T, store SnapshotStore) []string {
+	t.Helper()
+	snaps, err := store.List(context.Background())
+	require.NoError(t, err)
+	var names []string
+	for _, snap := range snaps {
+		names = append(names, snap.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setCreated sets the creation time of the named snapshot files, as the local
+// store reports modification times.
+func setCreated(t *testing.T, store *LocalStore, created map[string]time.Time) {
+	t.Helper()
+	for name, ts := range created {
+		require.NoError(t, os.Chtimes(filepath.Join(store.dir, name), ts, ts))
+	}
+}
+
+func TestAgent_SnapshotOnce(t *testing.T) {
+	a, store := testAgent(t, Config{FilePrefix: "consul"})
+	ctx := context.Background()
+
+	require.NoError(t, a.SnapshotOnce(ctx))
+	require.NoError(t, a.SnapshotOnce(ctx))
+
+	require.Equal(t, []string{
+		"consul-1672531260000-1.snap",
+		"consul-1672531320000-2.snap",
+	}, listNames(t, store))
+
+	data, err := os.ReadFile(filepath.Join(store.dir, "consul-1672531260000-1.snap"))
+	require.NoError(t, err)
+	require.Equal(t, "snapshot data", string(data))
+}
+
+func TestAgent_SnapshotOnce_Error(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	a := New(Config{FilePrefix: "consul"}, store, func() (Snapshot, error) {
+		return nil, errors.New("no leader")
+	}, hclog.NewNullLogger())
+
+	require.EqualError(t, a.SnapshotOnce(context.Background()), "failed to create snapshot: no leader")
+	require.Empty(t, listNames(t, store))
+}
+
+func TestAgent_RetainCount(t *testing.T) {
+	a, store := testAgent(t, Config{FilePrefix: "consul", Retain: 2})
+	ctx := context.Background()
+
+	// Files that don't belong to the agent are left alone.
+	require.NoError(t, store.Put(ctx, "other-1.snap", bytes.NewReader(nil)))
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, a.SnapshotOnce(ctx))
+	}
+
+	require.Equal(t, []string{
+		"consul-1672531380000-3.snap",
+		"consul-1672531440000-4.snap",
+		"other-1.snap",
+	}, listNames(t, store))
+}
+
+func TestAgent_RetainAge(t *testing.T) {
+	a, store := testAgent(t, Config{FilePrefix: "consul", RetainAge: time.Hour})
+	ctx := context.Background()
+
+	require.NoError(t, a.SnapshotOnce(ctx))
+	require.NoError(t, a.SnapshotOnce(ctx))
+
+	old := "consul-1672531260000-1.snap"
+	setCreated(t, store, map[string]time.Time{
+		old: a.now().Add(-2 * time.Hour),
+	})
+
+	require.NoError(t, a.SnapshotOnce(ctx))
+	require.Equal(t, []string{
+		"consul-1672531320000-2.snap",
+		"consul-1672531380000-3.snap",
+	}, listNames(t, store))
+}
+
+func TestAgent_Run(t *testing.T) {
+	a, store := testAgent(t, Config{FilePrefix: "consul", Interval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- a.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return len(listNames(t, store)) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent did not stop")
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(filepath.Join(t.TempDir(), "a", "b"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "one.snap", bytes.NewReader([]byte("12345"))))
+	require.NoError(t, os.WriteFile(filepath.Join(store.dir, "notes.txt"), nil, 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(store.dir, "dir.snap"), 0700))
+
+	snaps, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, snaps, 1)
+	require.Equal(t, "one.snap", snaps[0].Name)
+	require.Equal(t, int64(5), snaps[0].Size)
+
+	require.NoError(t, store.Delete(ctx, "one.snap"))
+	require.NoError(t, store.Delete(ctx, "one.snap"))
+	require.Empty(t, listNames(t, store))
+
+	require.Error(t, store.Put(ctx, "../escape.snap", bytes.NewReader(nil)))
+	require.Error(t, store.Delete(ctx, "a/b.snap"))
+
+	// A failed write doesn't leave a partial snapshot behind.
+	err = store.Put(ctx, "partial.snap", io.MultiReader(bytes.NewReader([]byte("1")), errReader{}))
+	require.Error(t, err)
+	require.Empty(t, listNames(t, store))
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("boom") }
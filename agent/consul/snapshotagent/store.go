@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshotagent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rboyer/safeio"
+)
+
+// SnapshotStore is where the snapshot agent keeps the snapshots it takes.
+// Implementations must be safe to use from a single goroutine at a time.
+type SnapshotStore interface {
+	// Put stores a snapshot archive under the given name. The snapshot must
+	// not be visible to List until it has been completely written.
+	Put(ctx context.Context, name string, r io.Reader) error
+
+	// List returns the snapshots in the store, in no particular order.
+	List(ctx context.Context) ([]SnapshotInfo, error)
+
+	// Delete removes the named snapshot from the store.
+	Delete(ctx context.Context, name string) error
+}
+
+// SnapshotInfo describes a snapshot held in a SnapshotStore.
+type SnapshotInfo struct {
+	Name    string
+	Size    int64
+	Created time.Time
+}
+
+// sortByCreated sorts snapshots from newest to oldest.
+func sortByCreated(snaps []SnapshotInfo) {
+	sort.Slice(snaps, func(i, j int) bool {
+		if snaps[i].Created.Equal(snaps[j].Created) {
+			return snaps[i].Name > snaps[j].Name
+		}
+		return snaps[i].Created.After(snaps[j].Created)
+	})
+}
+
+// snapshotExt is the extension of the snapshot files written by the agent.
+const snapshotExt = ".snap"
+
+// LocalStore is a SnapshotStore that keeps snapshots as files in a directory
+// on the server's local filesystem.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore returns a LocalStore for the given directory, creating it if
+// needed.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put implements SnapshotStore. The file is written atomically so a partial
+// snapshot is never left behind.
+func (s *LocalStore) Put(_ context.Context, name string, r io.Reader) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if _, err := safeio.WriteToFile(r, path, 0600); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	return nil
+}
+
+// List implements SnapshotStore. Only files with a ".snap" extension are
+// included, and their modification time is used as their creation time.
+func (s *LocalStore) List(_ context.Context) ([]SnapshotInfo, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot directory: %w", err)
+	}
+
+	var snaps []SnapshotInfo
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), snapshotExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat snapshot file: %w", err)
+		}
+		snaps = append(snaps, SnapshotInfo{
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			Created: info.ModTime(),
+		})
+	}
+	return snaps, nil
+}
+
+// Delete implements SnapshotStore.
+func (s *LocalStore) Delete(_ context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove snapshot file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return filepath.Join(s.dir, name), nil
+}
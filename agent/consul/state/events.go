@@ -6,6 +6,7 @@ package state
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/consul/stream"
@@ -51,6 +52,14 @@ func PBToStreamSubscribeRequest(req *pbsubscribe.SubscribeRequest, entMeta acl.E
 				Name:           named.Key,
 				EnterpriseMeta: &entMeta,
 			}
+		case EventTopicKV:
+			if !strings.HasSuffix(named.Key, "/") {
+				return nil, fmt.Errorf("topic %s requires a key prefix ending in %q, or WildcardSubject", EventTopicKV, "/")
+			}
+			subject = EventSubjectKV{
+				Prefix:         named.Key,
+				EnterpriseMeta: entMeta,
+			}
 		case EventTopicServiceList:
 			// Events on this topic are published to SubjectNone, but rather than
 			// exposing this in (and further complicating) the streaming API we rely
@@ -152,6 +152,44 @@ func TestPBToStreamSubscribeRequest(t *testing.T) {
 			expectedSubscribeRequest: nil,
 			err:                      fmt.Errorf("topic %s can only be consumed using WildcardSubject", EventTopicServiceList),
 		},
+		"KV prefix": {
+			req: &pbsubscribe.SubscribeRequest{
+				Topic: EventTopicKV,
+				Subject: &pbsubscribe.SubscribeRequest_NamedSubject{
+					NamedSubject: &pbsubscribe.NamedSubject{
+						Key:       "config/",
+						Namespace: "consul",
+						Partition: "partition",
+					},
+				},
+				Token: aclToken,
+				Index: 2,
+			},
+			entMeta: acl.EnterpriseMeta{},
+			expectedSubscribeRequest: &stream.SubscribeRequest{
+				Topic: EventTopicKV,
+				Subject: EventSubjectKV{
+					Prefix:         "config/",
+					EnterpriseMeta: acl.EnterpriseMeta{},
+				},
+				Token: aclToken,
+				Index: 2,
+			},
+			err: nil,
+		},
+		"KV prefix without trailing slash returns error": {
+			req: &pbsubscribe.SubscribeRequest{
+				Topic: EventTopicKV,
+				Subject: &pbsubscribe.SubscribeRequest_NamedSubject{
+					NamedSubject: &pbsubscribe.NamedSubject{
+						Key: "config",
+					},
+				},
+			},
+			entMeta:                  acl.EnterpriseMeta{},
+			expectedSubscribeRequest: nil,
+			err:                      fmt.Errorf(`topic %s requires a key prefix ending in "/", or WildcardSubject`, EventTopicKV),
+		},
 		"Unrecognized topic returns error": {
 			req: &pbsubscribe.SubscribeRequest{
 				Topic: 99999,
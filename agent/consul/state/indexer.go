@@ -161,6 +161,14 @@ func (b *indexBuilder) Int64(v int64) {
 	b.Raw(buf)
 }
 
+// Uint64 appends the big-endian encoding of v, so that the index is ordered
+// by value.
+func (b *indexBuilder) Uint64(v uint64) {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, v)
+	b.Raw(buf)
+}
+
 // Raw appends the bytes without a null terminator to the buffer. Raw should
 // only be used when v has a fixed length, or when building the last segment of
 // a prefix index.
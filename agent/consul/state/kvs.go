@@ -4,6 +4,7 @@
 package state
 
 import (
+	"bytes"
 	"fmt"
 	"time"
 
@@ -18,6 +19,7 @@ const (
 	tableTombstones = "tombstones"
 
 	indexSession = "session"
+	indexExpires = "expires"
 )
 
 // kvsTableSchema returns a new table schema used for storing structs.DirEntry
@@ -39,10 +41,32 @@ func kvsTableSchema() *memdb.TableSchema {
 					Field: "Session",
 				},
 			},
+			indexExpires: {
+				Name:         indexExpires,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerSingle[*TimeQuery, *structs.DirEntry]{
+					readIndex:  indexFromTimeQuery,
+					writeIndex: indexExpiresFromDirEntry,
+				},
+			},
 		},
 	}
 }
 
+func indexExpiresFromDirEntry(e *structs.DirEntry) ([]byte, error) {
+	if e.Expires == nil {
+		return nil, errMissingValueForIndex
+	}
+	if e.Expires.Unix() < 0 {
+		return nil, fmt.Errorf("kv expiration time cannot be before the unix epoch: %s", e.Expires)
+	}
+
+	var b indexBuilder
+	b.Time(*e.Expires)
+	return b.Bytes(), nil
+}
+
 // indexFromIDValue creates an index key from any struct that implements singleValueID
 func indexFromIDValue(e singleValueID) ([]byte, error) {
 	v := e.IDValue()
@@ -224,6 +248,24 @@ func (s *Store) KVSList(ws memdb.WatchSet,
 	return s.kvsListTxn(tx, ws, prefix, *entMeta)
 }
 
+// KVSListExpiring returns the entries of every partition and namespace which
+// have an expiration time, ordered by expiration time.
+func (s *Store) KVSListExpiring() (structs.DirEntries, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	iter, err := tx.Get(tableKVs, indexExpires)
+	if err != nil {
+		return nil, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+
+	var ents structs.DirEntries
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ents = append(ents, raw.(*structs.DirEntry))
+	}
+	return ents, nil
+}
+
 // kvsListTxn is the inner method that gets a list of KVS entries matching a
 // prefix.
 func (s *Store) kvsListTxn(tx ReadTxn,
@@ -548,6 +590,32 @@ func kvsCheckSessionTxn(tx WriteTxn,
 	return e, nil
 }
 
+// kvsCheckValueTxn checks to see if the given value matches the value of the
+// current entry for a key. If prefix is set, the current value only needs to
+// start with the given value.
+func kvsCheckValueTxn(tx ReadTxn,
+	key string, value []byte, prefix bool, entMeta acl.EnterpriseMeta) (*structs.DirEntry, error) {
+
+	entry, err := tx.First(tableKVs, indexID, Query{Value: key, EnterpriseMeta: entMeta})
+	if err != nil {
+		return nil, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("failed to check value, key %q doesn't exist", key)
+	}
+
+	e := entry.(*structs.DirEntry)
+	if prefix {
+		if !bytes.HasPrefix(e.Value, value) {
+			return nil, fmt.Errorf("failed value check for key %q, current value doesn't start with the given prefix", key)
+		}
+	} else if !bytes.Equal(e.Value, value) {
+		return nil, fmt.Errorf("failed value check for key %q, current value doesn't match", key)
+	}
+
+	return e, nil
+}
+
 // kvsCheckIndexTxn checks to see if the given modify index matches the current
 // entry for a key.
 func kvsCheckIndexTxn(tx WriteTxn,
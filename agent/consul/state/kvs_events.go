@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/stream"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/private/pbsubscribe"
+)
+
+// EventSubjectKV is a stream.Subject used to route and receive events for all
+// of the keys under a prefix. Events are only published to prefixes that end
+// at a "/" boundary, so subscribers must use a Prefix ending in "/" (or
+// subscribe using stream.SubjectWildcard to receive events for every key).
+type EventSubjectKV struct {
+	Prefix         string
+	EnterpriseMeta acl.EnterpriseMeta
+}
+
+func (s EventSubjectKV) String() string {
+	return fmt.Sprintf(
+		"%s/%s/%s",
+		s.EnterpriseMeta.PartitionOrDefault(),
+		s.EnterpriseMeta.NamespaceOrDefault(),
+		s.Prefix,
+	)
+}
+
+// EventPayloadKV is used as the Payload for a stream.Event to indicate changes
+// to a KV entry.
+//
+// EventPayloadKV implements stream.MultiSubjectPayload so that a change to a
+// key is delivered to the subscribers of every "/"-delimited prefix of it.
+type EventPayloadKV struct {
+	Op    pbsubscribe.KVUpdate_UpdateOp
+	Value *structs.DirEntry
+}
+
+// Subject is required to satisfy the stream.Payload interface, but events are
+// routed using Subjects instead.
+func (e EventPayloadKV) Subject() stream.Subject {
+	return EventSubjectKV{
+		Prefix:         e.Value.Key,
+		EnterpriseMeta: e.Value.EnterpriseMeta,
+	}
+}
+
+// Subjects returns a subject for every prefix of the key that ends in "/"
+// (e.g. "a/b/c" is published to "a/" and "a/b/").
+func (e EventPayloadKV) Subjects() []stream.Subject {
+	var subjects []stream.Subject
+	key := e.Value.Key
+	for i := 0; i < len(key); i++ {
+		if key[i] != '/' {
+			continue
+		}
+		subjects = append(subjects, EventSubjectKV{
+			Prefix:         key[:i+1],
+			EnterpriseMeta: e.Value.EnterpriseMeta,
+		})
+	}
+	return subjects
+}
+
+func (e EventPayloadKV) HasReadPermission(authz acl.Authorizer) bool {
+	var authzContext acl.AuthorizerContext
+	e.Value.FillAuthzContext(&authzContext)
+	return authz.KeyRead(e.Value.Key, &authzContext) == acl.Allow
+}
+
+func (e EventPayloadKV) ToSubscriptionEvent(idx uint64) *pbsubscribe.Event {
+	return &pbsubscribe.Event{
+		Index: idx,
+		Payload: &pbsubscribe.Event_KV{
+			KV: pbsubscribe.NewKVUpdateFromStructs(e.Op, e.Value),
+		},
+	}
+}
+
+// KVEventsFromChanges returns events that will be emitted when entries in the
+// KV store change.
+func KVEventsFromChanges(_ ReadTxn, changes Changes) ([]stream.Event, error) {
+	var events []stream.Event
+	for _, c := range changes.Changes {
+		if c.Table != tableKVs {
+			continue
+		}
+
+		op := pbsubscribe.KVUpdate_Upsert
+		if c.Deleted() {
+			op = pbsubscribe.KVUpdate_Delete
+		}
+		events = append(events, kvEvent(changes.Index, op, changeObject(c).(*structs.DirEntry)))
+	}
+	return events, nil
+}
+
+// KVSnapshot is a stream.SnapshotFunc that returns a snapshot of the KV
+// entries under the subscription's prefix.
+func (s *Store) KVSnapshot(req stream.SubscribeRequest, buf stream.SnapshotAppender) (uint64, error) {
+	var (
+		prefix  string
+		entMeta *acl.EnterpriseMeta
+	)
+	if subject, ok := req.Subject.(EventSubjectKV); ok {
+		prefix = subject.Prefix
+		entMeta = &subject.EnterpriseMeta
+	} else if req.Subject == stream.SubjectWildcard {
+		entMeta = structs.WildcardEnterpriseMetaInPartition(structs.WildcardSpecifier)
+	} else {
+		return 0, fmt.Errorf("subject must be of type EventSubjectKV or be SubjectWildcard, was: %T", req.Subject)
+	}
+
+	idx, entries, err := s.KVSList(nil, prefix, entMeta)
+	if err != nil {
+		return 0, err
+	}
+
+	if l := len(entries); l != 0 {
+		events := make([]stream.Event, l)
+		for i, e := range entries {
+			events[i] = kvEvent(idx, pbsubscribe.KVUpdate_Upsert, e)
+		}
+		buf.Append(events)
+	}
+
+	return idx, nil
+}
+
+func kvEvent(idx uint64, op pbsubscribe.KVUpdate_UpdateOp, entry *structs.DirEntry) stream.Event {
+	return stream.Event{
+		Topic: EventTopicKV,
+		Index: idx,
+		Payload: EventPayloadKV{
+			Op:    op,
+			Value: entry,
+		},
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/stream"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/private/pbsubscribe"
+)
+
+func TestKVEventsFromChanges(t *testing.T) {
+	const changeIndex uint64 = 123
+
+	testCases := map[string]struct {
+		setup  func(tx *txn) error
+		mutate func(s *Store, tx *txn) error
+		events []stream.Event
+	}{
+		"upsert key": {
+			mutate: func(_ *Store, tx *txn) error {
+				return kvsSetTxn(tx, changeIndex, &structs.DirEntry{Key: "config/web", Value: []byte("foo")}, false)
+			},
+			events: []stream.Event{
+				{
+					Topic: EventTopicKV,
+					Index: changeIndex,
+					Payload: EventPayloadKV{
+						Op: pbsubscribe.KVUpdate_Upsert,
+						Value: &structs.DirEntry{
+							Key:       "config/web",
+							Value:     []byte("foo"),
+							RaftIndex: structs.RaftIndex{CreateIndex: changeIndex, ModifyIndex: changeIndex},
+						},
+					},
+				},
+			},
+		},
+		"delete key": {
+			setup: func(tx *txn) error {
+				return kvsSetTxn(tx, 1, &structs.DirEntry{Key: "config/web", Value: []byte("foo")}, false)
+			},
+			mutate: func(s *Store, tx *txn) error {
+				return s.kvsDeleteTxn(tx, changeIndex, "config/web", nil)
+			},
+			events: []stream.Event{
+				{
+					Topic: EventTopicKV,
+					Index: changeIndex,
+					Payload: EventPayloadKV{
+						Op: pbsubscribe.KVUpdate_Delete,
+						Value: &structs.DirEntry{
+							Key:       "config/web",
+							Value:     []byte("foo"),
+							RaftIndex: structs.RaftIndex{CreateIndex: 1, ModifyIndex: 1},
+						},
+					},
+				},
+			},
+		},
+		"unrelated change": {
+			mutate: func(_ *Store, tx *txn) error {
+				return ensureConfigEntryTxn(tx, changeIndex, false, &structs.MeshConfigEntry{})
+			},
+			events: nil,
+		},
+	}
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			store := testStateStore(t)
+
+			if tc.setup != nil {
+				tx := store.db.WriteTxn(0)
+				require.NoError(t, tc.setup(tx))
+				require.NoError(t, tx.Commit())
+			}
+
+			tx := store.db.WriteTxn(0)
+			t.Cleanup(tx.Abort)
+
+			if tc.mutate != nil {
+				require.NoError(t, tc.mutate(store, tx))
+			}
+
+			events, err := KVEventsFromChanges(tx, Changes{Index: changeIndex, Changes: tx.Changes()})
+			require.NoError(t, err)
+			require.Equal(t, tc.events, events)
+		})
+	}
+}
+
+func TestEventPayloadKV_Subjects(t *testing.T) {
+	testCases := map[string][]stream.Subject{
+		"foo":          nil,
+		"foo/":         {EventSubjectKV{Prefix: "foo/"}},
+		"config/web/1": {EventSubjectKV{Prefix: "config/"}, EventSubjectKV{Prefix: "config/web/"}},
+		"/leading":     {EventSubjectKV{Prefix: "/"}},
+		"a//b":         {EventSubjectKV{Prefix: "a/"}, EventSubjectKV{Prefix: "a//"}},
+	}
+	for key, expected := range testCases {
+		t.Run(key, func(t *testing.T) {
+			payload := EventPayloadKV{Value: &structs.DirEntry{Key: key}}
+			require.Equal(t, expected, payload.Subjects())
+		})
+	}
+}
+
+func TestEventPayloadKV_HasReadPermission(t *testing.T) {
+	payload := EventPayloadKV{Value: &structs.DirEntry{Key: "config/web"}}
+
+	require.True(t, payload.HasReadPermission(acl.AllowAll()))
+	require.False(t, payload.HasReadPermission(acl.DenyAll()))
+}
+
+func TestKVSnapshot(t *testing.T) {
+	store := testStateStore(t)
+	require.NoError(t, store.KVSSet(10, &structs.DirEntry{Key: "config/web/port", Value: []byte("8080")}))
+	require.NoError(t, store.KVSSet(11, &structs.DirEntry{Key: "config/db/port", Value: []byte("5432")}))
+	require.NoError(t, store.KVSSet(12, &structs.DirEntry{Key: "other", Value: []byte("bar")}))
+
+	testCases := map[string]struct {
+		subject stream.Subject
+		index   uint64
+		keys    []string
+	}{
+		"prefix": {
+			subject: EventSubjectKV{Prefix: "config/web/"},
+			index:   10,
+			keys:    []string{"config/web/port"},
+		},
+		"wider prefix": {
+			subject: EventSubjectKV{Prefix: "config/"},
+			index:   11,
+			keys:    []string{"config/db/port", "config/web/port"},
+		},
+		"no matches": {
+			subject: EventSubjectKV{Prefix: "missing/"},
+			index:   12,
+		},
+		"wildcard": {
+			subject: stream.SubjectWildcard,
+			index:   12,
+			keys:    []string{"config/db/port", "config/web/port", "other"},
+		},
+	}
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			buf := &snapshotAppender{}
+
+			idx, err := store.KVSnapshot(stream.SubscribeRequest{Subject: tc.subject}, buf)
+			require.NoError(t, err)
+			require.Equal(t, tc.index, idx)
+
+			if len(tc.keys) == 0 {
+				require.Empty(t, buf.events)
+				return
+			}
+
+			require.Len(t, buf.events, 1)
+			var keys []string
+			for _, event := range buf.events[0] {
+				require.Equal(t, EventTopicKV, event.Topic)
+				require.Equal(t, tc.index, event.Index)
+
+				payload := event.Payload.(EventPayloadKV)
+				require.Equal(t, pbsubscribe.KVUpdate_Upsert, payload.Op)
+				keys = append(keys, payload.Value.Key)
+			}
+			require.Equal(t, tc.keys, keys)
+		})
+	}
+}
+
+func TestKVSnapshot_InvalidSubject(t *testing.T) {
+	store := testStateStore(t)
+
+	_, err := store.KVSnapshot(stream.SubscribeRequest{Subject: stream.SubjectNone}, &snapshotAppender{})
+	require.Error(t, err)
+}
@@ -0,0 +1,371 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const tableKVsRevisions = "kvs-revisions"
+
+// KVHistoryConfig holds the local state used to bound the history of the KV
+// entries kept by the state store. The max number of revisions of each key
+// is replicated as the structs.SystemMetadataKVHistoryMaxRevisions system
+// metadata, so that every server records and trims the history the same way.
+type KVHistoryConfig struct {
+	// GC is hinted the index of the revisions so that the leader can reap the
+	// revisions older than their max age. It is nil when revisions are only
+	// bounded by their number.
+	GC *TombstoneGC
+}
+
+// kvsRevisionsTableSchema returns a new table schema used for storing the
+// history of the KV entries as structs.DirEntryRevision.
+func kvsRevisionsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: tableKVsRevisions,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer:      kvsRevisionsIndexer(),
+			},
+		},
+	}
+}
+
+// KVRevisionQuery is used to lookup the revision of a key written at a given
+// index.
+type KVRevisionQuery struct {
+	Key   string
+	Index uint64
+	acl.EnterpriseMeta
+}
+
+// SetKVHistoryConfig configures the local state of the history of the KV
+// entries. It must be called before the store is used.
+func (s *Store) SetKVHistoryConfig(cfg KVHistoryConfig) {
+	s.db.kvHistory = cfg
+}
+
+// kvsHistoryTxn returns the max number of revisions kept for each key,
+// including its current version, and the index the history was enabled at.
+// The history is disabled when the max number of revisions is zero.
+func kvsHistoryTxn(tx ReadTxn) (int, uint64, error) {
+	_, entry, err := systemMetadataGetTxn(tx, nil, structs.SystemMetadataKVHistoryMaxRevisions)
+	if err != nil {
+		return 0, 0, err
+	}
+	if entry == nil {
+		return 0, 0, nil
+	}
+	max, err := strconv.Atoi(entry.Value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid kv history max revisions %q: %v", entry.Value, err)
+	}
+	return max, entry.CreateIndex, nil
+}
+
+// updateKVSHistory records a revision for every KV entry written or deleted
+// by the transaction, and drops the revisions of those keys exceeding the
+// max number of revisions. All the revisions are trimmed when the max number
+// of revisions is changed.
+func updateKVSHistory(tx *txn, changes Changes) error {
+	// WriteTxnRestore transactions restore the history from the snapshot.
+	if tx.kvHistory == nil {
+		return nil
+	}
+
+	var kvsChanged, maxChanged bool
+	for _, change := range changes.Changes {
+		switch change.Table {
+		case tableKVs:
+			kvsChanged = true
+		case tableSystemMetadata:
+			entry := change.After
+			if change.Deleted() {
+				entry = change.Before
+			}
+			if entry.(*structs.SystemMetadataEntry).Key == structs.SystemMetadataKVHistoryMaxRevisions {
+				maxChanged = true
+			}
+		}
+	}
+	if !kvsChanged && !maxChanged {
+		return nil
+	}
+
+	max, _, err := kvsHistoryTxn(tx)
+	if err != nil {
+		return err
+	}
+
+	var recorded bool
+	for _, change := range changes.Changes {
+		if change.Table != tableKVs || max <= 0 {
+			continue
+		}
+
+		var rev *structs.DirEntryRevision
+		if change.Deleted() {
+			before := change.Before.(*structs.DirEntry)
+			rev = &structs.DirEntryRevision{
+				DirEntry: structs.DirEntry{
+					Key:            before.Key,
+					EnterpriseMeta: before.EnterpriseMeta,
+					RaftIndex: structs.RaftIndex{
+						CreateIndex: before.CreateIndex,
+						ModifyIndex: changes.Index,
+					},
+				},
+				Deleted: true,
+			}
+		} else {
+			rev = &structs.DirEntryRevision{DirEntry: *change.After.(*structs.DirEntry)}
+		}
+
+		if err := tx.Insert(tableKVsRevisions, rev); err != nil {
+			return fmt.Errorf("failed inserting kvs revision: %s", err)
+		}
+		revs, err := kvsRevisionsListTxn(tx, nil, rev.Key, rev.EnterpriseMeta)
+		if err != nil {
+			return err
+		}
+		if err := kvsRevisionsDropTxn(tx, revs, len(revs)-max); err != nil {
+			return err
+		}
+		recorded = true
+	}
+
+	if maxChanged {
+		groups, err := kvsRevisionsByKeyTxn(tx)
+		if err != nil {
+			return err
+		}
+		for _, revs := range groups {
+			if len(revs) > max {
+				if err := kvsRevisionsDropTxn(tx, revs, len(revs)-max); err != nil {
+					return err
+				}
+				recorded = true
+			}
+		}
+	}
+
+	if !recorded {
+		return nil
+	}
+	if err := tx.Insert(tableIndex, &IndexEntry{tableKVsRevisions, changes.Index}); err != nil {
+		return fmt.Errorf("failed updating kvs revisions index: %s", err)
+	}
+	if tx.kvHistory.GC != nil {
+		tx.kvHistory.GC.Hint(changes.Index)
+	}
+	return nil
+}
+
+// kvsRevisionsDropTxn deletes the n oldest of the given revisions of a key,
+// and flags the oldest revision left as truncated.
+func kvsRevisionsDropTxn(tx WriteTxn, revs structs.DirEntryRevisions, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	if n > len(revs) {
+		n = len(revs)
+	}
+	for _, rev := range revs[:n] {
+		if err := tx.Delete(tableKVsRevisions, rev); err != nil {
+			return fmt.Errorf("failed deleting kvs revision: %s", err)
+		}
+	}
+	if n == len(revs) || revs[n].Truncated {
+		return nil
+	}
+
+	// Copy the revision before modifying it, it's owned by the memdb.
+	oldest := *revs[n]
+	oldest.Truncated = true
+	if err := tx.Insert(tableKVsRevisions, &oldest); err != nil {
+		return fmt.Errorf("failed updating kvs revision: %s", err)
+	}
+	return nil
+}
+
+// kvsRevisionsByKeyTxn returns all the revisions of the history, grouped by
+// key and oldest first.
+func kvsRevisionsByKeyTxn(tx ReadTxn) ([]structs.DirEntryRevisions, error) {
+	iter, err := tx.Get(tableKVsRevisions, indexID+"_prefix", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed kvs revisions lookup: %s", err)
+	}
+
+	var groups []structs.DirEntryRevisions
+	var last *structs.DirEntryRevision
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rev := raw.(*structs.DirEntryRevision)
+		if last == nil || last.Key != rev.Key || !last.EnterpriseMeta.IsSame(&rev.EnterpriseMeta) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], rev)
+		last = rev
+	}
+	return groups, nil
+}
+
+// kvsRevisionsListTxn returns the revisions of a key, oldest first.
+func kvsRevisionsListTxn(tx ReadTxn, ws memdb.WatchSet, key string, entMeta acl.EnterpriseMeta) (structs.DirEntryRevisions, error) {
+	iter, err := tx.Get(tableKVsRevisions, indexID+"_prefix", Query{Value: key, EnterpriseMeta: entMeta})
+	if err != nil {
+		return nil, fmt.Errorf("failed kvs revisions lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var revs structs.DirEntryRevisions
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		revs = append(revs, raw.(*structs.DirEntryRevision))
+	}
+	return revs, nil
+}
+
+// KVSRevisions returns the revisions of a key in the history, newest first.
+func (s *Store) KVSRevisions(ws memdb.WatchSet, key string, entMeta *acl.EnterpriseMeta) (uint64, structs.DirEntryRevisions, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	// TODO: accept non-pointer entMeta
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+
+	idx := maxIndexTxn(tx, tableKVsRevisions)
+	revs, err := kvsRevisionsListTxn(tx, ws, key, *entMeta)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i, j := 0, len(revs)-1; i < j; i, j = i+1, j-1 {
+		revs[i], revs[j] = revs[j], revs[i]
+	}
+	return idx, revs, nil
+}
+
+// KVSGetAtIndex returns the KV entry as it was at the given Raft index, or
+// nil if the key didn't exist at that index. Keys last written before the
+// history was enabled are found as long as they weren't modified since.
+// structs.ErrKVSRevisionNotRetained is returned when the revision at that
+// index was dropped from the history, or predates it.
+func (s *Store) KVSGetAtIndex(ws memdb.WatchSet, key string, index uint64, entMeta *acl.EnterpriseMeta) (uint64, *structs.DirEntry, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	// TODO: accept non-pointer entMeta
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+
+	idx, current, err := kvsGetTxn(tx, ws, key, *entMeta)
+	if err != nil {
+		return 0, nil, err
+	}
+	if current != nil && current.ModifyIndex <= index {
+		return idx, current, nil
+	}
+
+	revs, err := kvsRevisionsListTxn(tx, ws, key, *entMeta)
+	if err != nil {
+		return 0, nil, err
+	}
+	var found *structs.DirEntryRevision
+	for _, rev := range revs {
+		if rev.ModifyIndex > index {
+			break
+		}
+		found = rev
+	}
+	if found != nil {
+		if found.Deleted {
+			return idx, nil, nil
+		}
+		return idx, &found.DirEntry, nil
+	}
+
+	// The index is before the oldest revision kept for the key. The key
+	// didn't exist at that index only if the history was already enabled,
+	// and if that revision created the key without older revisions having
+	// been dropped since. Keys whose whole history was dropped after they
+	// were deleted are reported as missing.
+	max, enabledIdx, err := kvsHistoryTxn(tx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if max <= 0 || index < enabledIdx {
+		return 0, nil, structs.ErrKVSRevisionNotRetained
+	}
+	if len(revs) == 0 {
+		if current != nil {
+			return 0, nil, structs.ErrKVSRevisionNotRetained
+		}
+		return idx, nil, nil
+	}
+	oldest := revs[0]
+	if oldest.Truncated || oldest.Deleted || oldest.CreateIndex != oldest.ModifyIndex {
+		return 0, nil, structs.ErrKVSRevisionNotRetained
+	}
+	return idx, nil, nil
+}
+
+// KVSRevisionsReap is used to delete all the revisions with an index less
+// than or equal to the given index. This is used to bound the age of the
+// revisions kept in the history.
+func (s *Store) KVSRevisionsReap(idx uint64, index uint64) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	groups, err := kvsRevisionsByKeyTxn(tx)
+	if err != nil {
+		return err
+	}
+
+	// Delete the eligible revisions of each key and update the index.
+	var reaped bool
+	for _, revs := range groups {
+		n := 0
+		for n < len(revs) && revs[n].ModifyIndex <= index {
+			n++
+		}
+		if err := kvsRevisionsDropTxn(tx, revs, n); err != nil {
+			return err
+		}
+		reaped = reaped || n > 0
+	}
+	if reaped {
+		if err := tx.Insert(tableIndex, &IndexEntry{tableKVsRevisions, idx}); err != nil {
+			return fmt.Errorf("failed updating kvs revisions index: %s", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// KVRevisions is used to pull the full list of KV revisions for use during
+// snapshots.
+func (s *Snapshot) KVRevisions() (memdb.ResultIterator, error) {
+	return s.tx.Get(tableKVsRevisions, indexID+"_prefix", "")
+}
+
+// KVRevision is used when restoring from a snapshot.
+func (s *Restore) KVRevision(rev *structs.DirEntryRevision) error {
+	if err := s.tx.Insert(tableKVsRevisions, rev); err != nil {
+		return fmt.Errorf("failed restoring kvs revision: %s", err)
+	}
+	if err := indexUpdateMaxTxn(s.tx, rev.ModifyIndex, tableKVsRevisions); err != nil {
+		return fmt.Errorf("failed updating kvs revisions index: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func testStateStoreWithKVHistory(t *testing.T, maxRevisions int) *Store {
+	s := testStateStore(t)
+	testSetKVHistoryMaxRevisions(t, s, 0, maxRevisions)
+	return s
+}
+
+func testSetKVHistoryMaxRevisions(t *testing.T, s *Store, idx uint64, maxRevisions int) {
+	t.Helper()
+	require.NoError(t, s.SystemMetadataSet(idx, &structs.SystemMetadataEntry{
+		Key:   structs.SystemMetadataKVHistoryMaxRevisions,
+		Value: strconv.Itoa(maxRevisions),
+	}))
+}
+
+func TestStateStore_KVSRevisions(t *testing.T) {
+	s := testStateStoreWithKVHistory(t, 3)
+
+	// An unknown key has no history.
+	ws := memdb.NewWatchSet()
+	idx, revs, err := s.KVSRevisions(ws, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), idx)
+	require.Empty(t, revs)
+
+	testSetKey(t, s, 1, "foo", "one", nil)
+	require.True(t, watchFired(ws))
+
+	// Keys sharing a prefix have their own history.
+	testSetKey(t, s, 2, "foobar", "other", nil)
+	testSetKey(t, s, 3, "foo", "two", nil)
+	require.NoError(t, s.KVSDelete(4, "foo", nil))
+
+	idx, revs, err = s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), idx)
+	require.Len(t, revs, 3)
+
+	// Revisions are returned newest first, deletes keep the key and indexes.
+	require.True(t, revs[0].Deleted)
+	require.Equal(t, "foo", revs[0].Key)
+	require.Nil(t, revs[0].Value)
+	require.Equal(t, uint64(1), revs[0].CreateIndex)
+	require.Equal(t, uint64(4), revs[0].ModifyIndex)
+	require.False(t, revs[1].Deleted)
+	require.Equal(t, []byte("two"), revs[1].Value)
+	require.Equal(t, uint64(3), revs[1].ModifyIndex)
+	require.Equal(t, []byte("one"), revs[2].Value)
+	require.Equal(t, uint64(1), revs[2].ModifyIndex)
+
+	// The oldest revisions are dropped past the max number of revisions.
+	testSetKey(t, s, 5, "foo", "three", nil)
+	_, revs, err = s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Len(t, revs, 3)
+	require.Equal(t, uint64(5), revs[0].ModifyIndex)
+	require.Equal(t, uint64(3), revs[2].ModifyIndex)
+
+	// Deleting a tree records a revision for each key.
+	require.NoError(t, s.KVSDeleteTree(6, "foo", nil))
+	_, revs, err = s.KVSRevisions(nil, "foobar", nil)
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+	require.True(t, revs[0].Deleted)
+	require.Equal(t, uint64(6), revs[0].ModifyIndex)
+}
+
+func TestStateStore_KVSRevisions_Disabled(t *testing.T) {
+	s := testStateStore(t)
+
+	testSetKey(t, s, 1, "foo", "one", nil)
+	testSetKey(t, s, 2, "foo", "two", nil)
+
+	idx, revs, err := s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), idx)
+	require.Empty(t, revs)
+}
+
+func TestStateStore_KVSGetAtIndex(t *testing.T) {
+	s := testStateStore(t)
+
+	// Keys written before the history is enabled are found as long as they
+	// weren't modified.
+	testSetKey(t, s, 1, "foo", "one", nil)
+	_, _, err := s.KVSGetAtIndex(nil, "foo", 0, nil)
+	require.ErrorIs(t, err, structs.ErrKVSRevisionNotRetained)
+
+	testSetKVHistoryMaxRevisions(t, s, 2, 10)
+	testSetKey(t, s, 3, "foo", "three", nil)
+	require.NoError(t, s.KVSDelete(5, "foo", nil))
+	testSetKey(t, s, 7, "foo", "seven", nil)
+
+	cases := []struct {
+		index       uint64
+		value       string
+		notRetained bool
+	}{
+		{index: 1, notRetained: true},
+		{index: 2, notRetained: true},
+		{index: 3, value: "three"},
+		{index: 4, value: "three"},
+		{index: 5, value: ""},
+		{index: 6, value: ""},
+		{index: 7, value: "seven"},
+		{index: 100, value: "seven"},
+	}
+	for _, tc := range cases {
+		_, entry, err := s.KVSGetAtIndex(nil, "foo", tc.index, nil)
+		if tc.notRetained {
+			require.ErrorIs(t, err, structs.ErrKVSRevisionNotRetained, "index %d", tc.index)
+			continue
+		}
+		require.NoError(t, err)
+		if tc.value == "" {
+			require.Nil(t, entry, "index %d", tc.index)
+			continue
+		}
+		require.NotNil(t, entry, "index %d", tc.index)
+		require.Equal(t, tc.value, string(entry.Value), "index %d", tc.index)
+	}
+
+	// An unmodified key is found at any index after its last write, and
+	// didn't exist before it was created once the history was enabled.
+	testSetKey(t, s, 8, "bar", "eight", nil)
+	_, entry, err := s.KVSGetAtIndex(nil, "bar", 9, nil)
+	require.NoError(t, err)
+	require.Equal(t, "eight", string(entry.Value))
+	_, entry, err = s.KVSGetAtIndex(nil, "bar", 6, nil)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+
+	// Unknown keys didn't exist since the history was enabled.
+	_, entry, err = s.KVSGetAtIndex(nil, "baz", 6, nil)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+	_, _, err = s.KVSGetAtIndex(nil, "baz", 1, nil)
+	require.ErrorIs(t, err, structs.ErrKVSRevisionNotRetained)
+}
+
+func TestStateStore_KVSGetAtIndex_Truncated(t *testing.T) {
+	s := testStateStoreWithKVHistory(t, 2)
+
+	testSetKey(t, s, 1, "foo", "one", nil)
+	testSetKey(t, s, 2, "foo", "two", nil)
+	testSetKey(t, s, 3, "foo", "three", nil)
+
+	// The first revision was dropped, so the key is no longer known to not
+	// exist before it.
+	_, revs, err := s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+	require.True(t, revs[1].Truncated)
+	require.False(t, revs[0].Truncated)
+
+	_, entry, err := s.KVSGetAtIndex(nil, "foo", 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, "two", string(entry.Value))
+	for _, index := range []uint64{0, 1} {
+		_, _, err := s.KVSGetAtIndex(nil, "foo", index, nil)
+		require.ErrorIs(t, err, structs.ErrKVSRevisionNotRetained, "index %d", index)
+	}
+}
+
+func TestStateStore_KVSRevisions_MaxRevisionsChanged(t *testing.T) {
+	s := testStateStoreWithKVHistory(t, 10)
+
+	for i, value := range []string{"one", "two", "three"} {
+		testSetKey(t, s, uint64(i+1), "foo", value, nil)
+	}
+	testSetKey(t, s, 4, "bar", "four", nil)
+
+	// Lowering the max number of revisions trims the history of every key.
+	testSetKVHistoryMaxRevisions(t, s, 5, 2)
+	idx, revs, err := s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), idx)
+	require.Len(t, revs, 2)
+	require.Equal(t, uint64(3), revs[0].ModifyIndex)
+	require.Equal(t, uint64(2), revs[1].ModifyIndex)
+	require.True(t, revs[1].Truncated)
+
+	_, revs, err = s.KVSRevisions(nil, "bar", nil)
+	require.NoError(t, err)
+	require.Len(t, revs, 1)
+	require.False(t, revs[0].Truncated)
+
+	// Disabling the history drops it.
+	require.NoError(t, s.SystemMetadataDelete(6, &structs.SystemMetadataEntry{
+		Key: structs.SystemMetadataKVHistoryMaxRevisions,
+	}))
+	_, revs, err = s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Empty(t, revs)
+
+	testSetKey(t, s, 7, "foo", "seven", nil)
+	_, revs, err = s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Empty(t, revs)
+}
+
+func TestStateStore_KVSRevisionsReap(t *testing.T) {
+	s := testStateStoreWithKVHistory(t, 10)
+
+	testSetKey(t, s, 1, "foo", "one", nil)
+	testSetKey(t, s, 2, "bar", "two", nil)
+	testSetKey(t, s, 3, "foo", "three", nil)
+
+	// Reap the revisions up to index 2.
+	ws := memdb.NewWatchSet()
+	_, _, err := s.KVSRevisions(ws, "foo", nil)
+	require.NoError(t, err)
+	require.NoError(t, s.KVSRevisionsReap(4, 2))
+	require.True(t, watchFired(ws))
+
+	idx, revs, err := s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), idx)
+	require.Len(t, revs, 1)
+	require.Equal(t, uint64(3), revs[0].ModifyIndex)
+	require.True(t, revs[0].Truncated)
+
+	_, revs, err = s.KVSRevisions(nil, "bar", nil)
+	require.NoError(t, err)
+	require.Empty(t, revs)
+
+	// The current entries aren't affected.
+	_, entry, err := s.KVSGet(nil, "bar", nil)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+}
+
+func TestStateStore_KVSRevisions_GCHint(t *testing.T) {
+	gc, err := NewTombstoneGC(time.Hour, time.Second)
+	require.NoError(t, err)
+	gc.SetEnabled(true)
+	defer gc.SetEnabled(false)
+
+	s := testStateStore(t)
+	s.SetKVHistoryConfig(KVHistoryConfig{GC: gc})
+	testSetKVHistoryMaxRevisions(t, s, 0, 10)
+
+	require.False(t, gc.PendingExpiration())
+	testSetKey(t, s, 1, "foo", "one", nil)
+	require.True(t, gc.PendingExpiration())
+}
+
+func TestStateStore_KVRevisions_Snapshot_Restore(t *testing.T) {
+	s := testStateStoreWithKVHistory(t, 10)
+
+	testSetKey(t, s, 1, "foo", "one", nil)
+	testSetKey(t, s, 2, "foo", "two", nil)
+	require.NoError(t, s.KVSDelete(3, "foo", nil))
+
+	// Snapshot the revisions.
+	snap := s.Snapshot()
+	defer snap.Close()
+
+	// Alter the real state store.
+	require.NoError(t, s.KVSRevisionsReap(4, 3))
+
+	// Verify the snapshot.
+	iter, err := snap.KVRevisions()
+	require.NoError(t, err)
+	var dump structs.DirEntryRevisions
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		dump = append(dump, raw.(*structs.DirEntryRevision))
+	}
+	require.Len(t, dump, 3)
+
+	// Restore the values into a new state store.
+	s = testStateStore(t)
+	restore := s.Restore()
+	for _, rev := range dump {
+		require.NoError(t, restore.KVRevision(rev))
+	}
+	require.NoError(t, restore.Commit())
+
+	idx, revs, err := s.KVSRevisions(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), idx)
+	require.Len(t, revs, 3)
+	require.True(t, revs[0].Deleted)
+	require.Equal(t, []byte("one"), revs[2].Value)
+}
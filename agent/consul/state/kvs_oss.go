@@ -43,6 +43,40 @@ func prefixIndexForIDValue(arg interface{}) ([]byte, error) {
 	return nil, fmt.Errorf("unexpected type %T for singleValueID prefix index", arg)
 }
 
+func kvsRevisionsIndexer() indexerSingleWithPrefix[KVRevisionQuery, *structs.DirEntryRevision, any] {
+	return indexerSingleWithPrefix[KVRevisionQuery, *structs.DirEntryRevision, any]{
+		readIndex:   indexFromKVRevisionQuery,
+		writeIndex:  indexFromKVRevision,
+		prefixIndex: prefixIndexForKVRevisions,
+	}
+}
+
+func indexFromKVRevisionQuery(q KVRevisionQuery) ([]byte, error) {
+	var b indexBuilder
+	b.String(q.Key)
+	b.Uint64(q.Index)
+	return b.Bytes(), nil
+}
+
+func indexFromKVRevision(rev *structs.DirEntryRevision) ([]byte, error) {
+	return indexFromKVRevisionQuery(KVRevisionQuery{Key: rev.Key, Index: rev.ModifyIndex})
+}
+
+func prefixIndexForKVRevisions(arg interface{}) ([]byte, error) {
+	switch v := arg.(type) {
+	// Used to iterate over all the revisions
+	case string:
+		return []byte(v), nil
+	// Used to iterate over the revisions of a single key, the null terminator
+	// is kept so that keys sharing a prefix don't match.
+	case Query:
+		var b indexBuilder
+		b.String(v.Value)
+		return b.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unexpected type %T for kvs revisions prefix index", arg)
+}
+
 func insertKVTxn(tx WriteTxn, entry *structs.DirEntry, updateMax bool, _ bool) error {
 	if err := tx.Insert(tableKVs, entry); err != nil {
 		return err
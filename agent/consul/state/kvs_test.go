@@ -488,6 +488,48 @@ func TestStateStore_KVSList(t *testing.T) {
 	}
 }
 
+func TestStateStore_KVSListExpiring(t *testing.T) {
+	s := testStateStore(t)
+
+	// Nothing expires in an empty store.
+	entries, err := s.KVSListExpiring()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	now := time.Now()
+	later, sooner := now.Add(time.Hour), now.Add(time.Minute)
+	require.NoError(t, s.KVSSet(1, &structs.DirEntry{Key: "later", TTL: "1h", Expires: &later}))
+	require.NoError(t, s.KVSSet(2, &structs.DirEntry{Key: "permanent"}))
+	require.NoError(t, s.KVSSet(3, &structs.DirEntry{Key: "sooner", TTL: "1m", Expires: &sooner}))
+
+	// The expiring entries are returned in the order they expire.
+	entries, err = s.KVSListExpiring()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "sooner", entries[0].Key)
+	require.Equal(t, "later", entries[1].Key)
+
+	// Updating an entry with a new expiration time is a write, while clearing
+	// it removes the entry from the index.
+	evenLater := now.Add(2 * time.Hour)
+	require.NoError(t, s.KVSSet(4, &structs.DirEntry{Key: "sooner", TTL: "2h", Expires: &evenLater}))
+	_, entry, err := s.KVSGet(nil, "sooner", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), entry.ModifyIndex)
+	require.NoError(t, s.KVSSet(5, &structs.DirEntry{Key: "later"}))
+
+	entries, err = s.KVSListExpiring()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "sooner", entries[0].Key)
+
+	// Deleted entries no longer expire.
+	require.NoError(t, s.KVSDelete(6, "sooner", nil))
+	entries, err = s.KVSListExpiring()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
 func TestStateStore_KVSDelete(t *testing.T) {
 	s := testStateStore(t)
 
@@ -56,6 +56,7 @@ type changeTrackerDB struct {
 	db             *memdb.MemDB
 	publisher      EventPublisher
 	processChanges func(ReadTxn, Changes) ([]stream.Event, error)
+	kvHistory      KVHistoryConfig
 }
 
 type EventPublisher interface {
@@ -97,6 +98,7 @@ func (c *changeTrackerDB) WriteTxn(idx uint64) *txn {
 		Index:      idx,
 		publish:    c.publisher.Publish,
 		prePublish: c.processChanges,
+		kvHistory:  &c.kvHistory,
 	}
 	t.Txn.TrackChanges()
 	return t
@@ -141,6 +143,10 @@ type txn struct {
 
 	prePublish prePublishFuncType
 
+	// kvHistory is used to record the history of the KV entries, it is
+	// nil for WriteTxnRestore transactions.
+	kvHistory *KVHistoryConfig
+
 	commitLock sync.Mutex
 }
 
@@ -160,6 +166,9 @@ func (tx *txn) Commit() error {
 		if err := updateUsage(tx, changes); err != nil {
 			return err
 		}
+		if err := updateKVSHistory(tx, changes); err != nil {
+			return err
+		}
 	}
 
 	// This lock prevents events from concurrent transactions getting published out of order.
@@ -211,6 +220,7 @@ var (
 	EventTopicBoundAPIGateway      = pbsubscribe.Topic_BoundAPIGateway
 	EventTopicIPRateLimit          = pbsubscribe.Topic_IPRateLimit
 	EventTopicSamenessGroup        = pbsubscribe.Topic_SamenessGroup
+	EventTopicKV                   = pbsubscribe.Topic_KV
 )
 
 func processDBChanges(tx ReadTxn, changes Changes) ([]stream.Event, error) {
@@ -221,6 +231,7 @@ func processDBChanges(tx ReadTxn, changes Changes) ([]stream.Event, error) {
 		ServiceHealthEventsFromChanges,
 		ServiceListUpdateEventsFromChanges,
 		ConfigEntryEventsFromChanges,
+		KVEventsFromChanges,
 		// TODO: add other table handlers here.
 	}
 	for _, fn := range fns {
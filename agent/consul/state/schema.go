@@ -31,6 +31,7 @@ func newDBSchema() *memdb.DBSchema {
 		intentionsTableSchema,
 		kindServiceNameTableSchema,
 		kvsTableSchema,
+		kvsRevisionsTableSchema,
 		meshTopologyTableSchema,
 		nodesTableSchema,
 		peeringTableSchema,
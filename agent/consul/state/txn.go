@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 
+	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 )
@@ -94,6 +95,10 @@ func (s *Store) txnKVS(tx WriteTxn, idx uint64, op *structs.TxnKVOp) (structs.Tx
 			err = fmt.Errorf("key %q exists", op.DirEnt.Key)
 		}
 
+	case api.KVCheckValue, api.KVCheckValuePrefix:
+		prefix := op.Verb == api.KVCheckValuePrefix
+		entry, err = kvsCheckValueTxn(tx, op.DirEnt.Key, op.DirEnt.Value, prefix, op.DirEnt.EnterpriseMeta)
+
 	default:
 		err = fmt.Errorf("unknown KV verb %q", op.Verb)
 	}
@@ -126,13 +131,31 @@ func txnSession(tx WriteTxn, idx uint64, op *structs.TxnSessionOp) error {
 	switch op.Verb {
 	case api.SessionDelete:
 		err = sessionDeleteWithSession(tx, &op.Session, idx)
+		if err != nil {
+			err = fmt.Errorf("failed to delete session: %v", err)
+		}
+	case api.SessionCheckValid:
+		err = sessionCheckValidTxn(tx, op.Session.ID, &op.Session.EnterpriseMeta)
 	default:
 		err = fmt.Errorf("unknown Session verb %q", op.Verb)
 	}
+
+	return err
+}
+
+// sessionCheckValidTxn checks that the given session exists. Sessions are
+// destroyed as soon as they are invalidated, so an existing session is valid.
+func sessionCheckValidTxn(tx ReadTxn, sessionID string, entMeta *acl.EnterpriseMeta) error {
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+	session, err := tx.First(tableSessions, indexID, Query{Value: sessionID, EnterpriseMeta: *entMeta})
 	if err != nil {
-		return fmt.Errorf("failed to delete session: %v", err)
+		return fmt.Errorf("failed session lookup: %s", err)
+	}
+	if session == nil {
+		return fmt.Errorf("session %q doesn't exist", sessionID)
 	}
-
 	return nil
 }
 
@@ -170,7 +193,7 @@ func (s *Store) txnNode(tx WriteTxn, idx uint64, op *structs.TxnNodeOp) (structs
 	}
 
 	switch op.Verb {
-	case api.NodeGet:
+	case api.NodeGet, api.NodeCheckExists:
 		entry, err = getNode()
 		if entry == nil && err == nil {
 			err = fmt.Errorf("node %q doesn't exist", op.Node.Node)
@@ -270,11 +293,52 @@ func (s *Store) txnService(tx WriteTxn, idx uint64, op *structs.TxnServiceOp) (s
 		}
 		return nil, err
 
+	case api.ServiceCheckHealthy:
+		return nil, serviceCheckHealthyTxn(tx, op)
+
 	default:
 		return nil, fmt.Errorf("unknown Service verb %q", op.Verb)
 	}
 }
 
+// serviceCheckHealthyTxn checks that the service of the given operation has at
+// least one instance and that all the node and service checks of its instances
+// are passing. The instances can be narrowed down to a node and a service ID.
+func serviceCheckHealthyTxn(tx ReadTxn, op *structs.TxnServiceOp) error {
+	name := op.Service.Service
+	if name == "" {
+		return fmt.Errorf("service name is required to check its health")
+	}
+
+	_, nodes, err := checkServiceNodesTxn(tx, nil, name, false, &op.Service.EnterpriseMeta, op.Service.PeerName)
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for _, csn := range nodes {
+		if op.Node != "" && csn.Node.Node != op.Node {
+			continue
+		}
+		if op.Service.ID != "" && csn.Service.ID != op.Service.ID {
+			continue
+		}
+		found = true
+		for _, check := range csn.Checks {
+			if check.Status != api.HealthPassing {
+				// The nodes and checks aren't named, the operation only
+				// requires node:read when it names the node itself.
+				return fmt.Errorf("failed health check for service %q, an instance has a check in %s state",
+					name, check.Status)
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("failed health check for service %q, no matching instance exists", name)
+	}
+	return nil
+}
+
 // newTxnResultFromNodeServiceEntry returns a TxnResults with a single result,
 // a copy of entry. The entry is copied to prevent modification of the state
 // store.
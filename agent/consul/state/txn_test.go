@@ -1287,6 +1287,108 @@ func TestStateStore_Txn_KVS_RO_Safety(t *testing.T) {
 	}
 }
 
+func TestStateStore_Txn_CheckOps(t *testing.T) {
+	s := testStateStore(t)
+
+	testSetKey(t, s, 1, "flags/rollout", "enabled=true", nil)
+	testRegisterNode(t, s, 2, "node1")
+	testRegisterNode(t, s, 3, "node2")
+	testRegisterService(t, s, 4, "node1", "web")
+	testRegisterCheck(t, s, 5, "node1", "web", "web-check", api.HealthPassing)
+	testRegisterService(t, s, 6, "node2", "db")
+	testRegisterCheck(t, s, 7, "node2", "", "node-check", api.HealthCritical)
+	session := &structs.Session{ID: testUUID(), Node: "node1"}
+	require.NoError(t, s.SessionCreate(8, session))
+
+	kvOp := func(verb api.KVOp, key, value string) *structs.TxnOp {
+		return &structs.TxnOp{
+			KV: &structs.TxnKVOp{
+				Verb:   verb,
+				DirEnt: structs.DirEntry{Key: key, Value: []byte(value)},
+			},
+		}
+	}
+	nodeOp := func(name string) *structs.TxnOp {
+		return &structs.TxnOp{
+			Node: &structs.TxnNodeOp{
+				Verb: api.NodeCheckExists,
+				Node: structs.Node{Node: name},
+			},
+		}
+	}
+	serviceOp := func(node, name, id string) *structs.TxnOp {
+		return &structs.TxnOp{
+			Service: &structs.TxnServiceOp{
+				Verb:    api.ServiceCheckHealthy,
+				Node:    node,
+				Service: structs.NodeService{Service: name, ID: id},
+			},
+		}
+	}
+	sessionOp := func(id string) *structs.TxnOp {
+		return &structs.TxnOp{
+			Session: &structs.TxnSessionOp{
+				Verb:    api.SessionCheckValid,
+				Session: structs.Session{ID: id},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		op    *structs.TxnOp
+		error string
+	}{
+		"value matches":              {op: kvOp(api.KVCheckValue, "flags/rollout", "enabled=true")},
+		"value doesn't match":        {op: kvOp(api.KVCheckValue, "flags/rollout", "enabled"), error: "current value doesn't match"},
+		"value prefix matches":       {op: kvOp(api.KVCheckValuePrefix, "flags/rollout", "enabled")},
+		"value prefix doesn't match": {op: kvOp(api.KVCheckValuePrefix, "flags/rollout", "disabled"), error: "doesn't start with the given prefix"},
+		"value of missing key":       {op: kvOp(api.KVCheckValue, "flags/nope", ""), error: `key "flags/nope" doesn't exist`},
+		"node exists":                {op: nodeOp("node1")},
+		"node doesn't exist":         {op: nodeOp("node3"), error: `node "node3" doesn't exist`},
+		"service healthy":            {op: serviceOp("", "web", "")},
+		"service healthy on node":    {op: serviceOp("node1", "web", "web")},
+		"service not on node":        {op: serviceOp("node2", "web", ""), error: "no matching instance exists"},
+		"service with failing node":  {op: serviceOp("", "db", ""), error: "an instance has a check in critical state"},
+		"service doesn't exist":      {op: serviceOp("", "api", ""), error: "no matching instance exists"},
+		"service without name":       {op: serviceOp("node1", "", "web"), error: "service name is required"},
+		"session valid":              {op: sessionOp(session.ID)},
+		"session doesn't exist":      {op: sessionOp(testUUID()), error: "doesn't exist"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, errors := s.TxnRO(structs.TxnOps{tc.op})
+			if tc.error == "" {
+				require.Empty(t, errors)
+				return
+			}
+			require.Len(t, errors, 1)
+			require.Contains(t, errors[0].What, tc.error)
+		})
+	}
+
+	// A failed check rolls back the writes of the transaction.
+	ops := structs.TxnOps{
+		serviceOp("", "db", ""),
+		kvOp(api.KVSet, "flags/rollout", "enabled=false"),
+	}
+	_, errors := s.TxnRW(9, ops)
+	require.Len(t, errors, 1)
+	require.Equal(t, 0, errors[0].OpIndex)
+
+	_, entry, err := s.KVSGet(nil, "flags/rollout", nil)
+	require.NoError(t, err)
+	require.Equal(t, "enabled=true", string(entry.Value))
+
+	// And the writes are applied when it passes.
+	ops[0] = serviceOp("", "web", "")
+	_, errors = s.TxnRW(10, ops)
+	require.Empty(t, errors)
+
+	_, entry, err = s.KVSGet(nil, "flags/rollout", nil)
+	require.NoError(t, err)
+	require.Equal(t, "enabled=false", string(entry.Value))
+}
+
 func TestStateStore_Txn_KVS_ModifyIndexes(t *testing.T) {
 	s := testStateStore(t)
 
@@ -65,6 +65,20 @@ type Payload interface {
 	ToSubscriptionEvent(idx uint64) *pbsubscribe.Event
 }
 
+// MultiSubjectPayload is a Payload that should be delivered to subscribers of
+// more than one subject, for example a change to a KV entry is of interest to
+// subscribers of every prefix of its key. EventPublisher routes the event using
+// Subjects instead of Subject when it is implemented, and wildcard subscribers
+// still receive a single copy of the event.
+type MultiSubjectPayload interface {
+	Payload
+
+	// Subjects returns the subjects to which the event should be delivered. It
+	// may be empty, in which case the event is only delivered to wildcard
+	// subscribers.
+	Subjects() []Subject
+}
+
 // PayloadEvents is a Payload that may be returned by Subscription.Next when
 // there are multiple events at an index.
 //
@@ -165,8 +165,10 @@ func (e *EventPublisher) Publish(events []Event) {
 			continue
 		}
 
-		if event.Payload.Subject() == SubjectWildcard {
-			panic(fmt.Sprintf("SubjectWildcard can only be used for subscription, not for publishing (topic: %s, index: %d)", event.Topic, idx))
+		for _, subject := range eventSubjects(event) {
+			if subject == SubjectWildcard {
+				panic(fmt.Sprintf("SubjectWildcard can only be used for subscription, not for publishing (topic: %s, index: %d)", event.Topic, idx))
+			}
 		}
 	}
 
@@ -197,11 +199,13 @@ func (e *EventPublisher) publishEvent(events []Event) {
 			continue
 		}
 
-		groupKey := topicSubject{
-			Topic:   event.Topic.String(),
-			Subject: event.Payload.Subject().String(),
+		for _, subject := range eventSubjects(event) {
+			groupKey := topicSubject{
+				Topic:   event.Topic.String(),
+				Subject: subject.String(),
+			}
+			groupedEvents[groupKey] = append(groupedEvents[groupKey], event)
 		}
-		groupedEvents[groupKey] = append(groupedEvents[groupKey], event)
 
 		// If the topic supports wildcard subscribers, copy the events to a wildcard
 		// buffer too.
@@ -225,6 +229,14 @@ func (e *EventPublisher) publishEvent(events []Event) {
 	}
 }
 
+// eventSubjects returns the subjects to which the event should be routed.
+func eventSubjects(event Event) []Subject {
+	if multi, ok := event.Payload.(MultiSubjectPayload); ok {
+		return multi.Subjects()
+	}
+	return []Subject{event.Payload.Subject()}
+}
+
 // bufferForSubscription returns the topic event buffer to which events for the
 // given topic and key will be appended. If no such buffer exists, a new buffer
 // will be created.
@@ -675,6 +675,61 @@ func (wildcardPayload) Subject() Subject                              { return S
 func (wildcardPayload) HasReadPermission(acl.Authorizer) bool         { return true }
 func (wildcardPayload) ToSubscriptionEvent(uint64) *pbsubscribe.Event { return &pbsubscribe.Event{} }
 
+func TestEventPublisher_Publish_MultiSubjectPayload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	publisher := NewEventPublisher(0)
+	go publisher.Run(ctx)
+
+	handler := func(SubscribeRequest, SnapshotAppender) (uint64, error) { return 1, nil }
+	require.NoError(t, publisher.RegisterHandler(testTopic, handler, true))
+
+	subscribe := func(subject Subject) <-chan eventOrErr {
+		sub, err := publisher.Subscribe(&SubscribeRequest{
+			Topic:   testTopic,
+			Subject: subject,
+		})
+		require.NoError(t, err)
+		t.Cleanup(sub.Unsubscribe)
+
+		eventCh := runSubscription(ctx, sub)
+		require.True(t, getNextEvent(t, eventCh).IsEndOfSnapshot(), "expected end of snapshot")
+		return eventCh
+	}
+
+	aCh := subscribe(StringSubject("a"))
+	bCh := subscribe(StringSubject("b"))
+	cCh := subscribe(StringSubject("c"))
+	wildcardCh := subscribe(SubjectWildcard)
+
+	event := Event{
+		Topic:   testTopic,
+		Index:   2,
+		Payload: multiSubjectPayload{subjects: []Subject{StringSubject("a"), StringSubject("b")}},
+	}
+	publisher.Publish([]Event{event})
+
+	require.Equal(t, event, getNextEvent(t, aCh))
+	require.Equal(t, event, getNextEvent(t, bCh))
+	assertNoResult(t, cCh)
+
+	// Wildcard subscribers receive a single copy of the event.
+	require.Equal(t, event, getNextEvent(t, wildcardCh))
+	assertNoResult(t, wildcardCh)
+}
+
+type multiSubjectPayload struct {
+	subjects []Subject
+}
+
+func (p multiSubjectPayload) Subject() Subject                    { return p.subjects[0] }
+func (p multiSubjectPayload) Subjects() []Subject                 { return p.subjects }
+func (multiSubjectPayload) HasReadPermission(acl.Authorizer) bool { return true }
+func (multiSubjectPayload) ToSubscriptionEvent(uint64) *pbsubscribe.Event {
+	return &pbsubscribe.Event{}
+}
+
 func TestEventPublisher_SnapshotIndex0(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
@@ -62,6 +62,18 @@ func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps) structs.T
 				break
 			}
 
+			// Checks reveal whether the node exists, so they need read
+			// access to it.
+			if op.Node.Verb == api.NodeCheckExists {
+				if err := vetNodeCheckTxnOp(op.Node, authorizer); err != nil {
+					errors = append(errors, &structs.TxnError{
+						OpIndex: i,
+						What:    err.Error(),
+					})
+				}
+				break
+			}
+
 			node := op.Node.Node
 			if err := nodePreApply(node.Node, string(node.ID)); err != nil {
 				errors = append(errors, &structs.TxnError{
@@ -84,6 +96,18 @@ func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps) structs.T
 				break
 			}
 
+			// Checks reveal the health of the service, so they need read
+			// access to it.
+			if op.Service.Verb == api.ServiceCheckHealthy {
+				if err := vetServiceCheckTxnOp(op.Service, authorizer); err != nil {
+					errors = append(errors, &structs.TxnError{
+						OpIndex: i,
+						What:    err.Error(),
+					})
+				}
+				break
+			}
+
 			service := &op.Service.Service
 			if err := servicePreApply(service, authorizer, op.Service.FillAuthzContext); err != nil {
 				errors = append(errors, &structs.TxnError{
@@ -106,6 +130,19 @@ func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps) structs.T
 					What:    err.Error(),
 				})
 			}
+		case op.Session != nil:
+			// Only checks are vetted here, the other session operations
+			// are internal.
+			if op.Session.Verb != api.SessionCheckValid {
+				break
+			}
+
+			if err := t.vetSessionCheckTxnOp(op.Session, authorizer); err != nil {
+				errors = append(errors, &structs.TxnError{
+					OpIndex: i,
+					What:    err.Error(),
+				})
+			}
 		}
 	}
 
@@ -123,6 +160,52 @@ func vetNodeTxnOp(op *structs.TxnNodeOp, authz resolver.Result) error {
 	return nil
 }
 
+// vetNodeCheckTxnOp applies the given ACL policy to a node check transaction
+// operation.
+func vetNodeCheckTxnOp(op *structs.TxnNodeOp, authz resolver.Result) error {
+	var authzContext acl.AuthorizerContext
+	op.FillAuthzContext(&authzContext)
+
+	return authz.ToAllowAuthorizer().NodeReadAllowed(op.Node.Node, &authzContext)
+}
+
+// vetServiceCheckTxnOp applies the given ACL policy to a service health check
+// transaction operation. The node checks of the instances are also evaluated,
+// so read access to the node is needed when the check is narrowed down to one.
+func vetServiceCheckTxnOp(op *structs.TxnServiceOp, authz resolver.Result) error {
+	var authzContext acl.AuthorizerContext
+	op.FillAuthzContext(&authzContext)
+
+	if err := authz.ToAllowAuthorizer().ServiceReadAllowed(op.Service.Service, &authzContext); err != nil {
+		return err
+	}
+	if op.Node != "" {
+		if err := authz.ToAllowAuthorizer().NodeReadAllowed(op.Node, &authzContext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vetSessionCheckTxnOp applies the given ACL policy to a session check
+// transaction operation. Sessions are read through the node they belong to,
+// so the session is looked up first; a missing session fails the transaction
+// in the state store.
+func (t *Txn) vetSessionCheckTxnOp(op *structs.TxnSessionOp, authz resolver.Result) error {
+	_, session, err := t.srv.fsm.State().SessionGet(nil, op.Session.ID, &op.Session.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return nil
+	}
+
+	var authzContext acl.AuthorizerContext
+	session.FillAuthzContext(&authzContext)
+
+	return authz.ToAllowAuthorizer().SessionReadAllowed(session.Node, &authzContext)
+}
+
 // vetCheckTxnOp applies the given ACL policy to a check transaction operation.
 func vetCheckTxnOp(op *structs.TxnCheckOp, authz resolver.Result) error {
 	var authzContext acl.AuthorizerContext
@@ -173,6 +256,15 @@ func (t *Txn) Apply(args *structs.TxnRequest, reply *structs.TxnResponse) error
 	} else {
 		return fmt.Errorf("unexpected return type %T", resp)
 	}
+
+	// Track the expiration of the KV entries written by the transaction.
+	if len(reply.Errors) == 0 {
+		for _, op := range args.Ops {
+			if op.KV != nil {
+				t.srv.updateKVSTimer(op.KV.Verb, &op.KV.DirEnt)
+			}
+		}
+	}
 	return nil
 }
 
@@ -945,4 +945,132 @@ func TestTxn_Read_ACLDeny(t *testing.T) {
 
 		require.Empty(t, out.Results)
 	})
+
+	t.Run("check operations (return permission denied errors)", func(t *testing.T) {
+		session := &structs.Session{ID: generateUUID(), Node: "nope"}
+		require.NoError(t, state.SessionCreate(5, session))
+
+		arg := structs.TxnReadRequest{
+			Datacenter:   "dc1",
+			QueryOptions: structs.QueryOptions{Token: token.SecretID},
+			Ops: structs.TxnOps{
+				{
+					KV: &structs.TxnKVOp{
+						Verb: api.KVCheckValue,
+						DirEnt: structs.DirEntry{
+							Key:   "nope",
+							Value: []byte("hello"),
+						},
+					},
+				},
+				{
+					Node: &structs.TxnNodeOp{
+						Verb: api.NodeCheckExists,
+						Node: structs.Node{Node: "nope"},
+					},
+				},
+				{
+					Service: &structs.TxnServiceOp{
+						Verb:    api.ServiceCheckHealthy,
+						Service: structs.NodeService{Service: "nope"},
+					},
+				},
+				{
+					Session: &structs.TxnSessionOp{
+						Verb:    api.SessionCheckValid,
+						Session: structs.Session{ID: session.ID},
+					},
+				},
+			},
+		}
+
+		var out structs.TxnReadResponse
+		err := msgpackrpc.CallWithCodec(codec, "Txn.Read", &arg, &out)
+		require.NoError(t, err)
+		require.Len(t, out.Errors, 4)
+		acl.RequirePermissionDeniedMessage(t, out.Errors[0].What, token.AccessorID, nil, acl.ResourceKey, acl.AccessRead, "nope")
+		acl.RequirePermissionDeniedMessage(t, out.Errors[1].What, token.AccessorID, nil, acl.ResourceNode, acl.AccessRead, "nope")
+		acl.RequirePermissionDeniedMessage(t, out.Errors[2].What, token.AccessorID, nil, acl.ResourceService, acl.AccessRead, "nope")
+		acl.RequirePermissionDeniedMessage(t, out.Errors[3].What, token.AccessorID, nil, acl.ResourceSession, acl.AccessRead, "nope")
+
+		require.Empty(t, out.Results)
+	})
+}
+
+func TestTxn_Apply_CheckOps(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	require.NoError(t, state.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+	require.NoError(t, state.EnsureService(2, "foo", &structs.NodeService{ID: "web", Service: "web"}))
+	require.NoError(t, state.EnsureCheck(3, &structs.HealthCheck{
+		Node:      "foo",
+		CheckID:   "web",
+		ServiceID: "web",
+		Status:    api.HealthCritical,
+	}))
+
+	// Only flip the flag if the web service is healthy.
+	arg := structs.TxnRequest{
+		Datacenter: "dc1",
+		Ops: structs.TxnOps{
+			{
+				Service: &structs.TxnServiceOp{
+					Verb:    api.ServiceCheckHealthy,
+					Service: structs.NodeService{Service: "web"},
+				},
+			},
+			{
+				Node: &structs.TxnNodeOp{
+					Verb: api.NodeCheckExists,
+					Node: structs.Node{Node: "foo"},
+				},
+			},
+			{
+				KV: &structs.TxnKVOp{
+					Verb: api.KVSet,
+					DirEnt: structs.DirEntry{
+						Key:   "flag",
+						Value: []byte("on"),
+					},
+				},
+			},
+		},
+	}
+	var out structs.TxnResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Txn.Apply", &arg, &out))
+	require.Len(t, out.Errors, 1)
+	require.Equal(t, 0, out.Errors[0].OpIndex)
+	require.Contains(t, out.Errors[0].What, "an instance has a check in critical state")
+	require.NotContains(t, out.Errors[0].What, "foo")
+
+	_, entry, err := state.KVSGet(nil, "flag", nil)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+
+	require.NoError(t, state.EnsureCheck(4, &structs.HealthCheck{
+		Node:      "foo",
+		CheckID:   "web",
+		ServiceID: "web",
+		Status:    api.HealthPassing,
+	}))
+
+	out = structs.TxnResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Txn.Apply", &arg, &out))
+	require.Empty(t, out.Errors)
+
+	_, entry, err = state.KVSGet(nil, "flag", nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("on"), entry.Value)
 }
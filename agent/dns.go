@@ -5,6 +5,7 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -82,6 +83,8 @@ type dnsSOAConfig struct {
 
 type dnsConfig struct {
 	AllowStale       bool
+	AnswerOrdering   agentdns.AnswerOrdering
+	AnswerPreferNear bool
 	Datacenter       string
 	EnableTruncate   bool
 	MaxStale         time.Duration
@@ -97,7 +100,16 @@ type dnsConfig struct {
 	UDPAnswerLimit   int
 	ARecordLimit     int
 	NodeMetaTXT      bool
-	SOAConfig        dnsSOAConfig
+	// KVPrefix is the prefix of the keys served by the KV lookups, ending
+	// with a slash. They are disabled when it is empty.
+	KVPrefix  string
+	SOAConfig dnsSOAConfig
+	// DNSSEC holds the keys signing the responses, it is nil when signing is
+	// disabled.
+	DNSSEC *dnssecConfig
+	// ZoneTransfer holds the keys and clients allowed to transfer the zone,
+	// it is nil when zone transfers are disabled.
+	ZoneTransfer *dnsZoneTransferConfig
 	// TTLRadix sets service TTLs by prefix, eg: "database-*"
 	TTLRadix *radix.Tree
 	// TTLStict sets TTLs to service by full name match. It Has higher priority than TTLRadix
@@ -185,6 +197,8 @@ func NewDNSServer(a *Agent) (*DNSServer, error) {
 func GetDNSConfig(conf *config.RuntimeConfig) (*dnsConfig, error) {
 	cfg := &dnsConfig{
 		AllowStale:         conf.DNSAllowStale,
+		AnswerOrdering:     conf.DNSAnswerOrdering,
+		AnswerPreferNear:   conf.DNSAnswerPreferNear,
 		ARecordLimit:       conf.DNSARecordLimit,
 		Datacenter:         conf.Datacenter,
 		EnableTruncate:     conf.DNSEnableTruncate,
@@ -197,6 +211,7 @@ func GetDNSConfig(conf *config.RuntimeConfig) (*dnsConfig, error) {
 		SegmentName:        conf.SegmentName,
 		UDPAnswerLimit:     conf.DNSUDPAnswerLimit,
 		NodeMetaTXT:        conf.DNSNodeMetaTXT,
+		KVPrefix:           kvPrefix(conf.DNSKVPrefix),
 		DisableCompression: conf.DNSDisableCompression,
 		UseCache:           conf.DNSUseCache,
 		CacheMaxAge:        conf.DNSCacheMaxAge,
@@ -229,10 +244,46 @@ func GetDNSConfig(conf *config.RuntimeConfig) (*dnsConfig, error) {
 		}
 		cfg.Recursors = append(cfg.Recursors, ra)
 	}
+	dnssec, err := newDNSSECConfig(conf.DNSSEC)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DNSSEC = dnssec
+
+	zt := conf.DNSZoneTransfer
+	if len(zt.TSIGKeys) > 0 {
+		cfg.ZoneTransfer = &dnsZoneTransferConfig{
+			AllowFrom: zt.AllowFrom,
+			TSIGKeys:  make(map[string]dnsTSIGKey, len(zt.TSIGKeys)),
+		}
+		for _, k := range zt.TSIGKeys {
+			cfg.ZoneTransfer.TSIGKeys[k.Name] = dnsTSIGKey{
+				Algorithm: k.Algorithm,
+				Secret:    k.Secret,
+				Token:     k.Token,
+			}
+		}
+		for _, s := range zt.Secondaries {
+			addr, err := recursorAddr(s)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid zone transfer secondary address: %v", err)
+			}
+			cfg.ZoneTransfer.Secondaries = append(cfg.ZoneTransfer.Secondaries, addr)
+		}
+	}
 
 	return cfg, nil
 }
 
+// kvPrefix returns the prefix of the keys served by the KV lookups, which
+// ends with a slash so that only the keys under it are served.
+func kvPrefix(prefix string) string {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+	return prefix + "/"
+}
+
 // GetTTLForService Find the TTL for a given service.
 // return ttl, true if found, 0, false otherwise
 func (cfg *dnsConfig) GetTTLForService(service string) (time.Duration, bool) {
@@ -257,6 +308,7 @@ func (d *DNSServer) ListenAndServe(network, addr string, notif func()) error {
 		Net:               network,
 		Handler:           d.mux,
 		NotifyStartedFunc: notif,
+		TsigSecret:        d.tsigSecrets(),
 	}
 	if network == "udp" {
 		d.UDPSize = 65535
@@ -264,6 +316,19 @@ func (d *DNSServer) ListenAndServe(network, addr string, notif func()) error {
 	return d.Server.ListenAndServe()
 }
 
+// ListenAndServeTLS serves DNS over TLS (RFC 7858) on the given TCP address.
+func (d *DNSServer) ListenAndServeTLS(addr string, tlsConfig *tls.Config, notif func()) error {
+	d.Server = &dns.Server{
+		Addr:              addr,
+		Net:               "tcp-tls",
+		TLSConfig:         tlsConfig,
+		Handler:           d.mux,
+		NotifyStartedFunc: notif,
+		TsigSecret:        d.tsigSecrets(),
+	}
+	return d.Server.ListenAndServe()
+}
+
 // toggleRecursorHandlerFromConfig enables or disables the recursor handler based on config idempotently
 func (d *DNSServer) toggleRecursorHandlerFromConfig(cfg *dnsConfig) {
 	shouldEnable := len(cfg.Recursors) > 0
@@ -513,6 +578,11 @@ func (d *DNSServer) handleQuery(resp dns.ResponseWriter, req *dns.Msg) {
 
 	cfg := d.config.Load().(*dnsConfig)
 
+	if (q.Qtype == dns.TypeAXFR || q.Qtype == dns.TypeIXFR) && cfg.ZoneTransfer != nil && strings.EqualFold(dns.Fqdn(q.Name), d.domain) {
+		d.handleZoneTransfer(cfg, resp, req, network)
+		return
+	}
+
 	// Setup the message response
 	m := new(dns.Msg)
 	m.SetReply(req)
@@ -520,6 +590,8 @@ func (d *DNSServer) handleQuery(resp dns.ResponseWriter, req *dns.Msg) {
 	m.Authoritative = true
 	m.RecursionAvailable = (len(cfg.Recursors) > 0)
 
+	dnssec := dnssecRequested(cfg, req)
+
 	var err error
 
 	switch req.Question[0].Qtype {
@@ -540,10 +612,23 @@ func (d *DNSServer) handleQuery(resp dns.ResponseWriter, req *dns.Msg) {
 		m.SetRcode(req, dns.RcodeNotImplemented)
 
 	default:
+		if keys, ok := d.dnssecKeyRecords(cfg, q); ok {
+			m.Answer = keys
+			m.SetRcode(req, dns.RcodeSuccess)
+			break
+		}
+
 		err = d.dispatch(resp.RemoteAddr(), req, m, maxRecursionLevelDefault)
 		rCode := rCodeFromError(err)
-		if rCode == dns.RcodeNameError || errors.Is(err, errNoData) {
+		// Signed empty answers must be proven with the SOA and NSEC records,
+		// including those not reported as errNoData.
+		noData := errors.Is(err, errNoData) || (dnssec && rCode == dns.RcodeSuccess && len(m.Answer) == 0)
+		if rCode == dns.RcodeNameError || noData {
 			d.addSOA(cfg, m, q.Name)
+			if dnssec {
+				d.addDenial(cfg, m, q, rCode == dns.RcodeNameError)
+				rCode = dns.RcodeSuccess
+			}
 		}
 		m.SetRcode(req, rCode)
 	}
@@ -552,6 +637,15 @@ func (d *DNSServer) handleQuery(resp dns.ResponseWriter, req *dns.Msg) {
 
 	d.trimDNSResponse(cfg, network, req, m)
 
+	// Sign the response once trimmed so that only complete record sets are
+	// signed, the signatures may require truncating it further.
+	if dnssec {
+		d.signResponse(cfg, m)
+		if network == "udp" {
+			m.Truncate(maxUDPResponseSize(req))
+		}
+	}
+
 	if err := resp.WriteMsg(m); err != nil {
 		d.logger.Warn("failed to respond", "error", err)
 	}
@@ -763,6 +857,18 @@ func (d *DNSServer) dispatch(remoteAddr net.Addr, req, resp *dns.Msg, maxRecursi
 			}
 		}
 	}
+	// The "kv" label is only followed by the optional datacenter, and the
+	// other kinds take precedence so that a datacenter, namespace or peer
+	// named "kv" keeps working. The label followed by a datacenter is tried
+	// first, for the lookups in a datacenter named "kv".
+	for i := len(labels) - 2; i < len(labels) && !done; i++ {
+		if i >= 0 && labels[i] == "kv" {
+			queryParts = labels[:i]
+			querySuffixes = labels[i+1:]
+			queryKind = labels[i]
+			done = true
+		}
+	}
 
 	invalid := func() error {
 		d.logger.Warn("QName invalid", "qname", qName)
@@ -990,6 +1096,22 @@ func (d *DNSServer) dispatch(remoteAddr net.Addr, req, resp *dns.Msg, maxRecursi
 		err := d.preparedQueryLookup(cfg, datacenter, query, remoteAddr, req, resp, maxRecursionLevel)
 		return ecsNotGlobalError{error: err}
 
+	case "kv":
+		// <key-path-reversed>.kv.<datacenter>.<domain> - datacenter is optional
+		if cfg.KVPrefix == "" || len(queryParts) < 1 {
+			return invalid()
+		}
+
+		datacenter := d.agent.config.Datacenter
+		if !d.parseDatacenter(querySuffixes, &datacenter) {
+			return invalid()
+		}
+
+		// The keys are case sensitive, so the labels of the question are
+		// used instead of the lower cased ones.
+		labels := dns.SplitDomainName(req.Question[0].Name)[:len(queryParts)]
+		return d.kvLookup(cfg, datacenter, labels, req, resp)
+
 	case "addr":
 		// <address>.addr.<suffixes>.<domain> - addr must be the second label, datacenter is optional
 
@@ -1314,13 +1436,9 @@ func trimTCPResponse(req, resp *dns.Msg) (trimmed bool) {
 	return truncated
 }
 
-// trimUDPResponse makes sure a UDP response is not longer than allowed by RFC
-// 1035. Enforce an arbitrary limit that can be further ratcheted down by
-// config, and then make sure the response doesn't exceed 512 bytes. Any extra
-// records will be trimmed along with answers.
-func trimUDPResponse(req, resp *dns.Msg, udpAnswerLimit int) (trimmed bool) {
-	numAnswers := len(resp.Answer)
-	hasExtra := len(resp.Extra) > 0
+// maxUDPResponseSize returns the max size of the response to a query received
+// over UDP.
+func maxUDPResponseSize(req *dns.Msg) int {
 	maxSize := defaultMaxUDPSize
 
 	// Update to the maximum edns size
@@ -1334,6 +1452,17 @@ func trimUDPResponse(req, resp *dns.Msg, udpAnswerLimit int) (trimmed bool) {
 	if maxSize > maxUDPDatagramSize {
 		maxSize = maxUDPDatagramSize
 	}
+	return maxSize
+}
+
+// trimUDPResponse makes sure a UDP response is not longer than allowed by RFC
+// 1035. Enforce an arbitrary limit that can be further ratcheted down by
+// config, and then make sure the response doesn't exceed 512 bytes. Any extra
+// records will be trimmed along with answers.
+func trimUDPResponse(req, resp *dns.Msg, udpAnswerLimit int) (trimmed bool) {
+	numAnswers := len(resp.Answer)
+	hasExtra := len(resp.Extra) > 0
+	maxSize := maxUDPResponseSize(req)
 
 	// We avoid some function calls and allocations by only handling the
 	// extra data when necessary.
@@ -1433,7 +1562,6 @@ func (d *DNSServer) lookupServiceNodes(cfg *dnsConfig, lookup serviceLookup) (st
 		},
 		EnterpriseMeta: lookup.EnterpriseMeta,
 	}
-
 	out, _, err := d.agent.rpcClientHealth.ServiceNodes(context.TODO(), args)
 	if err != nil {
 		return out, err
@@ -1459,8 +1587,7 @@ func (d *DNSServer) serviceLookup(cfg *dnsConfig, lookup serviceLookup, req, res
 		return errNameNotFound
 	}
 
-	// Perform a random shuffle
-	out.Nodes.Shuffle()
+	d.orderServiceNodes(cfg, out.Nodes, d.answerPreferNear(cfg, lookup))
 
 	// Determine the TTL
 	ttl, _ := cfg.GetTTLForService(lookup.Service)
@@ -1479,6 +1606,117 @@ func (d *DNSServer) serviceLookup(cfg *dnsConfig, lookup serviceLookup, req, res
 	return nil
 }
 
+// answerPreferNear returns whether the answers of a service lookup are biased
+// towards the nearest nodes. Distances are only known for the nodes of the
+// datacenter of the agent when coordinates are enabled.
+func (d *DNSServer) answerPreferNear(cfg *dnsConfig, lookup serviceLookup) bool {
+	return cfg.AnswerPreferNear &&
+		!d.agent.config.DisableCoordinates &&
+		lookup.PeerName == "" &&
+		lookup.Datacenter == d.agent.config.Datacenter
+}
+
+// nearRTTFloor is the round trip time below which nodes are considered as
+// near as the agent itself when biasing the answers towards the nearest nodes.
+const nearRTTFloor = time.Millisecond
+
+// orderServiceNodes orders the nodes of a service lookup, which are answered
+// in this order and trimmed from the end when the response is too large.
+func (d *DNSServer) orderServiceNodes(cfg *dnsConfig, nodes structs.CheckServiceNodes, preferNear bool) {
+	if cfg.AnswerOrdering != agentdns.AnswerOrderingWeighted {
+		// Perform a random shuffle
+		nodes.Shuffle()
+		return
+	}
+
+	weights := make([]float64, len(nodes))
+	for i, node := range nodes {
+		weights[i] = float64(findWeight(node))
+	}
+	if preferNear {
+		rtts, err := d.nodeRTTs(nodes)
+		if err != nil {
+			d.logger.Warn("Failed to get the node coordinates, answers aren't biased towards the nearest nodes",
+				"error", err,
+			)
+		} else {
+			biasNear(weights, rtts)
+		}
+	}
+
+	ordered := make(structs.CheckServiceNodes, 0, len(nodes))
+	for _, idx := range cfg.AnswerOrdering.Indexes(weights) {
+		ordered = append(ordered, nodes[idx])
+	}
+	copy(nodes, ordered)
+}
+
+// nodeRTTs returns the estimated round trip time in seconds between the
+// agent and each of the given nodes, using the network coordinates of the
+// catalog. It is positive infinity for the nodes without coordinates. The
+// coordinates are read from the agent cache, kept up to date by a single
+// blocking query rather than fetched for each lookup.
+func (d *DNSServer) nodeRTTs(nodes structs.CheckServiceNodes) ([]float64, error) {
+	self, err := d.agent.GetLANCoordinate()
+	if err != nil {
+		return nil, err
+	}
+
+	args := structs.DCSpecificRequest{
+		Datacenter: d.agent.config.Datacenter,
+		QueryOptions: structs.QueryOptions{
+			Token: d.agent.tokens.UserToken(),
+		},
+		EnterpriseMeta: *d.agent.AgentEnterpriseMeta(),
+	}
+	raw, _, err := d.agent.cache.Get(context.TODO(), cachetype.CoordinateListNodesName, &args)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := raw.(*structs.IndexedCoordinates)
+	if !ok {
+		// This should never happen, but we want to protect against panics
+		return nil, fmt.Errorf("internal error: response type not correct")
+	}
+
+	coords := make(map[string]lib.CoordinateSet)
+	for _, c := range out.Coordinates {
+		if coords[c.Node] == nil {
+			coords[c.Node] = make(lib.CoordinateSet)
+		}
+		coords[c.Node][c.Segment] = c.Coord
+	}
+
+	rtts := make([]float64, len(nodes))
+	for i, node := range nodes {
+		rtts[i] = lib.ComputeDistance(self.Intersect(coords[node.Node.Node]))
+	}
+	return rtts, nil
+}
+
+// biasNear divides the weights by the round trip times to their nodes, so
+// that a node twice as far is answered first half as often. The nodes without
+// a known round trip time are considered as far as the farthest known node,
+// and the weights are kept as is when none is known.
+func biasNear(weights []float64, rtts []float64) {
+	farthest := math.Inf(-1)
+	for _, rtt := range rtts {
+		if !math.IsInf(rtt, 1) && rtt > farthest {
+			farthest = rtt
+		}
+	}
+	if math.IsInf(farthest, -1) {
+		return
+	}
+
+	for i, rtt := range rtts {
+		if math.IsInf(rtt, 1) {
+			rtt = farthest
+		}
+		weights[i] /= math.Max(rtt, nearRTTFloor.Seconds())
+	}
+}
+
 func ednsSubnetForRequest(req *dns.Msg) *dns.EDNS0_SUBNET {
 	// IsEdns0 returns the EDNS RR if present or nil otherwise
 	edns := req.IsEdns0()
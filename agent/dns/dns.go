@@ -4,8 +4,10 @@
 package dns
 
 import (
+	"math"
 	"math/rand"
 	"regexp"
+	"sort"
 )
 
 // MaxLabelLength is the maximum length for a name that can be used in DNS.
@@ -35,3 +37,38 @@ func (s RecursorStrategy) Indexes(max int) []int {
 
 	}
 }
+
+// AnswerOrdering controls the order of the answers of the service lookups.
+type AnswerOrdering string
+
+const (
+	AnswerOrderingRandom   AnswerOrdering = "random"
+	AnswerOrderingWeighted AnswerOrdering = "weighted"
+)
+
+// Indexes returns the order in which the items with the given weights are
+// answered. 'weighted' picks every position among the remaining items with a
+// probability proportional to their weight, items with a weight of zero are
+// answered last. Weights are ignored otherwise.
+func (o AnswerOrdering) Indexes(weights []float64) []int {
+	if o != AnswerOrderingWeighted {
+		return rand.Perm(len(weights))
+	}
+
+	// Weighted random sampling without replacement (Efraimidis and Spirakis):
+	// sorting the items by u^(1/weight), with u uniform in (0, 1), is the same
+	// as picking them one by one proportionally to their weight.
+	keys := make([]float64, len(weights))
+	for i, w := range weights {
+		if w > 0 {
+			keys[i] = math.Pow(1-rand.Float64(), 1/w)
+		} else {
+			keys[i] = -rand.Float64()
+		}
+	}
+	idxs := rand.Perm(len(weights))
+	sort.SliceStable(idxs, func(i, j int) bool {
+		return keys[idxs[i]] > keys[idxs[j]]
+	})
+	return idxs
+}
@@ -42,3 +42,37 @@ func TestDNS_Recursor_StrategySequential(t *testing.T) {
 	// in the configuration
 	require.Equal(t, recursorsToQuery, expectedRecursors)
 }
+
+func TestDNS_AnswerOrderingRandom(t *testing.T) {
+	ordering := AnswerOrdering("random")
+
+	retry.RunWith(&retry.Counter{Count: 5}, t, func(r *retry.R) {
+		idxs := ordering.Indexes([]float64{1, 1, 1, 1, 1})
+
+		// Ensure the slices contain the same elements
+		require.ElementsMatch(r, []int{0, 1, 2, 3, 4}, idxs)
+
+		// Ensure the elements are not in the same order
+		require.NotEqual(r, []int{0, 1, 2, 3, 4}, idxs)
+	})
+}
+
+func TestDNS_AnswerOrderingWeighted(t *testing.T) {
+	ordering := AnswerOrdering("weighted")
+	weights := []float64{1, 0, 1000, 1}
+
+	var heavyFirst int
+	for i := 0; i < 100; i++ {
+		idxs := ordering.Indexes(weights)
+		require.ElementsMatch(t, []int{0, 1, 2, 3}, idxs)
+
+		// Items without weight are always answered last.
+		require.Equal(t, 1, idxs[3])
+		if idxs[0] == 2 {
+			heavyFirst++
+		}
+	}
+
+	// The heavy item is expected first 99.8% of the time.
+	require.GreaterOrEqual(t, heavyFirst, 90)
+}
@@ -0,0 +1,318 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/hashicorp/consul/agent/config"
+)
+
+// dnsTypeNXNAME is the meta type set in the type bitmap of the NSEC records
+// denying the existence of a name (RFC 9824).
+const dnsTypeNXNAME uint16 = 128
+
+// dnssecInceptionSkew backdates the inception of the signatures so that they
+// are valid for resolvers whose clock is late.
+const dnssecInceptionSkew = time.Hour
+
+// dnssecKey is a key used to sign the responses, loaded from the files
+// written by dnssec-keygen.
+type dnssecKey struct {
+	key    *dns.DNSKEY
+	signer *ecdsa.PrivateKey
+	tag    uint16
+}
+
+// dnssecConfig holds the keys signing the responses of the DNS domains.
+type dnssecConfig struct {
+	// KeySigningKeys sign the DNSKEY record set. All of them sign it so that
+	// the DS record in the parent zone can be changed during a rollover.
+	KeySigningKeys []*dnssecKey
+
+	// ZoneSigningKeys are published in the DNSKEY record set, only the first
+	// one signs the other record sets.
+	ZoneSigningKeys []*dnssecKey
+
+	SignatureValidity time.Duration
+}
+
+// newDNSSECConfig loads the DNSSEC keys, it returns nil when signing is
+// disabled.
+func newDNSSECConfig(conf config.RuntimeDNSSECConfig) (*dnssecConfig, error) {
+	if len(conf.ZoneSigningKeys) == 0 {
+		return nil, nil
+	}
+
+	cfg := &dnssecConfig{SignatureValidity: conf.SignatureValidity}
+	for _, path := range conf.KeySigningKeys {
+		k, err := loadDNSSECKey(path, true)
+		if err != nil {
+			return nil, err
+		}
+		cfg.KeySigningKeys = append(cfg.KeySigningKeys, k)
+	}
+	for _, path := range conf.ZoneSigningKeys {
+		k, err := loadDNSSECKey(path, false)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ZoneSigningKeys = append(cfg.ZoneSigningKeys, k)
+	}
+	return cfg, nil
+}
+
+// loadDNSSECKey reads the public key of path.key and the private key of
+// path.private, and checks they are a key signing key or a zone signing key
+// using ECDSA P-256.
+func loadDNSSECKey(path string, ksk bool) (*dnssecKey, error) {
+	pub, err := os.Open(path + ".key")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DNSSEC key: %w", err)
+	}
+	defer pub.Close()
+	rr, err := dns.ReadRR(pub, path+".key")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DNSSEC key %s.key: %w", path, err)
+	}
+	key, ok := rr.(*dns.DNSKEY)
+	if !ok {
+		return nil, fmt.Errorf("DNSSEC key %s.key is not a DNSKEY record", path)
+	}
+	if key.Algorithm != dns.ECDSAP256SHA256 {
+		return nil, fmt.Errorf("DNSSEC key %s.key uses algorithm %s. Must be ECDSAP256SHA256", path, dns.AlgorithmToString[key.Algorithm])
+	}
+	if key.Flags&dns.ZONE == 0 {
+		return nil, fmt.Errorf("DNSSEC key %s.key is not a zone key", path)
+	}
+	if ksk && key.Flags&dns.SEP == 0 {
+		return nil, fmt.Errorf("DNSSEC key %s.key is not a key signing key", path)
+	}
+	if !ksk && key.Flags&dns.SEP != 0 {
+		return nil, fmt.Errorf("DNSSEC key %s.key is not a zone signing key", path)
+	}
+
+	priv, err := os.Open(path + ".private")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DNSSEC key: %w", err)
+	}
+	defer priv.Close()
+	pk, err := key.ReadPrivateKey(priv, path+".private")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DNSSEC key %s.private: %w", path, err)
+	}
+	signer, ok := pk.(*ecdsa.PrivateKey)
+	if !ok || dnssecPublicKey(signer) != strings.Join(strings.Fields(key.PublicKey), "") {
+		return nil, fmt.Errorf("DNSSEC key %s.private doesn't match %s.key", path, path)
+	}
+
+	return &dnssecKey{key: key, signer: signer, tag: key.KeyTag()}, nil
+}
+
+// dnssecPublicKey returns the public key derived from an ECDSA P-256 private
+// key in the format of the DNSKEY records (RFC 6605). The public key of the
+// parsed private key can't be used since it's copied from the DNSKEY record.
+func dnssecPublicKey(k *ecdsa.PrivateKey) string {
+	x, y := k.Curve.ScalarBaseMult(k.D.Bytes())
+	buf := make([]byte, 64)
+	x.FillBytes(buf[:32])
+	y.FillBytes(buf[32:])
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// dnskey returns the DNSKEY record of the key for the given zone.
+func (k *dnssecKey) dnskey(zone string) *dns.DNSKEY {
+	rr := *k.key
+	rr.Hdr.Name = zone
+	return &rr
+}
+
+// sign returns the signature of a record set.
+func (k *dnssecKey) sign(zone string, rrset []dns.RR, now time.Time, validity time.Duration) (*dns.RRSIG, error) {
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Ttl: rrset[0].Header().Ttl},
+		Algorithm:  k.key.Algorithm,
+		KeyTag:     k.tag,
+		SignerName: zone,
+		Inception:  uint32(now.Add(-dnssecInceptionSkew).Unix()),
+		Expiration: uint32(now.Add(validity).Unix()),
+	}
+	if err := sig.Sign(k.signer, rrset); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// dnssecRequested returns whether the response to a query must be signed,
+// that is when signing is enabled and the client set the DO bit.
+func dnssecRequested(cfg *dnsConfig, req *dns.Msg) bool {
+	if cfg.DNSSEC == nil {
+		return false
+	}
+	edns := req.IsEdns0()
+	return edns != nil && edns.Do()
+}
+
+// zoneOf returns the DNS domain a name belongs to.
+func (d *DNSServer) zoneOf(name string) (string, bool) {
+	var zone string
+	for _, domain := range []string{d.domain, d.altDomain} {
+		if domain == "." || !dns.IsSubDomain(domain, name) {
+			continue
+		}
+		if len(domain) > len(zone) {
+			zone = domain
+		}
+	}
+	return zone, zone != ""
+}
+
+// dnssecKeyRecords returns the answer of the DNSKEY and DS queries for the
+// apex of the DNS domains. The DS records are those of the key signing keys,
+// to be set in the parent zone.
+func (d *DNSServer) dnssecKeyRecords(cfg *dnsConfig, q dns.Question) ([]dns.RR, bool) {
+	if cfg.DNSSEC == nil || (q.Qtype != dns.TypeDNSKEY && q.Qtype != dns.TypeDS) {
+		return nil, false
+	}
+	zone, ok := d.zoneOf(q.Name)
+	if !ok || !strings.EqualFold(dns.Fqdn(q.Name), zone) {
+		return nil, false
+	}
+
+	var rrs []dns.RR
+	if q.Qtype == dns.TypeDS {
+		for _, k := range cfg.DNSSEC.KeySigningKeys {
+			rrs = append(rrs, k.dnskey(zone).ToDS(dns.SHA256))
+		}
+		return rrs, true
+	}
+	for _, k := range cfg.DNSSEC.KeySigningKeys {
+		rrs = append(rrs, k.dnskey(zone))
+	}
+	for _, k := range cfg.DNSSEC.ZoneSigningKeys {
+		rrs = append(rrs, k.dnskey(zone))
+	}
+	return rrs, true
+}
+
+// addDenial adds the NSEC record proving that a name or the queried type
+// don't exist. The records are "black lies" (RFC 4470, RFC 9824) since the
+// names of the zone can't be enumerated: the NSEC record only covers the
+// queried name, and a name which doesn't exist is answered as a name without
+// any type, so the caller must answer NOERROR instead of NXDOMAIN.
+func (d *DNSServer) addDenial(cfg *dnsConfig, msg *dns.Msg, q dns.Question, nxdomain bool) {
+	name := strings.ToLower(dns.Fqdn(q.Name))
+
+	types := []uint16{dns.TypeRRSIG, dns.TypeNSEC}
+	if nxdomain {
+		types = append(types, dnsTypeNXNAME)
+	} else {
+		// The name may have records of other types, which must not be
+		// denied since resolvers can synthesize answers from the NSEC record
+		// (RFC 8198).
+		for _, t := range []uint16{dns.TypeA, dns.TypeTXT, dns.TypeAAAA, dns.TypeSRV} {
+			if t != q.Qtype {
+				types = append(types, t)
+			}
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	msg.Ns = append(msg.Ns, &dns.NSEC{
+		Hdr: dns.RR_Header{
+			Name:   name,
+			Rrtype: dns.TypeNSEC,
+			Class:  dns.ClassINET,
+			// Has to be consistent with the SOA MinTTL like negative answers
+			Ttl: cfg.SOAConfig.Minttl,
+		},
+		NextDomain: "\\000." + name,
+		TypeBitMap: types,
+	})
+}
+
+// signResponse adds the signatures of the record sets of a response which
+// belong to the DNS domains, and sets the DO bit of the response.
+func (d *DNSServer) signResponse(cfg *dnsConfig, msg *dns.Msg) {
+	if edns := msg.IsEdns0(); edns != nil {
+		edns.SetDo()
+	}
+
+	now := time.Now()
+	msg.Answer = d.signRecords(cfg, msg.Answer, now)
+	msg.Ns = d.signRecords(cfg, msg.Ns, now)
+	msg.Extra = d.signRecords(cfg, msg.Extra, now)
+}
+
+// signRecords groups the records of a section in record sets followed by
+// their signatures. The records which don't belong to the DNS domains, like
+// those returned by the recursors, are left unsigned at the end.
+func (d *DNSServer) signRecords(cfg *dnsConfig, rrs []dns.RR, now time.Time) []dns.RR {
+	type rrsetKey struct {
+		name   string
+		zone   string
+		rrtype uint16
+		class  uint16
+	}
+
+	var keys []rrsetKey
+	var unsigned []dns.RR
+	rrsets := make(map[rrsetKey][]dns.RR)
+	for _, rr := range rrs {
+		h := rr.Header()
+		zone, ok := d.zoneOf(h.Name)
+		if !ok || h.Rrtype == dns.TypeOPT || h.Rrtype == dns.TypeRRSIG {
+			unsigned = append(unsigned, rr)
+			continue
+		}
+		key := rrsetKey{name: h.Name, zone: zone, rrtype: h.Rrtype, class: h.Class}
+		if _, ok := rrsets[key]; !ok {
+			keys = append(keys, key)
+		}
+		rrsets[key] = append(rrsets[key], rr)
+	}
+
+	out := make([]dns.RR, 0, len(rrs)+len(keys))
+	for _, key := range keys {
+		rrset := rrsets[key]
+
+		// The records of a set must have the same TTL to be validated.
+		ttl := rrset[0].Header().Ttl
+		for _, rr := range rrset {
+			if rr.Header().Ttl < ttl {
+				ttl = rr.Header().Ttl
+			}
+		}
+		for _, rr := range rrset {
+			rr.Header().Ttl = ttl
+		}
+		out = append(out, rrset...)
+
+		signers := cfg.DNSSEC.ZoneSigningKeys[:1]
+		if key.rrtype == dns.TypeDNSKEY {
+			signers = cfg.DNSSEC.KeySigningKeys
+		}
+		for _, k := range signers {
+			sig, err := k.sign(key.zone, rrset, now, cfg.DNSSEC.SignatureValidity)
+			if err != nil {
+				d.logger.Warn("failed to sign DNS records",
+					"name", key.name,
+					"type", dns.Type(key.rrtype),
+					"error", err,
+				)
+				continue
+			}
+			out = append(out, sig)
+		}
+	}
+	return append(out, unsigned...)
+}
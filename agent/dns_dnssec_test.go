@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+// writeTestDNSSECKey generates an ECDSA P-256 key and writes it in the files
+// format of dnssec-keygen, it returns the path of the key.
+func writeTestDNSSECKey(t *testing.T, dir string, flags uint16) (string, *dns.DNSKEY) {
+	t.Helper()
+	k := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: "consul.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     flags,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := k.Generate(256)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, fmt.Sprintf("K%s+%03d+%05d", k.Hdr.Name, k.Algorithm, k.KeyTag()))
+	require.NoError(t, os.WriteFile(path+".key", []byte(k.String()+"\n"), 0600))
+	require.NoError(t, os.WriteFile(path+".private", []byte(k.PrivateKeyString(priv)), 0600))
+	return path, k
+}
+
+// verifyDNSSEC checks that every record set of a section is signed by one of
+// the given keys and returns the number of signatures.
+func verifyDNSSEC(t *testing.T, rrs []dns.RR, keys ...*dns.DNSKEY) int {
+	t.Helper()
+	var sigs int
+	for _, rr := range rrs {
+		sig, ok := rr.(*dns.RRSIG)
+		if !ok {
+			continue
+		}
+		var rrset []dns.RR
+		for _, rr := range rrs {
+			if rr.Header().Name == sig.Hdr.Name && rr.Header().Rrtype == sig.TypeCovered {
+				rrset = append(rrset, rr)
+			}
+		}
+		require.NotEmpty(t, rrset, "no records for %s", sig)
+		require.True(t, sig.ValidityPeriod(time.Now()))
+
+		var verified bool
+		for _, k := range keys {
+			if k.KeyTag() == sig.KeyTag && sig.Verify(k, rrset) == nil {
+				verified = true
+			}
+		}
+		require.True(t, verified, "invalid signature %s", sig)
+		sigs++
+	}
+
+	for _, rr := range rrs {
+		if rr.Header().Rrtype == dns.TypeRRSIG || rr.Header().Rrtype == dns.TypeOPT {
+			continue
+		}
+		var signed bool
+		for _, sig := range rrs {
+			if sig, ok := sig.(*dns.RRSIG); ok && sig.Hdr.Name == rr.Header().Name && sig.TypeCovered == rr.Header().Rrtype {
+				signed = true
+			}
+		}
+		require.True(t, signed, "record not signed %s", rr)
+	}
+	return sigs
+}
+
+func TestDNS_DNSSEC(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir := testutil.TempDir(t, "dnssec")
+	kskPath, ksk := writeTestDNSSECKey(t, dir, dns.ZONE|dns.SEP)
+	zskPath, zsk := writeTestDNSSECKey(t, dir, dns.ZONE)
+	nextZSKPath, nextZSK := writeTestDNSSECKey(t, dir, dns.ZONE)
+
+	a := NewTestAgent(t, `
+		dns_config {
+			dnssec {
+				key_signing_keys = ["`+kskPath+`"]
+				zone_signing_keys = ["`+zskPath+`", "`+nextZSKPath+`"]
+			}
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	query := func(t *testing.T, name string, qtype uint16, do bool) *dns.Msg {
+		t.Helper()
+		m := new(dns.Msg)
+		m.SetQuestion(name, qtype)
+		m.SetEdns0(4096, do)
+		c := new(dns.Client)
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		return in
+	}
+
+	t.Run("DNSKEY", func(t *testing.T) {
+		in := query(t, "consul.", dns.TypeDNSKEY, true)
+		require.Equal(t, dns.RcodeSuccess, in.Rcode)
+		require.True(t, in.IsEdns0().Do())
+
+		var tags []uint16
+		for _, rr := range in.Answer {
+			if k, ok := rr.(*dns.DNSKEY); ok {
+				tags = append(tags, k.KeyTag())
+			}
+		}
+		require.ElementsMatch(t, []uint16{ksk.KeyTag(), zsk.KeyTag(), nextZSK.KeyTag()}, tags)
+
+		// The key set is signed by the key signing key only.
+		require.Equal(t, 1, verifyDNSSEC(t, in.Answer, ksk))
+	})
+
+	t.Run("DS", func(t *testing.T) {
+		in := query(t, "consul.", dns.TypeDS, true)
+		require.Equal(t, dns.RcodeSuccess, in.Rcode)
+		require.Len(t, in.Answer, 2)
+		ds, ok := in.Answer[0].(*dns.DS)
+		require.True(t, ok, "First answer is not a DS record")
+		require.Equal(t, ksk.ToDS(dns.SHA256).Digest, ds.Digest)
+		require.Equal(t, 1, verifyDNSSEC(t, in.Answer, zsk))
+	})
+
+	t.Run("signed answer", func(t *testing.T) {
+		in := query(t, a.config.NodeName+".node.consul.", dns.TypeA, true)
+		require.Equal(t, dns.RcodeSuccess, in.Rcode)
+		require.Len(t, in.Answer, 2)
+		// Only the active zone signing key signs the answers.
+		require.Equal(t, 1, verifyDNSSEC(t, in.Answer, zsk))
+	})
+
+	t.Run("unsigned answer without DO", func(t *testing.T) {
+		in := query(t, a.config.NodeName+".node.consul.", dns.TypeA, false)
+		require.Equal(t, dns.RcodeSuccess, in.Rcode)
+		require.Len(t, in.Answer, 1)
+		require.False(t, in.IsEdns0().Do())
+	})
+
+	t.Run("SOA", func(t *testing.T) {
+		in := query(t, "consul.", dns.TypeSOA, true)
+		require.Equal(t, dns.RcodeSuccess, in.Rcode)
+		require.Equal(t, 1, verifyDNSSEC(t, in.Answer, zsk))
+		verifyDNSSEC(t, in.Ns, zsk)
+	})
+
+	t.Run("black lie for NXDOMAIN", func(t *testing.T) {
+		in := query(t, "nope.node.consul.", dns.TypeA, true)
+		require.Equal(t, dns.RcodeSuccess, in.Rcode)
+		require.Empty(t, in.Answer)
+		require.Equal(t, 2, verifyDNSSEC(t, in.Ns, zsk))
+
+		var nsec *dns.NSEC
+		for _, rr := range in.Ns {
+			if rr, ok := rr.(*dns.NSEC); ok {
+				nsec = rr
+			}
+		}
+		require.NotNil(t, nsec)
+		require.Equal(t, "nope.node.consul.", nsec.Hdr.Name)
+		require.Equal(t, "\\000.nope.node.consul.", nsec.NextDomain)
+		require.Equal(t, []uint16{dns.TypeRRSIG, dns.TypeNSEC, dnsTypeNXNAME}, nsec.TypeBitMap)
+	})
+
+	t.Run("NXDOMAIN without DO", func(t *testing.T) {
+		in := query(t, "nope.node.consul.", dns.TypeA, false)
+		require.Equal(t, dns.RcodeNameError, in.Rcode)
+		for _, rr := range in.Ns {
+			require.NotEqual(t, dns.TypeNSEC, rr.Header().Rrtype)
+		}
+	})
+
+	t.Run("black lie for NODATA", func(t *testing.T) {
+		in := query(t, a.config.NodeName+".node.consul.", dns.TypeSRV, true)
+		require.Equal(t, dns.RcodeSuccess, in.Rcode)
+		require.Empty(t, in.Answer)
+		require.Equal(t, 2, verifyDNSSEC(t, in.Ns, zsk))
+
+		var nsec *dns.NSEC
+		for _, rr := range in.Ns {
+			if rr, ok := rr.(*dns.NSEC); ok {
+				nsec = rr
+			}
+		}
+		require.NotNil(t, nsec)
+		require.Contains(t, nsec.TypeBitMap, dns.TypeA)
+		require.NotContains(t, nsec.TypeBitMap, dns.TypeSRV)
+		require.NotContains(t, nsec.TypeBitMap, dnsTypeNXNAME)
+	})
+}
+
+func TestLoadDNSSECKey(t *testing.T) {
+	dir := testutil.TempDir(t, "dnssec")
+	kskPath, ksk := writeTestDNSSECKey(t, dir, dns.ZONE|dns.SEP)
+	zskPath, _ := writeTestDNSSECKey(t, dir, dns.ZONE)
+
+	k, err := loadDNSSECKey(kskPath, true)
+	require.NoError(t, err)
+	require.Equal(t, ksk.KeyTag(), k.tag)
+
+	_, err = loadDNSSECKey(kskPath, false)
+	require.ErrorContains(t, err, "is not a zone signing key")
+
+	_, err = loadDNSSECKey(zskPath, true)
+	require.ErrorContains(t, err, "is not a key signing key")
+
+	_, err = loadDNSSECKey(filepath.Join(dir, "missing"), true)
+	require.ErrorContains(t, err, "failed to read DNSSEC key")
+
+	// The private key of another key is rejected.
+	mismatch := filepath.Join(dir, "mismatch")
+	pub, err := os.ReadFile(kskPath + ".key")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(mismatch+".key", pub, 0600))
+	other, _ := writeTestDNSSECKey(t, dir, dns.ZONE|dns.SEP)
+	priv, err := os.ReadFile(other + ".private")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(mismatch+".private", priv, 0600))
+	_, err = loadDNSSECKey(mismatch, true)
+	require.ErrorContains(t, err, "doesn't match")
+}
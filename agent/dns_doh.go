@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// dohMediaType is the media type of the DNS messages exchanged over HTTPS.
+const dohMediaType = "application/dns-message"
+
+// dohResponseWriter is a dns.ResponseWriter keeping the response to a DNS
+// over HTTPS query so it can be written to the HTTP response.
+type dohResponseWriter struct {
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	msg        *dns.Msg
+}
+
+func (w *dohResponseWriter) LocalAddr() net.Addr  { return w.localAddr }
+func (w *dohResponseWriter) RemoteAddr() net.Addr { return w.remoteAddr }
+
+func (w *dohResponseWriter) WriteMsg(m *dns.Msg) error {
+	w.msg = m
+	return nil
+}
+
+func (w *dohResponseWriter) Write(b []byte) (int, error) {
+	m := new(dns.Msg)
+	if err := m.Unpack(b); err != nil {
+		return 0, err
+	}
+	w.msg = m
+	return len(b), nil
+}
+
+func (w *dohResponseWriter) Close() error        { return nil }
+func (w *dohResponseWriter) TsigStatus() error   { return nil }
+func (w *dohResponseWriter) TsigTimersOnly(bool) {}
+func (w *dohResponseWriter) Hijack()             {}
+
+// DNSQuery serves DNS over HTTPS queries (RFC 8484). The query is handled
+// like a DNS query received over TCP by the agent DNS server, so the same
+// token and recursors are used.
+func (s *HTTPHandlers) DNSQuery(resp http.ResponseWriter, req *http.Request) {
+	// DNS over HTTPS is meant to encrypt DNS, so refuse serving it on the
+	// plain HTTP endpoint.
+	if req.TLS == nil {
+		http.Error(resp, "DNS over HTTPS requires the HTTPS endpoint", http.StatusForbidden)
+		return
+	}
+
+	var raw []byte
+	switch req.Method {
+	case http.MethodGet:
+		param := req.URL.Query().Get("dns")
+		if param == "" {
+			http.Error(resp, "Missing dns query parameter", http.StatusBadRequest)
+			return
+		}
+		var err error
+		raw, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(param, "="))
+		if err != nil {
+			http.Error(resp, fmt.Sprintf("Invalid dns query parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+
+	case http.MethodPost:
+		if ct := req.Header.Get("Content-Type"); ct != dohMediaType {
+			http.Error(resp, fmt.Sprintf("Content-Type must be %s", dohMediaType), http.StatusUnsupportedMediaType)
+			return
+		}
+		var err error
+		raw, err = io.ReadAll(io.LimitReader(req.Body, dns.MaxMsgSize+1))
+		if err != nil {
+			http.Error(resp, fmt.Sprintf("Failed to read query: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(raw) > dns.MaxMsgSize {
+			http.Error(resp, "Query too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+	default:
+		resp.Header().Set("Allow", "GET, POST")
+		http.Error(resp, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg := new(dns.Msg)
+	if err := msg.Unpack(raw); err != nil {
+		http.Error(resp, fmt.Sprintf("Invalid DNS message: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	srv := s.agent.localDNSServer
+	if srv == nil {
+		http.Error(resp, "DNS server is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Report the addresses as TCP ones so that responses aren't truncated
+	// like UDP ones.
+	w := &dohResponseWriter{localAddr: &net.TCPAddr{}, remoteAddr: &net.TCPAddr{}}
+	if addr, ok := req.Context().Value(http.LocalAddrContextKey).(*net.TCPAddr); ok {
+		w.localAddr = addr
+	}
+	if addr, err := net.ResolveTCPAddr("tcp", req.RemoteAddr); err == nil {
+		w.remoteAddr = addr
+	}
+	srv.mux.ServeDNS(w, msg)
+	if w.msg == nil {
+		http.Error(resp, "No DNS response", http.StatusInternalServerError)
+		return
+	}
+
+	out, err := w.msg.Pack()
+	if err != nil {
+		http.Error(resp, fmt.Sprintf("Failed to pack DNS response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	resp.Header().Set("Content-Type", dohMediaType)
+	if ttl, ok := dohMinTTL(w.msg); ok {
+		resp.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", ttl))
+	}
+	resp.Write(out)
+}
+
+// dohMinTTL returns the smallest TTL of the records of a response, which
+// bounds how long it can be cached by HTTP caches.
+func dohMinTTL(m *dns.Msg) (uint32, bool) {
+	var ttl uint32
+	var found bool
+	for _, section := range [][]dns.RR{m.Answer, m.Ns, m.Extra} {
+		for _, rr := range section {
+			if rr.Header().Rrtype == dns.TypeOPT {
+				continue
+			}
+			if !found || rr.Header().Ttl < ttl {
+				ttl = rr.Header().Ttl
+				found = true
+			}
+		}
+	}
+	return ttl, found
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/freeport"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestDNS_OverTLS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	port := freeport.GetOne(t)
+	a := NewTestAgent(t, `
+		ports {
+			dns_tls = `+strconv.Itoa(port)+`
+		}
+		tls {
+			defaults {
+				cert_file = "../test/key/ourdomain.cer"
+				key_file = "../test/key/ourdomain.key"
+			}
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	m := new(dns.Msg)
+	m.SetQuestion(a.config.NodeName+".node.consul.", dns.TypeA)
+
+	c := &dns.Client{
+		Net:       "tcp-tls",
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	in, _, err := c.Exchange(m, "127.0.0.1:"+strconv.Itoa(port))
+	require.NoError(t, err)
+	require.Len(t, in.Answer, 1)
+	aRec, ok := in.Answer[0].(*dns.A)
+	require.True(t, ok, "Answer is not an A record")
+	require.Equal(t, "127.0.0.1", aRec.A.String())
+}
+
+func TestDNSQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	args := &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.1",
+	}
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+
+	m := new(dns.Msg)
+	m.SetQuestion("foo.node.consul.", dns.TypeA)
+	raw, err := m.Pack()
+	require.NoError(t, err)
+
+	verify := func(t *testing.T, resp *httptest.ResponseRecorder) {
+		t.Helper()
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.Equal(t, dohMediaType, resp.Header().Get("Content-Type"))
+		require.Equal(t, "max-age=0", resp.Header().Get("Cache-Control"))
+
+		in := new(dns.Msg)
+		require.NoError(t, in.Unpack(resp.Body.Bytes()))
+		require.Equal(t, m.Id, in.Id)
+		require.Len(t, in.Answer, 1)
+		aRec, ok := in.Answer[0].(*dns.A)
+		require.True(t, ok, "Answer is not an A record")
+		require.Equal(t, "127.0.0.1", aRec.A.String())
+	}
+
+	t.Run("get", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/dns-query?dns="+base64.RawURLEncoding.EncodeToString(raw), nil)
+		req.TLS = &tls.ConnectionState{}
+		resp := httptest.NewRecorder()
+		a.srv.DNSQuery(resp, req)
+		verify(t, resp)
+	})
+
+	t.Run("post", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/dns-query", bytes.NewReader(raw))
+		req.Header.Set("Content-Type", dohMediaType)
+		req.TLS = &tls.ConnectionState{}
+		resp := httptest.NewRecorder()
+		a.srv.DNSQuery(resp, req)
+		verify(t, resp)
+	})
+
+	t.Run("plain http", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/dns-query", bytes.NewReader(raw))
+		req.Header.Set("Content-Type", dohMediaType)
+		resp := httptest.NewRecorder()
+		a.srv.DNSQuery(resp, req)
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("bad content type", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/dns-query", bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		req.TLS = &tls.ConnectionState{}
+		resp := httptest.NewRecorder()
+		a.srv.DNSQuery(resp, req)
+		require.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+	})
+
+	t.Run("bad message", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/dns-query?dns=AAAA", nil)
+		req.TLS = &tls.ConnectionState{}
+		resp := httptest.NewRecorder()
+		a.srv.DNSQuery(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		req, _ := http.NewRequest("PUT", "/dns-query", nil)
+		req.TLS = &tls.ConnectionState{}
+		resp := httptest.NewRecorder()
+		a.srv.DNSQuery(resp, req)
+		require.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+		require.Equal(t, "GET, POST", resp.Header().Get("Allow"))
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/miekg/dns"
+
+	"github.com/hashicorp/consul/acl"
+	cachetype "github.com/hashicorp/consul/agent/cache-types"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// maxTXTStringSize is the maximum length of a character string of a TXT
+// record (RFC 1035).
+const maxTXTStringSize = 255
+
+// dnsKVCacheMaxAge is the age above which a cached KV entry is fetched again
+// when dns_config.cache_max_age isn't set. The KV entries aren't refreshed in
+// the background, without a max age a changed value would never be served.
+const dnsKVCacheMaxAge = 5 * time.Second
+
+// kvLookup answers the lookups of the KV entries as TXT records. The labels
+// are the segments of the key in reverse order, like the labels of a domain
+// name: enabled.flags.kv.consul. is the key flags/enabled. Only the keys
+// under the configured prefix are served, the prefix ends with a slash so
+// that "public" doesn't serve "public-secrets/". The keys with a segment
+// naming another kind of lookup, like "service" or "node", are resolved as
+// that lookup instead.
+func (d *DNSServer) kvLookup(cfg *dnsConfig, datacenter string, labels []string, req, resp *dns.Msg) error {
+	segments := make([]string, len(labels))
+	for i, label := range labels {
+		segments[len(labels)-1-i] = label
+	}
+	key := strings.Join(segments, "/")
+	if !strings.HasPrefix(key, cfg.KVPrefix) {
+		d.logger.Debug("KV lookup outside of the allowed prefix", "key", key)
+		return errNameNotFound
+	}
+
+	maxAge := cfg.CacheMaxAge
+	if maxAge == 0 {
+		maxAge = dnsKVCacheMaxAge
+	}
+	args := structs.KeyRequest{
+		Datacenter:     datacenter,
+		Key:            key,
+		EnterpriseMeta: d.defaultEnterpriseMeta,
+		QueryOptions: structs.QueryOptions{
+			Token:      d.agent.tokens.UserToken(),
+			AllowStale: cfg.AllowStale,
+			MaxAge:     maxAge,
+		},
+	}
+	out, err := d.lookupKV(cfg, &args)
+	if acl.IsErrPermissionDenied(err) {
+		// The keys which can't be read with the DNS token are answered like
+		// the missing ones.
+		d.logger.Debug("KV lookup denied", "key", key)
+		return errNameNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("rpc request failed: %w", err)
+	}
+	if len(out.Entries) == 0 {
+		return errNameNotFound
+	}
+
+	q := req.Question[0]
+	if q.Qtype != dns.TypeTXT && q.Qtype != dns.TypeANY {
+		return errNoData
+	}
+	resp.Answer = append(resp.Answer, &dns.TXT{
+		Hdr: dns.RR_Header{
+			Name:   q.Name,
+			Rrtype: dns.TypeTXT,
+			Class:  dns.ClassINET,
+			Ttl:    uint32(cfg.NodeTTL / time.Second),
+		},
+		Txt: splitTXT(out.Entries[0].Value),
+	})
+	return nil
+}
+
+// lookupKV reads a KV entry from the agent cache.
+func (d *DNSServer) lookupKV(cfg *dnsConfig, args *structs.KeyRequest) (*structs.IndexedDirEntries, error) {
+	var out structs.IndexedDirEntries
+
+	useCache := cfg.UseCache
+RPC:
+	if useCache {
+		raw, _, err := d.agent.cache.Get(context.TODO(), cachetype.KVSGetName, args)
+		if err != nil {
+			return nil, err
+		}
+		reply, ok := raw.(*structs.IndexedDirEntries)
+		if !ok {
+			// This should never happen, but we want to protect against panics
+			return nil, fmt.Errorf("internal error: response type not correct")
+		}
+		out = *reply
+	} else {
+		if err := d.agent.RPC(context.Background(), "KVS.Get", args, &out); err != nil {
+			return nil, err
+		}
+	}
+
+	// Verify that request is not too stale, redo the request
+	if args.AllowStale {
+		if out.LastContact > cfg.MaxStale {
+			args.AllowStale = false
+			useCache = false
+			d.logger.Warn("Query results too stale, re-requesting")
+			goto RPC
+		} else if out.LastContact > staleCounterThreshold {
+			metrics.IncrCounter([]string{"dns", "stale_queries"}, 1)
+		}
+	}
+
+	return &out, nil
+}
+
+// splitTXT splits a value in the character strings of a TXT record. An
+// empty value is a single empty string. The backslashes are escaped since
+// the strings are unescaped when the record is packed.
+func splitTXT(value []byte) []string {
+	txt := []string{}
+	for {
+		n := len(value)
+		if n > maxTXTStringSize {
+			n = maxTXTStringSize
+		}
+		txt = append(txt, strings.ReplaceAll(string(value[:n]), `\`, `\\`))
+		value = value[n:]
+		if len(value) == 0 {
+			return txt
+		}
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func putTestKV(t *testing.T, a *TestAgent, key, value, token string) {
+	t.Helper()
+	args := structs.KVSRequest{
+		Datacenter: a.config.Datacenter,
+		Op:         api.KVSet,
+		DirEnt: structs.DirEntry{
+			Key:   key,
+			Value: []byte(value),
+		},
+		WriteRequest: structs.WriteRequest{Token: token},
+	}
+	var out bool
+	require.NoError(t, a.RPC(context.Background(), "KVS.Apply", &args, &out))
+}
+
+func TestDNS_KVLookup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			kv_prefix = "flags/"
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	large := strings.Repeat("a", 600)
+	putTestKV(t, a, "flags/Appliance/enabled", "true", "")
+	putTestKV(t, a, "flags/large", large, "")
+	putTestKV(t, a, "flags/empty", "", "")
+	putTestKV(t, a, "secret/password", "hunter2", "")
+
+	query := func(t *testing.T, name string, qtype uint16) *dns.Msg {
+		t.Helper()
+		m := new(dns.Msg)
+		m.SetQuestion(name, qtype)
+		c := &dns.Client{Net: "tcp"}
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		return in
+	}
+
+	for _, name := range []string{
+		"enabled.Appliance.flags.kv.consul.",
+		"enabled.Appliance.flags.kv.dc1.consul.",
+	} {
+		t.Run(name, func(t *testing.T) {
+			in := query(t, name, dns.TypeTXT)
+			require.Equal(t, dns.RcodeSuccess, in.Rcode)
+			require.Len(t, in.Answer, 1)
+			txt, ok := in.Answer[0].(*dns.TXT)
+			require.True(t, ok, "Answer is not a TXT record")
+			require.Equal(t, name, txt.Hdr.Name)
+			require.Equal(t, []string{"true"}, txt.Txt)
+		})
+	}
+
+	t.Run("chunked value", func(t *testing.T) {
+		in := query(t, "large.flags.kv.consul.", dns.TypeTXT)
+		require.Len(t, in.Answer, 1)
+		txt := in.Answer[0].(*dns.TXT)
+		require.Len(t, txt.Txt, 3)
+		require.Len(t, txt.Txt[0], 255)
+		require.Len(t, txt.Txt[2], 90)
+		require.Equal(t, large, strings.Join(txt.Txt, ""))
+	})
+
+	t.Run("empty value", func(t *testing.T) {
+		in := query(t, "empty.flags.kv.consul.", dns.TypeTXT)
+		require.Len(t, in.Answer, 1)
+		require.Equal(t, []string{""}, in.Answer[0].(*dns.TXT).Txt)
+	})
+
+	t.Run("keys are case sensitive", func(t *testing.T) {
+		in := query(t, "enabled.appliance.flags.kv.consul.", dns.TypeTXT)
+		require.Equal(t, dns.RcodeNameError, in.Rcode)
+	})
+
+	t.Run("other types", func(t *testing.T) {
+		in := query(t, "enabled.Appliance.flags.kv.consul.", dns.TypeA)
+		require.Equal(t, dns.RcodeSuccess, in.Rcode)
+		require.Empty(t, in.Answer)
+		require.Len(t, in.Ns, 1)
+	})
+
+	t.Run("outside of the prefix", func(t *testing.T) {
+		in := query(t, "password.secret.kv.consul.", dns.TypeTXT)
+		require.Equal(t, dns.RcodeNameError, in.Rcode)
+		require.Empty(t, in.Answer)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		in := query(t, "missing.flags.kv.consul.", dns.TypeTXT)
+		require.Equal(t, dns.RcodeNameError, in.Rcode)
+	})
+}
+
+func TestDNS_KVLookup_PrefixBoundary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			kv_prefix = "public"
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	putTestKV(t, a, "public/motd", "hello", "")
+	putTestKV(t, a, "public-secrets/password", "hunter2", "")
+
+	query := func(t *testing.T, name string) *dns.Msg {
+		t.Helper()
+		m := new(dns.Msg)
+		m.SetQuestion(name, dns.TypeTXT)
+		c := new(dns.Client)
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		return in
+	}
+
+	in := query(t, "motd.public.kv.consul.")
+	require.Len(t, in.Answer, 1)
+	require.Equal(t, []string{"hello"}, in.Answer[0].(*dns.TXT).Txt)
+
+	in = query(t, "password.public-secrets.kv.consul.")
+	require.Equal(t, dns.RcodeNameError, in.Rcode)
+	require.Empty(t, in.Answer)
+}
+
+func TestDNS_KVLookup_Cache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			kv_prefix = "flags/"
+			use_cache = true
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	putTestKV(t, a, "flags/enabled", "false", "")
+
+	lookup := func(t *testing.T) []string {
+		t.Helper()
+		m := new(dns.Msg)
+		m.SetQuestion("enabled.flags.kv.consul.", dns.TypeTXT)
+		c := new(dns.Client)
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		require.Len(t, in.Answer, 1)
+		return in.Answer[0].(*dns.TXT).Txt
+	}
+	require.Equal(t, []string{"false"}, lookup(t))
+
+	// The cached value is served until it is older than the max age, which
+	// has a default for the KV lookups since the entries aren't refreshed.
+	putTestKV(t, a, "flags/enabled", "true", "")
+	require.Equal(t, []string{"false"}, lookup(t))
+	require.Eventually(t, func() bool {
+		return lookup(t)[0] == "true"
+	}, 2*dnsKVCacheMaxAge, 100*time.Millisecond)
+}
+
+func TestDNS_KVLookup_DatacenterNamedKV(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		datacenter = "kv"
+		dns_config {
+			kv_prefix = "flags/"
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "kv")
+
+	args := &structs.RegisterRequest{
+		Datacenter: "kv",
+		Node:       "foo",
+		Address:    "127.0.0.2",
+		Service: &structs.NodeService{
+			Service: "web",
+			Port:    8080,
+		},
+	}
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	putTestKV(t, a, "flags/enabled", "true", "")
+
+	query := func(t *testing.T, name string, qtype uint16) *dns.Msg {
+		t.Helper()
+		m := new(dns.Msg)
+		m.SetQuestion(name, qtype)
+		c := new(dns.Client)
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		return in
+	}
+
+	for _, name := range []string{"foo.node.kv.consul.", "web.service.kv.consul."} {
+		t.Run(name, func(t *testing.T) {
+			in := query(t, name, dns.TypeA)
+			require.Len(t, in.Answer, 1)
+			require.Equal(t, "127.0.0.2", in.Answer[0].(*dns.A).A.String())
+		})
+	}
+
+	t.Run("kv lookup in the kv datacenter", func(t *testing.T) {
+		in := query(t, "enabled.flags.kv.kv.consul.", dns.TypeTXT)
+		require.Len(t, in.Answer, 1)
+		require.Equal(t, []string{"true"}, in.Answer[0].(*dns.TXT).Txt)
+	})
+}
+
+func TestDNS_KVLookup_Disabled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	putTestKV(t, a, "flags/enabled", "true", "")
+
+	m := new(dns.Msg)
+	m.SetQuestion("enabled.flags.kv.consul.", dns.TypeTXT)
+	c := new(dns.Client)
+	in, _, err := c.Exchange(m, a.DNSAddr())
+	require.NoError(t, err)
+	require.Equal(t, dns.RcodeNameError, in.Rcode)
+}
+
+func TestDNS_KVLookup_ACL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	tests := []struct {
+		token string
+		rcode int
+	}{
+		{token: "root", rcode: dns.RcodeSuccess},
+		{token: "anonymous", rcode: dns.RcodeNameError},
+	}
+	for _, tt := range tests {
+		t.Run("ACLToken == "+tt.token, func(t *testing.T) {
+			a := NewTestAgent(t, `
+				primary_datacenter = "dc1"
+
+				acl {
+					enabled = true
+					default_policy = "deny"
+					down_policy = "deny"
+
+					tokens {
+						initial_management = "root"
+						default = "`+tt.token+`"
+					}
+				}
+
+				dns_config {
+					kv_prefix = "flags/"
+				}
+			`)
+			defer a.Shutdown()
+			testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+			putTestKV(t, a, "flags/enabled", "true", "root")
+
+			m := new(dns.Msg)
+			m.SetQuestion("enabled.flags.kv.consul.", dns.TypeTXT)
+			c := new(dns.Client)
+			in, _, err := c.Exchange(m, a.DNSAddr())
+			require.NoError(t, err)
+			require.Equal(t, tt.rcode, in.Rcode)
+		})
+	}
+}
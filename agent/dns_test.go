@@ -4734,6 +4734,166 @@ func TestDNS_ServiceLookup_OnlyPassing(t *testing.T) {
 	require.Equal(t, []string{"127.0.0.1", "127.0.0.2"}, ips)
 }
 
+func TestDNS_ServiceLookup_WeightedOrdering(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			answer_ordering = "weighted"
+			udp_answer_limit = 1
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// Register a canary instance with a low weight next to stable ones.
+	for i := 0; i < 4; i++ {
+		weights := &structs.Weights{Passing: 1000, Warning: 1}
+		if i == 0 {
+			weights = &structs.Weights{Passing: 1, Warning: 1}
+		}
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       fmt.Sprintf("foo%d", i),
+			Address:    fmt.Sprintf("127.0.0.%d", i+1),
+			Service: &structs.NodeService{
+				Service: "web",
+				Port:    8000,
+				Weights: weights,
+			},
+		}
+
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	}
+
+	// The weights are honored when the answers are limited, so the canary
+	// is almost never answered.
+	var canary int
+	for i := 0; i < 50; i++ {
+		m := new(dns.Msg)
+		m.SetQuestion("web.service.consul.", dns.TypeA)
+
+		c := &dns.Client{Net: "udp"}
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		require.Len(t, in.Answer, 1)
+
+		aRec, ok := in.Answer[0].(*dns.A)
+		require.True(t, ok, "Answer is not an A record")
+		if aRec.A.String() == "127.0.0.1" {
+			canary++
+		}
+	}
+	require.LessOrEqual(t, canary, 5)
+}
+
+func TestDNS_ServiceLookup_PreferNear(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		dns_config {
+			answer_ordering = "weighted"
+			answer_prefer_near = true
+			udp_answer_limit = 1
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// Register a near and a far instance with the same weight.
+	for i, rtt := range []time.Duration{time.Millisecond, 500 * time.Millisecond} {
+		node := fmt.Sprintf("foo%d", i)
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Address:    fmt.Sprintf("127.0.0.%d", i+1),
+			Service: &structs.NodeService{
+				Service: "web",
+				Port:    8000,
+			},
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+
+		coordArgs := structs.CoordinateUpdateRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Coord:      lib.GenerateCoordinate(rtt),
+		}
+		require.NoError(t, a.RPC(context.Background(), "Coordinate.Update", &coordArgs, &out))
+	}
+
+	// Coordinates are applied in batches.
+	retry.Run(t, func(r *retry.R) {
+		args := structs.DCSpecificRequest{Datacenter: "dc1"}
+		var out structs.IndexedCoordinates
+		require.NoError(r, a.RPC(context.Background(), "Coordinate.ListNodes", &args, &out))
+		require.Len(r, out.Coordinates, 2)
+	})
+
+	// The far instance is almost never answered.
+	var far int
+	for i := 0; i < 50; i++ {
+		m := new(dns.Msg)
+		m.SetQuestion("web.service.consul.", dns.TypeA)
+
+		c := &dns.Client{Net: "udp"}
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		require.Len(t, in.Answer, 1)
+
+		aRec, ok := in.Answer[0].(*dns.A)
+		require.True(t, ok, "Answer is not an A record")
+		if aRec.A.String() == "127.0.0.2" {
+			far++
+		}
+	}
+	require.LessOrEqual(t, far, 5)
+}
+
+func TestDNS_BiasNear(t *testing.T) {
+	inf := math.Inf(1)
+	cases := map[string]struct {
+		weights  []float64
+		rtts     []float64
+		expected []float64
+	}{
+		"by round trip time": {
+			weights:  []float64{1, 1, 2},
+			rtts:     []float64{0.002, 0.004, 0.004},
+			expected: []float64{500, 250, 500},
+		},
+		"floored round trip time": {
+			weights:  []float64{1, 1},
+			rtts:     []float64{0, 0.0001},
+			expected: []float64{1000, 1000},
+		},
+		"unknown round trip time": {
+			weights:  []float64{1, 1, 1},
+			rtts:     []float64{0.002, inf, 0.01},
+			expected: []float64{500, 100, 100},
+		},
+		"no known round trip time": {
+			weights:  []float64{1, 2},
+			rtts:     []float64{inf, inf},
+			expected: []float64{1, 2},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			biasNear(tc.weights, tc.rtts)
+			require.InDeltaSlice(t, tc.expected, tc.weights, 1e-9)
+		})
+	}
+}
+
 func TestDNS_ServiceLookup_Randomize(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,717 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	agentdns "github.com/hashicorp/consul/agent/dns"
+	grpcDNS "github.com/hashicorp/consul/agent/grpc-external/services/dns"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const (
+	// dnsZoneHistorySize is the number of versions of the zone kept to answer
+	// the IXFR queries with the differences since the version of the client.
+	dnsZoneHistorySize = 16
+
+	// dnsZoneTransferMsgSize is the size above which the records of a zone
+	// transfer are split in another message.
+	dnsZoneTransferMsgSize = 16 * 1024
+
+	// dnsZoneResolveTimeout is how long the hostname of a secondary is
+	// resolved for when checking the client of a zone transfer.
+	dnsZoneResolveTimeout = 2 * time.Second
+
+	// dnsZoneNotifyInterval is the minimum time between two renderings of
+	// the zone by the notifier, so that frequent catalog changes don't
+	// render it continuously.
+	dnsZoneNotifyInterval = time.Second
+
+	// dnsZoneNotifyRetry is the time waited by the notifier after an error,
+	// or while no secondaries are configured.
+	dnsZoneNotifyRetry = 10 * time.Second
+)
+
+// dnsZoneTransferConfig holds the keys and clients allowed to transfer the
+// zone of the DNS domain.
+type dnsZoneTransferConfig struct {
+	// AllowFrom are the networks of the clients allowed to transfer the zone,
+	// in addition to the secondaries. Any client is allowed when neither
+	// are set.
+	AllowFrom []*net.IPNet
+
+	// Secondaries are the addresses notified of the changes of the zone, as
+	// host:port. The host is an IP address or a hostname resolved when a
+	// client transfers the zone.
+	Secondaries []string
+
+	// TSIGKeys are the keys the zone transfer queries must be signed with,
+	// by their fully qualified name.
+	TSIGKeys map[string]dnsTSIGKey
+}
+
+// dnsTSIGKey is a TSIG key allowed to transfer the zone.
+type dnsTSIGKey struct {
+	Algorithm string
+	Secret    string
+
+	// Token is the ACL token the zone is read with for the clients of the
+	// key.
+	Token string
+}
+
+// tsigKeyNames returns the sorted names of the TSIG keys.
+func (cfg *dnsZoneTransferConfig) tsigKeyNames() []string {
+	names := make([]string, 0, len(cfg.TSIGKeys))
+	for name := range cfg.TSIGKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tsigSecrets returns the secrets of the TSIG keys allowed to transfer the
+// zone, which the DNS servers check the signed queries with. They are read
+// when the servers start.
+func (d *DNSServer) tsigSecrets() map[string]string {
+	cfg := d.config.Load().(*dnsConfig)
+	if cfg.ZoneTransfer == nil {
+		return nil
+	}
+	secrets := make(map[string]string, len(cfg.ZoneTransfer.TSIGKeys))
+	for name, key := range cfg.ZoneTransfer.TSIGKeys {
+		secrets[name] = key.Secret
+	}
+	return secrets
+}
+
+// dnsZoneVersion is the content of the zone at a given serial.
+type dnsZoneVersion struct {
+	serial uint32
+
+	// records are sorted by their text representation, kept in keys.
+	records []dns.RR
+	keys    []string
+}
+
+// newDNSZoneVersion sorts and deduplicates the records of a version of the
+// zone. The serial is set when the version is recorded by the zone.
+func newDNSZoneVersion(rrs []dns.RR) *dnsZoneVersion {
+	byKey := make(map[string]dns.RR, len(rrs))
+	for _, rr := range rrs {
+		byKey[rr.String()] = rr
+	}
+
+	v := &dnsZoneVersion{keys: make([]string, 0, len(byKey))}
+	for key := range byKey {
+		v.keys = append(v.keys, key)
+	}
+	sort.Strings(v.keys)
+	for _, key := range v.keys {
+		v.records = append(v.records, byKey[key])
+	}
+	return v
+}
+
+// equal returns whether two versions hold the same records.
+func (v *dnsZoneVersion) equal(other *dnsZoneVersion) bool {
+	if len(v.keys) != len(other.keys) {
+		return false
+	}
+	for i := range v.keys {
+		if v.keys[i] != other.keys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// diff returns the records deleted and added since an older version.
+func (v *dnsZoneVersion) diff(old *dnsZoneVersion) (deleted, added []dns.RR) {
+	current := make(map[string]struct{}, len(v.keys))
+	for _, key := range v.keys {
+		current[key] = struct{}{}
+	}
+	previous := make(map[string]struct{}, len(old.keys))
+	for i, key := range old.keys {
+		previous[key] = struct{}{}
+		if _, ok := current[key]; !ok {
+			deleted = append(deleted, old.records[i])
+		}
+	}
+	for i, key := range v.keys {
+		if _, ok := previous[key]; !ok {
+			added = append(added, v.records[i])
+		}
+	}
+	return deleted, added
+}
+
+// dnsZones keeps the zone of the DNS domain of each TSIG key, shared by the
+// DNS servers of the agent. The zones hold what the tokens of the keys can
+// read, so each has its own versions and serials.
+type dnsZones struct {
+	lock  sync.Mutex
+	zones map[string]*dnsZone
+}
+
+// zone returns the zone of the given TSIG key.
+func (z *dnsZones) zone(key string) *dnsZone {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	if z.zones == nil {
+		z.zones = make(map[string]*dnsZone)
+	}
+	zone, ok := z.zones[key]
+	if !ok {
+		zone = new(dnsZone)
+		z.zones[key] = zone
+	}
+	return zone
+}
+
+// dnsZone keeps the last versions of the zone of the DNS domain.
+type dnsZone struct {
+	lock     sync.Mutex
+	versions []*dnsZoneVersion
+
+	// serial is the serial of the latest version. It isn't derived from the
+	// catalog index, which goes back when a snapshot is restored, so that the
+	// secondaries always see the zone moving forward.
+	serial uint32
+}
+
+// update records a version of the zone and returns the version to serve. The
+// latest version is kept when the records didn't change, otherwise the new
+// version gets the next serial.
+func (z *dnsZone) update(v *dnsZoneVersion) *dnsZoneVersion {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	if n := len(z.versions); n > 0 && z.versions[n-1].equal(v) {
+		return z.versions[n-1]
+	}
+
+	z.serial = nextDNSZoneSerial(z.serial, uint32(time.Now().Unix()))
+	v.serial = z.serial
+	z.versions = append(z.versions, v)
+	if len(z.versions) > dnsZoneHistorySize {
+		z.versions = z.versions[len(z.versions)-dnsZoneHistorySize:]
+	}
+	return v
+}
+
+// nextDNSZoneSerial returns the serial following last: the current Unix time
+// like the serial of the SOA lookups, or last+1 when the time isn't after
+// last. Serials are compared and incremented with the serial number
+// arithmetic of RFC 1982 so that they keep moving forward when they wrap
+// around, and across restarts of the agent.
+func nextDNSZoneSerial(last, now uint32) uint32 {
+	if dnsSerialAfter(now, last) {
+		return now
+	}
+	return last + 1
+}
+
+// dnsSerialAfter returns whether the serial s1 is after s2 in the serial
+// number arithmetic of RFC 1982.
+func dnsSerialAfter(s1, s2 uint32) bool {
+	return int32(s1-s2) > 0
+}
+
+// version returns the version of the zone at the given serial, or nil if it
+// is no longer in the history.
+func (z *dnsZone) version(serial uint32) *dnsZoneVersion {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	for _, v := range z.versions {
+		if v.serial == serial {
+			return v
+		}
+	}
+	return nil
+}
+
+// renderZone returns the records of the zone of the DNS domain: the nodes and
+// the instances of the services which would be answered by the DNS lookups of
+// the local datacenter, along with the catalog index. The catalog is read with
+// the token of the TSIG key of the client, so that the zone only holds what
+// the token can read with node:read and service:read. It blocks until the
+// catalog index is greater than minIndex.
+func (d *DNSServer) renderZone(cfg *dnsConfig, token string, minIndex uint64) (*dnsZoneVersion, uint64, error) {
+	args := structs.DCSpecificRequest{
+		Datacenter: d.agent.config.Datacenter,
+		QueryOptions: structs.QueryOptions{
+			Token:            token,
+			AllowStale:       cfg.AllowStale,
+			MaxStaleDuration: cfg.MaxStale,
+			MinQueryIndex:    minIndex,
+		},
+		EnterpriseMeta: d.defaultEnterpriseMeta,
+	}
+	var out structs.IndexedNodeDump
+	if err := d.agent.RPC(context.Background(), "Internal.NodeDump", &args, &out); err != nil {
+		return nil, 0, fmt.Errorf("rpc request failed: %w", err)
+	}
+
+	var rrs []dns.RR
+	// Imported nodes are in the dump of the peers, which are only answered
+	// under the peer labels and aren't part of the zone.
+	for _, n := range out.Dump {
+		if n.PeerName != "" {
+			continue
+		}
+		rrs = append(rrs, d.zoneNodeRecords(cfg, n)...)
+	}
+	return newDNSZoneVersion(rrs), out.Index, nil
+}
+
+// zoneNodeRecords returns the records of a node and of the instances of
+// services it runs.
+func (d *DNSServer) zoneNodeRecords(cfg *dnsConfig, n *structs.NodeInfo) []dns.RR {
+	dc := d.agent.config.Datacenter
+	node := &structs.Node{
+		ID:              n.ID,
+		Node:            n.Node,
+		Partition:       n.Partition,
+		Address:         n.Address,
+		Datacenter:      dc,
+		TaggedAddresses: n.TaggedAddresses,
+		Meta:            n.Meta,
+	}
+
+	var rrs []dns.RR
+	validNode := !agentdns.InvalidNameRe.MatchString(n.Node)
+	if validNode {
+		for _, name := range d.zoneNames(n.Node + ".node") {
+			rrs = append(rrs, zoneAddressRecords(name, n.Address, cfg.NodeTTL)...)
+			if cfg.NodeMetaTXT && net.ParseIP(n.Address) != nil {
+				rrs = append(rrs, d.generateMeta(name, node, cfg.NodeTTL)...)
+			}
+		}
+	}
+
+	for _, svc := range n.Services {
+		// Only the services of the default namespace are answered without
+		// the namespace labels.
+		if !svc.EnterpriseMeta.InDefaultNamespace() || agentdns.InvalidNameRe.MatchString(svc.Service) {
+			continue
+		}
+
+		var checks structs.HealthChecks
+		for _, c := range n.Checks {
+			if c.ServiceID == "" || c.ServiceID == svc.ID {
+				checks = append(checks, c)
+			}
+		}
+		instance := structs.CheckServiceNode{Node: node, Service: svc, Checks: checks}
+		if len(structs.CheckServiceNodes{instance}.Filter(cfg.OnlyPassing)) == 0 {
+			continue
+		}
+		rrs = append(rrs, d.zoneServiceRecords(cfg, instance, validNode)...)
+
+		// All the servers are name servers of the zone, unlike the NS
+		// lookups which return three of them at random.
+		if svc.Service == structs.ConsulServiceName && validNode {
+			rrs = append(rrs, &dns.NS{
+				Hdr: dns.RR_Header{
+					Name:   d.domain,
+					Rrtype: dns.TypeNS,
+					Class:  dns.ClassINET,
+					Ttl:    uint32(cfg.NodeTTL / time.Second),
+				},
+				Ns: d.zoneNames(n.Node + ".node")[1],
+			})
+		}
+	}
+	return rrs
+}
+
+// zoneServiceRecords returns the A, AAAA and SRV records of an instance of a
+// service under the names of the service, of its tags and of RFC 2782.
+func (d *DNSServer) zoneServiceRecords(cfg *dnsConfig, instance structs.CheckServiceNode, validNode bool) []dns.RR {
+	svc := instance.Service
+	ttl, _ := cfg.GetTTLForService(svc.Service)
+
+	addr := svc.Address
+	if addr == "" {
+		addr = instance.Node.Address
+	}
+	ip := net.ParseIP(addr)
+
+	var rrs []dns.RR
+
+	// The SRV records target the node when the instance uses its address, an
+	// addr name otherwise, like the service lookups.
+	var target string
+	switch {
+	case svc.Address == "" && validNode:
+		target = d.zoneNames(instance.Node.Node + ".node")[1]
+	case ip != nil:
+		target = d.encodeIPAsFqdn(d.domain, serviceLookup{Datacenter: d.agent.config.Datacenter}, ip)
+		rrs = append(rrs, zoneAddressRecords(target, addr, ttl)...)
+	default:
+		target = dns.Fqdn(addr)
+	}
+
+	names := d.zoneNames(svc.Service + ".service")
+	srvNames := append(d.zoneNames("_"+svc.Service+"._tcp.service"), names...)
+	for _, tag := range svc.Tags {
+		if agentdns.InvalidNameRe.MatchString(tag) {
+			continue
+		}
+		tagNames := d.zoneNames(tag + "." + svc.Service + ".service")
+		names = append(names, tagNames...)
+		srvNames = append(srvNames, tagNames...)
+		srvNames = append(srvNames, d.zoneNames("_"+svc.Service+"._"+tag+".service")...)
+	}
+
+	if ip != nil {
+		for _, name := range names {
+			rrs = append(rrs, zoneAddressRecords(name, addr, ttl)...)
+		}
+	}
+	weight := uint16(findWeight(instance))
+	for _, name := range srvNames {
+		rrs = append(rrs, &dns.SRV{
+			Hdr: dns.RR_Header{
+				Name:   name,
+				Rrtype: dns.TypeSRV,
+				Class:  dns.ClassINET,
+				Ttl:    uint32(ttl / time.Second),
+			},
+			Priority: 1,
+			Weight:   weight,
+			Port:     uint16(d.agent.TranslateServicePort(d.agent.config.Datacenter, svc.Port, svc.TaggedAddresses)),
+			Target:   target,
+		})
+	}
+	return rrs
+}
+
+// zoneNames returns the names of the zone for the given labels, without and
+// with the datacenter label.
+func (d *DNSServer) zoneNames(labels string) []string {
+	labels = strings.ToLower(labels)
+	return []string{
+		labels + "." + d.domain,
+		labels + "." + strings.ToLower(d.agent.config.Datacenter) + "." + d.domain,
+	}
+}
+
+// zoneAddressRecords returns the A or AAAA record of an IP address, or the
+// CNAME record of a hostname.
+func zoneAddressRecords(name, addr string, ttl time.Duration) []dns.RR {
+	if addr == "" {
+		return nil
+	}
+	if ip := net.ParseIP(addr); ip != nil {
+		rr := makeARecord(dns.TypeANY, ip, ttl)
+		rr.Header().Name = name
+		return []dns.RR{rr}
+	}
+	return []dns.RR{&dns.CNAME{
+		Hdr: dns.RR_Header{
+			Name:   name,
+			Rrtype: dns.TypeCNAME,
+			Class:  dns.ClassINET,
+			Ttl:    uint32(ttl / time.Second),
+		},
+		Target: dns.Fqdn(addr),
+	}}
+}
+
+// zoneSOA returns the SOA record of the zone at the given serial.
+func (d *DNSServer) zoneSOA(cfg *dnsConfig, serial uint32) *dns.SOA {
+	soa := d.soa(cfg, d.domain)
+	soa.Serial = serial
+	return soa
+}
+
+// zoneTransferKey returns the TSIG record of a zone transfer query and the key
+// which signed it, or an error if the client isn't allowed to transfer the
+// zone. The query must be signed with one of the configured keys, and the
+// client must be in the allowed networks or a secondary when any are set.
+func (d *DNSServer) zoneTransferKey(cfg *dnsConfig, resp dns.ResponseWriter, req *dns.Msg) (*dns.TSIG, dnsTSIGKey, error) {
+	// The transfers are sent in several messages which the DNS over HTTPS and
+	// gRPC writers can't hold, and these writers don't check the signatures.
+	switch resp.(type) {
+	case *dohResponseWriter, *grpcDNS.BufferResponseWriter:
+		return nil, dnsTSIGKey{}, errors.New("zone transfers are only served over UDP, TCP and TLS")
+	}
+
+	tsig := req.IsTsig()
+	if tsig == nil {
+		return nil, dnsTSIGKey{}, errors.New("query not signed with a TSIG key")
+	}
+	key, ok := cfg.ZoneTransfer.TSIGKeys[strings.ToLower(tsig.Hdr.Name)]
+	if !ok {
+		return nil, dnsTSIGKey{}, fmt.Errorf("unknown TSIG key %q", tsig.Hdr.Name)
+	}
+	if err := resp.TsigStatus(); err != nil {
+		return nil, dnsTSIGKey{}, fmt.Errorf("invalid TSIG signature: %w", err)
+	}
+	if !strings.EqualFold(tsig.Algorithm, key.Algorithm) {
+		return nil, dnsTSIGKey{}, fmt.Errorf("TSIG algorithm %q doesn't match the key", tsig.Algorithm)
+	}
+	if !d.zoneTransferClientAllowed(cfg, resp) {
+		return nil, dnsTSIGKey{}, errors.New("client not allowed")
+	}
+	return tsig, key, nil
+}
+
+// zoneTransferClientAllowed returns whether the address of the client of a
+// zone transfer is in the allowed networks or is a secondary.
+func (d *DNSServer) zoneTransferClientAllowed(cfg *dnsConfig, resp dns.ResponseWriter) bool {
+	if len(cfg.ZoneTransfer.AllowFrom) == 0 && len(cfg.ZoneTransfer.Secondaries) == 0 {
+		return true
+	}
+
+	var ip net.IP
+	switch addr := resp.RemoteAddr().(type) {
+	case *net.TCPAddr:
+		ip = addr.IP
+	case *net.UDPAddr:
+		ip = addr.IP
+	default:
+		return false
+	}
+
+	for _, network := range cfg.ZoneTransfer.AllowFrom {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	for _, secondary := range cfg.ZoneTransfer.Secondaries {
+		host, _, err := net.SplitHostPort(secondary)
+		if err != nil {
+			continue
+		}
+		for _, addr := range d.resolveZoneSecondary(host) {
+			if ip.Equal(addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveZoneSecondary returns the IP addresses of the host of a secondary,
+// which is either an IP address or a hostname.
+func (d *DNSServer) resolveZoneSecondary(host string) []net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dnsZoneResolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		d.logger.Warn("failed to resolve the zone transfer secondary", "secondary", host, "error", err)
+		return nil
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	return ips
+}
+
+// handleZoneTransfer answers the AXFR and IXFR queries for the zone of the
+// DNS domain. The answers are signed with the TSIG key of the query.
+func (d *DNSServer) handleZoneTransfer(cfg *dnsConfig, resp dns.ResponseWriter, req *dns.Msg, network string) {
+	q := req.Question[0]
+
+	m := new(dns.Msg)
+	m.SetReply(req)
+	m.Authoritative = true
+
+	tsig, key, err := d.zoneTransferKey(cfg, resp, req)
+	if err != nil {
+		d.logger.Warn("zone transfer refused",
+			"type", dns.Type(q.Qtype),
+			"client", resp.RemoteAddr().String(),
+			"error", err,
+		)
+		m.SetRcode(req, dns.RcodeRefused)
+		d.writeZoneTransferMsg(resp, m)
+		return
+	}
+	signZoneTransferMsg(m, tsig)
+
+	// The full zone doesn't fit in a UDP response, the clients retry an
+	// outdated IXFR over TCP.
+	if q.Qtype == dns.TypeAXFR && network != "tcp" {
+		m.SetRcode(req, dns.RcodeRefused)
+		d.writeZoneTransferMsg(resp, m)
+		return
+	}
+
+	current, _, err := d.renderZone(cfg, key.Token, 0)
+	if err != nil {
+		d.logger.Warn("failed to render the zone", "error", err)
+		m.SetRcode(req, dns.RcodeServerFailure)
+		d.writeZoneTransferMsg(resp, m)
+		return
+	}
+	zone := d.agent.dnsZones.zone(strings.ToLower(tsig.Hdr.Name))
+	current = zone.update(current)
+	soa := d.zoneSOA(cfg, current.serial)
+
+	// The IXFR queries hold the SOA record of the version of the client in
+	// the authority section.
+	if q.Qtype == dns.TypeIXFR {
+		var clientSOA *dns.SOA
+		for _, rr := range req.Ns {
+			if rr, ok := rr.(*dns.SOA); ok {
+				clientSOA = rr
+			}
+		}
+		if clientSOA != nil && clientSOA.Serial == current.serial || network != "tcp" {
+			m.Answer = []dns.RR{soa}
+			d.writeZoneTransferMsg(resp, m)
+			return
+		}
+		if clientSOA != nil {
+			if old := zone.version(clientSOA.Serial); old != nil {
+				deleted, added := current.diff(old)
+				rrs := []dns.RR{soa, d.zoneSOA(cfg, old.serial)}
+				rrs = append(rrs, deleted...)
+				rrs = append(rrs, soa)
+				rrs = append(rrs, added...)
+				rrs = append(rrs, soa)
+				d.writeZoneTransfer(resp, req, tsig, rrs)
+				return
+			}
+		}
+		// The version of the client isn't in the history anymore, the
+		// whole zone is sent like for AXFR.
+	}
+
+	rrs := make([]dns.RR, 0, len(current.records)+2)
+	rrs = append(rrs, soa)
+	rrs = append(rrs, current.records...)
+	rrs = append(rrs, soa)
+	d.writeZoneTransfer(resp, req, tsig, rrs)
+}
+
+// writeZoneTransfer writes the records of a zone transfer, split in several
+// messages signed with the TSIG key of the query.
+func (d *DNSServer) writeZoneTransfer(resp dns.ResponseWriter, req *dns.Msg, tsig *dns.TSIG, rrs []dns.RR) {
+	reply := func() *dns.Msg {
+		m := new(dns.Msg)
+		m.SetReply(req)
+		m.Authoritative = true
+		m.Compress = true
+		signZoneTransferMsg(m, tsig)
+		return m
+	}
+
+	m := reply()
+	for _, rr := range rrs {
+		m.Answer = append(m.Answer, rr)
+		if len(m.Answer) > 1 && m.Len() > dnsZoneTransferMsgSize {
+			m.Answer = m.Answer[:len(m.Answer)-1]
+			if !d.writeZoneTransferMsg(resp, m) {
+				return
+			}
+			// The messages after the first one are signed with the timers
+			// only, as described in RFC 8945.
+			resp.TsigTimersOnly(true)
+			m = reply()
+			m.Answer = append(m.Answer, rr)
+		}
+	}
+	d.writeZoneTransferMsg(resp, m)
+}
+
+// signZoneTransferMsg adds a TSIG record with the key of the query to a
+// message, which the DNS server signs when writing it.
+func signZoneTransferMsg(m *dns.Msg, tsig *dns.TSIG) {
+	m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, tsig.Fudge, time.Now().Unix())
+}
+
+func (d *DNSServer) writeZoneTransferMsg(resp dns.ResponseWriter, m *dns.Msg) bool {
+	if err := resp.WriteMsg(m); err != nil {
+		d.logger.Warn("failed to respond", "error", err)
+		return false
+	}
+	return true
+}
+
+// notifySecondaries watches the catalog and sends a NOTIFY to the secondaries
+// every time the zone of a TSIG key changes, until stopCh is closed.
+func (d *DNSServer) notifySecondaries(stopCh <-chan struct{}) {
+	indexes := make(map[string]uint64)
+	notified := make(map[string]uint32)
+	for {
+		wait := dnsZoneNotifyInterval
+
+		cfg := d.config.Load().(*dnsConfig)
+		if cfg.ZoneTransfer == nil || len(cfg.ZoneTransfer.Secondaries) == 0 {
+			wait = dnsZoneNotifyRetry
+		} else {
+			for _, name := range cfg.ZoneTransfer.tsigKeyNames() {
+				key := cfg.ZoneTransfer.TSIGKeys[name]
+				current, idx, err := d.renderZone(cfg, key.Token, indexes[name])
+				if err != nil {
+					d.logger.Warn("failed to render the zone", "tsig_key", name, "error", err)
+					wait = dnsZoneNotifyRetry
+					continue
+				}
+				indexes[name] = idx
+				current = d.agent.dnsZones.zone(name).update(current)
+				if current.serial != notified[name] {
+					d.sendNotify(cfg, current.serial)
+					notified[name] = current.serial
+				}
+			}
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// sendNotify sends a NOTIFY of the given serial to the secondaries. It isn't
+// signed since the secondaries aren't tied to a TSIG key, it only makes them
+// transfer the zone with their key.
+func (d *DNSServer) sendNotify(cfg *dnsConfig, serial uint32) {
+	for _, secondary := range cfg.ZoneTransfer.Secondaries {
+		m := new(dns.Msg)
+		m.SetNotify(d.domain)
+		m.Authoritative = true
+		m.Answer = []dns.RR{d.zoneSOA(cfg, serial)}
+
+		c := &dns.Client{Net: "udp"}
+		in, _, err := c.Exchange(m, secondary)
+		if err == nil && in.Rcode != dns.RcodeSuccess {
+			err = fmt.Errorf("rcode %s", dns.RcodeToString[in.Rcode])
+		}
+		if err != nil {
+			d.logger.Warn("failed to notify zone change",
+				"secondary", secondary,
+				"serial", serial,
+				"error", err,
+			)
+			continue
+		}
+		d.logger.Debug("notified zone change", "secondary", secondary, "serial", serial)
+	}
+}
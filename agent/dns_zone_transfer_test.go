@@ -0,0 +1,443 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/freeport"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/hashicorp/consul/types"
+)
+
+const (
+	testTSIGKeyName = "transfer."
+	testTSIGSecret  = "c2VjcmV0IGtleSBvZiB0aGUgem9uZSB0cmFuc2ZlcnM="
+)
+
+// testTSIGSecrets are the secrets the clients of the tests sign their queries
+// with.
+var testTSIGSecrets = map[string]string{testTSIGKeyName: testTSIGSecret}
+
+// zoneTransferConfig returns the configuration of the zone transfers with a
+// TSIG key reading the zone with the given token, and the other options.
+func zoneTransferConfig(token, options string) string {
+	return `
+		dns_config {
+			zone_transfer {
+				tsig_keys = [{
+					name = "` + testTSIGKeyName + `"
+					secret = "` + testTSIGSecret + `"
+					token = "` + token + `"
+				}]
+				` + options + `
+			}
+		}
+	`
+}
+
+// signZoneQuery signs a query with the TSIG key of the tests.
+func signZoneQuery(m *dns.Msg) *dns.Msg {
+	m.SetTsig(testTSIGKeyName, dns.HmacSHA256, 300, time.Now().Unix())
+	return m
+}
+
+// transferZone runs a zone transfer and returns the records received.
+func transferZone(t *testing.T, addr string, m *dns.Msg) ([]dns.RR, error) {
+	t.Helper()
+	tr := &dns.Transfer{TsigSecret: testTSIGSecrets}
+	ch, err := tr.In(m, addr)
+	require.NoError(t, err)
+
+	var rrs []dns.RR
+	for env := range ch {
+		if env.Error != nil {
+			return nil, env.Error
+		}
+		rrs = append(rrs, env.RR...)
+	}
+	return rrs, nil
+}
+
+func TestDNS_ZoneTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, zoneTransferConfig("zone-token", `allow_from = ["127.0.0.0/8"]`))
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	register := func(node, addr, service, status string) {
+		t.Helper()
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Address:    addr,
+			Service: &structs.NodeService{
+				ID:      service,
+				Service: service,
+				Tags:    []string{"primary"},
+				Port:    12345,
+			},
+			Check: &structs.HealthCheck{
+				CheckID:   types.CheckID(service),
+				Name:      service,
+				ServiceID: service,
+				Status:    status,
+			},
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	}
+	register("foo", "127.0.0.2", "db", api.HealthPassing)
+	register("bar", "127.0.0.3", "db", api.HealthCritical)
+
+	m := new(dns.Msg)
+	m.SetAxfr("consul.")
+	rrs, err := transferZone(t, a.DNSAddr(), signZoneQuery(m))
+	require.NoError(t, err)
+
+	// The transfer starts and ends with the SOA record.
+	first, ok := rrs[0].(*dns.SOA)
+	require.True(t, ok, "First record is not a SOA record")
+	last, ok := rrs[len(rrs)-1].(*dns.SOA)
+	require.True(t, ok, "Last record is not a SOA record")
+	require.Equal(t, first.Serial, last.Serial)
+
+	records := make(map[string]bool)
+	for _, rr := range rrs {
+		records[rr.String()] = true
+	}
+	for _, rr := range []string{
+		"foo.node.consul.\t0\tIN\tA\t127.0.0.2",
+		"foo.node.dc1.consul.\t0\tIN\tA\t127.0.0.2",
+		"bar.node.consul.\t0\tIN\tA\t127.0.0.3",
+		"db.service.consul.\t0\tIN\tA\t127.0.0.2",
+		"primary.db.service.dc1.consul.\t0\tIN\tA\t127.0.0.2",
+		"db.service.consul.\t0\tIN\tSRV\t1 1 12345 foo.node.dc1.consul.",
+		"_db._tcp.service.consul.\t0\tIN\tSRV\t1 1 12345 foo.node.dc1.consul.",
+		"_db._primary.service.dc1.consul.\t0\tIN\tSRV\t1 1 12345 foo.node.dc1.consul.",
+		"consul.\t0\tIN\tNS\t" + strings.ToLower(a.config.NodeName) + ".node.dc1.consul.",
+	} {
+		require.True(t, records[rr], "missing record %s", rr)
+	}
+	// The critical instance isn't served.
+	require.False(t, records["db.service.consul.\t0\tIN\tA\t127.0.0.3"])
+
+	t.Run("IXFR up to date", func(t *testing.T) {
+		m := new(dns.Msg)
+		m.SetIxfr("consul.", first.Serial, "ns.consul.", "hostmaster.consul.")
+
+		c := &dns.Client{Net: "tcp", TsigSecret: testTSIGSecrets}
+		in, _, err := c.Exchange(signZoneQuery(m), a.DNSAddr())
+		require.NoError(t, err)
+		require.NotNil(t, in.IsTsig(), "Answer is not signed")
+		require.Len(t, in.Answer, 1)
+		soa, ok := in.Answer[0].(*dns.SOA)
+		require.True(t, ok, "Answer is not a SOA record")
+		require.Equal(t, first.Serial, soa.Serial)
+	})
+
+	t.Run("IXFR", func(t *testing.T) {
+		register("baz", "127.0.0.4", "web", api.HealthPassing)
+
+		m := new(dns.Msg)
+		m.SetIxfr("consul.", first.Serial, "ns.consul.", "hostmaster.consul.")
+		rrs, err := transferZone(t, a.DNSAddr(), signZoneQuery(m))
+		require.NoError(t, err)
+
+		// The differences are framed by the new and old SOA records.
+		current, ok := rrs[0].(*dns.SOA)
+		require.True(t, ok, "First record is not a SOA record")
+		require.Greater(t, current.Serial, first.Serial)
+		old, ok := rrs[1].(*dns.SOA)
+		require.True(t, ok, "Second record is not a SOA record")
+		require.Equal(t, first.Serial, old.Serial)
+
+		var added []string
+		for _, rr := range rrs[2:] {
+			if _, ok := rr.(*dns.SOA); !ok {
+				added = append(added, rr.String())
+			}
+		}
+		require.Contains(t, added, "web.service.consul.\t0\tIN\tA\t127.0.0.4")
+		require.Contains(t, added, "baz.node.consul.\t0\tIN\tA\t127.0.0.4")
+		require.NotContains(t, added, "foo.node.consul.\t0\tIN\tA\t127.0.0.2")
+	})
+
+	t.Run("AXFR over UDP", func(t *testing.T) {
+		m := new(dns.Msg)
+		m.SetAxfr("consul.")
+
+		c := &dns.Client{Net: "udp", TsigSecret: testTSIGSecrets}
+		in, _, err := c.Exchange(signZoneQuery(m), a.DNSAddr())
+		require.NoError(t, err)
+		require.Equal(t, dns.RcodeRefused, in.Rcode)
+	})
+}
+
+func TestDNS_ZoneTransfer_Refused(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, zoneTransferConfig("zone-token", ""))
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	b := NewTestAgent(t, zoneTransferConfig("zone-token", `allow_from = ["10.0.0.0/8"]`))
+	defer b.Shutdown()
+	testrpc.WaitForLeader(t, b.RPC, "dc1")
+
+	axfr := func(t *testing.T, addr string, secrets map[string]string, sign func(*dns.Msg)) int {
+		t.Helper()
+		m := new(dns.Msg)
+		m.SetAxfr("consul.")
+		if sign != nil {
+			sign(m)
+		}
+		c := &dns.Client{Net: "tcp", TsigSecret: secrets}
+		in, _, err := c.Exchange(m, addr)
+		require.NoError(t, err)
+		return in.Rcode
+	}
+
+	t.Run("unsigned", func(t *testing.T) {
+		require.Equal(t, dns.RcodeRefused, axfr(t, a.DNSAddr(), nil, nil))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		secrets := map[string]string{"other.": testTSIGSecret}
+		sign := func(m *dns.Msg) { m.SetTsig("other.", dns.HmacSHA256, 300, time.Now().Unix()) }
+		require.Equal(t, dns.RcodeRefused, axfr(t, a.DNSAddr(), secrets, sign))
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		secrets := map[string]string{testTSIGKeyName: "b3RoZXIgc2VjcmV0"}
+		sign := func(m *dns.Msg) { signZoneQuery(m) }
+		require.Equal(t, dns.RcodeRefused, axfr(t, a.DNSAddr(), secrets, sign))
+	})
+
+	t.Run("other algorithm", func(t *testing.T) {
+		sign := func(m *dns.Msg) { m.SetTsig(testTSIGKeyName, dns.HmacSHA512, 300, time.Now().Unix()) }
+		require.Equal(t, dns.RcodeRefused, axfr(t, a.DNSAddr(), testTSIGSecrets, sign))
+	})
+
+	t.Run("client not allowed", func(t *testing.T) {
+		sign := func(m *dns.Msg) { signZoneQuery(m) }
+		require.Equal(t, dns.RcodeRefused, axfr(t, b.DNSAddr(), testTSIGSecrets, sign))
+	})
+}
+
+func TestDNS_ZoneTransfer_ACL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	const token = "0e4a3d5f-61b8-4b8c-9d3e-7c1f9b0a2e64"
+	a := NewTestAgent(t, TestACLConfig()+zoneTransferConfig(token, ""))
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1", testrpc.WithToken("root"))
+
+	// The token of the TSIG key can read the nodes and the web service only,
+	// while the DNS server has no token and can't read the catalog.
+	policy := structs.ACLPolicySetRequest{
+		Datacenter: "dc1",
+		Policy: structs.ACLPolicy{
+			Name:  "zone-transfer",
+			Rules: `node_prefix "" { policy = "read" } service "web" { policy = "read" }`,
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	require.NoError(t, a.RPC(context.Background(), "ACL.PolicySet", &policy, &structs.ACLPolicy{}))
+	tokenReq := structs.ACLTokenSetRequest{
+		Datacenter: "dc1",
+		ACLToken: structs.ACLToken{
+			SecretID: token,
+			Policies: []structs.ACLTokenPolicyLink{{Name: "zone-transfer"}},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	require.NoError(t, a.RPC(context.Background(), "ACL.TokenSet", &tokenReq, &structs.ACLToken{}))
+
+	for _, service := range []string{"web", "db"} {
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       "foo",
+			Address:    "127.0.0.2",
+			Service: &structs.NodeService{
+				ID:      service,
+				Service: service,
+				Port:    12345,
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	}
+
+	m := new(dns.Msg)
+	m.SetAxfr("consul.")
+	rrs, err := transferZone(t, a.DNSAddr(), signZoneQuery(m))
+	require.NoError(t, err)
+
+	records := make(map[string]bool)
+	for _, rr := range rrs {
+		records[rr.String()] = true
+	}
+	require.True(t, records["foo.node.consul.\t0\tIN\tA\t127.0.0.2"])
+	require.True(t, records["web.service.consul.\t0\tIN\tA\t127.0.0.2"])
+	require.False(t, records["db.service.consul.\t0\tIN\tA\t127.0.0.2"])
+}
+
+func TestDNS_ZoneTransfer_SecondaryHostname(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, zoneTransferConfig("zone-token", `secondaries = ["localhost:5353"]`))
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	m := new(dns.Msg)
+	m.SetAxfr("consul.")
+	rrs, err := transferZone(t, a.DNSAddr(), signZoneQuery(m))
+	require.NoError(t, err)
+	require.NotEmpty(t, rrs)
+	require.Equal(t, dns.TypeSOA, rrs[0].Header().Rrtype)
+}
+
+func TestDNS_ZoneTransfer_Notify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	notifies := make(chan uint32, 10)
+	secondary := &dns.Server{
+		Addr: fmt.Sprintf("127.0.0.1:%d", freeport.GetOne(t)),
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			if req.Opcode == dns.OpcodeNotify && len(req.Answer) == 1 {
+				notifies <- req.Answer[0].(*dns.SOA).Serial
+			}
+			m := new(dns.Msg)
+			m.SetReply(req)
+			w.WriteMsg(m)
+		}),
+	}
+	started := make(chan struct{})
+	secondary.NotifyStartedFunc = func() { close(started) }
+	go secondary.ListenAndServe()
+	defer secondary.Shutdown()
+	<-started
+
+	a := NewTestAgent(t, zoneTransferConfig("zone-token", `secondaries = ["`+secondary.Addr+`"]`))
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	receive := func() uint32 {
+		t.Helper()
+		select {
+		case serial := <-notifies:
+			return serial
+		case <-time.After(10 * time.Second):
+			t.Fatal("no NOTIFY received")
+			return 0
+		}
+	}
+	serial := receive()
+
+	// The zone changes as soon as the catalog holds the agent, wait for it
+	// to settle before changing it.
+	time.Sleep(2 * dnsZoneNotifyInterval)
+	for len(notifies) > 0 {
+		serial = <-notifies
+	}
+
+	args := &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.2",
+	}
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	require.Greater(t, receive(), serial)
+}
+
+func TestDNSZone_Update(t *testing.T) {
+	a := &dns.A{Hdr: dns.RR_Header{Name: "foo.node.consul.", Rrtype: dns.TypeA, Class: dns.ClassINET}}
+	b := &dns.A{Hdr: dns.RR_Header{Name: "bar.node.consul.", Rrtype: dns.TypeA, Class: dns.ClassINET}}
+
+	// The first version gets the current time as serial.
+	z := new(dnsZone)
+	now := uint32(time.Now().Unix())
+	v1 := z.update(newDNSZoneVersion([]dns.RR{a}))
+	require.GreaterOrEqual(t, v1.serial, now)
+
+	// Unchanged records keep the serial of the latest version.
+	require.Same(t, v1, z.update(newDNSZoneVersion([]dns.RR{a})))
+
+	// Changed records get the next serial, even when the last serial is
+	// ahead of the time like after a restart of the agent.
+	z = &dnsZone{serial: now + 1000}
+	v1 = z.update(newDNSZoneVersion([]dns.RR{a}))
+	require.Equal(t, now+1001, v1.serial)
+	v2 := z.update(newDNSZoneVersion([]dns.RR{a, b}))
+	require.Equal(t, now+1002, v2.serial)
+	require.Same(t, v1, z.version(now+1001))
+
+	deleted, added := v2.diff(v1)
+	require.Empty(t, deleted)
+	require.Equal(t, []dns.RR{b}, added)
+
+	for i := 0; i < dnsZoneHistorySize; i++ {
+		z.update(newDNSZoneVersion([]dns.RR{a, b, &dns.TXT{Txt: []string{fmt.Sprint(i)}}}))
+	}
+	require.Nil(t, z.version(now+1001))
+}
+
+func TestNextDNSZoneSerial(t *testing.T) {
+	tests := []struct {
+		last, now, expected uint32
+	}{
+		{last: 10, now: 20, expected: 20},
+		{last: 20, now: 20, expected: 21},
+		{last: 20, now: 10, expected: 21},
+		// The serials wrap around.
+		{last: math.MaxUint32, now: 5, expected: 5},
+		{last: math.MaxUint32, now: math.MaxUint32 - 10, expected: 0},
+		{last: 5, now: math.MaxUint32, expected: 6},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, nextDNSZoneSerial(tt.last, tt.now), "last %d, now %d", tt.last, tt.now)
+	}
+}
+
+func TestDNSZones(t *testing.T) {
+	a := &dns.A{Hdr: dns.RR_Header{Name: "foo.node.consul.", Rrtype: dns.TypeA, Class: dns.ClassINET}}
+
+	zones := new(dnsZones)
+	require.Same(t, zones.zone("transfer."), zones.zone("transfer."))
+	require.NotSame(t, zones.zone("transfer."), zones.zone("other."))
+
+	// The versions of a zone aren't served for another key.
+	v := zones.zone("transfer.").update(newDNSZoneVersion([]dns.RR{a}))
+	require.Nil(t, zones.zone("other.").version(v.serial))
+}
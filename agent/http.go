@@ -245,6 +245,12 @@ func (s *HTTPHandlers) handler(enableDebug bool) http.Handler {
 		handleFuncMetrics(pattern, s.wrap(bound, methods))
 	}
 
+	// DNS over HTTPS isn't part of the versioned API and answers with DNS
+	// messages, so it isn't wrapped like the endpoints.
+	if s.agent.config.DNSEnableDoH {
+		handleFuncMetrics("/dns-query", s.DNSQuery)
+	}
+
 	// If enableDebug or ACL enabled, register wrapped pprof handlers
 	if enableDebug || !s.checkACLDisabled() {
 		handlePProf("/debug/pprof/", pprof.Index)
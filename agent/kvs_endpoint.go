@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
@@ -38,6 +39,9 @@ func (s *HTTPHandlers) KVSEndpoint(resp http.ResponseWriter, req *http.Request)
 		if keyList {
 			return s.KVSGetKeys(resp, req, &args)
 		}
+		if _, ok := params["revisions"]; ok {
+			return s.KVSGetRevisions(resp, req, &args)
+		}
 		return s.KVSGet(resp, req, &args)
 	case "PUT":
 		return s.KVSPut(resp, req, &args)
@@ -70,9 +74,43 @@ func (s *HTTPHandlers) KVSGet(resp http.ResponseWriter, req *http.Request, args
 		}
 	}
 
+	// Check for a point-in-time read
+	var atIndexArgs *structs.KeyRevisionRequest
+	if _, ok := params["at-index"]; ok {
+		if method != "KVS.Get" {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "The at-index parameter can't be used with recurse"}
+		}
+		atIndex, err := strconv.ParseUint(params.Get("at-index"), 10, 64)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid at-index %q", params.Get("at-index"))}
+		}
+		method = "KVS.GetAtIndex"
+		atIndexArgs = &structs.KeyRevisionRequest{
+			Datacenter:     args.Datacenter,
+			Key:            args.Key,
+			AtIndex:        atIndex,
+			EnterpriseMeta: args.EnterpriseMeta,
+			QueryOptions:   args.QueryOptions,
+		}
+	}
+
 	// Make the RPC
 	var out structs.IndexedDirEntries
-	if err := s.agent.RPC(req.Context(), method, args, &out); err != nil {
+	if method == "KVS.GetAtIndex" {
+		if err := s.agent.RPC(req.Context(), method, atIndexArgs, &out); err != nil {
+			if structs.IsErrKVSRevisionNotRetained(err) {
+				return nil, HTTPError{StatusCode: http.StatusGone, Reason: err.Error()}
+			}
+			return nil, err
+		}
+	} else if method == "KVS.List" {
+		// Blocking list requests may be served from a streamed view.
+		var err error
+		out, _, err = s.agent.rpcClientKVS.List(req.Context(), *args)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := s.agent.RPC(req.Context(), method, args, &out); err != nil {
 		return nil, err
 	}
 	setMeta(resp, &out.QueryMeta)
@@ -91,7 +129,7 @@ func (s *HTTPHandlers) KVSGet(resp http.ResponseWriter, req *http.Request, args
 	// should also skip sniffing the body, otherwise it might ignore the Content-Type
 	// header in some situations. The sandbox option provides another layer of defense
 	// using the browser's content security policy to prevent code execution.
-	if _, ok := params["raw"]; ok && method == "KVS.Get" {
+	if _, ok := params["raw"]; ok && method != "KVS.List" {
 		body := out.Entries[0].Value
 		resp.Header().Set("Content-Length", strconv.FormatInt(int64(len(body)), 10))
 		resp.Header().Set("Content-Type", "text/plain")
@@ -104,6 +142,30 @@ func (s *HTTPHandlers) KVSGet(resp http.ResponseWriter, req *http.Request, args
 	return out.Entries, nil
 }
 
+// KVSGetRevisions handles a GET request for the history of a key
+func (s *HTTPHandlers) KVSGetRevisions(resp http.ResponseWriter, req *http.Request, args *structs.KeyRequest) (interface{}, error) {
+	if err := s.parseEntMetaNoWildcard(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+	if args.Key == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing key name"}
+	}
+
+	// Make the RPC
+	var out structs.IndexedDirEntryRevisions
+	if err := s.agent.RPC(req.Context(), "KVS.Revisions", args, &out); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &out.QueryMeta)
+
+	// Check if we get a not found
+	if len(out.Revisions) == 0 {
+		resp.WriteHeader(http.StatusNotFound)
+		return nil, nil
+	}
+	return out.Revisions, nil
+}
+
 // KVSGetKeys handles a GET request for keys
 func (s *HTTPHandlers) KVSGetKeys(resp http.ResponseWriter, req *http.Request, args *structs.KeyRequest) (interface{}, error) {
 	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
@@ -206,6 +268,15 @@ func (s *HTTPHandlers) KVSPut(resp http.ResponseWriter, req *http.Request, args
 		applyReq.Op = api.KVUnlock
 	}
 
+	// Check for a TTL, the servers will delete the entry once it expires
+	if _, ok := params["ttl"]; ok {
+		ttl, err := time.ParseDuration(params.Get("ttl"))
+		if err != nil || ttl <= 0 {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid TTL %q, must be a positive duration", params.Get("ttl"))}
+		}
+		applyReq.DirEnt.TTL = params.Get("ttl")
+	}
+
 	// Check the content-length
 	if req.ContentLength > int64(s.agent.config.KVMaxValueSize) {
 		return nil, HTTPError{
@@ -10,7 +10,11 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 
 	"github.com/hashicorp/consul/agent/structs"
@@ -159,6 +163,91 @@ func TestKVSEndpoint_Recurse(t *testing.T) {
 	}
 }
 
+func TestKVSEndpoint_Recurse_Blocking(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		hcl          string
+		queryBackend string
+	}{
+		{
+			name:         "no streaming",
+			queryBackend: "blocking-query",
+			hcl:          `use_streaming_backend = false`,
+		},
+		{
+			name: "streaming",
+			hcl: `
+rpc { enable_streaming = true }
+use_streaming_backend = true
+`,
+			queryBackend: "streaming",
+		},
+	}
+
+	put := func(t *testing.T, a *TestAgent, key string) {
+		req, _ := http.NewRequest("PUT", "/v1/kv/"+key, bytes.NewBufferString("test"))
+		obj, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.True(t, obj.(bool))
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			a := NewTestAgent(t, tc.hcl)
+			defer a.Shutdown()
+
+			testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+			put(t, a, "config/web/port")
+
+			req, _ := http.NewRequest("GET", "/v1/kv/config/web?recurse", nil)
+			resp := httptest.NewRecorder()
+			_, err := a.srv.KVSEndpoint(resp, req)
+			require.NoError(t, err)
+			require.Equal(t, "blocking-query", resp.Header().Get("X-Consul-Query-Backend"))
+			lastIndex := getIndex(t, resp)
+
+			var (
+				// obj and resp are not safe to read until reading from errCh
+				obj   interface{}
+				errCh = make(chan error, 1)
+			)
+			resp = httptest.NewRecorder()
+			go func() {
+				url := fmt.Sprintf("/v1/kv/config/web?recurse&index=%d&wait=30s", lastIndex)
+				req, _ := http.NewRequest("GET", url, nil)
+
+				var err error
+				obj, err = a.srv.KVSEndpoint(resp, req)
+				errCh <- err
+			}()
+
+			time.Sleep(200 * time.Millisecond)
+
+			// A write outside of the prefix does not wake up the streamed view,
+			// so the query returns once the matching key is written.
+			put(t, a, "other/key")
+			put(t, a, "config/web/host")
+
+			require.NoError(t, <-errCh)
+			require.Equal(t, tc.queryBackend, resp.Header().Get("X-Consul-Query-Backend"))
+
+			var keys []string
+			for _, entry := range obj.(structs.DirEntries) {
+				keys = append(keys, entry.Key)
+			}
+			require.Equal(t, []string{"config/web/host", "config/web/port"}, keys)
+		})
+	}
+}
+
 func TestKVSEndpoint_DELETE_CAS(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -507,6 +596,127 @@ func TestKVSEndpoint_PUT_ConflictingFlags(t *testing.T) {
 	}
 }
 
+func TestKVSEndpoint_PUT_TTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	for _, ttl := range []string{"foo", "-5s", "0"} {
+		req, _ := http.NewRequest("PUT", "/v1/kv/test?ttl="+ttl, bytes.NewBuffer([]byte("test")))
+		resp := httptest.NewRecorder()
+		_, err := a.srv.KVSEndpoint(resp, req)
+		require.True(t, isHTTPBadRequest(err), "ttl %q: %v", ttl, err)
+	}
+
+	req, _ := http.NewRequest("PUT", "/v1/kv/test?ttl=200ms", bytes.NewBuffer([]byte("test")))
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.True(t, obj.(bool))
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test", nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	d := obj.(structs.DirEntries)[0]
+	require.Equal(t, "200ms", d.TTL)
+	require.NotNil(t, d.Expires)
+
+	// The key is deleted once it expires.
+	retry.Run(t, func(r *retry.R) {
+		req, _ := http.NewRequest("GET", "/v1/kv/test", nil)
+		resp := httptest.NewRecorder()
+		if _, err := a.srv.KVSEndpoint(resp, req); err != nil {
+			r.Fatalf("err: %v", err)
+		}
+		if resp.Code != http.StatusNotFound {
+			r.Fatalf("expected 404, got %d", resp.Code)
+		}
+	})
+}
+
+func TestKVSEndpoint_GET_Revisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "kv_history_max_revisions = 10")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// There is no history before the key is written.
+	req, _ := http.NewRequest("GET", "/v1/kv/test?revisions", nil)
+	resp := httptest.NewRecorder()
+	_, err := a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	var indexes []uint64
+	for _, value := range []string{"one", "two"} {
+		req, _ := http.NewRequest("PUT", "/v1/kv/test", bytes.NewBuffer([]byte(value)))
+		resp := httptest.NewRecorder()
+		_, err := a.srv.KVSEndpoint(resp, req)
+		require.NoError(t, err)
+
+		req, _ = http.NewRequest("GET", "/v1/kv/test", nil)
+		resp = httptest.NewRecorder()
+		obj, err := a.srv.KVSEndpoint(resp, req)
+		require.NoError(t, err)
+		indexes = append(indexes, obj.(structs.DirEntries)[0].ModifyIndex)
+	}
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test?revisions", nil)
+	resp = httptest.NewRecorder()
+	obj, err := a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	assertIndex(t, resp)
+	revs := obj.(structs.DirEntryRevisions)
+	require.Len(t, revs, 2)
+	require.Equal(t, "two", string(revs[0].Value))
+	require.Equal(t, "one", string(revs[1].Value))
+
+	// Read the first value back.
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/v1/kv/test?at-index=%d", indexes[0]), nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.Equal(t, "one", string(obj.(structs.DirEntries)[0].Value))
+
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/v1/kv/test?raw&at-index=%d", indexes[0]), nil)
+	resp = httptest.NewRecorder()
+	_, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.Equal(t, "one", resp.Body.String())
+
+	// The key didn't exist before it was first written.
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/v1/kv/test?at-index=%d", indexes[0]-1), nil)
+	resp = httptest.NewRecorder()
+	_, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	// Nothing is known about the key before the history was enabled.
+	req, _ = http.NewRequest("GET", "/v1/kv/test?at-index=0", nil)
+	resp = httptest.NewRecorder()
+	_, err = a.srv.KVSEndpoint(resp, req)
+	httpErr, ok := err.(HTTPError)
+	require.True(t, ok, "err: %v", err)
+	require.Equal(t, http.StatusGone, httpErr.StatusCode)
+
+	for _, query := range []string{"at-index=foo", "at-index=1&recurse"} {
+		req, _ = http.NewRequest("GET", "/v1/kv/test?"+query, nil)
+		resp = httptest.NewRecorder()
+		_, err = a.srv.KVSEndpoint(resp, req)
+		require.True(t, isHTTPBadRequest(err), "query %q: %v", query, err)
+	}
+}
+
 func TestKVSEndpoint_GET(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
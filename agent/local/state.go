@@ -745,6 +745,24 @@ func (l *State) UpdateCheck(id structs.CheckID, status, output string) {
 	l.TriggerSyncChanges()
 }
 
+// UpdateCheckFlapping is used to set whether a check is flapping. It
+// implements the checks.FlapNotifier interface.
+func (l *State) UpdateCheckFlapping(id structs.CheckID, flapping bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	c := l.checks[id]
+	if c == nil || c.Deleted || c.Check.Flapping == flapping {
+		return
+	}
+
+	c = c.Clone()
+	c.Check.Flapping = flapping
+	c.InSync = false
+	l.checks[id] = c
+	l.TriggerSyncChanges()
+}
+
 // Check returns the locally registered check that the
 // agent is aware of and are being kept in sync with the server
 func (l *State) Check(id structs.CheckID) *structs.HealthCheck {
@@ -1755,6 +1755,51 @@ func TestAgent_UpdateCheck_DiscardOutput(t *testing.T) {
 	}
 }
 
+func TestAgent_UpdateCheckFlapping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	check := &structs.HealthCheck{
+		Node:    a.Config.NodeName,
+		CheckID: "web",
+		Name:    "web",
+		Status:  api.HealthPassing,
+	}
+	require.NoError(t, a.State.AddCheck(check, "", false))
+	require.NoError(t, a.State.SyncFull())
+
+	// Setting the same value is a no-op.
+	a.State.UpdateCheckFlapping(check.CompoundCheckID(), false)
+	require.True(t, a.State.CheckState(check.CompoundCheckID()).InSync)
+
+	a.State.UpdateCheckFlapping(check.CompoundCheckID(), true)
+	require.True(t, a.State.Check(check.CompoundCheckID()).Flapping)
+	require.False(t, a.State.CheckState(check.CompoundCheckID()).InSync)
+
+	// The flag is synced to the catalog.
+	require.NoError(t, a.State.SyncChanges())
+	req := structs.NodeSpecificRequest{
+		Datacenter: "dc1",
+		Node:       a.Config.NodeName,
+	}
+	var checks structs.IndexedHealthChecks
+	require.NoError(t, a.RPC(context.Background(), "Health.NodeChecks", &req, &checks))
+	var found bool
+	for _, chk := range checks.HealthChecks {
+		if chk.CheckID == "web" {
+			found = true
+			require.True(t, chk.Flapping)
+		}
+	}
+	require.True(t, found)
+}
+
 func TestAgentAntiEntropy_Check_DeferSync(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
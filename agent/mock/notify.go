@@ -20,6 +20,7 @@ type Notify struct {
 	state      map[structs.CheckID]string
 	updates    map[structs.CheckID]int
 	output     map[structs.CheckID]string
+	flapping   map[structs.CheckID]bool
 	serviceIDs map[structs.ServiceID]bool
 }
 
@@ -28,6 +29,7 @@ func NewNotify() *Notify {
 		state:      make(map[structs.CheckID]string),
 		updates:    make(map[structs.CheckID]int),
 		output:     make(map[structs.CheckID]string),
+		flapping:   make(map[structs.CheckID]bool),
 		serviceIDs: make(map[structs.ServiceID]bool),
 	}
 }
@@ -44,10 +46,11 @@ func (c *Notify) AddServiceID(serviceID structs.ServiceID) {
 
 func NewNotifyChan() (*Notify, chan int) {
 	n := &Notify{
-		updated: make(chan int),
-		state:   make(map[structs.CheckID]string),
-		updates: make(map[structs.CheckID]int),
-		output:  make(map[structs.CheckID]string),
+		updated:  make(chan int),
+		state:    make(map[structs.CheckID]string),
+		updates:  make(map[structs.CheckID]int),
+		output:   make(map[structs.CheckID]string),
+		flapping: make(map[structs.CheckID]bool),
 	}
 	return n, n.updated
 }
@@ -75,6 +78,19 @@ func (m *Notify) UpdateCheck(id structs.CheckID, status, output string) {
 	}
 }
 
+func (m *Notify) UpdateCheckFlapping(id structs.CheckID, flapping bool) {
+	m.Lock()
+	defer m.Unlock()
+	m.flapping[id] = flapping
+}
+
+// Flapping returns whether the specified health-check is flapping.
+func (m *Notify) Flapping(id structs.CheckID) bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.flapping[id]
+}
+
 // State returns the state of the specified health-check.
 func (m *Notify) State(id structs.CheckID) string {
 	m.RLock()
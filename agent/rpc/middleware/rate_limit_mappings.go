@@ -110,10 +110,12 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 	"Internal.ServiceGateways":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 	"Internal.ServiceTopology":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 
-	"KVS.Apply":    {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryKV},
-	"KVS.Get":      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
-	"KVS.List":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
-	"KVS.ListKeys": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
+	"KVS.Apply":      {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryKV},
+	"KVS.Get":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
+	"KVS.GetAtIndex": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
+	"KVS.List":       {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
+	"KVS.ListKeys":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
+	"KVS.Revisions":  {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
 
 	"Operator.AutopilotGetConfiguration": {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
 	"Operator.AutopilotSetConfiguration": {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
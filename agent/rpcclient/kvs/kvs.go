@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kvs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/rpcclient"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/agent/submatview"
+	"github.com/hashicorp/consul/proto/private/pbsubscribe"
+)
+
+// Client provides access to KV data.
+type Client struct {
+	rpcclient.Client
+
+	// EnforceKeyListPolicy disables the streaming backend, because the
+	// key-list permission is only checked by the KVS.List RPC endpoint.
+	EnforceKeyListPolicy bool
+}
+
+// List returns the KV entries under the given prefix. Blocking queries are
+// served from a materialized view of the KV topic when the streaming backend
+// is enabled, so that they are only woken by changes under the prefix.
+// Consistent reads, and the keys without a "/" which would need the whole KV
+// topic, always go to the servers.
+func (c *Client) List(ctx context.Context, req structs.KeyRequest) (structs.IndexedDirEntries, cache.ResultMeta, error) {
+	if c.useStreaming(req) {
+		c.QueryOptionDefaults(&req.QueryOptions)
+
+		result, err := c.ViewStore.Get(ctx, c.newListRequest(req))
+		if err != nil {
+			return structs.IndexedDirEntries{}, cache.ResultMeta{}, err
+		}
+		meta := cache.ResultMeta{Index: result.Index, Hit: result.Cached}
+		return *result.Value.(*structs.IndexedDirEntries), meta, err
+	}
+
+	var out structs.IndexedDirEntries
+	err := c.NetRPC.RPC(ctx, "KVS.List", &req, &out)
+	return out, cache.ResultMeta{}, err
+}
+
+func (c *Client) useStreaming(req structs.KeyRequest) bool {
+	return c.UseStreamingBackend && !c.EnforceKeyListPolicy && req.QueryOptions.MinQueryIndex > 0 &&
+		!req.QueryOptions.RequireConsistent && subscriptionPrefix(req.Key) != ""
+}
+
+func (c *Client) newListRequest(req structs.KeyRequest) listRequest {
+	return listRequest{
+		KeyRequest: req,
+		deps:       c.MaterializerDeps,
+	}
+}
+
+var _ submatview.Request = (*listRequest)(nil)
+
+type listRequest struct {
+	structs.KeyRequest
+	deps rpcclient.MaterializerDeps
+}
+
+func (r listRequest) CacheInfo() cache.RequestInfo {
+	return r.KeyRequest.CacheInfo()
+}
+
+// Type returns a string which uniquely identifies the KV list request. The
+// returned value is used as the prefix of the key used to index entries in
+// the Store.
+func (r listRequest) Type() string {
+	return "agent.rpcclient.kvs.listRequest"
+}
+
+// NewMaterializer will be called if there is no active materializer to fulfill
+// the request. It returns a Materializer appropriate for streaming the KV
+// entries under the requested prefix.
+func (r listRequest) NewMaterializer() (submatview.Materializer, error) {
+	deps := submatview.Deps{
+		View:    NewKVListView(r.KeyRequest),
+		Logger:  r.deps.Logger,
+		Request: newMaterializerRequest(r.KeyRequest),
+	}
+
+	return submatview.NewRPCMaterializer(pbsubscribe.NewStateChangeSubscriptionClient(r.deps.Conn), deps), nil
+}
+
+// newMaterializerRequest returns a function which creates a SubscribeRequest
+// for the KV topic.
+//
+// Events are only published to prefixes ending in "/", so the subscription is
+// made to the longest such prefix of the requested key and the view filters
+// out entries that do not match. The keys without such a prefix are served by
+// the RPC endpoint instead.
+func newMaterializerRequest(srvReq structs.KeyRequest) func(index uint64) *pbsubscribe.SubscribeRequest {
+	return func(index uint64) *pbsubscribe.SubscribeRequest {
+		return &pbsubscribe.SubscribeRequest{
+			Topic: pbsubscribe.Topic_KV,
+			Subject: &pbsubscribe.SubscribeRequest_NamedSubject{
+				NamedSubject: &pbsubscribe.NamedSubject{
+					Key:       subscriptionPrefix(srvReq.Key),
+					Namespace: srvReq.EnterpriseMeta.NamespaceOrEmpty(),
+					Partition: srvReq.EnterpriseMeta.PartitionOrEmpty(),
+				},
+			},
+			Token:      srvReq.Token,
+			Datacenter: srvReq.Datacenter,
+			Index:      index,
+		}
+	}
+}
+
+// subscriptionPrefix returns the longest prefix of key that ends in "/".
+func subscriptionPrefix(key string) string {
+	return key[:strings.LastIndex(key, "/")+1]
+}
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kvs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/rpcclient"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/agent/submatview"
+)
+
+func TestClient_List_BackendRouting(t *testing.T) {
+	type testCase struct {
+		name                 string
+		req                  structs.KeyRequest
+		useStreamingBackend  bool
+		enforceKeyListPolicy bool
+		expectStreaming      bool
+	}
+
+	run := func(t *testing.T, tc testCase) {
+		rpc := &fakeNetRPC{}
+		store := &fakeViewStore{}
+		c := &Client{
+			Client: rpcclient.Client{
+				NetRPC:              rpc,
+				ViewStore:           store,
+				UseStreamingBackend: tc.useStreamingBackend,
+				QueryOptionDefaults: config.ApplyDefaultQueryOptions(&config.RuntimeConfig{}),
+			},
+			EnforceKeyListPolicy: tc.enforceKeyListPolicy,
+		}
+
+		_, _, err := c.List(context.Background(), tc.req)
+		require.NoError(t, err)
+
+		if tc.expectStreaming {
+			require.Len(t, rpc.calls, 0)
+			require.Len(t, store.calls, 1)
+		} else {
+			require.Equal(t, []string{"KVS.List"}, rpc.calls)
+			require.Len(t, store.calls, 0)
+		}
+	}
+
+	blocking := structs.QueryOptions{MinQueryIndex: 22}
+
+	testCases := []testCase{
+		{
+			name:                "rpc for non-blocking queries",
+			req:                 structs.KeyRequest{Key: "config/"},
+			useStreamingBackend: true,
+		},
+		{
+			name:                "streaming for blocking queries",
+			req:                 structs.KeyRequest{Key: "config/", QueryOptions: blocking},
+			useStreamingBackend: true,
+			expectStreaming:     true,
+		},
+		{
+			name:                "rpc for keys without a slash",
+			req:                 structs.KeyRequest{Key: "config", QueryOptions: blocking},
+			useStreamingBackend: true,
+		},
+		{
+			name: "rpc when streaming is disabled",
+			req:  structs.KeyRequest{Key: "config/", QueryOptions: blocking},
+		},
+		{
+			name:                 "rpc when key list policy is enforced",
+			req:                  structs.KeyRequest{Key: "config/", QueryOptions: blocking},
+			useStreamingBackend:  true,
+			enforceKeyListPolicy: true,
+		},
+		{
+			name: "rpc for consistent queries",
+			req: structs.KeyRequest{
+				Key:          "config/",
+				QueryOptions: structs.QueryOptions{MinQueryIndex: 22, RequireConsistent: true},
+			},
+			useStreamingBackend: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func TestClient_List_SetsDefaults(t *testing.T) {
+	store := &fakeViewStore{}
+	c := &Client{
+		Client: rpcclient.Client{
+			ViewStore:           store,
+			UseStreamingBackend: true,
+			QueryOptionDefaults: config.ApplyDefaultQueryOptions(&config.RuntimeConfig{
+				MaxQueryTime:     200 * time.Second,
+				DefaultQueryTime: 100 * time.Second,
+			}),
+		},
+	}
+
+	req := structs.KeyRequest{
+		Datacenter:   "dc1",
+		Key:          "config/",
+		QueryOptions: structs.QueryOptions{MinQueryIndex: 22},
+	}
+
+	_, _, err := c.List(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Len(t, store.calls, 1)
+	require.Equal(t, 100*time.Second, store.calls[0].CacheInfo().Timeout)
+}
+
+var _ rpcclient.NetRPC = (*fakeNetRPC)(nil)
+
+type fakeNetRPC struct {
+	calls []string
+}
+
+func (f *fakeNetRPC) RPC(_ context.Context, method string, _ interface{}, _ interface{}) error {
+	f.calls = append(f.calls, method)
+	return nil
+}
+
+var _ rpcclient.MaterializedViewStore = (*fakeViewStore)(nil)
+
+type fakeViewStore struct {
+	calls []submatview.Request
+}
+
+func (f *fakeViewStore) Get(_ context.Context, req submatview.Request) (submatview.Result, error) {
+	f.calls = append(f.calls, req)
+	return submatview.Result{Value: &structs.IndexedDirEntries{}}, nil
+}
+
+func (f *fakeViewStore) NotifyCallback(_ context.Context, req submatview.Request, _ string, _ cache.Callback) error {
+	f.calls = append(f.calls, req)
+	return nil
+}
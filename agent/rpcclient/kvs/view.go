@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kvs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/agent/submatview"
+	"github.com/hashicorp/consul/proto/private/pbsubscribe"
+)
+
+// NewKVListView returns a view of the KV entries under the request's key.
+func NewKVListView(req structs.KeyRequest) *KVListView {
+	return &KVListView{
+		prefix:  req.Key,
+		entMeta: req.EnterpriseMeta,
+		state:   make(map[string]*structs.DirEntry),
+	}
+}
+
+var _ submatview.View = (*KVListView)(nil)
+
+// KVListView implements submatview.View for the entries under a KV prefix.
+type KVListView struct {
+	prefix  string
+	entMeta acl.EnterpriseMeta
+	state   map[string]*structs.DirEntry
+}
+
+// Reset resets the view to an empty set of entries.
+func (v *KVListView) Reset() {
+	v.state = make(map[string]*structs.DirEntry)
+}
+
+// Update implements View
+func (v *KVListView) Update(events []*pbsubscribe.Event) error {
+	for _, event := range events {
+		update := event.GetKV()
+		if update == nil {
+			return fmt.Errorf("unexpected event type for KV view: %T", event.GetPayload())
+		}
+
+		entry := update.DirEntryToStructs()
+		if !v.matches(entry) {
+			continue
+		}
+
+		switch update.Op {
+		case pbsubscribe.KVUpdate_Upsert:
+			v.state[entry.Key] = entry
+		case pbsubscribe.KVUpdate_Delete:
+			delete(v.state, entry.Key)
+		}
+	}
+	return nil
+}
+
+// matches returns true if the entry is under the view's prefix and in its
+// partition and namespace. The subscription may cover a wider set of keys than
+// the request, see newMaterializerRequest.
+func (v *KVListView) matches(entry *structs.DirEntry) bool {
+	if !strings.HasPrefix(entry.Key, v.prefix) {
+		return false
+	}
+	if !acl.EqualPartitions(v.entMeta.PartitionOrDefault(), entry.PartitionOrDefault()) {
+		return false
+	}
+	return acl.EqualNamespaces(v.entMeta.NamespaceOrDefault(), entry.NamespaceOrDefault())
+}
+
+// Result returns the structs.IndexedDirEntries stored by this view, sorted by
+// key.
+func (v *KVListView) Result(index uint64) interface{} {
+	result := structs.IndexedDirEntries{
+		QueryMeta: structs.QueryMeta{
+			Index:   index,
+			Backend: structs.QueryBackendStreaming,
+		},
+	}
+	if len(v.state) == 0 {
+		return &result
+	}
+
+	result.Entries = make(structs.DirEntries, 0, len(v.state))
+	for _, entry := range v.state {
+		result.Entries = append(result.Entries, entry)
+	}
+	sort.Slice(result.Entries, func(i, j int) bool {
+		return result.Entries[i].Key < result.Entries[j].Key
+	})
+	return &result
+}
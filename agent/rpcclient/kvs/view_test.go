@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kvs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/private/pbsubscribe"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestKVListView(t *testing.T) {
+	const index uint64 = 123
+
+	view := NewKVListView(structs.KeyRequest{Key: "config/web"})
+
+	testutil.RunStep(t, "initial state", func(t *testing.T) {
+		result := view.Result(index)
+		resp, ok := result.(*structs.IndexedDirEntries)
+		require.Truef(t, ok, "expected IndexedDirEntries, got: %T", result)
+		require.Nil(t, resp.Entries)
+		require.Equal(t, index, resp.QueryMeta.Index)
+		require.Equal(t, structs.QueryBackendStreaming, resp.QueryMeta.Backend)
+	})
+
+	testutil.RunStep(t, "upsert events", func(t *testing.T) {
+		err := view.Update([]*pbsubscribe.Event{
+			newKVEvent(index, pbsubscribe.KVUpdate_Upsert, "config/web/port", "8080"),
+			newKVEvent(index, pbsubscribe.KVUpdate_Upsert, "config/web-admin/port", "9090"),
+			newKVEvent(index, pbsubscribe.KVUpdate_Upsert, "config/db/port", "5432"),
+			newKVEvent(index, pbsubscribe.KVUpdate_Upsert, "config/web/host", "localhost"),
+		})
+		require.NoError(t, err)
+
+		resp := view.Result(index).(*structs.IndexedDirEntries)
+		require.Equal(t, []string{"config/web-admin/port", "config/web/host", "config/web/port"}, entryKeys(resp.Entries))
+		require.Equal(t, []byte("8080"), resp.Entries[2].Value)
+	})
+
+	testutil.RunStep(t, "delete event", func(t *testing.T) {
+		err := view.Update([]*pbsubscribe.Event{
+			newKVEvent(index, pbsubscribe.KVUpdate_Delete, "config/web/port", ""),
+		})
+		require.NoError(t, err)
+
+		resp := view.Result(index).(*structs.IndexedDirEntries)
+		require.Equal(t, []string{"config/web-admin/port", "config/web/host"}, entryKeys(resp.Entries))
+	})
+
+	testutil.RunStep(t, "reset", func(t *testing.T) {
+		view.Reset()
+
+		resp := view.Result(index).(*structs.IndexedDirEntries)
+		require.Nil(t, resp.Entries)
+	})
+
+	testutil.RunStep(t, "unexpected event type", func(t *testing.T) {
+		err := view.Update([]*pbsubscribe.Event{
+			{
+				Index:   index,
+				Payload: &pbsubscribe.Event_ConfigEntry{},
+			},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestSubscriptionPrefix(t *testing.T) {
+	testCases := map[string]string{
+		"":             "",
+		"config":       "",
+		"config/":      "config/",
+		"config/web":   "config/",
+		"config/web/":  "config/web/",
+		"config/web/x": "config/web/",
+	}
+	for key, expected := range testCases {
+		require.Equal(t, expected, subscriptionPrefix(key), "key %q", key)
+	}
+}
+
+func TestNewMaterializerRequest(t *testing.T) {
+	req := newMaterializerRequest(structs.KeyRequest{
+		Datacenter:   "dc1",
+		Key:          "config/web",
+		QueryOptions: structs.QueryOptions{Token: "token"},
+	})(5)
+	require.Equal(t, pbsubscribe.Topic_KV, req.Topic)
+	require.Equal(t, uint64(5), req.Index)
+	require.Equal(t, "dc1", req.Datacenter)
+	require.Equal(t, "token", req.Token)
+	require.Equal(t, "config/", req.GetNamedSubject().GetKey())
+}
+
+func newKVEvent(index uint64, op pbsubscribe.KVUpdate_UpdateOp, key, value string) *pbsubscribe.Event {
+	return &pbsubscribe.Event{
+		Index: index,
+		Payload: &pbsubscribe.Event_KV{
+			KV: &pbsubscribe.KVUpdate{
+				Op:          op,
+				Key:         key,
+				Value:       []byte(value),
+				ModifyIndex: index,
+			},
+		},
+	}
+}
+
+func entryKeys(entries structs.DirEntries) []string {
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
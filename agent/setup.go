@@ -18,10 +18,12 @@ import (
 
 	autoconf "github.com/hashicorp/consul/agent/auto-config"
 	"github.com/hashicorp/consul/agent/cache"
+	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/config"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/consul/fsm"
 	"github.com/hashicorp/consul/agent/consul/rate"
+	"github.com/hashicorp/consul/agent/consul/snapshotagent"
 	"github.com/hashicorp/consul/agent/consul/stream"
 	"github.com/hashicorp/consul/agent/consul/usagemetrics"
 	"github.com/hashicorp/consul/agent/consul/xdscapacity"
@@ -274,6 +276,7 @@ func getPrometheusDefs(cfg *config.RuntimeConfig, isServer bool) ([]prometheus.G
 		cache.Gauges,
 		consul.RPCGauges,
 		consul.SessionGauges,
+		consul.KVSTTLGauges,
 		grpcWare.StatsGauges,
 		xds.StatsGauges,
 		usagemetrics.Gauges,
@@ -317,6 +320,10 @@ func getPrometheusDefs(cfg *config.RuntimeConfig, isServer bool) ([]prometheus.G
 		gauges = append(gauges, walGauges)
 	}
 
+	if isServer && cfg.SnapshotSchedule.Enabled {
+		gauges = append(gauges, snapshotagent.Gauges)
+	}
+
 	// Flatten definitions
 	// NOTE(kit): Do we actually want to create a set here so we can ensure definition names are unique?
 	var gaugeDefs []prometheus.GaugeDefinition
@@ -351,6 +358,7 @@ func getPrometheusDefs(cfg *config.RuntimeConfig, isServer bool) ([]prometheus.G
 	var counters = [][]prometheus.CounterDefinition{
 		CatalogCounters,
 		cache.Counters,
+		checks.FlapCounters,
 		consul.ACLCounters,
 		consul.CatalogCounters,
 		consul.ClientCounters,
@@ -362,6 +370,10 @@ func getPrometheusDefs(cfg *config.RuntimeConfig, isServer bool) ([]prometheus.G
 		rate.Counters,
 	}
 
+	if isServer && cfg.SnapshotSchedule.Enabled {
+		counters = append(counters, snapshotagent.Counters)
+	}
+
 	// For some unknown reason, we seem to add the raft counters above without
 	// checking if this is a server like we do above for some of the summaries
 	// above. We should probably fix that but I want to not change behavior right
@@ -429,6 +441,7 @@ func getPrometheusDefs(cfg *config.RuntimeConfig, isServer bool) ([]prometheus.G
 		consul.FederationStateSummaries,
 		consul.IntentionSummaries,
 		consul.KVSummaries,
+		consul.KVSTTLSummaries,
 		consul.LeaderSummaries,
 		consul.PreparedQuerySummaries,
 		consul.RPCSummaries,
@@ -441,6 +454,11 @@ func getPrometheusDefs(cfg *config.RuntimeConfig, isServer bool) ([]prometheus.G
 		raftSummaries,
 		xds.StatsSummaries,
 	}
+
+	if isServer && cfg.SnapshotSchedule.Enabled {
+		summaries = append(summaries, snapshotagent.Summaries)
+	}
+
 	// Flatten definitions
 	// NOTE(kit): Do we actually want to create a set here so we can ensure definition names are unique?
 	var summaryDefs []prometheus.SummaryDefinition
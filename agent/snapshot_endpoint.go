@@ -41,6 +41,19 @@ func (s *HTTPHandlers) Snapshot(resp http.ResponseWriter, req *http.Request) (in
 
 	case "PUT":
 		args.Op = structs.SnapshotRestore
+
+		// Selecting any records turns this into a filtered restore, which
+		// streams back a summary of what was restored.
+		query := req.URL.Query()
+		args.Filter.KVPrefixes = query["kv-prefix"]
+		args.Filter.ConfigEntries = query["config-entry"]
+		if _, ok := query["acls"]; ok {
+			args.Filter.ACLs = true
+		}
+		if !args.Filter.IsEmpty() {
+			args.Op = structs.SnapshotRestoreFiltered
+		}
+
 		if err := s.agent.delegate.SnapshotRPC(&args, req.Body, resp, nil); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul/types"
+)
+
+// The modes used by a composite check to compute its status from the status
+// of the checks it references.
+const (
+	// CompositeModeAll is passing when all the checks are passing, and
+	// otherwise takes the status of the worst check.
+	CompositeModeAll = "all"
+
+	// CompositeModeAny is passing when any of the checks is passing, and
+	// otherwise takes the status of the best check.
+	CompositeModeAny = "any"
+
+	// CompositeModeQuorum is passing when a majority of the checks are
+	// passing, warning when a majority are passing or warning, and critical
+	// otherwise.
+	CompositeModeQuorum = "quorum"
+
+	// CompositeModeThreshold is critical when at least CriticalThreshold
+	// checks are critical, warning when at least WarningThreshold checks are
+	// warning or critical, and passing otherwise.
+	CompositeModeThreshold = "threshold"
+)
+
+// CompositeCheckRef references a check whose status contributes to a
+// composite check.
+type CompositeCheckRef struct {
+	// Node is the node the check is registered on. If empty, the check is
+	// looked up in the local agent's state.
+	Node string `json:",omitempty"`
+
+	CheckID types.CheckID
+}
+
+// String returns the check ID, qualified with the node for remote checks.
+func (r CompositeCheckRef) String() string {
+	if r.Node == "" {
+		return string(r.CheckID)
+	}
+	return fmt.Sprintf("%s/%s", r.Node, r.CheckID)
+}
+
+// IsComposite checks if this is a composite check.
+func (c *CheckType) IsComposite() bool {
+	return len(c.CompositeChecks) > 0
+}
+
+func (c *CheckType) validateComposite() error {
+	for i, ref := range c.CompositeChecks {
+		if ref.CheckID == "" {
+			return fmt.Errorf("CompositeChecks[%d]: CheckID must be set", i)
+		}
+		if ref.Node == "" && c.CheckID != "" && ref.CheckID == c.CheckID {
+			return fmt.Errorf("CompositeChecks[%d]: a composite check can't reference itself", i)
+		}
+	}
+
+	switch c.CompositeMode {
+	case "", CompositeModeAll, CompositeModeAny, CompositeModeQuorum:
+		if c.CompositeCriticalThreshold != 0 || c.CompositeWarningThreshold != 0 {
+			return fmt.Errorf("CompositeCriticalThreshold and CompositeWarningThreshold can only be set with the %q mode", CompositeModeThreshold)
+		}
+	case CompositeModeThreshold:
+		if c.CompositeCriticalThreshold < 1 || c.CompositeCriticalThreshold > len(c.CompositeChecks) {
+			return fmt.Errorf("CompositeCriticalThreshold must be between 1 and the number of CompositeChecks")
+		}
+		if c.CompositeWarningThreshold < 0 || c.CompositeWarningThreshold > c.CompositeCriticalThreshold {
+			return fmt.Errorf("CompositeWarningThreshold must be between 0 and CompositeCriticalThreshold")
+		}
+	default:
+		return fmt.Errorf("CompositeMode must be one of %q, %q, %q or %q",
+			CompositeModeAll, CompositeModeAny, CompositeModeQuorum, CompositeModeThreshold)
+	}
+	return nil
+}
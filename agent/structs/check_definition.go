@@ -35,6 +35,7 @@ type CheckDefinition struct {
 	Method                         string
 	Body                           string
 	DisableRedirects               bool
+	HTTPAssertions                 []HTTPAssertion
 	TCP                            string
 	UDP                            string
 	Interval                       time.Duration
@@ -43,15 +44,32 @@ type CheckDefinition struct {
 	GRPC                           string
 	GRPCUseTLS                     bool
 	OSService                      string
+	DNS                            string
+	DNSQueryName                   string
+	DNSQueryType                   string
+	DNSProtocol                    string
+	DNSExpectedAnswer              []string
+	DNSExpectedRcode               string
+	TLS                            string
+	TLSCAFile                      string
+	TLSExpiryWarningDays           int
+	TLSExpiryCriticalDays          int
 	TLSServerName                  string
 	TLSSkipVerify                  bool
 	AliasNode                      string
 	AliasService                   string
+	CompositeChecks                []CompositeCheckRef
+	CompositeMode                  string
+	CompositeCriticalThreshold     int
+	CompositeWarningThreshold      int
 	Timeout                        time.Duration
 	TTL                            time.Duration
 	SuccessBeforePassing           int
 	FailuresBeforeWarning          int
 	FailuresBeforeCritical         int
+	FlapDetection                  bool
+	FlapLowThreshold               int
+	FlapHighThreshold              int
 	DeregisterCriticalServiceAfter time.Duration
 	OutputMaxSize                  int
 
@@ -209,6 +227,10 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		ScriptArgs:                     c.ScriptArgs,
 		AliasNode:                      c.AliasNode,
 		AliasService:                   c.AliasService,
+		CompositeChecks:                c.CompositeChecks,
+		CompositeMode:                  c.CompositeMode,
+		CompositeCriticalThreshold:     c.CompositeCriticalThreshold,
+		CompositeWarningThreshold:      c.CompositeWarningThreshold,
 		HTTP:                           c.HTTP,
 		H2PING:                         c.H2PING,
 		H2PingUseTLS:                   c.H2PingUseTLS,
@@ -218,6 +240,7 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		Method:                         c.Method,
 		Body:                           c.Body,
 		DisableRedirects:               c.DisableRedirects,
+		HTTPAssertions:                 c.HTTPAssertions,
 		OutputMaxSize:                  c.OutputMaxSize,
 		TCP:                            c.TCP,
 		UDP:                            c.UDP,
@@ -225,6 +248,16 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		DockerContainerID:              c.DockerContainerID,
 		Shell:                          c.Shell,
 		OSService:                      c.OSService,
+		DNS:                            c.DNS,
+		DNSQueryName:                   c.DNSQueryName,
+		DNSQueryType:                   c.DNSQueryType,
+		DNSProtocol:                    c.DNSProtocol,
+		DNSExpectedAnswer:              c.DNSExpectedAnswer,
+		DNSExpectedRcode:               c.DNSExpectedRcode,
+		TLS:                            c.TLS,
+		TLSCAFile:                      c.TLSCAFile,
+		TLSExpiryWarningDays:           c.TLSExpiryWarningDays,
+		TLSExpiryCriticalDays:          c.TLSExpiryCriticalDays,
 		TLSServerName:                  c.TLSServerName,
 		TLSSkipVerify:                  c.TLSSkipVerify,
 		Timeout:                        c.Timeout,
@@ -232,6 +265,9 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		SuccessBeforePassing:           c.SuccessBeforePassing,
 		FailuresBeforeWarning:          c.FailuresBeforeWarning,
 		FailuresBeforeCritical:         c.FailuresBeforeCritical,
+		FlapDetection:                  c.FlapDetection,
+		FlapLowThreshold:               c.FlapLowThreshold,
+		FlapHighThreshold:              c.FlapHighThreshold,
 		DeregisterCriticalServiceAfter: c.DeregisterCriticalServiceAfter,
 	}
 }
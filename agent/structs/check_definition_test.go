@@ -34,7 +34,11 @@ func (w *walker) Struct(reflect.Value) error {
 
 func (w *walker) StructField(f reflect.StructField, v reflect.Value) error {
 	if !f.Anonymous {
-		w.fields[f.Name] = v
+		// Keep the first field seen with a name, so that the fields of
+		// nested structs don't shadow the top-level ones.
+		if _, ok := w.fields[f.Name]; !ok {
+			w.fields[f.Name] = v
+		}
 		return nil
 	}
 	return reflectwalk.SkipEntry
@@ -6,19 +6,36 @@ package structs
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
+	"github.com/miekg/dns"
+
 	"github.com/hashicorp/consul/lib"
 	"github.com/hashicorp/consul/types"
 )
 
 type CheckTypes []*CheckType
 
+// The default thresholds of flap detection, as percentages of state changes.
+const (
+	DefaultFlapLowThreshold  = 5
+	DefaultFlapHighThreshold = 20
+)
+
+// The default thresholds of TLS checks, as days before the expiry of the
+// certificate.
+const (
+	DefaultTLSExpiryWarningDays  = 30
+	DefaultTLSExpiryCriticalDays = 7
+)
+
 // CheckType is used to create either the CheckMonitor or the CheckTTL.
-// The following types are supported: Script, HTTP, TCP, Docker, TTL, GRPC, Alias, H2PING. Script,
-// HTTP, Docker, TCP, GRPC, and H2PING all require Interval. Only one of the types may
-// to be provided: TTL or Script/Interval or HTTP/Interval or TCP/Interval or
-// Docker/Interval or GRPC/Interval or AliasService or H2PING/Interval.
+// The following types are supported: Script, HTTP, TCP, Docker, TTL, GRPC, Alias, H2PING, DNS, TLS,
+// Composite. Script, HTTP, Docker, TCP, GRPC, H2PING, DNS and TLS all require Interval. Only one of
+// the types may to be provided: TTL or Script/Interval or HTTP/Interval or TCP/Interval or
+// Docker/Interval or GRPC/Interval or AliasService or H2PING/Interval or DNS/Interval
+// or TLS/Interval or CompositeChecks.
 // Since types like CheckHTTP and CheckGRPC derive from CheckType, there are
 // helper conversion methods that do the reverse conversion. ie. checkHTTP.CheckType()
 type CheckType struct {
@@ -41,6 +58,7 @@ type CheckType struct {
 	Method                 string
 	Body                   string
 	DisableRedirects       bool
+	HTTPAssertions         []HTTPAssertion
 	TCP                    string
 	UDP                    string
 	Interval               time.Duration
@@ -51,6 +69,16 @@ type CheckType struct {
 	GRPC                   string
 	GRPCUseTLS             bool
 	OSService              string
+	DNS                    string
+	DNSQueryName           string
+	DNSQueryType           string
+	DNSProtocol            string
+	DNSExpectedAnswer      []string
+	DNSExpectedRcode       string
+	TLS                    string
+	TLSCAFile              string
+	TLSExpiryWarningDays   int
+	TLSExpiryCriticalDays  int
 	TLSServerName          string
 	TLSSkipVerify          bool
 	Timeout                time.Duration
@@ -59,6 +87,18 @@ type CheckType struct {
 	FailuresBeforeWarning  int
 	FailuresBeforeCritical int
 
+	CompositeChecks            []CompositeCheckRef
+	CompositeMode              string
+	CompositeCriticalThreshold int
+	CompositeWarningThreshold  int
+
+	// FlapDetection suppresses the status changes of a check while it is
+	// flapping, using FlapLowThreshold and FlapHighThreshold as the
+	// percentages of state changes to stop and start flapping.
+	FlapDetection     bool
+	FlapLowThreshold  int
+	FlapHighThreshold int
+
 	// Definition fields used when exposing checks through a proxy
 	ProxyHTTP string
 	ProxyGRPC string
@@ -184,13 +224,13 @@ func (t *CheckType) UnmarshalJSON(data []byte) (err error) {
 
 // Validate returns an error message if the check is invalid
 func (c *CheckType) Validate() error {
-	intervalCheck := c.IsScript() || c.HTTP != "" || c.TCP != "" || c.UDP != "" || c.GRPC != "" || c.H2PING != "" || c.OSService != ""
+	intervalCheck := c.IsScript() || c.HTTP != "" || c.TCP != "" || c.UDP != "" || c.GRPC != "" || c.H2PING != "" || c.OSService != "" || c.DNS != "" || c.TLS != ""
 
 	if c.Interval > 0 && c.TTL > 0 {
 		return fmt.Errorf("Interval and TTL cannot both be specified")
 	}
 	if intervalCheck && c.Interval <= 0 {
-		return fmt.Errorf("Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP or OSService checks")
+		return fmt.Errorf("Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP, OSService, DNS or TLS checks")
 	}
 	if intervalCheck && c.IsAlias() {
 		return fmt.Errorf("Interval cannot be set for Alias checks")
@@ -198,7 +238,15 @@ func (c *CheckType) Validate() error {
 	if c.IsAlias() && c.TTL > 0 {
 		return fmt.Errorf("TTL must be not be set for Alias checks")
 	}
-	if !intervalCheck && !c.IsAlias() && c.TTL <= 0 {
+	if c.IsComposite() {
+		if intervalCheck || c.IsAlias() || c.TTL > 0 {
+			return fmt.Errorf("Composite checks can't be combined with another check type")
+		}
+		if err := c.validateComposite(); err != nil {
+			return err
+		}
+	}
+	if !intervalCheck && !c.IsAlias() && !c.IsComposite() && c.TTL <= 0 {
 		return fmt.Errorf("TTL must be > 0 for TTL checks")
 	}
 	if c.OutputMaxSize < 0 {
@@ -207,15 +255,88 @@ func (c *CheckType) Validate() error {
 	if c.FailuresBeforeWarning > c.FailuresBeforeCritical {
 		return fmt.Errorf("FailuresBeforeWarning can't be higher than FailuresBeforeCritical")
 	}
+	if c.DNS != "" {
+		if err := c.validateDNS(); err != nil {
+			return err
+		}
+	}
+	for i, a := range c.HTTPAssertions {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("HTTPAssertions[%d]: %w", i, err)
+		}
+	}
+	if c.FlapLowThreshold < 0 || c.FlapLowThreshold > 100 || c.FlapHighThreshold < 0 || c.FlapHighThreshold > 100 {
+		return fmt.Errorf("FlapLowThreshold and FlapHighThreshold must be between 0 and 100")
+	}
+	if low, high := c.FlapThresholds(); c.FlapDetection && low >= high {
+		return fmt.Errorf("FlapLowThreshold must be lower than FlapHighThreshold")
+	}
+	if c.TLSExpiryWarningDays < 0 || c.TLSExpiryCriticalDays < 0 {
+		return fmt.Errorf("TLSExpiryWarningDays and TLSExpiryCriticalDays must be >= 0")
+	}
+	if warning, critical := c.TLSExpiryThresholds(); warning < critical {
+		return fmt.Errorf("TLSExpiryWarningDays (%d) can't be lower than TLSExpiryCriticalDays (%d)", warning, critical)
+	}
 
 	return nil
 }
 
+func (c *CheckType) validateDNS() error {
+	if c.DNSQueryName == "" {
+		return fmt.Errorf("DNSQueryName must be set for DNS checks")
+	}
+	if _, ok := dns.IsDomainName(c.DNSQueryName); !ok {
+		return fmt.Errorf("DNSQueryName %q is not a valid domain name", c.DNSQueryName)
+	}
+	if c.DNSQueryType != "" {
+		if _, ok := dns.StringToType[strings.ToUpper(c.DNSQueryType)]; !ok {
+			return fmt.Errorf("DNSQueryType %q is not a valid DNS record type", c.DNSQueryType)
+		}
+	}
+	switch strings.ToLower(c.DNSProtocol) {
+	case "", "udp", "tcp":
+	default:
+		return fmt.Errorf("DNSProtocol must be one of 'udp' or 'tcp'")
+	}
+	if c.DNSExpectedRcode != "" {
+		if _, ok := dns.StringToRcode[strings.ToUpper(c.DNSExpectedRcode)]; !ok {
+			return fmt.Errorf("DNSExpectedRcode %q is not a valid DNS response code", c.DNSExpectedRcode)
+		}
+	}
+	return nil
+}
+
 // Empty checks if the CheckType has no fields defined. Empty checks parsed from json configs are filtered out
 func (c *CheckType) Empty() bool {
 	return reflect.DeepEqual(c, &CheckType{})
 }
 
+// FlapThresholds returns the thresholds of flap detection, using the defaults
+// for the ones that aren't set.
+func (c *CheckType) FlapThresholds() (low, high int) {
+	low, high = c.FlapLowThreshold, c.FlapHighThreshold
+	if low == 0 {
+		low = DefaultFlapLowThreshold
+	}
+	if high == 0 {
+		high = DefaultFlapHighThreshold
+	}
+	return low, high
+}
+
+// TLSExpiryThresholds returns the thresholds of TLS checks, using the
+// defaults for the ones that aren't set.
+func (c *CheckType) TLSExpiryThresholds() (warning, critical int) {
+	warning, critical = c.TLSExpiryWarningDays, c.TLSExpiryCriticalDays
+	if warning == 0 {
+		warning = DefaultTLSExpiryWarningDays
+	}
+	if critical == 0 {
+		critical = DefaultTLSExpiryCriticalDays
+	}
+	return warning, critical
+}
+
 // IsAlias checks if this is an alias check.
 func (c *CheckType) IsAlias() bool {
 	return c.AliasNode != "" || c.AliasService != ""
@@ -265,6 +386,16 @@ func (c *CheckType) IsH2PING() bool {
 	return c.H2PING != "" && c.Interval > 0
 }
 
+// IsDNS checks if this is a DNS type
+func (c *CheckType) IsDNS() bool {
+	return c.DNS != "" && c.Interval > 0
+}
+
+// IsTLS checks if this is a TLS type
+func (c *CheckType) IsTLS() bool {
+	return c.TLS != "" && c.Interval > 0
+}
+
 // IsOSService checks if this is a WindowsService/systemd type
 func (c *CheckType) IsOSService() bool {
 	return c.OSService != "" && c.Interval > 0
@@ -284,6 +415,8 @@ func (c *CheckType) Type() string {
 		return "udp"
 	case c.IsAlias():
 		return "alias"
+	case c.IsComposite():
+		return "composite"
 	case c.IsDocker():
 		return "docker"
 	case c.IsScript():
@@ -292,6 +425,10 @@ func (c *CheckType) Type() string {
 		return "h2ping"
 	case c.IsOSService():
 		return "os_service"
+	case c.IsDNS():
+		return "dns"
+	case c.IsTLS():
+		return "tls"
 	default:
 		return ""
 	}
@@ -19,6 +19,7 @@ const (
 	errServiceNotFound            = "Service not found: "
 	errQueryNotFound              = "Query not found"
 	errLeaderNotTracked           = "Raft leader not found in server lookup mapping"
+	errKVSRevisionNotRetained     = "KV revision is no longer retained in the history"
 )
 
 var (
@@ -31,6 +32,7 @@ var (
 	ErrDCNotAvailable             = errors.New(errDCNotAvailable)
 	ErrQueryNotFound              = errors.New(errQueryNotFound)
 	ErrLeaderNotTracked           = errors.New(errLeaderNotTracked)
+	ErrKVSRevisionNotRetained     = errors.New(errKVSRevisionNotRetained)
 )
 
 func IsErrNoDCPath(err error) bool {
@@ -49,6 +51,10 @@ func IsErrRPCRateExceeded(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errRPCRateExceeded)
 }
 
+func IsErrKVSRevisionNotRetained(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errKVSRevisionNotRetained)
+}
+
 func IsErrServiceNotFound(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errServiceNotFound)
 }
@@ -0,0 +1,293 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// HTTPAssertion is a condition on the response of an HTTP check. Exactly one
+// of StatusRange, BodyContains, BodyRegex or JSONPath must be set. When the
+// condition doesn't hold the check takes the assertion's Status.
+type HTTPAssertion struct {
+	// StatusRange is a comma separated list of status codes or ranges of
+	// status codes the response must have, eg "200-299,301". When a check has
+	// status range assertions, they replace the default rule that 2xx
+	// responses are passing, 429 is warning and anything else is critical.
+	StatusRange string `json:",omitempty"`
+
+	// BodyContains is a string the response body must contain.
+	BodyContains string `json:",omitempty"`
+
+	// BodyRegex is a regular expression the response body must match.
+	BodyRegex string `json:",omitempty"`
+
+	// JSONPath is the path to a value in the JSON response body, eg
+	// "$.checks[0].status". If JSONValue is set the value must equal it,
+	// otherwise it only needs to be present. Strings are compared as-is, other
+	// values are compared using their JSON encoding.
+	JSONPath  string `json:",omitempty"`
+	JSONValue string `json:",omitempty"`
+
+	// Negate inverts the assertion, so that the response must not match it.
+	Negate bool `json:",omitempty"`
+
+	// Status is the status of the check when the assertion fails, either
+	// warning or critical. Defaults to critical.
+	Status string `json:",omitempty"`
+}
+
+// Validate returns an error if the assertion is invalid.
+func (a *HTTPAssertion) Validate() error {
+	set := 0
+	for _, v := range []string{a.StatusRange, a.BodyContains, a.BodyRegex, a.JSONPath} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of StatusRange, BodyContains, BodyRegex or JSONPath must be set")
+	}
+	if a.JSONValue != "" && a.JSONPath == "" {
+		return fmt.Errorf("JSONValue can only be set with JSONPath")
+	}
+	switch a.Status {
+	case "", api.HealthWarning, api.HealthCritical:
+	default:
+		return fmt.Errorf("Status must be one of %q or %q", api.HealthWarning, api.HealthCritical)
+	}
+
+	_, err := a.Compile()
+	return err
+}
+
+// Compile parses the status ranges, regular expression or JSON path of the
+// assertion, so that it can be evaluated repeatedly without parsing them on
+// every check run.
+func (a *HTTPAssertion) Compile() (*CompiledHTTPAssertion, error) {
+	c := &CompiledHTTPAssertion{HTTPAssertion: *a}
+
+	var err error
+	if a.StatusRange != "" {
+		if c.ranges, err = parseStatusRanges(a.StatusRange); err != nil {
+			return nil, err
+		}
+	}
+	if a.BodyRegex != "" {
+		if c.bodyRegex, err = regexp.Compile(a.BodyRegex); err != nil {
+			return nil, fmt.Errorf("invalid BodyRegex: %w", err)
+		}
+	}
+	if a.JSONPath != "" {
+		if c.jsonPath, err = parseJSONPath(a.JSONPath); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// FailureStatus returns the status of the check when the assertion fails.
+func (a *HTTPAssertion) FailureStatus() string {
+	if a.Status == "" {
+		return api.HealthCritical
+	}
+	return a.Status
+}
+
+// CompiledHTTPAssertion is an HTTPAssertion ready to be evaluated against
+// responses. It is returned by HTTPAssertion.Compile.
+type CompiledHTTPAssertion struct {
+	HTTPAssertion
+
+	ranges    [][2]int
+	bodyRegex *regexp.Regexp
+	jsonPath  jsonPath
+}
+
+// Evaluate returns whether the response satisfies the assertion and, when it
+// doesn't, a description of the failure.
+func (a *CompiledHTTPAssertion) Evaluate(statusCode int, body []byte) (bool, string) {
+	matched, desc, err := a.match(statusCode, body)
+	if err != nil {
+		return false, err.Error()
+	}
+	if matched == a.Negate {
+		if a.Negate {
+			return false, desc + " (negated)"
+		}
+		return false, desc
+	}
+	return true, ""
+}
+
+// match returns whether the response matches the assertion, ignoring Negate,
+// along with a description of the comparison.
+func (a *CompiledHTTPAssertion) match(statusCode int, body []byte) (bool, string, error) {
+	switch {
+	case a.StatusRange != "":
+		for _, r := range a.ranges {
+			if statusCode >= r[0] && statusCode <= r[1] {
+				return true, fmt.Sprintf("status %d is in %q", statusCode, a.StatusRange), nil
+			}
+		}
+		return false, fmt.Sprintf("status %d is not in %q", statusCode, a.StatusRange), nil
+
+	case a.BodyContains != "":
+		if strings.Contains(string(body), a.BodyContains) {
+			return true, fmt.Sprintf("body contains %q", a.BodyContains), nil
+		}
+		return false, fmt.Sprintf("body does not contain %q", a.BodyContains), nil
+
+	case a.BodyRegex != "":
+		if a.bodyRegex.Match(body) {
+			return true, fmt.Sprintf("body matches %q", a.BodyRegex), nil
+		}
+		return false, fmt.Sprintf("body does not match %q", a.BodyRegex), nil
+
+	case a.JSONPath != "":
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return false, fmt.Sprintf("body is not valid JSON: %s", err), nil
+		}
+		v, ok := a.jsonPath.lookup(doc)
+		if !ok {
+			return false, fmt.Sprintf("%s is not present", a.JSONPath), nil
+		}
+		got := jsonValueString(v)
+		if a.JSONValue == "" {
+			return true, fmt.Sprintf("%s is present with value %q", a.JSONPath, got), nil
+		}
+		if got == a.JSONValue {
+			return true, fmt.Sprintf("%s is %q", a.JSONPath, got), nil
+		}
+		return false, fmt.Sprintf("%s is %q, expected %q", a.JSONPath, got, a.JSONValue), nil
+	}
+	return false, "", fmt.Errorf("empty assertion")
+}
+
+// parseStatusRanges parses a comma separated list of status codes and ranges
+// into inclusive [min, max] pairs.
+func parseStatusRanges(s string) ([][2]int, error) {
+	var ranges [][2]int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		min, err := parseStatusCode(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid StatusRange %q: %w", s, err)
+		}
+		max := min
+		if isRange {
+			if max, err = parseStatusCode(hi); err != nil {
+				return nil, fmt.Errorf("invalid StatusRange %q: %w", s, err)
+			}
+		}
+		if min > max {
+			return nil, fmt.Errorf("invalid StatusRange %q: %d is greater than %d", s, min, max)
+		}
+		ranges = append(ranges, [2]int{min, max})
+	}
+	return ranges, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("%q is not a valid HTTP status code", s)
+	}
+	return code, nil
+}
+
+// jsonPath is a parsed JSONPath expression. Only the subset of the syntax
+// that selects a single value is supported: object members using dot or
+// bracket notation, and array elements by index, eg $.a.b[0]['c'].
+type jsonPath []interface{}
+
+func parseJSONPath(s string) (jsonPath, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(s), "$")
+	var path jsonPath
+	for p != "" {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end == -1 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty member name", s)
+			}
+			path = append(path, p[:end])
+			p = p[end:]
+
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath %q: missing ']'", s)
+			}
+			inner := p[1:end]
+			p = p[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path = append(path, inner[1:len(inner)-1])
+				continue
+			}
+			idx, err := strconv.Atoi(inner)
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: invalid index %q", s, inner)
+			}
+			path = append(path, idx)
+
+		default:
+			if len(path) > 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", s, p[0])
+			}
+			// Allow the leading "$." to be omitted.
+			p = "." + p
+		}
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("invalid JSONPath %q: the path must select a value", s)
+	}
+	return path, nil
+}
+
+func (p jsonPath) lookup(doc interface{}) (interface{}, bool) {
+	v := doc
+	for _, elem := range p {
+		switch elem := elem.(type) {
+		case string:
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if v, ok = obj[elem]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := v.([]interface{})
+			if !ok || elem >= len(arr) {
+				return nil, false
+			}
+			v = arr[elem]
+		}
+	}
+	return v, true
+}
+
+func jsonValueString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPAssertion_Validate(t *testing.T) {
+	cases := map[string]struct {
+		assertion HTTPAssertion
+		err       string
+	}{
+		"status range":        {assertion: HTTPAssertion{StatusRange: "200-299, 301"}},
+		"body contains":       {assertion: HTTPAssertion{BodyContains: "ok", Negate: true, Status: "warning"}},
+		"body regex":          {assertion: HTTPAssertion{BodyRegex: "^ok$"}},
+		"json path":           {assertion: HTTPAssertion{JSONPath: "$.checks[0]['status']", JSONValue: "ok"}},
+		"json path no prefix": {assertion: HTTPAssertion{JSONPath: "status"}},
+		"empty": {
+			err: "exactly one of StatusRange, BodyContains, BodyRegex or JSONPath must be set",
+		},
+		"several": {
+			assertion: HTTPAssertion{BodyContains: "ok", BodyRegex: "ok"},
+			err:       "exactly one of",
+		},
+		"json value without path": {
+			assertion: HTTPAssertion{BodyContains: "ok", JSONValue: "ok"},
+			err:       "JSONValue can only be set with JSONPath",
+		},
+		"bad status": {
+			assertion: HTTPAssertion{BodyContains: "ok", Status: "passing"},
+			err:       `Status must be one of "warning" or "critical"`,
+		},
+		"bad status code": {
+			assertion: HTTPAssertion{StatusRange: "200-600"},
+			err:       `"600" is not a valid HTTP status code`,
+		},
+		"inverted range": {
+			assertion: HTTPAssertion{StatusRange: "299-200"},
+			err:       "299 is greater than 200",
+		},
+		"bad regex": {
+			assertion: HTTPAssertion{BodyRegex: "("},
+			err:       "invalid BodyRegex",
+		},
+		"bad json path index": {
+			assertion: HTTPAssertion{JSONPath: "$.checks[x]"},
+			err:       `invalid index "x"`,
+		},
+		"unterminated json path": {
+			assertion: HTTPAssertion{JSONPath: "$.checks[0"},
+			err:       "missing ']'",
+		},
+		"root json path": {
+			assertion: HTTPAssertion{JSONPath: "$"},
+			err:       "the path must select a value",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.assertion.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestHTTPAssertion_Evaluate(t *testing.T) {
+	body := []byte(`{"status":"ok","checks":[{"name":"db","healthy":true,"latency":1.5}],"error":null}`)
+
+	cases := map[string]struct {
+		assertion HTTPAssertion
+		ok        bool
+		desc      string
+	}{
+		"json string": {
+			assertion: HTTPAssertion{JSONPath: "$.status", JSONValue: "ok"},
+			ok:        true,
+		},
+		"json bool": {
+			assertion: HTTPAssertion{JSONPath: "$.checks[0].healthy", JSONValue: "true"},
+			ok:        true,
+		},
+		"json number": {
+			assertion: HTTPAssertion{JSONPath: `$.checks[0]["latency"]`, JSONValue: "2"},
+			desc:      `$.checks[0]["latency"] is "1.5", expected "2"`,
+		},
+		"json null is present": {
+			assertion: HTTPAssertion{JSONPath: "$.error"},
+			ok:        true,
+		},
+		"json missing": {
+			assertion: HTTPAssertion{JSONPath: "$.checks[1].name"},
+			desc:      "$.checks[1].name is not present",
+		},
+		"json negated": {
+			assertion: HTTPAssertion{JSONPath: "$.checks[0].name", JSONValue: "db", Negate: true},
+			desc:      `$.checks[0].name is "db" (negated)`,
+		},
+		"status range": {
+			assertion: HTTPAssertion{StatusRange: "500-599"},
+			desc:      `status 200 is not in "500-599"`,
+		},
+		"body regex": {
+			assertion: HTTPAssertion{BodyRegex: `"name":"d[a-z]+"`},
+			ok:        true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			compiled, err := tc.assertion.Compile()
+			require.NoError(t, err)
+
+			ok, desc := compiled.Evaluate(200, body)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.desc, desc)
+		})
+	}
+
+	compiled, err := (&HTTPAssertion{JSONPath: "$.status"}).Compile()
+	require.NoError(t, err)
+
+	ok, desc := compiled.Evaluate(200, []byte("not json"))
+	require.False(t, ok)
+	require.Contains(t, desc, "body is not valid JSON")
+}
@@ -3,11 +3,19 @@
 
 package structs
 
+import "strings"
+
 type SnapshotOp int
 
 const (
 	SnapshotSave SnapshotOp = iota
 	SnapshotRestore
+
+	// SnapshotRestoreFiltered replays a subset of a snapshot's records through
+	// the usual Raft applies, rather than replacing the whole state. It's a
+	// distinct op so that older servers reject it instead of performing a full
+	// restore.
+	SnapshotRestoreFiltered
 )
 
 // SnapshotReplyFn gets a peek at the reply before the snapshot streams, which
@@ -32,6 +40,66 @@ type SnapshotRequest struct {
 
 	// Op is the operation code for the RPC.
 	Op SnapshotOp
+
+	// Filter selects the records to restore. Only applies to
+	// SnapshotRestoreFiltered.
+	Filter SnapshotRestoreFilter
+}
+
+// SnapshotRestoreFilter selects which records of a snapshot are replayed by a
+// filtered restore.
+type SnapshotRestoreFilter struct {
+	// KVPrefixes restores the KV entries whose keys start with any of the
+	// given prefixes. An empty prefix matches every key.
+	KVPrefixes []string
+
+	// ConfigEntries restores the config entries matching any of the given
+	// selectors, which take the form "<kind>" or "<kind>/<name>". A selector of
+	// "*" matches every config entry.
+	ConfigEntries []string
+
+	// ACLs restores ACL tokens and policies.
+	ACLs bool
+}
+
+// IsEmpty returns true if the filter doesn't select any records.
+func (f *SnapshotRestoreFilter) IsEmpty() bool {
+	return len(f.KVPrefixes) == 0 && len(f.ConfigEntries) == 0 && !f.ACLs
+}
+
+// MatchesKey returns true if the KV entry with the given key is selected.
+func (f *SnapshotRestoreFilter) MatchesKey(key string) bool {
+	for _, prefix := range f.KVPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesConfigEntry returns true if the given config entry is selected.
+func (f *SnapshotRestoreFilter) MatchesConfigEntry(entry ConfigEntry) bool {
+	for _, sel := range f.ConfigEntries {
+		kind, name, hasName := strings.Cut(sel, "/")
+		switch {
+		case sel == "*":
+			return true
+		case kind != entry.GetKind():
+			continue
+		case !hasName || name == entry.GetName():
+			return true
+		}
+	}
+	return false
+}
+
+// SnapshotRestoreSummary reports the number of records replayed by a filtered
+// restore. It's streamed back to the caller as JSON.
+type SnapshotRestoreSummary struct {
+	KV            int
+	ConfigEntries int
+	ACLTokens     int
+	ACLPolicies   int
 }
 
 // SnapshotResponse is used header for a snapshot RPC response. This will
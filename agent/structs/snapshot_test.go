@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestoreFilter(t *testing.T) {
+	var empty SnapshotRestoreFilter
+	require.True(t, empty.IsEmpty())
+	require.False(t, empty.MatchesKey("foo"))
+	require.False(t, empty.MatchesConfigEntry(&ServiceConfigEntry{Kind: ServiceDefaults, Name: "web"}))
+
+	filter := SnapshotRestoreFilter{
+		KVPrefixes:    []string{"app/", "config/web"},
+		ConfigEntries: []string{ServiceIntentions, "service-defaults/web"},
+	}
+	require.False(t, filter.IsEmpty())
+
+	require.True(t, filter.MatchesKey("app/foo"))
+	require.True(t, filter.MatchesKey("config/web/port"))
+	require.False(t, filter.MatchesKey("config/db/port"))
+	require.False(t, filter.MatchesKey("ap"))
+
+	require.True(t, filter.MatchesConfigEntry(&ServiceIntentionsConfigEntry{Kind: ServiceIntentions, Name: "db"}))
+	require.True(t, filter.MatchesConfigEntry(&ServiceConfigEntry{Kind: ServiceDefaults, Name: "web"}))
+	require.False(t, filter.MatchesConfigEntry(&ServiceConfigEntry{Kind: ServiceDefaults, Name: "db"}))
+	require.False(t, filter.MatchesConfigEntry(&ProxyConfigEntry{Kind: ProxyDefaults, Name: ProxyConfigGlobal}))
+
+	all := SnapshotRestoreFilter{KVPrefixes: []string{""}, ConfigEntries: []string{"*"}}
+	require.True(t, all.MatchesKey("anything"))
+	require.True(t, all.MatchesConfigEntry(&ProxyConfigEntry{Kind: ProxyDefaults, Name: ProxyConfigGlobal}))
+}
@@ -89,6 +89,8 @@ const (
 	RaftLogVerifierCheckpoint                   = 41 // Only used for log verifier, no-op on FSM.
 	ResourceOperationType                       = 42
 	UpdateVirtualIPRequestType                  = 43
+	KVSRevisionType                             = 44 // FSM snapshots only.
+	KVSRevisionReapRequestType                  = 45
 )
 
 const (
@@ -158,6 +160,8 @@ var requestTypeStrings = map[MessageType]string{
 	RaftLogVerifierCheckpoint:       "RaftLogVerifierCheckpoint",
 	ResourceOperationType:           "Resource",
 	UpdateVirtualIPRequestType:      "UpdateManualVirtualIPRequestType",
+	KVSRevisionType:                 "KVSRevision", // FSM snapshots only.
+	KVSRevisionReapRequestType:      "KVSRevisionReap",
 }
 
 const (
@@ -1798,6 +1802,10 @@ type HealthCheck struct {
 	// It is empty if the check was registered locally.
 	PeerName string `json:",omitempty"`
 
+	// Flapping is set while the status changes of the check are suppressed
+	// by flap detection.
+	Flapping bool `json:",omitempty"`
+
 	Definition HealthCheckDefinition `bexpr:"-"`
 
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash" bexpr:"-"`
@@ -1978,6 +1986,7 @@ func (c *HealthCheck) IsSame(other *HealthCheck) bool {
 		!reflect.DeepEqual(c.ServiceTags, other.ServiceTags) ||
 		!reflect.DeepEqual(c.Definition, other.Definition) ||
 		c.PeerName != other.PeerName ||
+		c.Flapping != other.Flapping ||
 		!c.EnterpriseMeta.IsSame(&other.EnterpriseMeta) {
 		return false
 	}
@@ -2593,6 +2602,12 @@ type DirEntry struct {
 	Value     []byte
 	Session   string `json:",omitempty"`
 
+	// TTL is the time to live of the entry, as a duration string. When it
+	// is set, the leader computes Expires before committing the write and
+	// deletes the entry once it is reached.
+	TTL     string     `json:",omitempty"`
+	Expires *time.Time `json:",omitempty" bexpr:"-"`
+
 	acl.EnterpriseMeta `bexpr:"-"`
 	RaftIndex
 }
@@ -2605,6 +2620,8 @@ func (d *DirEntry) Clone() *DirEntry {
 		Flags:     d.Flags,
 		Value:     d.Value,
 		Session:   d.Session,
+		TTL:       d.TTL,
+		Expires:   d.Expires,
 		RaftIndex: RaftIndex{
 			CreateIndex: d.CreateIndex,
 			ModifyIndex: d.ModifyIndex,
@@ -2618,7 +2635,16 @@ func (d *DirEntry) Equal(o *DirEntry) bool {
 		d.Key == o.Key &&
 		d.Flags == o.Flags &&
 		bytes.Equal(d.Value, o.Value) &&
-		d.Session == o.Session
+		d.Session == o.Session &&
+		d.TTL == o.TTL &&
+		equalTimePtr(d.Expires, o.Expires)
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
 }
 
 // IDValue implements the state.singleValueID interface for indexing.
@@ -2628,6 +2654,20 @@ func (d *DirEntry) IDValue() string {
 
 type DirEntries []*DirEntry
 
+// DirEntryRevision is a version of a KV entry kept in the history of its
+// key. The ModifyIndex of a revision is the index it was written at, or the
+// index the key was deleted at for deleted revisions.
+type DirEntryRevision struct {
+	DirEntry
+	Deleted bool `json:",omitempty"`
+
+	// Truncated is set on the oldest revision kept for a key once older
+	// revisions of the key have been dropped from the history.
+	Truncated bool `json:",omitempty"`
+}
+
+type DirEntryRevisions []*DirEntryRevision
+
 // KVSRequest is used to operate on the Key-Value store
 type KVSRequest struct {
 	Datacenter string
@@ -2652,6 +2692,44 @@ func (r *KeyRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+func (r *KeyRequest) CacheInfo() cache.RequestInfo {
+	info := cache.RequestInfo{
+		Token:          r.Token,
+		Datacenter:     r.Datacenter,
+		MinIndex:       r.MinQueryIndex,
+		Timeout:        r.MaxQueryTime,
+		MaxAge:         r.MaxAge,
+		MustRevalidate: r.MustRevalidate,
+	}
+
+	v, err := hashstructure.Hash([]interface{}{
+		r.Key,
+		r.EnterpriseMeta,
+	}, nil)
+	if err == nil {
+		// If there is an error, we don't set the key. A blank key forces
+		// no cache for this request so the request is forwarded directly
+		// to the server.
+		info.Key = strconv.FormatUint(v, 10)
+	}
+
+	return info
+}
+
+// KeyRevisionRequest is used to request the revision of a key at a given
+// Raft index
+type KeyRevisionRequest struct {
+	Datacenter string
+	Key        string
+	AtIndex    uint64
+	acl.EnterpriseMeta
+	QueryOptions
+}
+
+func (r *KeyRevisionRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
 // KeyListRequest is used to list keys
 type KeyListRequest struct {
 	Datacenter string
@@ -2670,6 +2748,11 @@ type IndexedDirEntries struct {
 	QueryMeta
 }
 
+type IndexedDirEntryRevisions struct {
+	Revisions DirEntryRevisions
+	QueryMeta
+}
+
 type IndexedKeyList struct {
 	Keys []string
 	QueryMeta
@@ -2872,6 +2955,18 @@ const (
 	TombstoneReap TombstoneOp = "reap"
 )
 
+// KVSRevisionReapRequest is used to trigger a reaping of the revisions of
+// the KV history
+type KVSRevisionReapRequest struct {
+	Datacenter string
+	ReapIndex  uint64
+	WriteRequest
+}
+
+func (r *KVSRevisionReapRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
 // TombstoneRequest is used to trigger a reaping of the tombstones
 type TombstoneRequest struct {
 	Datacenter string
@@ -33,6 +33,7 @@ const (
 	SystemMetadataIntentionFormatLegacyValue   = "legacy"
 	SystemMetadataVirtualIPsEnabled            = "virtual-ips"
 	SystemMetadataTermGatewayVirtualIPsEnabled = "virtual-ips-term-gateway"
+	SystemMetadataKVHistoryMaxRevisions        = "kv-history-max-revisions"
 )
 
 type SystemMetadataEntry struct {
@@ -16,13 +16,6 @@ import (
 	"github.com/hashicorp/consul/types"
 )
 
-const (
-	// maxTxnOps is used to set an upper limit on the number of operations
-	// inside a transaction. If there are more operations than this, then the
-	// client is likely abusing transactions.
-	maxTxnOps = 128
-)
-
 // decodeValue decodes the value member of the given operation.
 func decodeValue(rawKV interface{}) error {
 	rawMap, ok := rawKV.(map[string]interface{})
@@ -119,10 +112,11 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 
 	// Enforce a reasonable upper limit on the number of operations in a
 	// transaction in order to curb abuse.
-	if size := len(ops); size > maxTxnOps {
+	if size, maxTxnOps := len(ops), s.agent.config.TxnMaxOps; size > maxTxnOps {
 		return nil, 0, HTTPError{
 			StatusCode: http.StatusRequestEntityTooLarge,
-			Reason:     fmt.Sprintf("Transaction contains too many operations (%d > %d)", size, maxTxnOps),
+			Reason: fmt.Sprintf("Transaction contains too many operations (%d > %d). See %s.",
+				size, maxTxnOps, "https://www.consul.io/docs/agent/config/config-files#txn_max_ops"),
 		}
 	}
 
@@ -155,6 +149,7 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 						Value:   in.KV.Value,
 						Flags:   in.KV.Flags,
 						Session: in.KV.Session,
+						TTL:     in.KV.TTL,
 						EnterpriseMeta: acl.NewEnterpriseMetaWithPartition(
 							in.KV.Partition,
 							in.KV.Namespace,
@@ -168,7 +163,7 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 			opsRPC = append(opsRPC, out)
 
 		case in.Node != nil:
-			if in.Node.Verb != api.NodeGet {
+			if in.Node.Verb != api.NodeGet && in.Node.Verb != api.NodeCheckExists {
 				writes++
 			}
 
@@ -199,7 +194,7 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 			opsRPC = append(opsRPC, out)
 
 		case in.Service != nil:
-			if in.Service.Verb != api.ServiceGet {
+			if in.Service.Verb != api.ServiceGet && in.Service.Verb != api.ServiceCheckHealthy {
 				writes++
 			}
 
@@ -331,6 +326,31 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 				},
 			}
 			opsRPC = append(opsRPC, out)
+
+		case in.Session != nil:
+			// Sessions can only be checked, they are managed through the
+			// session endpoints.
+			if in.Session.Verb != api.SessionCheckValid {
+				return nil, 0, HTTPError{
+					StatusCode: http.StatusBadRequest,
+					Reason:     fmt.Sprintf("Invalid Session verb %q", in.Session.Verb),
+				}
+			}
+
+			session := in.Session.Entry
+			out := &structs.TxnOp{
+				Session: &structs.TxnSessionOp{
+					Verb: in.Session.Verb,
+					Session: structs.Session{
+						ID: session.ID,
+						EnterpriseMeta: acl.NewEnterpriseMetaWithPartition(
+							"",
+							session.Namespace,
+						),
+					},
+				},
+			}
+			opsRPC = append(opsRPC, out)
 		}
 	}
 
@@ -218,7 +218,7 @@ func TestTxnEndpoint_Bad_Size_Ops(t *testing.T) {
          }
      }
  ]
- `, strings.Repeat(`{ "KV": { "Verb": "get", "Key": "key" } },`, 2*maxTxnOps))))
+ `, strings.Repeat(`{ "KV": { "Verb": "get", "Key": "key" } },`, 2*a.config.TxnMaxOps))))
 	req, _ := http.NewRequest("PUT", "/v1/txn", buf)
 	resp := httptest.NewRecorder()
 	_, err := a.srv.Txn(resp, req)
@@ -518,6 +518,39 @@ func TestTxnEndpoint_KV_Actions(t *testing.T) {
 	})
 }
 
+func TestTxnEndpoint_KV_TTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	txn := func(t *testing.T, body string) structs.TxnResponse {
+		req, _ := http.NewRequest("PUT", "/v1/txn", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.Txn(resp, req)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.Code, resp.Body.String())
+		txnResp, ok := obj.(structs.TxnResponse)
+		require.True(t, ok, "bad type: %T", obj)
+		require.Len(t, txnResp.Results, 1)
+		return txnResp
+	}
+
+	// A set with a TTL must carry it through to the servers.
+	out := txn(t, `[{"KV": {"Verb": "set", "Key": "key", "Value": "aGVsbG8=", "TTL": "1h"}}]`)
+	require.Equal(t, "1h", out.Results[0].KV.TTL)
+	require.NotNil(t, out.Results[0].KV.Expires)
+
+	// A set without a TTL clears the expiration.
+	out = txn(t, `[{"KV": {"Verb": "set", "Key": "key", "Value": "aGVsbG8="}}]`)
+	require.Empty(t, out.Results[0].KV.TTL)
+	require.Nil(t, out.Results[0].KV.Expires)
+}
+
 func TestTxnEndpoint_UpdateCheck(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -802,6 +835,121 @@ func TestTxnEndpoint_NodeService(t *testing.T) {
 	assert.Equal(t, expected, txnResp)
 }
 
+func TestTxnEndpoint_CheckOps(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Put a key and create a session to check.
+	req, _ := http.NewRequest("PUT", "/v1/kv/flag", strings.NewReader("enabled"))
+	_, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+
+	req, _ = http.NewRequest("PUT", "/v1/session/create", nil)
+	obj, err := a.srv.SessionCreate(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	session := obj.(sessionCreateResponse).ID
+
+	txn := func(body string) (*httptest.ResponseRecorder, interface{}, error) {
+		req, _ := http.NewRequest("PUT", "/v1/txn", strings.NewReader(body))
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.Txn(resp, req)
+		return resp, obj, err
+	}
+
+	t.Run("passing checks are read-only", func(t *testing.T) {
+		resp, obj, err := txn(fmt.Sprintf(`
+[
+	{
+		"KV": {
+			"Verb": "check-value-prefix",
+			"Key": "flag",
+			"Value": %q
+		}
+	},
+	{
+		"Node": {
+			"Verb": "check-exists",
+			"Node": {
+				"Node": %q
+			}
+		}
+	},
+	{
+		"Service": {
+			"Verb": "check-healthy",
+			"Service": {
+				"Service": "consul"
+			}
+		}
+	},
+	{
+		"Session": {
+			"Verb": "check-valid",
+			"Session": {
+				"ID": %q
+			}
+		}
+	}
+]
+`, base64.StdEncoding.EncodeToString([]byte("enable")), a.config.NodeName, session))
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.Code)
+
+		txnResp, ok := obj.(structs.TxnReadResponse)
+		require.True(t, ok, "bad type: %T", obj)
+		require.Empty(t, txnResp.Errors)
+	})
+
+	t.Run("failed checks return a conflict", func(t *testing.T) {
+		resp, _, err := txn(fmt.Sprintf(`
+[
+	{
+		"KV": {
+			"Verb": "check-value",
+			"Key": "flag",
+			"Value": %q
+		}
+	},
+	{
+		"KV": {
+			"Verb": "set",
+			"Key": "flag",
+			"Value": %q
+		}
+	}
+]
+`, base64.StdEncoding.EncodeToString([]byte("disabled")), base64.StdEncoding.EncodeToString([]byte("on"))))
+		require.NoError(t, err)
+		require.Equal(t, 409, resp.Code)
+		require.Contains(t, resp.Body.String(), "failed value check")
+	})
+
+	t.Run("sessions can only be checked", func(t *testing.T) {
+		_, _, err := txn(fmt.Sprintf(`
+[
+	{
+		"Session": {
+			"Verb": "delete",
+			"Session": {
+				"ID": %q
+			}
+		}
+	}
+]
+`, session))
+		httpErr, ok := err.(HTTPError)
+		require.True(t, ok, "bad error: %v", err)
+		require.Equal(t, 400, httpErr.StatusCode)
+		require.Contains(t, httpErr.Reason, `Invalid Session verb "delete"`)
+	})
+}
+
 func TestTxnEndpoint_OperationsSize(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -851,4 +999,37 @@ func TestTxnEndpoint_OperationsSize(t *testing.T) {
 		require.NotNil(t, raw)
 		agent.Shutdown()
 	})
+
+	t.Run("configured-limit", func(t *testing.T) {
+		var ops []api.TxnOp
+		agent := NewTestAgent(t, "limits = { txn_max_req_len = 2000000, txn_max_ops = 300 }")
+		defer agent.Shutdown()
+		testrpc.WaitForTestAgent(t, agent.RPC, "dc1")
+
+		// The transaction is larger than a raft entry, and applied in chunks.
+		value := bytes.Repeat([]byte("x"), 4096)
+		for i := 0; i < 300; i++ {
+			ops = append(ops, api.TxnOp{
+				KV: &api.KVTxnOp{
+					Verb:  api.KVSet,
+					Key:   fmt.Sprintf("key%d", i),
+					Value: value,
+				},
+			})
+		}
+
+		req, _ := http.NewRequest("PUT", "/v1/txn", jsonBody(ops))
+		resp := httptest.NewRecorder()
+		raw, err := agent.srv.Txn(resp, req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Len(t, raw.(structs.TxnResponse).Results, 300)
+
+		ops = append(ops, ops[0])
+		req, _ = http.NewRequest("PUT", "/v1/txn", jsonBody(ops))
+		resp = httptest.NewRecorder()
+		_, err = agent.srv.Txn(resp, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Transaction contains too many operations (301 > 300)")
+	})
 }
@@ -13,10 +13,12 @@ import (
 	"os"
 	osexec "os/exec"
 	"strconv"
+	"time"
 
 	"github.com/armon/circbuf"
 	"github.com/hashicorp/consul/agent/exec"
 	"github.com/hashicorp/consul/api/watch"
+	"github.com/hashicorp/consul/logging"
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-hclog"
 	"golang.org/x/net/context"
@@ -188,6 +190,56 @@ func makeHTTPWatchHandler(logger hclog.Logger, config *watch.HttpHandlerConfig)
 	return fn
 }
 
+// fileWatchEntry is the JSON line appended by a file watch handler for each
+// result of the watch.
+type fileWatchEntry struct {
+	Index     uint64
+	Timestamp time.Time
+	Data      interface{}
+}
+
+// makeFileWatchHandler returns a handler appending the results of a watch to
+// a rotating local file, along with the file so it can be closed once the
+// watch is stopped. Each result is written as a single JSON line and synced to
+// disk before the handler returns, so that consumers tailing the file can
+// resume from the last index they processed.
+func makeFileWatchHandler(logger hclog.Logger, config *watch.FileHandlerConfig) (watch.HandlerFunc, io.Closer, error) {
+	logFile, err := logging.NewLogFile(config.Path, config.RotateDuration, config.RotateBytes, config.RotateMaxFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fn := func(idx uint64, data interface{}) {
+		// Encode the whole line first so that it is written at once and
+		// never split across rotated files.
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		entry := fileWatchEntry{Index: idx, Timestamp: time.Now().UTC(), Data: data}
+		if err := enc.Encode(entry); err != nil {
+			logger.Error("Failed to encode data for file watch",
+				"watch", config.Path,
+				"error", err,
+			)
+			return
+		}
+
+		if _, err := logFile.Write(buf.Bytes()); err != nil {
+			logger.Error("Failed to write file watch entry",
+				"watch", config.Path,
+				"error", err,
+			)
+			return
+		}
+		if err := logFile.Sync(); err != nil {
+			logger.Error("Failed to sync file watch entry",
+				"watch", config.Path,
+				"error", err,
+			)
+		}
+	}
+	return fn, logFile, nil
+}
+
 // TODO: return a fully constructed watch.Plan with a Plan.Handler, so that Exempt
 // can be ignored by the caller.
 func makeWatchPlan(logger hclog.Logger, params map[string]interface{}) (*watch.Plan, error) {
@@ -213,10 +265,11 @@ func makeWatchPlan(logger hclog.Logger, params map[string]interface{}) (*watch.P
 		}
 	}
 
-	if hasHandler && hasArgs || hasHandler && wp.HandlerType == "http" || hasArgs && wp.HandlerType == "http" {
+	nonScript := wp.HandlerType == "http" || wp.HandlerType == "file"
+	if hasHandler && hasArgs || hasHandler && nonScript || hasArgs && nonScript {
 		return nil, fmt.Errorf("Only one watch handler allowed")
 	}
-	if !hasHandler && !hasArgs && wp.HandlerType != "http" {
+	if !hasHandler && !hasArgs && !nonScript {
 		return nil, fmt.Errorf("Must define a watch handler")
 	}
 	return wp, nil
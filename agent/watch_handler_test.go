@@ -4,10 +4,13 @@
 package agent
 
 import (
+	"bufio"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -69,6 +72,45 @@ func TestMakeHTTPWatchHandler(t *testing.T) {
 	handler(100, []string{"foo", "bar", "baz"})
 }
 
+func TestMakeFileWatchHandler(t *testing.T) {
+	dir := testutil.TempDir(t, "watch")
+	config := watch.FileHandlerConfig{
+		Path:        filepath.Join(dir, "events.log"),
+		RotateBytes: 40,
+	}
+	handler, file, err := makeFileWatchHandler(testutil.Logger(t), &config)
+	require.NoError(t, err)
+	handler(100, []string{"foo", "bar", "baz"})
+	handler(101, []string{"foo"})
+	require.NoError(t, file.Close())
+
+	// Each entry exceeds the max size so they are written to their own file,
+	// and the files sort in the order they were written.
+	files, err := filepath.Glob(filepath.Join(dir, "events-*.log"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	var entries []fileWatchEntry
+	for _, name := range files {
+		f, err := os.Open(name)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var entry fileWatchEntry
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			entries = append(entries, entry)
+		}
+		require.NoError(t, scanner.Err())
+		f.Close()
+	}
+	require.Len(t, entries, 2)
+	require.Equal(t, uint64(100), entries[0].Index)
+	require.Equal(t, []interface{}{"foo", "bar", "baz"}, entries[0].Data)
+	require.False(t, entries[0].Timestamp.IsZero())
+	require.Equal(t, uint64(101), entries[1].Index)
+	require.Equal(t, []interface{}{"foo"}, entries[1].Data)
+}
+
 type raw map[string]interface{}
 
 func TestMakeWatchPlan(t *testing.T) {
@@ -162,6 +204,50 @@ func TestMakeWatchPlan(t *testing.T) {
 			},
 			expectedErr: "Only one watch handler allowed",
 		},
+		{
+			name: "handler_type file",
+			params: raw{
+				"type":         "services",
+				"handler_type": "file",
+				"file_handler_config": []map[string]interface{}{
+					{
+						"path":             "/var/log/consul/services.log",
+						"rotate_duration":  "1h",
+						"rotate_bytes":     1024,
+						"rotate_max_files": 5,
+					},
+				},
+			},
+			expected: func(t *testing.T, plan *watch.Plan) {
+				require.Equal(t, plan.HandlerType, "file")
+				require.Equal(t, &watch.FileHandlerConfig{
+					Path:              "/var/log/consul/services.log",
+					RotateDuration:    time.Hour,
+					RotateDurationRaw: "1h",
+					RotateBytes:       1024,
+					RotateMaxFiles:    5,
+				}, plan.Exempt["file_handler_config"])
+			},
+		},
+		{
+			name: "handler_type file, with args",
+			params: raw{
+				"type":                "services",
+				"handler_type":        "file",
+				"file_handler_config": map[string]interface{}{"path": "services.log"},
+				"args":                []interface{}{"./script.sh"},
+			},
+			expectedErr: "Only one watch handler allowed",
+		},
+		{
+			name: "handler_type file, without path",
+			params: raw{
+				"type":                "services",
+				"handler_type":        "file",
+				"file_handler_config": map[string]interface{}{"rotate_bytes": 1024},
+			},
+			expectedErr: "Requires 'path' to be set",
+		},
 		{
 			name: "no handler_type",
 			params: raw{
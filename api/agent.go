@@ -328,6 +328,36 @@ type AgentCheckRegistration struct {
 	Partition string `json:",omitempty"`
 }
 
+// HTTPAssertion is a condition on the response of an HTTP check. Exactly one
+// of StatusRange, BodyContains, BodyRegex or JSONPath must be set. When the
+// condition doesn't hold the check takes the assertion's Status, which is
+// critical by default.
+type HTTPAssertion struct {
+	// StatusRange is a comma separated list of status codes or ranges, eg
+	// "200-299,301". Status range assertions replace the default mapping of
+	// status codes to check statuses.
+	StatusRange  string `json:",omitempty"`
+	BodyContains string `json:",omitempty"`
+	BodyRegex    string `json:",omitempty"`
+
+	// JSONPath selects a value in the JSON response body, eg "$.status". If
+	// JSONValue is set the value must equal it, otherwise it must be present.
+	JSONPath  string `json:",omitempty"`
+	JSONValue string `json:",omitempty"`
+
+	// Negate inverts the assertion.
+	Negate bool   `json:",omitempty"`
+	Status string `json:",omitempty"`
+}
+
+// CompositeCheckRef references a check whose status contributes to a
+// composite check. If Node is empty the check is looked up on the agent the
+// composite check is registered with.
+type CompositeCheckRef struct {
+	Node    string `json:",omitempty"`
+	CheckID string
+}
+
 // AgentServiceCheck is used to define a node or service level check
 type AgentServiceCheck struct {
 	CheckID                string              `json:",omitempty"`
@@ -340,6 +370,7 @@ type AgentServiceCheck struct {
 	TTL                    string              `json:",omitempty"`
 	HTTP                   string              `json:",omitempty"`
 	Header                 map[string][]string `json:",omitempty"`
+	HTTPAssertions         []HTTPAssertion     `json:",omitempty"`
 	Method                 string              `json:",omitempty"`
 	Body                   string              `json:",omitempty"`
 	TCP                    string              `json:",omitempty"`
@@ -352,12 +383,35 @@ type AgentServiceCheck struct {
 	GRPCUseTLS             bool                `json:",omitempty"`
 	H2PING                 string              `json:",omitempty"`
 	H2PingUseTLS           bool                `json:",omitempty"`
+	DNS                    string              `json:",omitempty"`
+	DNSQueryName           string              `json:",omitempty"`
+	DNSQueryType           string              `json:",omitempty"`
+	DNSProtocol            string              `json:",omitempty"`
+	DNSExpectedAnswer      []string            `json:",omitempty"`
+	DNSExpectedRcode       string              `json:",omitempty"`
+	TLS                    string              `json:",omitempty"`
+	TLSCAFile              string              `json:",omitempty"`
+	TLSExpiryWarningDays   int                 `json:",omitempty"`
+	TLSExpiryCriticalDays  int                 `json:",omitempty"`
 	AliasNode              string              `json:",omitempty"`
 	AliasService           string              `json:",omitempty"`
 	SuccessBeforePassing   int                 `json:",omitempty"`
 	FailuresBeforeWarning  int                 `json:",omitempty"`
 	FailuresBeforeCritical int                 `json:",omitempty"`
 
+	// CompositeChecks are the checks whose status is combined by a composite
+	// check, using CompositeMode and, for the threshold mode, the thresholds.
+	CompositeChecks            []CompositeCheckRef `json:",omitempty"`
+	CompositeMode              string              `json:",omitempty"`
+	CompositeCriticalThreshold int                 `json:",omitempty"`
+	CompositeWarningThreshold  int                 `json:",omitempty"`
+
+	// FlapDetection suppresses the status changes of the check while it is
+	// flapping, see the Flapping field of HealthCheck.
+	FlapDetection     bool `json:",omitempty"`
+	FlapLowThreshold  int  `json:",omitempty"`
+	FlapHighThreshold int  `json:",omitempty"`
+
 	// In Consul 0.7 and later, checks that are associated with a service
 	// may also contain this optional DeregisterCriticalServiceAfter field,
 	// which is a timeout in the same Go time format as Interval and TTL. If
@@ -0,0 +1,266 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultElectionSessionName is the Session Name we assign if none is
+	// provided
+	DefaultElectionSessionName = "Consul API Election"
+)
+
+var (
+	// ErrElectionLeader is returned if we campaign while already being the
+	// leader
+	ErrElectionLeader = fmt.Errorf("Already the election leader")
+
+	// ErrElectionNotLeader is returned if we resign from an election we are
+	// not the leader of
+	ErrElectionNotLeader = fmt.Errorf("Not the election leader")
+)
+
+// Leader describes the holder of an election.
+type Leader struct {
+	// Session is the session holding the election key.
+	Session string
+
+	// Value is the value associated with the election key by the leader.
+	Value []byte
+
+	// Term is the number of times the election key has been acquired, it
+	// is incremented by every new leader.
+	Term uint64
+
+	// FencingToken is the ModifyIndex of the election key when it was
+	// acquired by the leader. It is monotonically increasing across leaders,
+	// so resources protected by the election can reject requests carrying
+	// a lower token than the last one they saw.
+	FencingToken uint64
+
+	// Since is the time the leadership was first seen by this client. Consul
+	// doesn't record when a key is acquired, so observers that start after
+	// the leader was elected report the time they first saw it.
+	Since time.Time
+}
+
+// sameTerm returns whether both leaders are the same leadership, ignoring the
+// time it was seen.
+func (l *Leader) sameTerm(other *Leader) bool {
+	if l == nil || other == nil {
+		return l == nil && other == nil
+	}
+	return l.Session == other.Session && l.FencingToken == other.FencingToken
+}
+
+// Election is used to implement client-side leader election on top of a
+// Lock, with an observable leadership. Any client can observe who the leader
+// is, and the leader gets a fencing token it can attach to the requests it
+// makes while it believes it is the leader. Election keys are compatible
+// with the keys of a Lock, so an Election can observe a Lock.
+type Election struct {
+	c    *Client
+	opts *LockOptions
+	lock *Lock
+
+	leader *Leader
+	l      sync.Mutex
+}
+
+// Election returns a handle to an election which can be used to campaign,
+// resign and observe the leadership. The key used must have write
+// permissions to campaign, and read permissions to observe.
+func (c *Client) Election(key string) (*Election, error) {
+	opts := &LockOptions{
+		Key: key,
+	}
+	return c.ElectionOpts(opts)
+}
+
+// ElectionOpts returns a handle to an election which can be used to campaign,
+// resign and observe the leadership. The options are the ones of the
+// underlying Lock.
+func (c *Client) ElectionOpts(opts *LockOptions) (*Election, error) {
+	if opts.SessionName == "" {
+		opts.SessionName = DefaultElectionSessionName
+	}
+	l, err := c.LockOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+	e := &Election{
+		c:    c,
+		opts: opts,
+		lock: l,
+	}
+	return e, nil
+}
+
+// Campaign attempts to become the leader and blocks while doing so.
+// Providing a non-nil stopCh can be used to abort the campaign, in which
+// case a nil Leader is returned. On success it returns the leadership along
+// with a channel that is closed if the leadership is lost. As for Lock, the
+// leadership can be lost at any time and an application must be able to
+// handle it.
+func (e *Election) Campaign(stopCh <-chan struct{}) (*Leader, <-chan struct{}, error) {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if e.leader != nil {
+		return nil, nil, ErrElectionLeader
+	}
+
+	leaderCh, err := e.lock.Lock(stopCh)
+	if err != nil || leaderCh == nil {
+		return nil, nil, err
+	}
+
+	// Read back the key to get the index it was acquired at.
+	kv := e.c.KV()
+	q := QueryOptions{
+		RequireConsistent: true,
+		Namespace:         e.opts.Namespace,
+	}
+	pair, _, err := kv.Get(e.opts.Key, &q)
+	if err == nil && (pair == nil || pair.Session != e.lock.lockSession) {
+		err = fmt.Errorf("leadership lost during the campaign")
+	}
+	if err != nil {
+		// We don't know whether we hold the key, so make sure we don't.
+		e.lock.Unlock()
+		return nil, nil, fmt.Errorf("failed to read election: %v", err)
+	}
+
+	e.leader = &Leader{
+		Session:      pair.Session,
+		Value:        pair.Value,
+		Term:         pair.LockIndex,
+		FencingToken: pair.ModifyIndex,
+		Since:        time.Now(),
+	}
+	return e.leader, leaderCh, nil
+}
+
+// Resign gives up the leadership. It is an error to call this if we are not
+// the leader.
+func (e *Election) Resign() error {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if e.leader == nil {
+		return ErrElectionNotLeader
+	}
+	e.leader = nil
+
+	if err := e.lock.Unlock(); err != nil {
+		if err == ErrLockNotHeld {
+			return ErrElectionNotLeader
+		}
+		return err
+	}
+	return nil
+}
+
+// Destroy is used to cleanup the election key. It is not necessary to invoke.
+// It will fail with ErrLockInUse if there is a leader.
+func (e *Election) Destroy() error {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if e.leader != nil {
+		return ErrElectionLeader
+	}
+	return e.lock.Destroy()
+}
+
+// Leader returns the current leader of the election, or nil if there is no
+// leader.
+func (e *Election) Leader(q *QueryOptions) (*Leader, *QueryMeta, error) {
+	pair, qm, err := e.c.KV().Get(e.opts.Key, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pair != nil && pair.Flags != LockFlagValue {
+		return nil, nil, ErrLockConflict
+	}
+	if pair == nil || pair.Session == "" {
+		return nil, qm, nil
+	}
+	leader := &Leader{
+		Session:      pair.Session,
+		Value:        pair.Value,
+		Term:         pair.LockIndex,
+		FencingToken: pair.ModifyIndex,
+		Since:        time.Now(),
+	}
+	return leader, qm, nil
+}
+
+// Observe watches the leadership of the election using blocking queries. The
+// current leader is sent on the returned channel, followed by every change
+// of leadership. A nil Leader is sent when there is no leader. The channel is
+// closed once stopCh is closed. Errors are retried after
+// DefaultMonitorRetryTime, or the MonitorRetryTime of the options.
+func (e *Election) Observe(stopCh <-chan struct{}) <-chan *Leader {
+	ch := make(chan *Leader)
+	go e.observe(stopCh, ch)
+	return ch
+}
+
+func (e *Election) observe(stopCh <-chan struct{}, ch chan<- *Leader) {
+	defer close(ch)
+
+	// Cancel the blocking query as soon as we are stopped.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	q := (&QueryOptions{
+		Namespace: e.opts.Namespace,
+	}).WithContext(ctx)
+	var last *Leader
+	first := true
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		leader, qm, err := e.Leader(q)
+		if err != nil {
+			select {
+			case <-time.After(e.opts.MonitorRetryTime):
+				q.WaitIndex = 0
+				continue
+			case <-stopCh:
+				return
+			}
+		}
+		q.WaitIndex = qm.LastIndex
+
+		if !first && leader.sameTerm(last) {
+			continue
+		}
+		first = false
+		last = leader
+
+		select {
+		case ch <- leader:
+		case <-stopCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_ElectionCampaignResign(t *testing.T) {
+	t.Parallel()
+	c, s := makeClientWithoutConnect(t)
+	defer s.Stop()
+
+	election, err := c.ElectionOpts(&LockOptions{Key: "test/election", Value: []byte("node-1")})
+	require.NoError(t, err)
+
+	// Initial resign should fail
+	require.Equal(t, ErrElectionNotLeader, election.Resign())
+
+	leader, _, err := election.Leader(nil)
+	require.NoError(t, err)
+	require.Nil(t, leader)
+
+	leader, leaderCh, err := election.Campaign(nil)
+	require.NoError(t, err)
+	require.NotNil(t, leaderCh)
+	require.Equal(t, []byte("node-1"), leader.Value)
+	require.Equal(t, uint64(1), leader.Term)
+	require.NotZero(t, leader.FencingToken)
+
+	// Double campaign should fail
+	_, _, err = election.Campaign(nil)
+	require.Equal(t, ErrElectionLeader, err)
+
+	// The leadership is visible to others
+	other, err := c.Election("test/election")
+	require.NoError(t, err)
+	current, _, err := other.Leader(nil)
+	require.NoError(t, err)
+	require.Equal(t, leader.Session, current.Session)
+	require.Equal(t, leader.FencingToken, current.FencingToken)
+
+	require.NoError(t, election.Resign())
+	select {
+	case <-leaderCh:
+	case <-time.After(time.Second):
+		t.Fatalf("should lose leadership")
+	}
+
+	current, _, err = other.Leader(nil)
+	require.NoError(t, err)
+	require.Nil(t, current)
+}
+
+func TestAPI_ElectionFencingToken(t *testing.T) {
+	t.Parallel()
+	c, s := makeClientWithoutConnect(t)
+	defer s.Stop()
+
+	var last *Leader
+	for i := 0; i < 3; i++ {
+		election, err := c.Election("test/election")
+		require.NoError(t, err)
+
+		leader, _, err := election.Campaign(nil)
+		require.NoError(t, err)
+		if last != nil {
+			require.Greater(t, leader.FencingToken, last.FencingToken)
+			require.Equal(t, last.Term+1, leader.Term)
+			require.NotEqual(t, last.Session, leader.Session)
+		}
+		last = leader
+
+		require.NoError(t, election.Resign())
+	}
+}
+
+func TestAPI_ElectionObserve(t *testing.T) {
+	t.Parallel()
+	c, s := makeClientWithoutConnect(t)
+	defer s.Stop()
+
+	observer, err := c.Election("test/election")
+	require.NoError(t, err)
+
+	stopCh := make(chan struct{})
+	observeCh := observer.Observe(stopCh)
+
+	next := func() *Leader {
+		t.Helper()
+		select {
+		case leader, ok := <-observeCh:
+			require.True(t, ok)
+			return leader
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no leadership change observed")
+			return nil
+		}
+	}
+
+	// There is no leader yet
+	require.Nil(t, next())
+
+	candidate, err := c.ElectionOpts(&LockOptions{Key: "test/election", Value: []byte("node-1")})
+	require.NoError(t, err)
+	leader, _, err := candidate.Campaign(nil)
+	require.NoError(t, err)
+
+	observed := next()
+	require.NotNil(t, observed)
+	require.Equal(t, leader.Session, observed.Session)
+	require.Equal(t, leader.FencingToken, observed.FencingToken)
+	require.Equal(t, []byte("node-1"), observed.Value)
+	require.False(t, observed.Since.IsZero())
+
+	require.NoError(t, candidate.Resign())
+	require.Nil(t, next())
+
+	close(stopCh)
+	select {
+	case _, ok := <-observeCh:
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("observe channel should be closed")
+	}
+}
+
+func TestAPI_ElectionObserveLock(t *testing.T) {
+	t.Parallel()
+	c, s := makeClientWithoutConnect(t)
+	defer s.Stop()
+
+	lock, session := createTestLock(t, c, "test/lock")
+	defer session.Destroy(lock.opts.Session, nil)
+
+	_, err := lock.Lock(nil)
+	require.NoError(t, err)
+	defer lock.Unlock()
+
+	// An election can observe a lock
+	election, err := c.Election("test/lock")
+	require.NoError(t, err)
+	leader, _, err := election.Leader(nil)
+	require.NoError(t, err)
+	require.Equal(t, lock.opts.Session, leader.Session)
+
+	// But not a key used for something else
+	_, err = c.KV().Put(&KVPair{Key: "test/other", Value: []byte("value")}, nil)
+	require.NoError(t, err)
+	election, err = c.Election("test/other")
+	require.NoError(t, err)
+	_, _, err = election.Leader(nil)
+	require.Equal(t, ErrLockConflict, err)
+}
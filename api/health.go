@@ -50,6 +50,7 @@ type HealthCheck struct {
 	Partition   string `json:",omitempty"`
 	ExposedPort int
 	PeerName    string `json:",omitempty"`
+	Flapping    bool   `json:",omitempty"`
 
 	Definition HealthCheckDefinition
 
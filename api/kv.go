@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // KVPair is used to represent a single K/V entry
@@ -44,6 +45,15 @@ type KVPair struct {
 	// session ID.
 	Session string
 
+	// TTL is the time to live of the key, as a duration string such as "30s".
+	// When set on a write, the servers delete the key once it elapses without
+	// another write. Writes without a TTL clear the expiration of the key.
+	TTL string `json:",omitempty"`
+
+	// Expires is the time after which the key is deleted, it is nil for keys
+	// without a TTL. This is a read-only field.
+	Expires *time.Time `json:",omitempty"`
+
 	// Namespace is the namespace the KVPair is associated with
 	// Namespacing is a Consul Enterprise feature.
 	Namespace string `json:",omitempty"`
@@ -56,6 +66,23 @@ type KVPair struct {
 // KVPairs is a list of KVPair objects
 type KVPairs []*KVPair
 
+// KVPairRevision is a revision of a K/V entry kept in its history
+type KVPairRevision struct {
+	KVPair
+
+	// Deleted is set when the key was deleted at the ModifyIndex of the
+	// revision. Only the Key and the indexes are set in that case.
+	Deleted bool `json:",omitempty"`
+
+	// Truncated is set on the oldest revision of the history once older
+	// revisions of the key have been dropped, reads at an index before it
+	// fail.
+	Truncated bool `json:",omitempty"`
+}
+
+// KVPairRevisions is a list of KVPairRevision objects
+type KVPairRevisions []*KVPairRevision
+
 // KV is used to manipulate the K/V API
 type KV struct {
 	c *Client
@@ -88,6 +115,50 @@ func (k *KV) Get(key string, q *QueryOptions) (*KVPair, *QueryMeta, error) {
 	return nil, qm, nil
 }
 
+// GetAtIndex is used to lookup a single key as it was at the given Raft
+// index, using its history. The returned pointer to the KVPair will be nil
+// if the key did not exist at that index. An error is returned if the
+// revision at that index is no longer part of the history.
+func (k *KV) GetAtIndex(key string, index uint64, q *QueryOptions) (*KVPair, *QueryMeta, error) {
+	params := map[string]string{"at-index": strconv.FormatUint(index, 10)}
+	resp, qm, err := k.getInternal(key, params, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp == nil {
+		return nil, qm, nil
+	}
+	defer closeResponseBody(resp)
+
+	var entries []*KVPair
+	if err := decodeBody(resp, &entries); err != nil {
+		return nil, nil, err
+	}
+	if len(entries) > 0 {
+		return entries[0], qm, nil
+	}
+	return nil, qm, nil
+}
+
+// Revisions is used to lookup the history of a key, newest revision first.
+// The history only holds revisions if it is enabled on the servers.
+func (k *KV) Revisions(key string, q *QueryOptions) (KVPairRevisions, *QueryMeta, error) {
+	resp, qm, err := k.getInternal(key, map[string]string{"revisions": ""}, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp == nil {
+		return nil, qm, nil
+	}
+	defer closeResponseBody(resp)
+
+	var entries []*KVPairRevision
+	if err := decodeBody(resp, &entries); err != nil {
+		return nil, nil, err
+	}
+	return entries, qm, nil
+}
+
 // List is used to lookup all keys under a prefix
 func (k *KV) List(prefix string, q *QueryOptions) (KVPairs, *QueryMeta, error) {
 	resp, qm, err := k.getInternal(prefix, map[string]string{"recurse": ""}, q)
@@ -164,6 +235,9 @@ func (k *KV) Put(p *KVPair, q *WriteOptions) (*WriteMeta, error) {
 	if p.Flags != 0 {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
+	if p.TTL != "" {
+		params["ttl"] = p.TTL
+	}
 	_, wm, err := k.put(p.Key, params, p.Value, q)
 	return wm, err
 }
@@ -177,6 +251,9 @@ func (k *KV) CAS(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
 	params["cas"] = strconv.FormatUint(p.ModifyIndex, 10)
+	if p.TTL != "" {
+		params["ttl"] = p.TTL
+	}
 	return k.put(p.Key, params, p.Value, q)
 }
 
@@ -189,6 +266,9 @@ func (k *KV) Acquire(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
 	params["acquire"] = p.Session
+	if p.TTL != "" {
+		params["ttl"] = p.TTL
+	}
 	return k.put(p.Key, params, p.Value, q)
 }
 
@@ -201,6 +281,9 @@ func (k *KV) Release(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
 	params["release"] = p.Session
+	if p.TTL != "" {
+		params["ttl"] = p.TTL
+	}
 	return k.put(p.Key, params, p.Value, q)
 }
 
@@ -11,6 +11,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 )
 
 func TestAPI_ClientPutGetDelete(t *testing.T) {
@@ -79,6 +82,89 @@ func TestAPI_ClientPutGetDelete(t *testing.T) {
 	}
 }
 
+func TestAPI_ClientPutTTL(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	kv := c.KV()
+
+	s.WaitForSerfCheck(t)
+	key := testKey()
+	_, err := kv.Put(&KVPair{Key: key, Value: []byte("test"), TTL: "500ms"}, nil)
+	require.NoError(t, err)
+
+	pair, _, err := kv.Get(key, nil)
+	require.NoError(t, err)
+	require.NotNil(t, pair)
+	require.Equal(t, "500ms", pair.TTL)
+	require.NotNil(t, pair.Expires)
+
+	// An invalid TTL is rejected
+	_, err = kv.Put(&KVPair{Key: key, TTL: "soon"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid TTL")
+
+	// The key is deleted once the TTL elapsed
+	retry.Run(t, func(r *retry.R) {
+		pair, _, err := kv.Get(key, nil)
+		require.NoError(r, err)
+		require.Nil(r, pair)
+	})
+}
+
+func TestAPI_ClientRevisions(t *testing.T) {
+	t.Parallel()
+	c, s := makeClientWithConfig(t, nil, func(conf *testutil.TestServerConfig) {
+		conf.Args = []string{"-hcl", "kv_history_max_revisions = 10"}
+	})
+	defer s.Stop()
+
+	kv := c.KV()
+
+	s.WaitForSerfCheck(t)
+	key := testKey()
+
+	// No history before the key is written
+	revs, _, err := kv.Revisions(key, nil)
+	require.NoError(t, err)
+	require.Empty(t, revs)
+
+	var indexes []uint64
+	for _, value := range []string{"one", "two"} {
+		_, err := kv.Put(&KVPair{Key: key, Value: []byte(value)}, nil)
+		require.NoError(t, err)
+		pair, _, err := kv.Get(key, nil)
+		require.NoError(t, err)
+		indexes = append(indexes, pair.ModifyIndex)
+	}
+	_, err = kv.Delete(key, nil)
+	require.NoError(t, err)
+
+	revs, meta, err := kv.Revisions(key, nil)
+	require.NoError(t, err)
+	require.NotZero(t, meta.LastIndex)
+	require.Len(t, revs, 3)
+	require.True(t, revs[0].Deleted)
+	require.Equal(t, key, revs[0].Key)
+	require.Equal(t, []byte("two"), revs[1].Value)
+	require.Equal(t, indexes[1], revs[1].ModifyIndex)
+	require.Equal(t, []byte("one"), revs[2].Value)
+
+	// Read the key as it was at each index
+	pair, _, err := kv.GetAtIndex(key, indexes[0], nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("one"), pair.Value)
+
+	pair, _, err = kv.GetAtIndex(key, indexes[0]-1, nil)
+	require.NoError(t, err)
+	require.Nil(t, pair)
+
+	pair, _, err = kv.GetAtIndex(key, revs[0].ModifyIndex, nil)
+	require.NoError(t, err)
+	require.Nil(t, pair)
+}
+
 func TestAPI_ClientList_DeleteRecurse(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
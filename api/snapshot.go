@@ -4,6 +4,8 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 )
 
@@ -55,3 +57,67 @@ func (s *Snapshot) Restore(q *WriteOptions, in io.Reader) error {
 	}
 	return nil
 }
+
+// SnapshotRestoreFilter selects the records replayed by RestoreFiltered.
+type SnapshotRestoreFilter struct {
+	// KVPrefixes restores the KV entries whose keys start with any of the
+	// given prefixes. An empty prefix matches every key.
+	KVPrefixes []string
+
+	// ConfigEntries restores the config entries matching any of the given
+	// selectors, which take the form "<kind>" or "<kind>/<name>". A selector of
+	// "*" matches every config entry.
+	ConfigEntries []string
+
+	// ACLs restores ACL tokens and policies.
+	ACLs bool
+}
+
+// SnapshotRestoreSummary reports the number of records replayed by
+// RestoreFiltered.
+type SnapshotRestoreSummary struct {
+	KV            int
+	ConfigEntries int
+	ACLTokens     int
+	ACLPolicies   int
+}
+
+// RestoreFiltered streams in an existing snapshot and replays only the records
+// selected by the filter, using the same writes as the KV, config entry and
+// ACL endpoints. Unlike Restore, the rest of the cluster's state is left
+// untouched.
+func (s *Snapshot) RestoreFiltered(q *WriteOptions, in io.Reader, filter *SnapshotRestoreFilter) (*SnapshotRestoreSummary, error) {
+	// Without any selectors the server would perform a full restore.
+	if filter == nil || (len(filter.KVPrefixes) == 0 && len(filter.ConfigEntries) == 0 && !filter.ACLs) {
+		return nil, fmt.Errorf("filter must select at least one kind of record")
+	}
+
+	r := s.c.newRequest("PUT", "/v1/snapshot")
+	r.body = in
+	r.header.Set("Content-Type", "application/octet-stream")
+	r.setWriteOptions(q)
+	for _, prefix := range filter.KVPrefixes {
+		r.params.Add("kv-prefix", prefix)
+	}
+	for _, sel := range filter.ConfigEntries {
+		r.params.Add("config-entry", sel)
+	}
+	if filter.ACLs {
+		r.params.Set("acls", "")
+	}
+
+	_, resp, err := s.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	var summary SnapshotRestoreSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
@@ -5,6 +5,7 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,6 +28,7 @@ type TxnOp struct {
 	Node    *NodeTxnOp
 	Service *ServiceTxnOp
 	Check   *CheckTxnOp
+	Session *SessionTxnOp
 }
 
 // TxnOps is a list of transaction operations.
@@ -58,6 +60,23 @@ type TxnResponse struct {
 	Errors  TxnErrors
 }
 
+const (
+	// DefaultTxnMaxOps is the default max number of operations in a
+	// transaction, set by the limits.txn_max_ops option of the agents.
+	DefaultTxnMaxOps = 128
+
+	// DefaultTxnMaxReqLen is the default max size in bytes of a transaction
+	// request, set by the limits.txn_max_req_len option of the agents.
+	DefaultTxnMaxReqLen = 512 * 1024
+)
+
+// TxnLimits are the limits the agent enforces on transactions. A zero value
+// uses the default limit of the agents.
+type TxnLimits struct {
+	MaxOps    int
+	MaxReqLen int
+}
+
 // KVOp constants give possible operations available in a transaction.
 type KVOp string
 
@@ -75,6 +94,11 @@ const (
 	KVCheckSession   KVOp = "check-session"
 	KVCheckIndex     KVOp = "check-index"
 	KVCheckNotExists KVOp = "check-not-exists"
+
+	// KVCheckValue checks that the value of a key is the given value, and
+	// KVCheckValuePrefix that it starts with the given value.
+	KVCheckValue       KVOp = "check-value"
+	KVCheckValuePrefix KVOp = "check-value-prefix"
 )
 
 // KVTxnOp defines a single operation inside a transaction.
@@ -87,6 +111,11 @@ type KVTxnOp struct {
 	Session   string
 	Namespace string `json:",omitempty"`
 	Partition string `json:",omitempty"`
+
+	// TTL is the time to live of the key, as a duration string such as
+	// "30s". Like other writes, a set without a TTL clears the expiration
+	// of the key.
+	TTL string `json:",omitempty"`
 }
 
 // KVTxnOps defines a set of operations to be performed inside a single
@@ -103,13 +132,20 @@ type KVTxnResponse struct {
 type SessionOp string
 
 const (
-	SessionDelete SessionOp = "delete"
+	SessionDelete     SessionOp = "delete"
+	SessionCheckValid SessionOp = "check-valid"
 )
 
 // SessionTxnOp defines a single operation inside a transaction.
 type SessionTxnOp struct {
-	Verb    SessionOp
-	Session Session
+	Verb SessionOp
+
+	// Session is unused, it is kept for compatibility. Use Entry instead.
+	Session Session `json:"-"`
+
+	// Entry is the session the operation applies to, only its ID and
+	// Namespace are used.
+	Entry SessionEntry `json:"Session"`
 }
 
 // NodeOp constants give possible operations available in a transaction.
@@ -121,6 +157,10 @@ const (
 	NodeCAS       NodeOp = "cas"
 	NodeDelete    NodeOp = "delete"
 	NodeDeleteCAS NodeOp = "delete-cas"
+
+	// NodeCheckExists checks that a node, looked up by its ID or name,
+	// exists.
+	NodeCheckExists NodeOp = "check-exists"
 )
 
 // NodeTxnOp defines a single operation inside a transaction.
@@ -138,6 +178,11 @@ const (
 	ServiceCAS       ServiceOp = "cas"
 	ServiceDelete    ServiceOp = "delete"
 	ServiceDeleteCAS ServiceOp = "delete-cas"
+
+	// ServiceCheckHealthy checks that a service has instances and that all
+	// their checks are passing. The instances can be narrowed down with the
+	// node and the service ID.
+	ServiceCheckHealthy ServiceOp = "check-healthy"
 )
 
 // ServiceTxnOp defines a single operation inside a transaction.
@@ -217,6 +262,33 @@ func (t *Txn) Txn(txn TxnOps, q *QueryOptions) (bool, *TxnResponse, *QueryMeta,
 	return t.c.txn(txn, q)
 }
 
+// Validate checks that the transaction fits in the given limits, so that a
+// transaction too large for the agent can be detected before sending any
+// part of it. Nil limits use the defaults of the agents.
+func (t *Txn) Validate(txn TxnOps, limits *TxnLimits) error {
+	maxOps, maxReqLen := DefaultTxnMaxOps, DefaultTxnMaxReqLen
+	if limits != nil {
+		if limits.MaxOps > 0 {
+			maxOps = limits.MaxOps
+		}
+		if limits.MaxReqLen > 0 {
+			maxReqLen = limits.MaxReqLen
+		}
+	}
+
+	if len(txn) > maxOps {
+		return fmt.Errorf("Transaction contains too many operations (%d > %d)", len(txn), maxOps)
+	}
+	body, err := json.Marshal(txn)
+	if err != nil {
+		return err
+	}
+	if size := len(body); size > maxReqLen {
+		return fmt.Errorf("Transaction is too large (%d bytes > %d bytes)", size, maxReqLen)
+	}
+	return nil
+}
+
 func (c *Client) txn(txn TxnOps, q *QueryOptions) (bool, *TxnResponse, *QueryMeta, error) {
 	r := c.newRequest("PUT", "/v1/txn")
 	r.setQueryOptions(q)
@@ -4,10 +4,12 @@
 package api
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 
 	"github.com/hashicorp/go-uuid"
@@ -389,3 +391,110 @@ func TestAPI_ClientTxn(t *testing.T) {
 		t.Fatalf("unexpected value: %#v", meta)
 	}
 }
+
+func TestAPI_ClientTxn_Validate(t *testing.T) {
+	t.Parallel()
+	c, s := makeClientWithConfig(t, nil, func(conf *testutil.TestServerConfig) {
+		conf.Args = []string{"-hcl", "limits { txn_max_ops = 300 }"}
+	})
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+	txn := c.Txn()
+
+	var ops TxnOps
+	for i := 0; i < 200; i++ {
+		ops = append(ops, &TxnOp{
+			KV: &KVTxnOp{
+				Verb:  KVSet,
+				Key:   fmt.Sprintf("key%d", i),
+				Value: []byte("test"),
+			},
+		})
+	}
+
+	// The transaction exceeds the default limits of the agents.
+	err := txn.Validate(ops, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "too many operations (200 > 128)")
+
+	err = txn.Validate(ops, &TxnLimits{MaxOps: 300, MaxReqLen: 1024})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Transaction is too large")
+
+	limits := &TxnLimits{MaxOps: 300}
+	require.NoError(t, txn.Validate(ops, limits))
+
+	ok, resp, _, err := txn.Txn(ops, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, resp.Results, 200)
+}
+
+func TestAPI_ClientTxn_CheckOps(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+	txn := c.Txn()
+
+	_, err := c.KV().Put(&KVPair{Key: "rollout", Value: []byte("stage=1")}, nil)
+	require.NoError(t, err)
+
+	id, _, err := c.Session().CreateNoChecks(nil, nil)
+	require.NoError(t, err)
+
+	// Only flip the flag if the gates pass.
+	ops := TxnOps{
+		&TxnOp{
+			KV: &KVTxnOp{
+				Verb:  KVCheckValuePrefix,
+				Key:   "rollout",
+				Value: []byte("stage="),
+			},
+		},
+		&TxnOp{
+			Node: &NodeTxnOp{
+				Verb: NodeCheckExists,
+				Node: Node{Node: s.Config.NodeName},
+			},
+		},
+		&TxnOp{
+			Service: &ServiceTxnOp{
+				Verb:    ServiceCheckHealthy,
+				Service: AgentService{Service: "consul"},
+			},
+		},
+		&TxnOp{
+			Session: &SessionTxnOp{
+				Verb:  SessionCheckValid,
+				Entry: SessionEntry{ID: id},
+			},
+		},
+		&TxnOp{
+			KV: &KVTxnOp{
+				Verb:  KVSet,
+				Key:   "rollout",
+				Value: []byte("stage=2"),
+			},
+		},
+	}
+	ok, resp, _, err := txn.Txn(ops, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Empty(t, resp.Errors)
+
+	// The value check now fails and the transaction is rolled back.
+	ops[0].KV.Verb = KVCheckValue
+	ops[4].KV.Value = []byte("stage=3")
+	ok, resp, _, err = txn.Txn(ops, nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, 0, resp.Errors[0].OpIndex)
+
+	pair, _, err := c.KV().Get("rollout", nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("stage=2"), pair.Value)
+}
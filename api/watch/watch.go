@@ -58,6 +58,16 @@ type HttpHandlerConfig struct {
 	TLSSkipVerify bool                `mapstructure:"tls_skip_verify"`
 }
 
+// FileHandlerConfig configures a handler appending the results of a watch to
+// a rotating local file, as one JSON line per result.
+type FileHandlerConfig struct {
+	Path              string        `mapstructure:"path"`
+	RotateDuration    time.Duration `mapstructure:"-"`
+	RotateDurationRaw string        `mapstructure:"rotate_duration"`
+	RotateBytes       int           `mapstructure:"rotate_bytes"`
+	RotateMaxFiles    int           `mapstructure:"rotate_max_files"`
+}
+
 // BlockingParamVal is an interface representing the common operations needed for
 // different styles of blocking. It's used to abstract the core watch plan from
 // whether we are performing index-based or hash-based blocking.
@@ -175,6 +185,17 @@ func ParseExempt(params map[string]interface{}, exempt []string) (*Plan, error)
 		plan.Exempt["http_handler_config"] = config
 		delete(params, "http_handler_config")
 
+	case "file":
+		if _, ok := params["file_handler_config"]; !ok {
+			return nil, fmt.Errorf("Handler type 'file' requires 'file_handler_config' to be set")
+		}
+		config, err := parseFileHandlerConfig(params["file_handler_config"])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse 'file_handler_config': %v", err)
+		}
+		plan.Exempt["file_handler_config"] = config
+		delete(params, "file_handler_config")
+
 	case "script":
 		// Let the caller check for configuration in exempt parameters
 	}
@@ -294,3 +315,35 @@ func parseHttpHandlerConfig(configParams interface{}) (*HttpHandlerConfig, error
 
 	return &config, nil
 }
+
+// Parse the 'file_handler_config' parameters
+func parseFileHandlerConfig(configParams interface{}) (*FileHandlerConfig, error) {
+	// HCL decodes blocks as a list of maps.
+	if list, ok := configParams.([]map[string]interface{}); ok && len(list) == 1 {
+		configParams = list[0]
+	}
+
+	var config FileHandlerConfig
+	if err := mapstructure.Decode(configParams, &config); err != nil {
+		return nil, err
+	}
+
+	if config.Path == "" {
+		return nil, fmt.Errorf("Requires 'path' to be set")
+	}
+	if config.RotateBytes < 0 {
+		return nil, fmt.Errorf("'rotate_bytes' cannot be negative")
+	}
+	if config.RotateDurationRaw != "" {
+		duration, err := time.ParseDuration(config.RotateDurationRaw)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse rotate_duration: %v", err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("'rotate_duration' must be positive")
+		}
+		config.RotateDuration = duration
+	}
+
+	return &config, nil
+}
@@ -138,3 +138,14 @@ func newDecodeConfigEntry(raw map[string]interface{}) (api.ConfigEntry, error) {
 
 	return entry, nil
 }
+
+// DecodeHCLOrJSON decodes the given HCL or JSON document into a generic map.
+// Callers are responsible for interpreting the result, which for HCL input
+// will contain blocks decoded as slices of maps.
+func DecodeHCLOrJSON(data string) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if err := hclDecode(&raw, data); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
@@ -4,9 +4,9 @@
 package exp
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
@@ -21,14 +21,18 @@ func New(ui cli.Ui) *cmd {
 }
 
 type cmd struct {
-	UI    cli.Ui
-	flags *flag.FlagSet
-	http  *flags.HTTPFlags
-	help  string
+	UI     cli.Ui
+	flags  *flag.FlagSet
+	http   *flags.HTTPFlags
+	help   string
+	format string
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.format, "format", impexp.FormatJSON,
+		fmt.Sprintf("Output format {%s}. The tree formats nest the keys by their "+
+			"path segments and write the values as plain text.", strings.Join(impexp.Formats, "|")))
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -61,6 +65,11 @@ func (c *cmd) Run(args []string) int {
 		key = key[1:]
 	}
 
+	if err := impexp.ValidFormat(c.format); err != nil {
+		c.UI.Error(fmt.Sprintf("Error! %s", err))
+		return 1
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -76,18 +85,13 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	exported := make([]*impexp.Entry, len(pairs))
-	for i, pair := range pairs {
-		exported[i] = impexp.ToEntry(pair)
-	}
-
-	marshaled, err := json.MarshalIndent(exported, "", "\t")
+	marshaled, err := impexp.Encode(c.format, pairs)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error exporting KV data: %s", err))
 		return 1
 	}
 
-	c.UI.Info(string(marshaled))
+	c.UI.Info(strings.TrimSuffix(string(marshaled), "\n"))
 
 	return 0
 }
@@ -101,9 +105,9 @@ func (c *cmd) Help() string {
 }
 
 const (
-	synopsis = "Exports a tree from the KV store as JSON"
+	synopsis = "Exports a tree from the KV store as JSON, YAML or HCL"
 	help     = `
-Usage: consul kv export [KEY_OR_PREFIX]
+Usage: consul kv export [options] [KEY_OR_PREFIX]
 
   Retrieves key-value pairs for the given prefix from Consul's key-value store,
   and writes a JSON representation to stdout. This can be used with the command
@@ -111,6 +115,14 @@ Usage: consul kv export [KEY_OR_PREFIX]
 
       $ consul kv export vault
 
+  The "-format" flag selects a nested document with plain-text values instead,
+  which is easier to review and to keep in version control:
+
+      $ consul kv export -format=yaml vault > vault.yaml
+
+  The tree formats can't represent binary values, flags, or a key which is also
+  the parent of other keys, the default "json" format must be used for those.
+
   For a full list of options and examples, please see the Consul documentation.
 `
 )
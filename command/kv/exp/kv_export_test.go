@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/kv/impexp"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKVExportCommand_noTabs(t *testing.T) {
@@ -76,3 +77,53 @@ func TestKVExportCommand(t *testing.T) {
 		}
 	}
 }
+
+func TestKVExportCommand_Format(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	keys := map[string]string{
+		"foo/a":   "a",
+		"foo/b/c": "c",
+		"bar":     "d",
+	}
+	for k, v := range keys {
+		pair := &api.KVPair{Key: k, Value: []byte(v)}
+		if _, err := client.KV().Put(pair, nil); err != nil {
+			t.Fatalf("err: %#v", err)
+		}
+	}
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-format=yaml", "foo"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Equal(t, "foo:\n    a: a\n    b:\n        c: c\n", ui.OutputWriter.String())
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+	code = c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-format=hcl", "foo"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Equal(t, "\"foo\" {\n  \"a\" = \"a\"\n  \"b\" {\n    \"c\" = \"c\"\n  }\n}\n", ui.OutputWriter.String())
+
+	// Keys which are also the parent of other keys require the JSON format.
+	_, err := client.KV().Put(&api.KVPair{Key: "foo/b", Value: []byte("b")}, nil)
+	require.NoError(t, err)
+	ui = cli.NewMockUi()
+	c = New(ui)
+	code = c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-format=tree-json", "foo"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "use the json format instead")
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+	code = c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-format=toml", "foo"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Invalid format")
+}
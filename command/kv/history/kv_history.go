@@ -0,0 +1,223 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package history
+
+import (
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI           cli.Ui
+	flags        *flag.FlagSet
+	http         *flags.HTTPFlags
+	help         string
+	base64encode bool
+	detailed     bool
+	atIndex      uint64
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.base64encode, "base64", false,
+		"Base64 encode the values. The default value is false.")
+	c.flags.BoolVar(&c.detailed, "detailed", false,
+		"Provide all the metadata of each revision of the key in addition to "+
+			"its value. The default value is false.")
+	c.flags.Uint64Var(&c.atIndex, "at-index", 0,
+		"Only output the value the key held at the given Raft index instead of "+
+			"listing its revisions.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	key := ""
+
+	// Check for arg validation
+	args = c.flags.Args()
+	switch len(args) {
+	case 0:
+		key = ""
+	case 1:
+		key = args[0]
+	default:
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 1, got %d)", len(args)))
+		return 1
+	}
+
+	// Pairs cannot start with a /, strip it like "consul kv get" does.
+	if len(key) > 0 && key[0] == '/' {
+		key = key[1:]
+	}
+	if key == "" {
+		c.UI.Error("Error! Missing KEY argument")
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	q := &api.QueryOptions{
+		AllowStale: c.http.Stale(),
+	}
+
+	if c.atIndex != 0 {
+		pair, _, err := client.KV().GetAtIndex(key, c.atIndex, q)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error querying Consul agent: %s", err))
+			return 1
+		}
+		if pair == nil {
+			c.UI.Error(fmt.Sprintf("Error! No revision of %s exists at index %d", key, c.atIndex))
+			return 1
+		}
+
+		if c.detailed {
+			var b bytes.Buffer
+			if err := prettyRevision(&b, &api.KVPairRevision{KVPair: *pair}, c.base64encode); err != nil {
+				c.UI.Error(fmt.Sprintf("Error rendering KV pair: %s", err))
+				return 1
+			}
+			c.UI.Info(b.String())
+			return 0
+		}
+		c.UI.Info(c.formatValue(pair.Value))
+		return 0
+	}
+
+	revs, _, err := client.KV().Revisions(key, q)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error querying Consul agent: %s", err))
+		return 1
+	}
+	if len(revs) == 0 {
+		c.UI.Error(fmt.Sprintf("Error! No history exists for: %s", key))
+		return 1
+	}
+
+	if c.detailed {
+		for i, rev := range revs {
+			var b bytes.Buffer
+			if err := prettyRevision(&b, rev, c.base64encode); err != nil {
+				c.UI.Error(fmt.Sprintf("Error rendering KV pair: %s", err))
+				return 1
+			}
+			c.UI.Info(b.String())
+
+			if i < len(revs)-1 {
+				c.UI.Info("")
+			}
+		}
+		return 0
+	}
+
+	result := []string{"ModifyIndex\x1fOperation\x1fValue"}
+	for _, rev := range revs {
+		if rev.Deleted {
+			result = append(result, fmt.Sprintf("%d\x1fdelete\x1f", rev.ModifyIndex))
+			continue
+		}
+		result = append(result, fmt.Sprintf("%d\x1fset\x1f%s", rev.ModifyIndex, c.formatValue(rev.Value)))
+	}
+	c.UI.Output(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
+func (c *cmd) formatValue(value []byte) string {
+	if c.base64encode {
+		return base64.StdEncoding.EncodeToString(value)
+	}
+	return string(value)
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+func prettyRevision(w io.Writer, rev *api.KVPairRevision, base64EncodeValue bool) error {
+	tw := tabwriter.NewWriter(w, 0, 2, 6, ' ', 0)
+	fmt.Fprintf(tw, "CreateIndex\t%d\n", rev.CreateIndex)
+	fmt.Fprintf(tw, "Deleted\t%t\n", rev.Deleted)
+	fmt.Fprintf(tw, "Flags\t%d\n", rev.Flags)
+	fmt.Fprintf(tw, "Key\t%s\n", rev.Key)
+	fmt.Fprintf(tw, "LockIndex\t%d\n", rev.LockIndex)
+	fmt.Fprintf(tw, "ModifyIndex\t%d\n", rev.ModifyIndex)
+	if rev.Session == "" {
+		fmt.Fprint(tw, "Session\t-\n")
+	} else {
+		fmt.Fprintf(tw, "Session\t%s\n", rev.Session)
+	}
+	if rev.Partition != "" {
+		fmt.Fprintf(tw, "Partition\t%s\n", rev.Partition)
+	}
+	if rev.Namespace != "" {
+		fmt.Fprintf(tw, "Namespace\t%s\n", rev.Namespace)
+	}
+	if base64EncodeValue {
+		fmt.Fprintf(tw, "Value\t%s", base64.StdEncoding.EncodeToString(rev.Value))
+	} else {
+		fmt.Fprintf(tw, "Value\t%s", rev.Value)
+	}
+	return tw.Flush()
+}
+
+const (
+	synopsis = "Lists the previous values of a key in the KV store"
+	help     = `
+Usage: consul kv history [options] KEY
+
+  Lists the revisions of the given key kept in the history of Consul's
+  key-value store, newest first. The history is only kept when the servers
+  are configured with "kv_history_max_revisions", and the revisions older than
+  "kv_history_max_age" are dropped.
+
+  To list the values the key named "foo" held and the index of each change:
+
+      $ consul kv history foo
+
+  To view all the metadata of each revision, specify the "-detailed" flag:
+
+      $ consul kv history -detailed foo
+
+  To retrieve the value the key held at a given Raft index, for example to
+  roll back a bad change:
+
+      $ consul kv history -at-index 1234 foo
+
+  For a full list of options and examples, please see the Consul documentation.
+`
+)
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package history
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+)
+
+func TestKVHistoryCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestKVHistoryCommand_Validation(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	cases := map[string]struct {
+		args   []string
+		output string
+	}{
+		"no key": {
+			[]string{},
+			"Missing KEY argument",
+		},
+		"extra args": {
+			[]string{"foo", "bar", "baz"},
+			"Too many arguments",
+		},
+	}
+
+	for name, tc := range cases {
+		// Ensure our buffer is always clear
+		if ui.ErrorWriter != nil {
+			ui.ErrorWriter.Reset()
+		}
+		if ui.OutputWriter != nil {
+			ui.OutputWriter.Reset()
+		}
+
+		code := c.Run(tc.args)
+		if code == 0 {
+			t.Errorf("%s: expected non-zero exit", name)
+		}
+
+		output := ui.ErrorWriter.String()
+		if !strings.Contains(output, tc.output) {
+			t.Errorf("%s: expected %q to contain %q", name, output, tc.output)
+		}
+	}
+}
+
+func TestKVHistoryCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, `kv_history_max_revisions = 10`)
+	defer a.Shutdown()
+	client := a.Client()
+
+	var indexes []uint64
+	for _, value := range []string{"bar", "baz"} {
+		_, err := client.KV().Put(&api.KVPair{Key: "foo", Value: []byte(value)}, nil)
+		require.NoError(t, err)
+		pair, _, err := client.KV().Get("foo", nil)
+		require.NoError(t, err)
+		indexes = append(indexes, pair.ModifyIndex)
+	}
+	_, err := client.KV().Delete("foo", nil)
+	require.NoError(t, err)
+
+	t.Run("list", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), "foo"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+		require.Len(t, lines, 4)
+		require.Regexp(t, `^ModifyIndex\s+Operation\s+Value$`, lines[0])
+		require.Regexp(t, `^\d+\s+delete$`, strings.TrimSpace(lines[1]))
+		require.Regexp(t, fmt.Sprintf(`^%d\s+set\s+baz$`, indexes[1]), lines[2])
+		require.Regexp(t, fmt.Sprintf(`^%d\s+set\s+bar$`, indexes[0]), lines[3])
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), "-detailed", "foo"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "Deleted")
+		require.Contains(t, output, fmt.Sprintf("ModifyIndex      %d", indexes[0]))
+		require.Contains(t, output, "Value            baz")
+	})
+
+	t.Run("at index", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), "-base64", fmt.Sprintf("-at-index=%d", indexes[0]), "foo"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Equal(t, base64.StdEncoding.EncodeToString([]byte("bar"))+"\n", ui.OutputWriter.String())
+	})
+
+	t.Run("at index missing", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), fmt.Sprintf("-at-index=%d", indexes[0]-1), "foo"})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "No revision of foo exists")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), "not-a-real-key"})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "No history exists")
+	})
+}
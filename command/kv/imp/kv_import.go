@@ -5,14 +5,14 @@ package imp
 
 import (
 	"bytes"
-	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
@@ -32,6 +32,13 @@ type cmd struct {
 	http   *flags.HTTPFlags
 	help   string
 	prefix string
+	format string
+	dryRun bool
+	prune  bool
+
+	atomic       bool
+	txnMaxOps    int
+	txnMaxReqLen int
 
 	// testStdin is the input for testing.
 	testStdin io.Reader
@@ -40,6 +47,24 @@ type cmd struct {
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.StringVar(&c.prefix, "prefix", "", "Key prefix for imported data")
+	c.flags.StringVar(&c.format, "format", impexp.FormatJSON,
+		fmt.Sprintf("Format of the imported data {%s}.", strings.Join(impexp.Formats, "|")))
+	c.flags.BoolVar(&c.dryRun, "dry-run", false,
+		"Print the keys that would be created, updated or deleted without "+
+			"modifying the KV store. The default value is false.")
+	c.flags.BoolVar(&c.prune, "prune", false,
+		"Delete the keys under the prefix that are absent from the imported "+
+			"data. This requires -prefix. The default value is false.")
+	c.flags.BoolVar(&c.atomic, "atomic", false,
+		"Apply all the changes in a single transaction, which fails if any "+
+			"of the keys was modified concurrently or if the changes exceed "+
+			"the transaction limits. The default value is false.")
+	c.flags.IntVar(&c.txnMaxOps, "txn-max-ops", api.DefaultTxnMaxOps,
+		"Max number of operations in a transaction with -atomic. This must "+
+			"match the limits.txn_max_ops option of the agent.")
+	c.flags.IntVar(&c.txnMaxReqLen, "txn-max-req-len", api.DefaultTxnMaxReqLen,
+		"Max size in bytes of a transaction with -atomic. This must match the "+
+			"limits.txn_max_req_len option of the agent.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -59,6 +84,18 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error! %s", err))
 		return 1
 	}
+	if err := impexp.ValidFormat(c.format); err != nil {
+		c.UI.Error(fmt.Sprintf("Error! %s", err))
+		return 1
+	}
+	if c.prune && c.prefix == "" {
+		c.UI.Error("Error! -prune requires -prefix")
+		return 1
+	}
+	if c.txnMaxOps <= 0 || c.txnMaxReqLen <= 0 {
+		c.UI.Error("Error! -txn-max-ops and -txn-max-req-len must be greater than zero")
+		return 1
+	}
 
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
@@ -67,42 +104,206 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	var entries []*impexp.Entry
-	if err := json.Unmarshal([]byte(data), &entries); err != nil {
-		c.UI.Error(fmt.Sprintf("Cannot unmarshal data: %s", err))
+	pairs, err := impexp.Decode(c.format, []byte(data))
+	if err != nil {
+		c.UI.Error(err.Error())
 		return 1
 	}
+	for _, pair := range pairs {
+		key := path.Join(c.prefix, pair.Key)
 
-	for _, entry := range entries {
-		value, err := base64.StdEncoding.DecodeString(entry.Value)
-		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error base 64 decoding value for key %s: %s", entry.Key, err))
-			return 1
+		// if the key is a directory, we need to append /
+		if len(pair.Key) > 0 && pair.Key[len(pair.Key)-1] == '/' {
+			key += "/"
 		}
+		pair.Key = key
+	}
 
-		pair := &api.KVPair{
-			Key:   path.Join(c.prefix, entry.Key),
-			Flags: entry.Flags,
-			Value: value,
+	changes, err := c.diff(client, pairs)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error querying Consul agent: %s", err))
+		return 1
+	}
+	if len(changes) == 0 {
+		c.UI.Info("No changes to import")
+		return 0
+	}
+
+	if c.dryRun {
+		var created, updated, deleted int
+		for _, change := range changes {
+			switch {
+			case change.Verb == api.KVDeleteCAS:
+				deleted++
+				c.UI.Info(fmt.Sprintf("- %s", change.Key))
+			case change.Index == 0:
+				created++
+				c.UI.Info(fmt.Sprintf("+ %s", change.Key))
+			default:
+				updated++
+				c.UI.Info(fmt.Sprintf("~ %s", change.Key))
+			}
 		}
+		c.UI.Info(fmt.Sprintf("Dry run: %d created, %d updated, %d deleted", created, updated, deleted))
+		return 0
+	}
 
-		// if the key is a directory, we need to append /
-		if len(entry.Key) > 0 && entry.Key[len(entry.Key)-1] == '/' {
-			pair.Key += "/"
+	if c.atomic {
+		return c.importTxn(client, changes)
+	}
+	return c.importKeys(client, changes)
+}
+
+// importKeys writes the changes key by key, stopping at the first failure.
+func (c *cmd) importKeys(client *api.Client, changes []*api.KVTxnOp) int {
+	for _, change := range changes {
+		w := &api.WriteOptions{Namespace: change.Namespace, Partition: change.Partition}
+		if change.Verb == api.KVDeleteCAS {
+			if _, err := client.KV().Delete(change.Key, w); err != nil {
+				c.UI.Error(fmt.Sprintf("Error! Failed deleting data for key %s: %s", change.Key, err))
+				return 1
+			}
+			c.UI.Info(fmt.Sprintf("Deleted: %s", change.Key))
+			continue
 		}
 
-		w := api.WriteOptions{Namespace: entry.Namespace}
-		if _, err := client.KV().Put(pair, &w); err != nil {
-			c.UI.Error(fmt.Sprintf("Error! Failed writing data for key %s: %s", pair.Key, err))
+		pair := &api.KVPair{Key: change.Key, Flags: change.Flags, Value: change.Value}
+		if _, err := client.KV().Put(pair, w); err != nil {
+			c.UI.Error(fmt.Sprintf("Error! Failed writing data for key %s: %s", change.Key, err))
 			return 1
 		}
+		c.UI.Info(fmt.Sprintf("Imported: %s", change.Key))
+	}
+	return 0
+}
 
-		c.UI.Info(fmt.Sprintf("Imported: %s", pair.Key))
+// importTxn applies all the changes in a single transaction. The changes are
+// check-and-set operations so the transaction fails if a key was modified
+// since it was read.
+func (c *cmd) importTxn(client *api.Client, changes []*api.KVTxnOp) int {
+	ops := make(api.TxnOps, len(changes))
+	for i, change := range changes {
+		ops[i] = &api.TxnOp{KV: change}
+	}
+	limits := &api.TxnLimits{MaxOps: c.txnMaxOps, MaxReqLen: c.txnMaxReqLen}
+	if err := client.Txn().Validate(ops, limits); err != nil {
+		c.UI.Error(fmt.Sprintf("Error! %s. Run without -atomic to import the changes key by key", err))
+		return 1
 	}
 
+	ok, resp, _, err := client.Txn().Txn(ops, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error! Failed writing data: %s", err))
+	} else if !ok {
+		for _, txnErr := range resp.Errors {
+			c.UI.Error(fmt.Sprintf("Error! Failed writing data for key %s: %s", ops[txnErr.OpIndex].KV.Key, txnErr.What))
+		}
+	}
+	if err != nil || !ok {
+		c.UI.Error("Error! No changes were imported")
+		return 1
+	}
+
+	for _, op := range ops {
+		if op.KV.Verb == api.KVDeleteCAS {
+			c.UI.Info(fmt.Sprintf("Deleted: %s", op.KV.Key))
+		} else {
+			c.UI.Info(fmt.Sprintf("Imported: %s", op.KV.Key))
+		}
+	}
 	return 0
 }
 
+// diff returns the operations needed for the KV store to hold the given
+// pairs. Keys are created or updated using a check-and-set on the index they
+// were read at, and deleted when pruning. The indexes are only checked by
+// -atomic imports.
+func (c *cmd) diff(client *api.Client, pairs []*api.KVPair) ([]*api.KVTxnOp, error) {
+	// Pairs may be in different namespaces or partitions when importing the
+	// JSON format, each of them is compared to its current state.
+	type tenancy struct {
+		Namespace string
+		Partition string
+	}
+	desired := make(map[tenancy]map[string]*api.KVPair)
+	var tenancies []tenancy
+	for _, pair := range pairs {
+		t := tenancy{Namespace: pair.Namespace, Partition: pair.Partition}
+		if _, ok := desired[t]; !ok {
+			desired[t] = make(map[string]*api.KVPair)
+			tenancies = append(tenancies, t)
+		}
+		desired[t][pair.Key] = pair
+	}
+	if c.prune && len(tenancies) == 0 {
+		tenancies = append(tenancies, tenancy{})
+		desired[tenancy{}] = make(map[string]*api.KVPair)
+	}
+
+	// The keys are imported under the prefix as a path segment, so keys only
+	// sharing its first characters aren't considered.
+	prefix := c.prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	var changes []*api.KVTxnOp
+	for _, t := range tenancies {
+		current, _, err := client.KV().List(prefix, &api.QueryOptions{
+			Namespace: t.Namespace,
+			Partition: t.Partition,
+		})
+		if err != nil {
+			return nil, err
+		}
+		existing := make(map[string]*api.KVPair, len(current))
+		for _, pair := range current {
+			existing[pair.Key] = pair
+		}
+
+		keys := make([]string, 0, len(desired[t]))
+		for key := range desired[t] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			pair := desired[t][key]
+			op := &api.KVTxnOp{
+				Verb:      api.KVCAS,
+				Key:       pair.Key,
+				Value:     pair.Value,
+				Flags:     pair.Flags,
+				Namespace: t.Namespace,
+				Partition: t.Partition,
+			}
+			if cur, ok := existing[key]; ok {
+				if cur.Flags == pair.Flags && bytes.Equal(cur.Value, pair.Value) {
+					continue
+				}
+				op.Index = cur.ModifyIndex
+			}
+			changes = append(changes, op)
+		}
+
+		if !c.prune {
+			continue
+		}
+		for _, cur := range current {
+			if _, ok := desired[t][cur.Key]; ok {
+				continue
+			}
+			changes = append(changes, &api.KVTxnOp{
+				Verb:      api.KVDeleteCAS,
+				Key:       cur.Key,
+				Index:     cur.ModifyIndex,
+				Namespace: t.Namespace,
+				Partition: t.Partition,
+			})
+		}
+	}
+	return changes, nil
+}
+
 func (c *cmd) dataFromArgs(args []string) (string, error) {
 	var stdin io.Reader = os.Stdin
 	if c.testStdin != nil {
@@ -153,12 +354,31 @@ func (c *cmd) Help() string {
 }
 
 const (
-	synopsis = "Imports a tree stored as JSON to the KV store"
+	synopsis = "Imports a tree stored as JSON, YAML or HCL to the KV store"
 	help     = `
-Usage: consul kv import [DATA]
+Usage: consul kv import [options] [DATA]
+
+  Imports key-value pairs to the key-value store from the representation
+  generated by the "consul kv export" command, using the same "-format".
+
+  Only the keys whose value or flags differ are written, one key at a time.
+  A line is printed for each key written, and for each key deleted:
+
+      Imported: <key>
+      Deleted: <key>
+
+  The "-dry-run" flag prints the keys that would be created (+), updated (~)
+  or deleted (-) without applying them:
+
+      $ consul kv import -format=yaml -dry-run @vault.yaml
+
+  The "-prune" flag deletes the keys under the "-prefix" that are absent from
+  the data, so that the KV store matches it exactly. It requires "-prefix".
 
-  Imports key-value pairs to the key-value store from the JSON representation
-  generated by the "consul kv export" command.
+  The "-atomic" flag applies all the changes in a single transaction which
+  fails if any of the keys was modified concurrently. The import then fails
+  before writing anything if the changes exceed the transaction limits of the
+  agent, set by "-txn-max-ops" and "-txn-max-req-len".
 
   The data can be read from a file by prefixing the filename with the "@"
   symbol. For example:
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
 )
@@ -167,3 +168,159 @@ func TestKVImportPrefixCommand(t *testing.T) {
 		t.Fatalf("bad: expected: bar, got %s", pair.Value)
 	}
 }
+
+func TestKVImportCommand_Tree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	for k, v := range map[string]string{
+		"app/name":    "old",
+		"app/unused":  "x",
+		"app/version": "1",
+		"other":       "kept",
+	} {
+		_, err := client.KV().Put(&api.KVPair{Key: k, Value: []byte(v)}, nil)
+		require.NoError(t, err)
+	}
+
+	const yaml = `
+name: web
+version: 1
+db:
+  port: 5432
+`
+	run := func(args ...string) (int, string, string) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		c.testStdin = strings.NewReader(yaml)
+		args = append([]string{"-http-addr=" + a.HTTPAddr(), "-format=yaml", "-prefix=app"}, args...)
+		code := c.Run(append(args, "-"))
+		return code, ui.OutputWriter.String(), ui.ErrorWriter.String()
+	}
+	get := func(key string) *api.KVPair {
+		pair, _, err := client.KV().Get(key, nil)
+		require.NoError(t, err)
+		return pair
+	}
+
+	// A dry run prints the diff without applying it.
+	code, out, stderr := run("-dry-run", "-prune")
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, "+ app/db/port\n~ app/name\n- app/unused\nDry run: 1 created, 1 updated, 1 deleted\n", out)
+	require.Equal(t, "old", string(get("app/name").Value))
+	require.NotNil(t, get("app/unused"))
+
+	// Without pruning the other keys are kept.
+	code, out, stderr = run()
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, "Imported: app/db/port\nImported: app/name\n", out)
+	require.Equal(t, "web", string(get("app/name").Value))
+	require.Equal(t, "5432", string(get("app/db/port").Value))
+	require.NotNil(t, get("app/unused"))
+
+	code, out, stderr = run("-prune")
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, "Deleted: app/unused\n", out)
+	require.Nil(t, get("app/unused"))
+	require.NotNil(t, get("other"))
+
+	// Importing the same data again is a no-op.
+	code, out, stderr = run("-prune")
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, "No changes to import\n", out)
+}
+
+func TestKVImportCommand_TxnLimits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	const json = `[
+		{"key": "a", "flags": 0, "value": "YQ=="},
+		{"key": "b", "flags": 0, "value": "Yg=="},
+		{"key": "c", "flags": 0, "value": "Yw=="}
+	]`
+	run := func(args ...string) (int, string, string) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		c.testStdin = strings.NewReader(json)
+		args = append([]string{"-http-addr=" + a.HTTPAddr(), "-txn-max-ops=2"}, args...)
+		code := c.Run(append(args, "-"))
+		return code, ui.OutputWriter.String(), ui.ErrorWriter.String()
+	}
+
+	// Nothing is written when the changes of an atomic import exceed the
+	// limits.
+	code, _, stderr := run("-atomic")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "Transaction contains too many operations (3 > 2)")
+	require.Contains(t, stderr, "Run without -atomic")
+	pairs, _, err := client.KV().List("", nil)
+	require.NoError(t, err)
+	require.Empty(t, pairs)
+
+	// The keys are imported one by one by default.
+	code, out, stderr := run()
+	require.Equal(t, 0, code, stderr)
+	require.Equal(t, "Imported: a\nImported: b\nImported: c\n", out)
+	pairs, _, err = client.KV().List("", nil)
+	require.NoError(t, err)
+	require.Len(t, pairs, 3)
+}
+
+func TestKVImportCommand_Atomic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	_, err := client.KV().Put(&api.KVPair{Key: "app/unused", Value: []byte("x")}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(`{"name": "web"}`)
+	code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-format=tree-json", "-prefix=app", "-prune", "-atomic", "-"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Equal(t, "Imported: app/name\nDeleted: app/unused\n", ui.OutputWriter.String())
+
+	pairs, _, err := client.KV().List("app/", nil)
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	require.Equal(t, "web", string(pairs[0].Value))
+}
+
+func TestKVImportCommand_PruneWithoutPrefix(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	code := c.Run([]string{"-prune", "[]"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-prune requires -prefix")
+}
+
+func TestKVImportCommand_InvalidFormat(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	code := c.Run([]string{"-format=toml", "{}"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Invalid format")
+}
@@ -4,11 +4,32 @@
 package impexp
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"gopkg.in/yaml.v3"
 
 	"github.com/hashicorp/consul/api"
 )
 
+const (
+	// FormatJSON is the flat JSON array of Entry, with base64 encoded values.
+	FormatJSON = "json"
+
+	// FormatTreeJSON, FormatYAML and FormatHCL are nested documents with
+	// plain-text values, see ToTree.
+	FormatTreeJSON = "tree-json"
+	FormatYAML     = "yaml"
+	FormatHCL      = "hcl"
+)
+
+// Formats are the formats supported by the import and export commands.
+var Formats = []string{FormatJSON, FormatTreeJSON, FormatYAML, FormatHCL}
+
 type Entry struct {
 	Key       string `json:"key"`
 	Flags     uint64 `json:"flags"`
@@ -26,3 +47,102 @@ func ToEntry(pair *api.KVPair) *Entry {
 		Partition: pair.Partition,
 	}
 }
+
+// ToPair decodes the value of the entry and returns it as a KVPair.
+func (e *Entry) ToPair() (*api.KVPair, error) {
+	value, err := base64.StdEncoding.DecodeString(e.Value)
+	if err != nil {
+		return nil, fmt.Errorf("Error base 64 decoding value for key %s: %s", e.Key, err)
+	}
+	return &api.KVPair{
+		Key:       e.Key,
+		Flags:     e.Flags,
+		Value:     value,
+		Namespace: e.Namespace,
+		Partition: e.Partition,
+	}, nil
+}
+
+// Encode returns the representation of the pairs in the given format.
+func Encode(format string, pairs []*api.KVPair) ([]byte, error) {
+	if format == FormatJSON {
+		exported := make([]*Entry, len(pairs))
+		for i, pair := range pairs {
+			exported[i] = ToEntry(pair)
+		}
+		return json.MarshalIndent(exported, "", "\t")
+	}
+
+	tree, err := ToTree(pairs)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatTreeJSON:
+		return json.MarshalIndent(tree, "", "\t")
+	case FormatYAML:
+		return yaml.Marshal(tree)
+	case FormatHCL:
+		var b bytes.Buffer
+		if err := writeHCL(&b, tree, 0); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	default:
+		return nil, invalidFormatError(format)
+	}
+}
+
+// Decode returns the pairs represented by data in the given format.
+func Decode(format string, data []byte) ([]*api.KVPair, error) {
+	if format == FormatJSON {
+		var entries []*Entry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("Cannot unmarshal data: %s", err)
+		}
+		pairs := make([]*api.KVPair, len(entries))
+		for i, entry := range entries {
+			pair, err := entry.ToPair()
+			if err != nil {
+				return nil, err
+			}
+			pairs[i] = pair
+		}
+		return pairs, nil
+	}
+
+	var tree map[string]interface{}
+	switch format {
+	case FormatTreeJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&tree); err != nil {
+			return nil, fmt.Errorf("Cannot unmarshal data: %s", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return nil, fmt.Errorf("Cannot unmarshal data: %s", err)
+		}
+	case FormatHCL:
+		if err := hcl.Decode(&tree, string(data)); err != nil {
+			return nil, fmt.Errorf("Cannot unmarshal data: %s", err)
+		}
+	default:
+		return nil, invalidFormatError(format)
+	}
+	return FromTree(tree)
+}
+
+// ValidFormat returns an error if format isn't one of Formats.
+func ValidFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return invalidFormatError(format)
+}
+
+func invalidFormatError(format string) error {
+	return fmt.Errorf("Invalid format %q, must be one of %s", format, strings.Join(Formats, ", "))
+}
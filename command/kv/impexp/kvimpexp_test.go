@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package impexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+)
+
+func TestToTree(t *testing.T) {
+	pairs := []*api.KVPair{
+		{Key: "app/", Value: []byte("")},
+		{Key: "app/db/host", Value: []byte("db.local")},
+		{Key: "app/db/port", Value: []byte("5432")},
+		{Key: "app/name", Value: []byte("web")},
+	}
+	tree, err := ToTree(pairs)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"app": map[string]interface{}{
+			"": "",
+			"db": map[string]interface{}{
+				"host": "db.local",
+				"port": "5432",
+			},
+			"name": "web",
+		},
+	}, tree)
+
+	pairs, err = FromTree(tree)
+	require.NoError(t, err)
+	require.Equal(t, []*api.KVPair{
+		{Key: "app/", Value: []byte("")},
+		{Key: "app/db/host", Value: []byte("db.local")},
+		{Key: "app/db/port", Value: []byte("5432")},
+		{Key: "app/name", Value: []byte("web")},
+	}, pairs)
+}
+
+func TestToTree_Errors(t *testing.T) {
+	cases := map[string]struct {
+		pairs []*api.KVPair
+		err   string
+	}{
+		"value and parent": {
+			pairs: []*api.KVPair{{Key: "foo"}, {Key: "foo/bar"}},
+			err:   `Key "foo/bar" is both a value and the parent of other keys`,
+		},
+		"flags": {
+			pairs: []*api.KVPair{{Key: "foo", Flags: 42}},
+			err:   `Key "foo" has flags`,
+		},
+		"binary": {
+			pairs: []*api.KVPair{{Key: "foo", Value: []byte{0xff, 0xfe}}},
+			err:   `Key "foo" doesn't have a text value`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ToTree(tc.pairs)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	pairs := []*api.KVPair{
+		{Key: "app/", Value: []byte("")},
+		{Key: "app/config", Value: []byte("line1\n\"quoted\" ${var} \\ é")},
+		{Key: "app/db/port", Value: []byte("5432")},
+		{Key: "other", Value: []byte("true")},
+	}
+
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			data, err := Encode(format, pairs)
+			require.NoError(t, err)
+
+			decoded, err := Decode(format, data)
+			require.NoError(t, err)
+			require.Equal(t, pairs, decoded)
+
+			// The export is stable.
+			again, err := Encode(format, decoded)
+			require.NoError(t, err)
+			require.Equal(t, string(data), string(again))
+		})
+	}
+
+	_, err := Encode("toml", pairs)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Invalid format "toml"`)
+}
+
+func TestDecode_Tree(t *testing.T) {
+	cases := map[string]string{
+		FormatTreeJSON: `{"app": {"port": 8080, "debug": false, "ratio": 0.5, "name": "web"}}`,
+		FormatYAML: `
+app:
+  port: 8080
+  debug: false
+  ratio: 0.5
+  name: web
+`,
+		FormatHCL: `
+app {
+  port = 8080
+  debug = false
+  ratio = 0.5
+  name = "web"
+}
+`,
+	}
+	for format, data := range cases {
+		t.Run(format, func(t *testing.T) {
+			pairs, err := Decode(format, []byte(data))
+			require.NoError(t, err)
+			require.Equal(t, []*api.KVPair{
+				{Key: "app/debug", Value: []byte("false")},
+				{Key: "app/name", Value: []byte("web")},
+				{Key: "app/port", Value: []byte("8080")},
+				{Key: "app/ratio", Value: []byte("0.5")},
+			}, pairs)
+		})
+	}
+
+	_, err := Decode(FormatYAML, []byte("app:\n  - a\n  - b\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Unsupported value of type []interface {} for key "app"`)
+
+	_, err = Decode(FormatTreeJSON, []byte(`{"": "value"}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package impexp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// ToTree returns the pairs as a nested document where each segment of the
+// keys, separated by "/", is an object and the values are plain-text
+// strings. Keys ending with "/", like the folders created by the UI, have an
+// empty last segment.
+//
+// The pairs are only representable as a tree when none of the keys is also
+// the parent of other keys, no flags are set and all the values are UTF-8
+// text. The flat JSON format must be used otherwise.
+func ToTree(pairs []*api.KVPair) (map[string]interface{}, error) {
+	tree := make(map[string]interface{})
+	for _, pair := range pairs {
+		if pair.Flags != 0 {
+			return nil, fmt.Errorf("Key %q has flags which can't be represented in a tree, use the %s format instead", pair.Key, FormatJSON)
+		}
+		if !utf8.Valid(pair.Value) {
+			return nil, fmt.Errorf("Key %q doesn't have a text value, use the %s format instead", pair.Key, FormatJSON)
+		}
+
+		node := tree
+		segments := strings.Split(pair.Key, "/")
+		for _, segment := range segments[:len(segments)-1] {
+			switch child := node[segment].(type) {
+			case nil:
+				next := make(map[string]interface{})
+				node[segment] = next
+				node = next
+			case map[string]interface{}:
+				node = child
+			default:
+				return nil, treeConflictError(pair.Key)
+			}
+		}
+
+		leaf := segments[len(segments)-1]
+		if _, ok := node[leaf]; ok {
+			return nil, treeConflictError(pair.Key)
+		}
+		node[leaf] = string(pair.Value)
+	}
+	return tree, nil
+}
+
+func treeConflictError(key string) error {
+	return fmt.Errorf("Key %q is both a value and the parent of other keys, which can't be represented in a tree, use the %s format instead", key, FormatJSON)
+}
+
+// FromTree returns the pairs represented by a nested document, the reverse of
+// ToTree. Numbers and booleans are accepted as values and converted to text,
+// null values are empty.
+func FromTree(tree map[string]interface{}) ([]*api.KVPair, error) {
+	var pairs []*api.KVPair
+	if err := fromTree(&pairs, nil, tree); err != nil {
+		return nil, err
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+	return pairs, nil
+}
+
+func fromTree(pairs *[]*api.KVPair, parents []string, node interface{}) error {
+	key := strings.Join(parents, "/")
+
+	var value string
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for segment, child := range v {
+			if err := fromTree(pairs, append(parents[:len(parents):len(parents)], segment), child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[interface{}]interface{}:
+		for segment, child := range v {
+			if err := fromTree(pairs, append(parents[:len(parents):len(parents)], fmt.Sprint(segment)), child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []map[string]interface{}:
+		// HCL decodes objects as lists of objects.
+		for _, child := range v {
+			if err := fromTree(pairs, parents, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case string:
+		value = v
+	case nil:
+	case bool:
+		value = strconv.FormatBool(v)
+	case int:
+		value = strconv.Itoa(v)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case uint64:
+		value = strconv.FormatUint(v, 10)
+	case json.Number:
+		value = v.String()
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("Unsupported value of type %T for key %q", node, key)
+	}
+
+	if key == "" {
+		return fmt.Errorf("Values must be nested under a key")
+	}
+	*pairs = append(*pairs, &api.KVPair{Key: key, Value: []byte(value)})
+	return nil
+}
+
+// writeHCL writes the tree as HCL, with the keys sorted so that exports are
+// stable.
+func writeHCL(w io.Writer, tree map[string]interface{}, depth int) error {
+	keys := make([]string, 0, len(tree))
+	for k := range tree {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	indent := strings.Repeat("  ", depth)
+	for _, k := range keys {
+		key, err := hclQuote(k)
+		if err != nil {
+			return fmt.Errorf("Key %q can't be represented in HCL, use another format instead", k)
+		}
+
+		switch v := tree[k].(type) {
+		case string:
+			quoted, err := hclQuote(v)
+			if err != nil {
+				return fmt.Errorf("Value of %q can't be represented in HCL, use another format instead", k)
+			}
+			if _, err := fmt.Fprintf(w, "%s%s = %s\n", indent, key, quoted); err != nil {
+				return err
+			}
+		case map[string]interface{}:
+			if _, err := fmt.Fprintf(w, "%s%s {\n", indent, key); err != nil {
+				return err
+			}
+			if err := writeHCL(w, v, depth+1); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "%s}\n", indent); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hclQuote returns the value as an HCL string. HCL doesn't unescape the
+// content of interpolations, so values containing one are only supported
+// when they are read back unchanged.
+func hclQuote(v string) (string, error) {
+	quoted := strconv.Quote(v)
+	if !strings.Contains(v, "${") {
+		return quoted, nil
+	}
+
+	var out map[string]interface{}
+	if err := hcl.Decode(&out, "v = "+quoted); err != nil {
+		return "", err
+	}
+	if out["v"] != v {
+		return "", fmt.Errorf("value changed")
+	}
+	return quoted, nil
+}
@@ -54,10 +54,16 @@ type cmd struct {
 	childLock sync.Mutex
 	verbose   bool
 
+	// fencingToken is the fencing token of the election when running with
+	// -election.
+	fencingToken uint64
+
 	// flags
+	election           bool
 	limit              int
 	monitorRetry       int
 	name               string
+	observe            bool
 	passStdin          bool
 	propagateChildCode bool
 	shell              bool
@@ -76,6 +82,11 @@ func (c *cmd) init() {
 		"Exit 2 if the child process exited with an error if this is true, "+
 			"otherwise this doesn't propagate an error from the child. The "+
 			"default value is false.")
+	c.flags.BoolVar(&c.election, "election", false,
+		"Use a leader election instead of a plain lock. The child process is "+
+			"given the fencing token of its leadership in the "+
+			"CONSUL_LOCK_FENCING_TOKEN environment variable. When no child "+
+			"command is given, the current leader is printed instead.")
 	c.flags.IntVar(&c.limit, "n", 1,
 		"Optional limit on the number of concurrent lock holders. The underlying "+
 			"implementation switches from a lock to a semaphore when the value is "+
@@ -89,6 +100,9 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.name, "name", "",
 		"Optional name to associate with the lock session. It not provided, one "+
 			"is generated based on the provided child command.")
+	c.flags.BoolVar(&c.observe, "observe", false,
+		"With -election and no child command, keep printing the leader of the "+
+			"election every time it changes, until interrupted.")
 	c.flags.BoolVar(&c.passStdin, "pass-stdin", false,
 		"Pass stdin to the child process.")
 	c.flags.BoolVar(&c.shell, "shell", true,
@@ -126,12 +140,29 @@ func (c *cmd) run(args []string, lu **LockUnlock) int {
 		return 1
 	}
 
-	// Verify the prefix and child are provided
+	if c.election && c.limit > 1 {
+		c.UI.Error("Election mode can't be used with more than one lock holder")
+		return 1
+	}
+	if c.observe && !c.election {
+		c.UI.Error("-observe can only be used with -election")
+		return 1
+	}
+
+	// Verify the prefix and child are provided. In election mode, the child
+	// is optional and the leader is printed without it.
 	extra := c.flags.Args()
+	if c.election && len(extra) == 1 {
+		return c.printLeader(strings.TrimPrefix(extra[0], "/"))
+	}
 	if len(extra) < 2 {
 		c.UI.Error("Key prefix and child command must be specified")
 		return 1
 	}
+	if c.observe {
+		c.UI.Error("-observe can't be used with a child command")
+		return 1
+	}
 	prefix := extra[0]
 	prefix = strings.TrimPrefix(prefix, "/")
 
@@ -166,8 +197,10 @@ func (c *cmd) run(args []string, lu **LockUnlock) int {
 		return 1
 	}
 
-	// Setup the lock or semaphore
-	if c.limit == 1 {
+	// Setup the lock, election or semaphore
+	if c.election {
+		*lu, err = c.setupElection(client, prefix, c.name, oneshot, c.timeout, c.monitorRetry)
+	} else if c.limit == 1 {
 		*lu, err = c.setupLock(client, prefix, c.name, oneshot, c.timeout, c.monitorRetry)
 	} else {
 		*lu, err = c.setupSemaphore(client, c.limit, prefix, c.name, oneshot, c.timeout, c.monitorRetry)
@@ -302,6 +335,109 @@ func (c *cmd) setupLock(client *api.Client, prefix, name string,
 	return lu, nil
 }
 
+// setupElection is used to setup a new Election given the API client, the key
+// prefix to operate on, and an optional session name. The election uses the
+// same key as a lock, so that `consul lock` and `consul lock -election`
+// exclude each other. The options are the same as for setupLock.
+func (c *cmd) setupElection(client *api.Client, prefix, name string,
+	oneshot bool, wait time.Duration, retry int) (*LockUnlock, error) {
+	key := path.Join(prefix, api.DefaultSemaphoreKey)
+	if c.verbose {
+		c.UI.Info(fmt.Sprintf("Setting up election at path: %s", key))
+	}
+	opts := api.LockOptions{
+		Key:              key,
+		SessionName:      name,
+		MonitorRetries:   retry,
+		MonitorRetryTime: defaultMonitorRetryTime,
+	}
+	if oneshot {
+		opts.LockTryOnce = true
+		opts.LockWaitTime = wait
+	}
+	e, err := client.ElectionOpts(&opts)
+	if err != nil {
+		return nil, err
+	}
+	campaign := func(stopCh <-chan struct{}) (<-chan struct{}, error) {
+		leader, leaderCh, err := e.Campaign(stopCh)
+		if leader != nil {
+			c.fencingToken = leader.FencingToken
+			if c.verbose {
+				c.UI.Info(fmt.Sprintf("Elected with fencing token %d", leader.FencingToken))
+			}
+		}
+		return leaderCh, err
+	}
+	lu := &LockUnlock{
+		lockFn:    campaign,
+		unlockFn:  e.Resign,
+		cleanupFn: e.Destroy,
+		inUseErr:  api.ErrLockInUse,
+		rawOpts:   &opts,
+	}
+	return lu, nil
+}
+
+// printLeader prints the leader of the election at the given prefix, and
+// with -observe keeps printing it every time it changes.
+func (c *cmd) printLeader(prefix string) int {
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+	e, err := client.Election(path.Join(prefix, api.DefaultSemaphoreKey))
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Election setup failed: %s", err))
+		return 1
+	}
+
+	if !c.observe {
+		leader, _, err := e.Leader(nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading the election: %s", err))
+			return 1
+		}
+		c.UI.Output(c.formatLeader(client, leader, false))
+		return 0
+	}
+
+	first := true
+	for leader := range e.Observe(c.ShutdownCh) {
+		if !first {
+			c.UI.Output("")
+		}
+		first = false
+		c.UI.Output(c.formatLeader(client, leader, true))
+	}
+	return 0
+}
+
+// formatLeader formats the leader of an election, along with the node and
+// name of its session. The time the leader was first seen is only meaningful
+// when observing the election.
+func (c *cmd) formatLeader(client *api.Client, leader *api.Leader, since bool) string {
+	if leader == nil {
+		return "No leader"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Session:       %s\n", leader.Session)
+	if se, _, err := client.Session().Info(leader.Session, nil); err == nil && se != nil {
+		fmt.Fprintf(&b, "Node:          %s\n", se.Node)
+		fmt.Fprintf(&b, "Name:          %s\n", se.Name)
+	}
+	if len(leader.Value) > 0 {
+		fmt.Fprintf(&b, "Value:         %s\n", leader.Value)
+	}
+	fmt.Fprintf(&b, "Term:          %d\n", leader.Term)
+	fmt.Fprintf(&b, "Fencing Token: %d", leader.FencingToken)
+	if since {
+		fmt.Fprintf(&b, "\nSince:         %s", leader.Since.Format(time.RFC3339))
+	}
+	return b.String()
+}
+
 // setupSemaphore is used to setup a new Semaphore given the API client, key
 // prefix, session name, and slot holder limit. If oneshot is true then we will
 // set up for a single attempt at acquisition, using the given wait time. The
@@ -361,6 +497,9 @@ func (c *cmd) startChild(args []string, passStdin, shell bool) error {
 	cmd.Env = append(os.Environ(),
 		"CONSUL_LOCK_HELD=true",
 	)
+	if c.fencingToken != 0 {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("CONSUL_LOCK_FENCING_TOKEN=%d", c.fencingToken))
+	}
 	if passStdin {
 		if c.verbose {
 			c.UI.Info("Stdin passed to handler process")
@@ -470,6 +609,7 @@ type LockUnlock struct {
 const synopsis = "Execute a command holding a lock"
 const help = `
 Usage: consul lock [options] prefix child...
+       consul lock -election [-observe] [options] prefix
 
   Acquires a lock or semaphore at a given path, and invokes a child process
   when successful. The child process can assume the lock is held while it
@@ -484,5 +624,13 @@ Usage: consul lock [options] prefix child...
   exclusion. Setting a higher value switches to a semaphore allowing multiple
   holders to coordinate.
 
+  With -election, the lock is held as the leader of an election and the child
+  process gets the fencing token of the leadership in the
+  CONSUL_LOCK_FENCING_TOKEN environment variable. Without a child process,
+  the current leader is printed, and -observe keeps printing it every time
+  the leadership changes:
+
+      $ consul lock -election -observe service/web/leader
+
   The prefix provided must have write privileges.
 `
@@ -6,12 +6,14 @@ package lock
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/mitchellh/cli"
 )
@@ -41,6 +43,9 @@ func TestLockCommand_BadArgs(t *testing.T) {
 	argFail(t, []string{"-try=blah", "test/prefix", "date"}, "parse error")
 	argFail(t, []string{"-try=-10s", "test/prefix", "date"}, "Timeout must be positive")
 	argFail(t, []string{"-monitor-retry=-5", "test/prefix", "date"}, "must be >= 0")
+	argFail(t, []string{"-election", "-n=3", "test/prefix", "date"}, "can't be used with more than one lock holder")
+	argFail(t, []string{"-observe", "test/prefix"}, "-observe can only be used with -election")
+	argFail(t, []string{"-election", "-observe", "test/prefix", "date"}, "-observe can't be used with a child command")
 }
 
 func TestLockCommand(t *testing.T) {
@@ -377,3 +382,127 @@ func TestLockCommand_ChildExitCode(t *testing.T) {
 		})
 	}
 }
+
+func TestLockCommand_Election(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Without a child, there is no leader to print
+	ui := cli.NewMockUi()
+	c := New(ui, nil)
+	code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-election", "test/prefix"})
+	if code != 0 {
+		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
+	}
+	if out := ui.OutputWriter.String(); out != "No leader\n" {
+		t.Fatalf("bad: %q", out)
+	}
+
+	// The child gets the fencing token of the leadership, and prints the
+	// leader while it holds it.
+	ui = cli.NewMockUi()
+	c = New(ui, nil)
+	filePath := filepath.Join(a.Config.DataDir, "test_token")
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(), "-election", "-name=test-leader", "test/prefix",
+		"echo $CONSUL_LOCK_FENCING_TOKEN > " + filePath,
+	}
+	var lu *LockUnlock
+	code = c.run(args, &lu)
+	if code != 0 {
+		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
+	}
+	token, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(token)), strconv.FormatUint(c.fencingToken, 10); got != want || c.fencingToken == 0 {
+		t.Fatalf("got fencing token %q want %q", got, want)
+	}
+	if _, ok := lu.rawOpts.(*api.LockOptions); !ok {
+		t.Fatalf("bad type")
+	}
+}
+
+func TestLockCommand_ElectionPrintLeader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	client := a.Client()
+	election, err := client.ElectionOpts(&api.LockOptions{
+		Key:         "test/prefix/.lock",
+		Value:       []byte("web-1"),
+		SessionName: "test-leader",
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	leader, _, err := election.Campaign(nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer election.Resign()
+
+	ui := cli.NewMockUi()
+	c := New(ui, nil)
+	code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-election", "test/prefix"})
+	if code != 0 {
+		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	for _, want := range []string{
+		"Session:       " + leader.Session,
+		"Node:          " + a.Config.NodeName,
+		"Name:          test-leader",
+		"Value:         web-1",
+		"Term:          1",
+		"Fencing Token: " + strconv.FormatUint(leader.FencingToken, 10),
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output %q should contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "Since:") {
+		t.Fatalf("output %q should not contain the time the leader was seen", out)
+	}
+
+	// Observing prints the leader until interrupted.
+	shutdownCh := make(chan struct{})
+	ui = cli.NewMockUi()
+	c = New(ui, shutdownCh)
+	doneCh := make(chan int)
+	go func() {
+		doneCh <- c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-election", "-observe", "test/prefix"})
+	}()
+	retry.Run(t, func(r *retry.R) {
+		if out := ui.OutputWriter.String(); !strings.Contains(out, "Since:") {
+			r.Fatalf("bad: %q", out)
+		}
+	})
+	if err := election.Resign(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	retry.Run(t, func(r *retry.R) {
+		if out := ui.OutputWriter.String(); !strings.Contains(out, "No leader") {
+			r.Fatalf("bad: %q", out)
+		}
+	})
+	close(shutdownCh)
+	if code := <-doneCh; code != 0 {
+		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package apply
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+	"github.com/hashicorp/consul/command/resource"
+	intresource "github.com/hashicorp/consul/internal/resource"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	grpc  *resource.GRPCFlags
+	help  string
+
+	filePath  string
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.filePath, "f", "",
+		"Path to the HCL or JSON file describing the resource, or - to read it from stdin.")
+	c.http = &flags.HTTPFlags{}
+	c.grpc = &resource.GRPCFlags{}
+	flags.Merge(c.flags, c.grpc.ClientFlags())
+	flags.Merge(c.flags, c.http.ClientFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.flags.Args()) != 0 {
+		c.UI.Error("Unexpected positional arguments; use -f to specify the resource file")
+		return 1
+	}
+	if c.filePath == "" {
+		c.UI.Error("Must specify the -f parameter")
+		return 1
+	}
+
+	data, err := helpers.LoadDataSourceNoRaw(c.filePath, c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load data: %v", err))
+		return 1
+	}
+
+	res, err := resource.ParseResource(data)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	client, err := c.grpc.ResourceClient(c.http)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul: %s", err))
+		return 1
+	}
+	defer client.Close()
+
+	rsp, err := client.Write(context.Background(), &pbresource.WriteRequest{Resource: res})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error writing resource %s/%s: %v", intresource.ToGVK(res.Id.Type), res.Id.Name, err))
+		return 1
+	}
+
+	written := rsp.Resource
+	c.UI.Info(fmt.Sprintf("Resource %s/%s written (version %s)",
+		intresource.ToGVK(written.Id.Type), written.Id.Name, written.Version))
+	for _, line := range resource.FormatConditions(written) {
+		c.UI.Info("  " + line)
+	}
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Write a resource"
+	help     = `
+Usage: consul resource apply [options] -f <file>
+
+  Writes the resource described by the given HCL or JSON file, creating it if
+  it does not exist. The file uses the JSON mapping of a resource; id.type may
+  be given as a <group>.<version>.<kind> string and the data is written as the
+  resource type's own fields. Tenancy defaults to the default partition and
+  namespace of the local cluster.
+
+  Example:
+
+    $ cat workload.hcl
+    id {
+      type = "catalog.v1alpha1.Workload"
+      name = "api-1"
+    }
+    data {
+      addresses {
+        host = "10.0.0.1"
+      }
+      ports {
+        http {
+          port = 8080
+        }
+      }
+      identity = "api"
+    }
+
+    $ consul resource apply -f workload.hcl
+`
+)
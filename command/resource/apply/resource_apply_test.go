@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package apply
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/command/resource"
+	"github.com/hashicorp/consul/internal/catalog"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func TestResourceApply_noTabs(t *testing.T) {
+	t.Parallel()
+
+	require.NotContains(t, New(cli.NewMockUi()).Help(), "\t")
+}
+
+func TestResourceApply(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	grpcAddr := a.Config.GRPCAddrs[0].String()
+
+	path := filepath.Join(t.TempDir(), "workload.hcl")
+	require.NoError(t, os.WriteFile(path, []byte(`
+id {
+  type = "catalog.v1alpha1.Workload"
+  name = "api-1"
+}
+data {
+  addresses {
+    host = "10.0.0.1"
+  }
+  ports {
+    http {
+      port = 8080
+    }
+  }
+  identity = "api"
+}
+`), 0600))
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	code := c.Run([]string{"-grpc-addr=" + grpcAddr, "-f=" + path})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Resource catalog.v1alpha1.Workload/api-1 written")
+
+	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	rsp, err := pbresource.NewResourceServiceClient(conn).Read(context.Background(), &pbresource.ReadRequest{
+		Id: &pbresource.ID{
+			Type:    catalog.WorkloadV1Alpha1Type,
+			Tenancy: resource.Tenancy("", "", ""),
+			Name:    "api-1",
+		},
+	})
+	require.NoError(t, err)
+
+	var workload pbcatalog.Workload
+	require.NoError(t, rsp.Resource.Data.UnmarshalTo(&workload))
+	require.Equal(t, "10.0.0.1", workload.Addresses[0].Host)
+	require.Equal(t, uint32(8080), workload.Ports["http"].Port)
+	require.Equal(t, "api", workload.Identity)
+}
+
+func TestResourceApply_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "invalid.hcl")
+	require.NoError(t, os.WriteFile(path, []byte(`data { identity = "api" }`), 0600))
+
+	cases := map[string][]string{
+		"no file":        {},
+		"positional arg": {"-f", path, "extra"},
+		"missing file":   {"-f", filepath.Join(t.TempDir(), "missing.hcl")},
+		"missing id":     {"-f", path},
+	}
+
+	for name, tcase := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+
+			require.NotEqual(t, 0, c.Run(tcase))
+			require.NotEmpty(t, ui.ErrorWriter.String())
+		})
+	}
+}
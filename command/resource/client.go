@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+// DefaultGRPCAddr is the address used when neither -grpc-addr nor the
+// CONSUL_GRPC_ADDR environment variable are set.
+const DefaultGRPCAddr = "127.0.0.1:8502"
+
+// GRPCFlags holds the flags used to reach a Consul server's gRPC port. The
+// ACL token and TLS client certificates are shared with flags.HTTPFlags so
+// that the resource commands honor the same flags and environment variables
+// as the rest of the CLI.
+type GRPCFlags struct {
+	address string
+	caFile  string
+	caPath  string
+}
+
+func (f *GRPCFlags) ClientFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.StringVar(&f.address, "grpc-addr", os.Getenv(api.GRPCAddrEnvName),
+		"The `address` and port of the Consul server's gRPC port. The value can be "+
+			"an IP address or DNS address, but it must also include the port. Prefix "+
+			"the address with https:// to connect using TLS. This can also be specified "+
+			"via the CONSUL_GRPC_ADDR environment variable. The default value is "+
+			DefaultGRPCAddr+".")
+	fs.StringVar(&f.caFile, "grpc-ca-file", os.Getenv(api.GRPCCAFileEnvName),
+		"Path to a CA file to use for TLS when communicating with Consul's gRPC port. "+
+			"This can also be specified via the CONSUL_GRPC_CACERT environment variable. "+
+			"Defaults to the value of -ca-file.")
+	fs.StringVar(&f.caPath, "grpc-ca-path", os.Getenv(api.GRPCCAPathEnvName),
+		"Path to a directory of CA certificates to use for TLS when communicating with "+
+			"Consul's gRPC port. This can also be specified via the CONSUL_GRPC_CAPATH "+
+			"environment variable. Defaults to the value of -ca-path.")
+	return fs
+}
+
+// Client is a resource service client that attaches the configured ACL token
+// to every request.
+type Client struct {
+	pbresource.ResourceServiceClient
+
+	conn *grpc.ClientConn
+}
+
+// Close tears down the underlying gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// ResourceClient dials the configured gRPC address and returns a client for
+// the resource service.
+func (f *GRPCFlags) ResourceClient(http *flags.HTTPFlags) (*Client, error) {
+	cfg := api.DefaultConfig()
+	http.MergeOntoConfig(cfg)
+
+	token := cfg.Token
+	if token == "" && cfg.TokenFile != "" {
+		data, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	addr := f.address
+	if addr == "" {
+		addr = DefaultGRPCAddr
+	}
+
+	creds := insecure.NewCredentials()
+	switch {
+	case strings.HasPrefix(strings.ToLower(addr), "https://"):
+		addr = addr[len("https://"):]
+
+		tlsCfg := cfg.TLSConfig
+		if f.caFile != "" {
+			tlsCfg.CAFile = f.caFile
+		}
+		if f.caPath != "" {
+			tlsCfg.CAPath = f.caPath
+		}
+		if tlsCfg.Address == "" {
+			tlsCfg.Address = addr
+		}
+		tc, err := api.SetupTLSConfig(&tlsCfg)
+		if err != nil {
+			return nil, fmt.Errorf("Error configuring TLS: %w", err)
+		}
+		creds = credentials.NewTLS(tc)
+	case strings.HasPrefix(strings.ToLower(addr), "http://"):
+		addr = addr[len("http://"):]
+	}
+
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withToken(ctx, token), method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withToken(ctx, token), desc, cc, method, opts...)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to %s: %w", addr, err)
+	}
+
+	return &Client{
+		ResourceServiceClient: pbresource.NewResourceServiceClient(conn),
+		conn:                  conn,
+	}, nil
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "x-consul-token", token)
+}
+
+// TenancyFromFlags returns the tenancy selected by the -partition and
+// -namespace flags (or their environment variables) and the given peer name.
+func TenancyFromFlags(http *flags.HTTPFlags, peerName string) *pbresource.Tenancy {
+	cfg := api.DefaultConfig()
+	http.MergeOntoConfig(cfg)
+	return Tenancy(cfg.Partition, cfg.Namespace, peerName)
+}
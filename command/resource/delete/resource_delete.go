@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package delete
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/helpers"
+	"github.com/hashicorp/consul/command/resource"
+	intresource "github.com/hashicorp/consul/internal/resource"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	grpc  *resource.GRPCFlags
+	help  string
+
+	filePath  string
+	peerName  string
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.filePath, "f", "",
+		"Path to an HCL or JSON file describing the resource to delete, or - to read "+
+			"it from stdin. Only the file's id is used.")
+	c.flags.StringVar(&c.peerName, "peer", "",
+		"Specifies the name of the peer the resource belongs to. Defaults to the local cluster.")
+	c.http = &flags.HTTPFlags{}
+	c.grpc = &resource.GRPCFlags{}
+	flags.Merge(c.flags, c.grpc.ClientFlags())
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	id, err := c.resourceID(c.flags.Args())
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	client, err := c.grpc.ResourceClient(c.http)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul: %s", err))
+		return 1
+	}
+	defer client.Close()
+
+	name := fmt.Sprintf("%s/%s", intresource.ToGVK(id.Type), id.Name)
+	if _, err := client.Delete(context.Background(), &pbresource.DeleteRequest{Id: id}); err != nil {
+		c.UI.Error(fmt.Sprintf("Error deleting resource %s: %v", name, err))
+		return 1
+	}
+
+	c.UI.Info(fmt.Sprintf("Resource %s deleted", name))
+	return 0
+}
+
+func (c *cmd) resourceID(args []string) (*pbresource.ID, error) {
+	if c.filePath != "" {
+		if len(args) != 0 {
+			return nil, fmt.Errorf("Cannot specify both -f and positional arguments")
+		}
+		data, err := helpers.LoadDataSourceNoRaw(c.filePath, c.testStdin)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load data: %v", err)
+		}
+		res, err := resource.ParseResource(data)
+		if err != nil {
+			return nil, err
+		}
+		return res.Id, nil
+	}
+
+	if len(args) != 2 {
+		return nil, fmt.Errorf("Must provide exactly two positional arguments (the resource type and name) or use -f")
+	}
+	typ, err := resource.ParseType(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return &pbresource.ID{
+		Type:    typ,
+		Tenancy: resource.TenancyFromFlags(c.http, c.peerName),
+		Name:    args[1],
+	}, nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Delete a resource"
+	help     = `
+Usage: consul resource delete [options] <type> <name>
+       consul resource delete [options] -f <file>
+
+  Deletes the resource with the given type and name, or the resource
+  described by the given file. Deleting a resource that does not exist is
+  not an error.
+
+  Example:
+
+    $ consul resource delete catalog.v1alpha1.Workload api-1
+`
+)
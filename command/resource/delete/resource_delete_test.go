@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package delete
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/command/resource"
+	"github.com/hashicorp/consul/internal/catalog"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func TestResourceDelete_noTabs(t *testing.T) {
+	t.Parallel()
+
+	require.NotContains(t, New(cli.NewMockUi()).Help(), "\t")
+}
+
+func TestResourceDelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	grpcAddr := a.Config.GRPCAddrs[0].String()
+
+	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pbresource.NewResourceServiceClient(conn)
+
+	writeWorkload := func(t *testing.T, name string) *pbresource.ID {
+		data, err := anypb.New(&pbcatalog.Workload{
+			Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+			Identity:  name,
+		})
+		require.NoError(t, err)
+
+		rsp, err := client.Write(context.Background(), &pbresource.WriteRequest{
+			Resource: &pbresource.Resource{
+				Id: &pbresource.ID{
+					Type:    catalog.WorkloadV1Alpha1Type,
+					Tenancy: resource.Tenancy("", "", ""),
+					Name:    name,
+				},
+				Data: data,
+			},
+		})
+		require.NoError(t, err)
+		return rsp.Resource.Id
+	}
+
+	requireNotFound := func(t *testing.T, id *pbresource.ID) {
+		_, err := client.Read(context.Background(), &pbresource.ReadRequest{Id: id})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		id := writeWorkload(t, "api-1")
+
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-grpc-addr=" + grpcAddr, "catalog.v1alpha1.Workload", "api-1"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "Resource catalog.v1alpha1.Workload/api-1 deleted")
+		requireNotFound(t, id)
+	})
+
+	t.Run("by file", func(t *testing.T) {
+		id := writeWorkload(t, "api-2")
+
+		path := filepath.Join(t.TempDir(), "workload.hcl")
+		require.NoError(t, os.WriteFile(path, []byte(`id { type = "catalog.v1alpha1.Workload" name = "api-2" }`), 0600))
+
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-grpc-addr=" + grpcAddr, "-f=" + path})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		requireNotFound(t, id)
+	})
+}
+
+func TestResourceDelete_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]string{
+		"no args":        {},
+		"no name":        {"catalog.v1alpha1.Workload"},
+		"invalid type":   {"Workload", "api-1"},
+		"file with args": {"-f=workload.hcl", "catalog.v1alpha1.Workload", "api-1"},
+	}
+
+	for name, tcase := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+
+			require.NotEqual(t, 0, c.Run(tcase))
+			require.NotEmpty(t, ui.ErrorWriter.String())
+		})
+	}
+}
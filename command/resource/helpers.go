@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/command/helpers"
+	"github.com/hashicorp/consul/internal/catalog"
+	"github.com/hashicorp/consul/internal/mesh"
+	"github.com/hashicorp/consul/internal/resource"
+	"github.com/hashicorp/consul/internal/resource/demo"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+const (
+	defaultPartition = "default"
+	defaultNamespace = "default"
+	defaultPeerName  = "local"
+)
+
+// registry holds the resource types known to the CLI, so that a resource's
+// data can be decoded into the right protobuf message before it is sent to
+// the server.
+var registry = newRegistry()
+
+func newRegistry() resource.Registry {
+	r := resource.NewRegistry()
+	catalog.RegisterTypes(r)
+	mesh.RegisterTypes(r)
+	// Servers only register the demo types in dev mode, but they're a
+	// convenient way to try out the resource API so the CLI knows them too.
+	demo.RegisterTypes(r)
+	return r
+}
+
+// ParseType parses a resource type given in the form <group>.<version>.<kind>.
+func ParseType(s string) (*pbresource.Type, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("resource type %q must be in the form <group>.<version>.<kind>", s)
+	}
+	return &pbresource.Type{
+		Group:        parts[0],
+		GroupVersion: parts[1],
+		Kind:         parts[2],
+	}, nil
+}
+
+// Tenancy returns a tenancy with any unset fields filled in with the values
+// the resource service expects.
+func Tenancy(partition, namespace, peerName string) *pbresource.Tenancy {
+	t := &pbresource.Tenancy{
+		Partition: partition,
+		Namespace: namespace,
+		PeerName:  peerName,
+	}
+	defaultTenancy(t)
+	return t
+}
+
+func defaultTenancy(t *pbresource.Tenancy) {
+	if t.Partition == "" {
+		t.Partition = defaultPartition
+	}
+	if t.Namespace == "" {
+		t.Namespace = defaultNamespace
+	}
+	if t.PeerName == "" {
+		t.PeerName = defaultPeerName
+	}
+}
+
+// ParseResource decodes an HCL or JSON document describing a resource.
+//
+// The document uses the JSON mapping of pbresource.Resource, with two
+// conveniences: id.type may be given as a "<group>.<version>.<kind>" string,
+// and data is written as the resource's own message (without an "@type"
+// field) since its type can be inferred from id.type.
+func ParseResource(data string) (*pbresource.Resource, error) {
+	raw, err := helpers.DecodeHCLOrJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode resource input: %v", err)
+	}
+	raw = normalizeMessage(raw, (&pbresource.Resource{}).ProtoReflect().Descriptor()).(map[string]interface{})
+
+	rawData, hasData := raw["data"]
+	delete(raw, "data")
+
+	if id, ok := raw["id"].(map[string]interface{}); ok {
+		if typ, ok := id["type"].(string); ok {
+			parsed, err := ParseType(typ)
+			if err != nil {
+				return nil, err
+			}
+			id["type"] = map[string]interface{}{
+				"group":        parsed.Group,
+				"groupVersion": parsed.GroupVersion,
+				"kind":         parsed.Kind,
+			}
+		}
+	}
+
+	res := &pbresource.Resource{}
+	if err := unmarshalJSON(raw, res); err != nil {
+		return nil, fmt.Errorf("Failed to decode resource: %v", err)
+	}
+
+	switch {
+	case res.Id == nil:
+		return nil, fmt.Errorf("Resource is missing an id")
+	case res.Id.Type == nil:
+		return nil, fmt.Errorf("Resource is missing id.type")
+	case res.Id.Name == "":
+		return nil, fmt.Errorf("Resource is missing id.name")
+	}
+	if res.Id.Tenancy == nil {
+		res.Id.Tenancy = &pbresource.Tenancy{}
+	}
+	defaultTenancy(res.Id.Tenancy)
+
+	if !hasData {
+		return res, nil
+	}
+
+	reg, ok := registry.Resolve(res.Id.Type)
+	if !ok {
+		return nil, fmt.Errorf("Unknown resource type %s", resource.ToGVK(res.Id.Type))
+	}
+
+	msg := reg.Proto.ProtoReflect().New().Interface()
+	rawData = normalizeMessage(rawData, msg.ProtoReflect().Descriptor())
+	if m, ok := rawData.(map[string]interface{}); ok {
+		delete(m, "@type")
+	}
+	if err := unmarshalJSON(rawData, msg); err != nil {
+		return nil, fmt.Errorf("Failed to decode %s data: %v", resource.ToGVK(res.Id.Type), err)
+	}
+
+	res.Data, err = anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func unmarshalJSON(v interface{}, msg proto.Message) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(b, msg)
+}
+
+// normalizeMessage undoes the HCL decoder's habit of turning every block into
+// a list of maps, using the message descriptor to decide which fields are
+// singular. JSON input passes through unchanged.
+func normalizeMessage(v interface{}, desc protoreflect.MessageDescriptor) interface{} {
+	if s, ok := v.([]map[string]interface{}); ok && len(s) == 1 {
+		v = s[0]
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	// The contents of an Any can't be interpreted without its type.
+	if desc.FullName() == "google.protobuf.Any" {
+		return m
+	}
+
+	fields := desc.Fields()
+	for k, fv := range m {
+		fd := fields.ByJSONName(k)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(k))
+		}
+		if fd == nil {
+			continue
+		}
+		m[k] = normalizeField(fv, fd)
+	}
+	return m
+}
+
+func normalizeField(v interface{}, fd protoreflect.FieldDescriptor) interface{} {
+	switch {
+	case fd.IsMap():
+		var m map[string]interface{}
+		switch mv := v.(type) {
+		case map[string]interface{}:
+			m = mv
+		case []map[string]interface{}:
+			m = make(map[string]interface{})
+			for _, item := range mv {
+				for k, iv := range item {
+					m[k] = iv
+				}
+			}
+		default:
+			return v
+		}
+		if fd.MapValue().Kind() == protoreflect.MessageKind {
+			for k, iv := range m {
+				m[k] = normalizeMessage(iv, fd.MapValue().Message())
+			}
+		}
+		return m
+	case fd.Kind() != protoreflect.MessageKind:
+		return v
+	case fd.IsList():
+		var items []interface{}
+		switch lv := v.(type) {
+		case []interface{}:
+			items = lv
+		case []map[string]interface{}:
+			for _, item := range lv {
+				items = append(items, item)
+			}
+		default:
+			return v
+		}
+		for i, item := range items {
+			items[i] = normalizeMessage(item, fd.Message())
+		}
+		return items
+	default:
+		return normalizeMessage(v, fd.Message())
+	}
+}
+
+// MarshalResource renders the given message (usually a resource) as indented
+// JSON.
+func MarshalResource(msg proto.Message) (string, error) {
+	b, err := protojson.MarshalOptions{Multiline: true, Indent: "    "}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// FormatConditions renders the status conditions of a resource, one per line,
+// ordered by the controller that reported them.
+func FormatConditions(res *pbresource.Resource) []string {
+	keys := make([]string, 0, len(res.Status))
+	for k := range res.Status {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, k := range keys {
+		for _, cond := range res.Status[k].Conditions {
+			line := fmt.Sprintf("%s: %s=%s", k, cond.Type, cond.State)
+			if cond.Reason != "" {
+				line += " (" + cond.Reason + ")"
+			}
+			if cond.Message != "" {
+				line += " " + cond.Message
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
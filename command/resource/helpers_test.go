@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/internal/catalog"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+	"github.com/hashicorp/consul/proto/private/prototest"
+)
+
+func TestParseType(t *testing.T) {
+	typ, err := ParseType("catalog.v1alpha1.Workload")
+	require.NoError(t, err)
+	require.Equal(t, "catalog", typ.Group)
+	require.Equal(t, "v1alpha1", typ.GroupVersion)
+	require.Equal(t, "Workload", typ.Kind)
+
+	for _, s := range []string{"", "catalog", "catalog.Workload", "catalog..Workload", "a.b.c.d"} {
+		_, err := ParseType(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestParseResource(t *testing.T) {
+	expected := &pbresource.Resource{
+		Id: &pbresource.ID{
+			Type:    catalog.WorkloadV1Alpha1Type,
+			Tenancy: Tenancy("", "", ""),
+			Name:    "api-1",
+		},
+		Metadata: map[string]string{"env": "dev"},
+	}
+	expectedData := &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{
+			{Host: "10.0.0.1", Ports: []string{"http"}},
+			{Host: "10.0.0.2"},
+		},
+		Ports: map[string]*pbcatalog.WorkloadPort{
+			"http": {Port: 8080, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+		},
+		Identity: "api",
+	}
+
+	cases := map[string]string{
+		"hcl": `
+id {
+  type = "catalog.v1alpha1.Workload"
+  name = "api-1"
+}
+metadata {
+  env = "dev"
+}
+data {
+  addresses {
+    host  = "10.0.0.1"
+    ports = ["http"]
+  }
+  addresses {
+    host = "10.0.0.2"
+  }
+  ports {
+    http {
+      port     = 8080
+      protocol = "PROTOCOL_HTTP"
+    }
+  }
+  identity = "api"
+}`,
+		"hcl with type block": `
+id {
+  type {
+    group         = "catalog"
+    group_version = "v1alpha1"
+    kind          = "Workload"
+  }
+  tenancy {
+    partition = "default"
+  }
+  name = "api-1"
+}
+metadata {
+  env = "dev"
+}
+data {
+  addresses = [
+    { host = "10.0.0.1", ports = ["http"] },
+    { host = "10.0.0.2" },
+  ]
+  ports {
+    http {
+      port     = 8080
+      protocol = "PROTOCOL_HTTP"
+    }
+  }
+  identity = "api"
+}`,
+		"json": `{
+  "id": {
+    "type": {"group": "catalog", "groupVersion": "v1alpha1", "kind": "Workload"},
+    "name": "api-1"
+  },
+  "metadata": {"env": "dev"},
+  "data": {
+    "@type": "hashicorp.consul.catalog.v1alpha1.Workload",
+    "addresses": [{"host": "10.0.0.1", "ports": ["http"]}, {"host": "10.0.0.2"}],
+    "ports": {"http": {"port": 8080, "protocol": "PROTOCOL_HTTP"}},
+    "identity": "api"
+  }
+}`,
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			res, err := ParseResource(input)
+			require.NoError(t, err)
+
+			data := &pbcatalog.Workload{}
+			require.NoError(t, res.Data.UnmarshalTo(data))
+			prototest.AssertDeepEqual(t, expectedData, data)
+
+			res.Data = nil
+			prototest.AssertDeepEqual(t, expected, res)
+		})
+	}
+}
+
+func TestParseResource_Errors(t *testing.T) {
+	cases := map[string]string{
+		"invalid input":  `id {`,
+		"missing id":     `metadata { env = "dev" }`,
+		"missing name":   `id { type = "catalog.v1alpha1.Workload" }`,
+		"invalid type":   `id { type = "Workload" name = "api-1" }`,
+		"unknown type":   `id { type = "foo.v1.Bar" name = "api-1" } data { a = 1 }`,
+		"unknown field":  `id { type = "catalog.v1alpha1.Workload" name = "api-1" } data { bogus = 1 }`,
+		"unknown id key": `id { type = "catalog.v1alpha1.Workload" name = "api-1" bogus = 1 }`,
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseResource(input)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFormatConditions(t *testing.T) {
+	res := &pbresource.Resource{
+		Status: map[string]*pbresource.Status{
+			"b-controller": {
+				Conditions: []*pbresource.Condition{
+					{Type: "Healthy", State: pbresource.Condition_STATE_FALSE, Reason: "Critical", Message: "check failing"},
+				},
+			},
+			"a-controller": {
+				Conditions: []*pbresource.Condition{
+					{Type: "Accepted", State: pbresource.Condition_STATE_TRUE},
+				},
+			},
+		},
+	}
+	require.Equal(t, []string{
+		"a-controller: Accepted=STATE_TRUE",
+		"b-controller: Healthy=STATE_FALSE (Critical) check failing",
+	}, FormatConditions(res))
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package list
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/resource"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+const (
+	formatPretty = "pretty"
+	formatJSON   = "json"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	grpc  *resource.GRPCFlags
+	help  string
+
+	namePrefix string
+	peerName   string
+	format     string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.namePrefix, "name-prefix", "",
+		"Only list resources whose name starts with the given prefix.")
+	c.flags.StringVar(&c.peerName, "peer", "",
+		"Specifies the name of the peer to list resources from. Defaults to the local cluster.")
+	c.flags.StringVar(&c.format, "format", formatPretty,
+		fmt.Sprintf("Output format {%s|%s} (default: %s)", formatPretty, formatJSON, formatPretty))
+	c.http = &flags.HTTPFlags{}
+	c.grpc = &resource.GRPCFlags{}
+	flags.Merge(c.flags, c.grpc.ClientFlags())
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.format != formatPretty && c.format != formatJSON {
+		c.UI.Error(fmt.Sprintf("Invalid format, valid formats are {%s|%s}", formatPretty, formatJSON))
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Must provide exactly one positional argument to specify the resource type")
+		return 1
+	}
+
+	typ, err := resource.ParseType(args[0])
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	client, err := c.grpc.ResourceClient(c.http)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul: %s", err))
+		return 1
+	}
+	defer client.Close()
+
+	rsp, err := client.List(context.Background(), &pbresource.ListRequest{
+		Type:       typ,
+		Tenancy:    resource.TenancyFromFlags(c.http, c.peerName),
+		NamePrefix: c.namePrefix,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error listing resources of type %s: %v", args[0], err))
+		return 1
+	}
+
+	sort.Slice(rsp.Resources, func(i, j int) bool {
+		return rsp.Resources[i].Id.Name < rsp.Resources[j].Id.Name
+	})
+
+	if c.format == formatJSON {
+		out, err := resource.MarshalResource(rsp)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to encode output data: %v", err))
+			return 1
+		}
+		c.UI.Output(out)
+		return 0
+	}
+
+	if len(rsp.Resources) == 0 {
+		c.UI.Info(fmt.Sprintf("There are no resources of type %s.", args[0]))
+		return 0
+	}
+
+	result := []string{"Name\x1fVersion\x1fStatus"}
+	for _, res := range rsp.Resources {
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%s",
+			res.Id.Name, res.Version, statusSummary(res)))
+	}
+	c.UI.Output(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
+// statusSummary condenses the conditions of every controller into a single
+// comma separated list of type=state pairs.
+func statusSummary(res *pbresource.Resource) string {
+	keys := make([]string, 0, len(res.Status))
+	for k := range res.Status {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		for _, cond := range res.Status[k].Conditions {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", cond.Type, cond.State))
+		}
+	}
+	if len(pairs) == 0 {
+		return "-"
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "List resources of a type"
+	help     = `
+Usage: consul resource list [options] <type>
+
+  Lists the resources of the given type along with their versions and a
+  summary of their status conditions. The type is given in the form
+  <group>.<version>.<kind>.
+
+  Example:
+
+    $ consul resource list catalog.v1alpha1.Workload
+
+  List only the resources whose name starts with "api":
+
+    $ consul resource list -name-prefix api catalog.v1alpha1.Workload
+`
+)
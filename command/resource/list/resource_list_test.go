@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package list
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/command/resource"
+	"github.com/hashicorp/consul/internal/catalog"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func TestResourceList_noTabs(t *testing.T) {
+	t.Parallel()
+
+	require.NotContains(t, New(cli.NewMockUi()).Help(), "\t")
+}
+
+func TestResourceList(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	grpcAddr := a.Config.GRPCAddrs[0].String()
+
+	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pbresource.NewResourceServiceClient(conn)
+
+	for _, name := range []string{"web-1", "api-2", "api-1"} {
+		data, err := anypb.New(&pbcatalog.Workload{
+			Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+			Identity:  name,
+		})
+		require.NoError(t, err)
+
+		_, err = client.Write(context.Background(), &pbresource.WriteRequest{
+			Resource: &pbresource.Resource{
+				Id: &pbresource.ID{
+					Type:    catalog.WorkloadV1Alpha1Type,
+					Tenancy: resource.Tenancy("", "", ""),
+					Name:    name,
+				},
+				Data: data,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("pretty", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-grpc-addr=" + grpcAddr, "-name-prefix=api", "catalog.v1alpha1.Workload"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		// The workload health controller may already have written its status
+		// to the workloads, so accept any value in the Status column.
+		require.Regexp(t, `(?s)Name\s+Version\s+Status\napi-1\s+\d+\s+\S+\napi-2\s+\d+\s+\S+\n$`, output)
+		require.NotContains(t, output, "web-1")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-grpc-addr=" + grpcAddr, "-format=json", "catalog.v1alpha1.Workload"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var rsp pbresource.ListResponse
+		require.NoError(t, protojson.Unmarshal(ui.OutputWriter.Bytes(), &rsp))
+		require.Len(t, rsp.Resources, 3)
+		require.Equal(t, "api-1", rsp.Resources[0].Id.Name)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-grpc-addr=" + grpcAddr, "catalog.v1alpha1.Service"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "There are no resources of type catalog.v1alpha1.Service.")
+	})
+}
+
+func TestResourceList_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]string{
+		"no type":        {},
+		"too many args":  {"catalog.v1alpha1.Workload", "api-1"},
+		"invalid type":   {"Workload"},
+		"invalid format": {"-format=yaml", "catalog.v1alpha1.Workload"},
+	}
+
+	for name, tcase := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+
+			require.NotEqual(t, 0, c.Run(tcase))
+			require.NotEmpty(t, ui.ErrorWriter.String())
+		})
+	}
+}
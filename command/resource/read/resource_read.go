@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package read
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/resource"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	grpc  *resource.GRPCFlags
+	help  string
+
+	peerName string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.peerName, "peer", "",
+		"Specifies the name of the peer the resource belongs to. Defaults to the local cluster.")
+	c.http = &flags.HTTPFlags{}
+	c.grpc = &resource.GRPCFlags{}
+	flags.Merge(c.flags, c.grpc.ClientFlags())
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 2 {
+		c.UI.Error("Must provide exactly two positional arguments: the resource type and name")
+		return 1
+	}
+
+	typ, err := resource.ParseType(args[0])
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	client, err := c.grpc.ResourceClient(c.http)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul: %s", err))
+		return 1
+	}
+	defer client.Close()
+
+	rsp, err := client.Read(context.Background(), &pbresource.ReadRequest{
+		Id: &pbresource.ID{
+			Type:    typ,
+			Tenancy: resource.TenancyFromFlags(c.http, c.peerName),
+			Name:    args[1],
+		},
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading resource %s/%s: %v", args[0], args[1], err))
+		return 1
+	}
+
+	out, err := resource.MarshalResource(rsp.Resource)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to encode output data: %v", err))
+		return 1
+	}
+
+	c.UI.Info(out)
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Read a resource"
+	help     = `
+Usage: consul resource read [options] <type> <name>
+
+  Reads the resource with the given type and name and outputs its JSON
+  representation, including any status conditions written by controllers.
+  The type is given in the form <group>.<version>.<kind>.
+
+  Example:
+
+    $ consul resource read catalog.v1alpha1.Workload api-1
+`
+)
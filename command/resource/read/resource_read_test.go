@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package read
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/command/resource"
+	"github.com/hashicorp/consul/internal/catalog"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func TestResourceRead_noTabs(t *testing.T) {
+	t.Parallel()
+
+	require.NotContains(t, New(cli.NewMockUi()).Help(), "\t")
+}
+
+func TestResourceRead(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	grpcAddr := a.Config.GRPCAddrs[0].String()
+
+	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	data, err := anypb.New(&pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+		Identity:  "api",
+	})
+	require.NoError(t, err)
+
+	_, err = pbresource.NewResourceServiceClient(conn).Write(context.Background(), &pbresource.WriteRequest{
+		Resource: &pbresource.Resource{
+			Id: &pbresource.ID{
+				Type:    catalog.WorkloadV1Alpha1Type,
+				Tenancy: resource.Tenancy("", "", ""),
+				Name:    "api-1",
+			},
+			Data: data,
+		},
+	})
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	code := c.Run([]string{"-grpc-addr=" + grpcAddr, "catalog.v1alpha1.Workload", "api-1"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	var res pbresource.Resource
+	require.NoError(t, protojson.Unmarshal(ui.OutputWriter.Bytes(), &res))
+	require.Equal(t, "api-1", res.Id.Name)
+
+	var workload pbcatalog.Workload
+	require.NoError(t, res.Data.UnmarshalTo(&workload))
+	require.Equal(t, "api", workload.Identity)
+
+	t.Run("not found", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-grpc-addr=" + grpcAddr, "catalog.v1alpha1.Workload", "missing"})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "NotFound")
+	})
+}
+
+func TestResourceRead_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]string{
+		"no args":      {},
+		"no name":      {"catalog.v1alpha1.Workload"},
+		"invalid type": {"Workload", "api-1"},
+	}
+
+	for name, tcase := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+
+			require.NotEqual(t, 0, c.Run(tcase))
+			require.NotEmpty(t, ui.ErrorWriter.String())
+		})
+	}
+}
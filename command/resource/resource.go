@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New() *cmd {
+	return &cmd{}
+}
+
+type cmd struct{}
+
+func (c *cmd) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(help, nil)
+}
+
+const synopsis = "Interact with Consul's v2 resources"
+const help = `
+Usage: consul resource <subcommand> [options] [args]
+
+  This command has subcommands for interacting with the resources served by
+  Consul's resource gRPC API. Resource types are given in the form
+  <group>.<version>.<kind>. These commands talk to a server's gRPC port, which
+  can be set with -grpc-addr or CONSUL_GRPC_ADDR. Here are some simple
+  examples, and more detailed examples are available in the subcommands or
+  the documentation.
+
+  Write a resource:
+
+    $ consul resource apply -f workload.hcl
+
+  Read a resource:
+
+    $ consul resource read catalog.v1alpha1.Workload api-1
+
+  List all resources of a type:
+
+    $ consul resource list catalog.v1alpha1.Workload
+
+  Delete a resource:
+
+    $ consul resource delete catalog.v1alpha1.Workload api-1
+
+  Watch resources of a type for changes:
+
+    $ consul resource watch catalog.v1alpha1.Workload
+
+  For more examples, ask for subcommand help or view the documentation.
+`
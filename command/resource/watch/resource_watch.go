@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package watch
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/resource"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func New(ui cli.Ui, shutdownCh <-chan struct{}) *cmd {
+	c := &cmd{UI: ui, shutdownCh: shutdownCh}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI         cli.Ui
+	flags      *flag.FlagSet
+	http       *flags.HTTPFlags
+	grpc       *resource.GRPCFlags
+	help       string
+	shutdownCh <-chan struct{}
+
+	namePrefix string
+	peerName   string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.namePrefix, "name-prefix", "",
+		"Only watch resources whose name starts with the given prefix.")
+	c.flags.StringVar(&c.peerName, "peer", "",
+		"Specifies the name of the peer to watch resources from. Defaults to the local cluster.")
+	c.http = &flags.HTTPFlags{}
+	c.grpc = &resource.GRPCFlags{}
+	flags.Merge(c.flags, c.grpc.ClientFlags())
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Must provide exactly one positional argument to specify the resource type")
+		return 1
+	}
+
+	typ, err := resource.ParseType(args[0])
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	client, err := c.grpc.ResourceClient(c.http)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul: %s", err))
+		return 1
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.shutdownCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	stream, err := client.WatchList(ctx, &pbresource.WatchListRequest{
+		Type:       typ,
+		Tenancy:    resource.TenancyFromFlags(c.http, c.peerName),
+		NamePrefix: c.namePrefix,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error watching resources of type %s: %v", args[0], err))
+		return 1
+	}
+
+	for {
+		event, err := stream.Recv()
+		if status.Code(err) == codes.Canceled || ctx.Err() != nil {
+			return 0
+		}
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error watching resources of type %s: %v", args[0], err))
+			return 1
+		}
+
+		// Events are written one per line so the output can be piped to
+		// tools that consume JSON lines.
+		out, err := protojson.Marshal(event)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to encode event: %v", err))
+			return 1
+		}
+		c.UI.Output(string(out))
+	}
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Watch resources of a type for changes"
+	help     = `
+Usage: consul resource watch [options] <type>
+
+  Watches the resources of the given type and prints an event for each one
+  that is created, updated or deleted, starting with an upsert event for
+  every existing resource. Each event is printed as a single line of JSON.
+  The command runs until it is interrupted.
+
+  Example:
+
+    $ consul resource watch catalog.v1alpha1.Workload
+`
+)
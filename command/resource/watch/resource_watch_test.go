@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package watch
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/command/resource"
+	"github.com/hashicorp/consul/internal/catalog"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+func TestResourceWatch_noTabs(t *testing.T) {
+	t.Parallel()
+
+	require.NotContains(t, New(cli.NewMockUi(), nil).Help(), "\t")
+}
+
+func TestResourceWatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	grpcAddr := a.Config.GRPCAddrs[0].String()
+
+	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pbresource.NewResourceServiceClient(conn)
+
+	id := &pbresource.ID{
+		Type:    catalog.WorkloadV1Alpha1Type,
+		Tenancy: resource.Tenancy("", "", ""),
+		Name:    "api-1",
+	}
+	data, err := anypb.New(&pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+		Identity:  "api",
+	})
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	shutdownCh := make(chan struct{})
+	c := New(ui, shutdownCh)
+
+	codeCh := make(chan int, 1)
+	go func() {
+		codeCh <- c.Run([]string{"-grpc-addr=" + grpcAddr, "catalog.v1alpha1.Workload"})
+	}()
+
+	_, err = client.Write(context.Background(), &pbresource.WriteRequest{
+		Resource: &pbresource.Resource{Id: id, Data: data},
+	})
+	require.NoError(t, err)
+
+	var event pbresource.WatchEvent
+	retry.Run(t, func(r *retry.R) {
+		lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+		require.NoError(r, protojson.Unmarshal([]byte(lines[0]), &event))
+	})
+	require.Equal(t, pbresource.WatchEvent_OPERATION_UPSERT, event.Operation)
+	require.Equal(t, "api-1", event.Resource.Id.Name)
+
+	close(shutdownCh)
+	select {
+	case code := <-codeCh:
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for watch to exit")
+	}
+}
+
+func TestResourceWatch_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]string{
+		"no type":      {},
+		"invalid type": {"Workload"},
+	}
+
+	for name, tcase := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui, nil)
+
+			require.NotEqual(t, 0, c.Run(tcase))
+			require.NotEmpty(t, ui.ErrorWriter.String())
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
+type Formatter interface {
+	Format(*Diff) (string, error)
+}
+
+func GetSupportedFormats() []string {
+	return []string{PrettyFormat, JSONFormat}
+}
+
+func NewFormatter(format string) (Formatter, error) {
+	switch format {
+	case PrettyFormat:
+		return &prettyFormatter{}, nil
+	case JSONFormat:
+		return &jsonFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("Unknown format: %s", format)
+	}
+}
+
+type prettyFormatter struct{}
+
+func (_ *prettyFormatter) Format(d *Diff) (string, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "--- %s (index %d)\n", d.From.ID, d.From.Index)
+	fmt.Fprintf(&b, "+++ %s (index %d)\n", d.To.ID, d.To.Index)
+
+	if d.Empty() {
+		b.WriteString("\nNo differences found")
+		return b.String(), nil
+	}
+
+	for _, s := range d.sections() {
+		if s.changes.Empty() {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s\n", s.name)
+		for _, k := range s.changes.Added {
+			fmt.Fprintf(&b, "  + %s\n", k)
+		}
+		for _, k := range s.changes.Removed {
+			fmt.Fprintf(&b, "  - %s\n", k)
+		}
+		for _, k := range s.changes.Changed {
+			fmt.Fprintf(&b, "  ~ %s\n", k)
+		}
+	}
+	return string(bytes.TrimRight(b.Bytes(), "\n")), nil
+}
+
+type jsonFormatter struct{}
+
+func (_ *jsonFormatter) Format(d *Diff) (string, error) {
+	b, err := json.MarshalIndent(d, "", "   ")
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal snapshot diff: %v", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/snapshot/keys"
+	"github.com/hashicorp/consul/snapshot"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	help  string
+	keys  keys.Flags
+
+	// flags
+	format string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(GetSupportedFormats(), "|")))
+	flags.Merge(c.flags, c.keys.EncryptionFlags(false))
+	flags.Merge(c.flags, c.keys.VerifyFlags())
+
+	c.help = flags.Usage(help, c.flags)
+}
+
+// MetadataInfo identifies one of the compared snapshots.
+type MetadataInfo struct {
+	File  string
+	ID    string
+	Index uint64
+	Term  uint64
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 2 {
+		c.UI.Error(fmt.Sprintf("Expected two snapshot files to compare, got %d", len(args)))
+		return 1
+	}
+
+	formatter, err := NewFormatter(c.format)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	encryptionKey, err := c.keys.EncryptionKey(nil)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	verifyKey, err := c.keys.VerifyKey()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	snapKeys := &snapshot.Keys{
+		EncryptionKey: encryptionKey,
+		VerifyKey:     verifyKey,
+	}
+
+	from, fromMeta, err := readFile(args[0], snapKeys)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading snapshot %q: %s", args[0], err))
+		return 1
+	}
+	to, toMeta, err := readFile(args[1], snapKeys)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading snapshot %q: %s", args[1], err))
+		return 1
+	}
+
+	d := diffStates(from, to)
+	d.From = fromMeta
+	d.To = toMeta
+
+	out, err := formatter.Format(d)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	c.UI.Output(out)
+	return 0
+}
+
+// readFile extracts the snapshot archive at the given path, decrypting it if
+// needed, and decodes its state.
+func readFile(path string, keys *snapshot.Keys) (*snapshotState, *MetadataInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	tmp, meta, err := snapshot.ReadWithKeys(hclog.New(nil), f, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	state, err := readState(tmp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode snapshot data: %w", err)
+	}
+
+	return state, &MetadataInfo{
+		File:  path,
+		ID:    meta.ID,
+		Index: meta.Index,
+		Term:  meta.Term,
+	}, nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Compares two Consul snapshot files"
+const help = `
+Usage: consul snapshot diff [options] FILE1 FILE2
+
+  Compares two snapshot files on disk and reports the KV keys, nodes, services,
+  ACL tokens, ACL policies, config entries and intentions that were added (+),
+  removed (-) or changed (~) between them. Raft indexes are ignored, so only
+  changes to the data itself are reported.
+
+  To see what changed between two backups:
+
+    $ consul snapshot diff backup-0200.snap backup-0300.snap
+
+  To output the differences as JSON:
+
+    $ consul snapshot diff -format=json backup-0200.snap backup-0300.snap
+
+  To compare encrypted snapshots, both encrypted with the same key:
+
+    $ consul snapshot diff -encrypt-key-file=snapshot.key \
+        backup-0200.snap backup-0300.snap
+
+  For a full list of options and examples, please see the Consul documentation.
+`
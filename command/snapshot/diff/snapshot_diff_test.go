@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/snapshot"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestSnapshotDiffCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestSnapshotDiffCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		output string
+	}{
+		"no files": {
+			[]string{},
+			"Expected two snapshot files to compare, got 0",
+		},
+		"one file": {
+			[]string{"a.snap"},
+			"Expected two snapshot files to compare, got 1",
+		},
+		"bad format": {
+			[]string{"-format=xml", "a.snap", "b.snap"},
+			"Unknown format: xml",
+		},
+		"missing file": {
+			[]string{filepath.Join(t.TempDir(), "a.snap"), "b.snap"},
+			"Error reading snapshot",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+
+			require.Equal(t, 1, c.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.output)
+		})
+	}
+}
+
+func TestSnapshotDiffCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+	client := a.Client()
+
+	kv := client.KV()
+	_, err := kv.Put(&api.KVPair{Key: "app/removed", Value: []byte("1")}, nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "app/changed", Value: []byte("1")}, nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "app/unchanged", Value: []byte("1")}, nil)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	before := saveSnapshot(t, client, filepath.Join(dir, "before.snap"))
+
+	_, err = kv.Delete("app/removed", nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "app/changed", Value: []byte("2")}, nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "app/added", Value: []byte("1")}, nil)
+	require.NoError(t, err)
+	// Rewriting a key with the same value bumps its index, but isn't a change.
+	_, err = kv.Put(&api.KVPair{Key: "app/unchanged", Value: []byte("1")}, nil)
+	require.NoError(t, err)
+
+	_, _, err = client.ConfigEntries().Set(&api.ServiceIntentionsConfigEntry{
+		Kind: api.ServiceIntentions,
+		Name: "db",
+		Sources: []*api.SourceIntention{
+			{Name: "web", Action: api.IntentionActionAllow},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	after := saveSnapshot(t, client, filepath.Join(dir, "after.snap"))
+
+	t.Run("pretty", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{before, after})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "  + app/added\n")
+		require.Contains(t, output, "  - app/removed\n")
+		require.Contains(t, output, "  ~ app/changed\n")
+		require.NotContains(t, output, "app/unchanged")
+		require.Contains(t, output, "  + service-intentions/db\n")
+		require.Contains(t, output, "  + web => db")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-format=json", before, after})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var d Diff
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &d))
+		require.Equal(t, before, d.From.File)
+		require.Equal(t, after, d.To.File)
+		require.Equal(t, Changes{
+			Added:   []string{"app/added"},
+			Removed: []string{"app/removed"},
+			Changed: []string{"app/changed"},
+		}, d.KV)
+		require.Equal(t, []string{"service-intentions/db"}, d.ConfigEntries.Added)
+		require.Equal(t, []string{"web => db"}, d.Intentions.Added)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		key := make([]byte, snapshot.EncryptionKeySize)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+		keyFile := filepath.Join(dir, "snapshot.key")
+		require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600))
+		sealed := sealSnapshot(t, after, filepath.Join(dir, "after-sealed.snap"), key)
+
+		ui := cli.NewMockUi()
+		c := New(ui)
+		code := c.Run([]string{before, sealed})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "snapshot is encrypted")
+
+		ui = cli.NewMockUi()
+		c = New(ui)
+		code = c.Run([]string{"-encrypt-key-file=" + keyFile, before, sealed})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "  + app/added\n")
+		require.Contains(t, output, "  ~ app/changed\n")
+	})
+
+	t.Run("identical", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{after, after})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "No differences found")
+	})
+}
+
+func TestDiffRecords(t *testing.T) {
+	from := map[string]interface{}{
+		"same":    &structs.DirEntry{Key: "same", Value: []byte("a")},
+		"changed": &structs.DirEntry{Key: "changed", Value: []byte("a")},
+		"removed": &structs.DirEntry{Key: "removed"},
+	}
+	to := map[string]interface{}{
+		"same":    &structs.DirEntry{Key: "same", Value: []byte("a")},
+		"changed": &structs.DirEntry{Key: "changed", Value: []byte("b")},
+		"added":   &structs.DirEntry{Key: "added"},
+	}
+
+	require.Equal(t, Changes{
+		Added:   []string{"added"},
+		Removed: []string{"removed"},
+		Changed: []string{"changed"},
+	}, diffRecords(from, to))
+}
+
+func saveSnapshot(t *testing.T, client *api.Client, path string) string {
+	t.Helper()
+
+	rc, _, err := client.Snapshot().Save(nil)
+	require.NoError(t, err)
+	defer rc.Close()
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = io.Copy(f, rc)
+	require.NoError(t, err)
+	return path
+}
+
+func sealSnapshot(t *testing.T, path, sealedPath string, key []byte) string {
+	t.Helper()
+
+	in, err := os.Open(path)
+	require.NoError(t, err)
+	defer in.Close()
+
+	out, err := os.Create(sealedPath)
+	require.NoError(t, err)
+	defer out.Close()
+
+	require.NoError(t, snapshot.Seal(in, out, snapshot.SealOptions{EncryptionKey: key}))
+	return sealedPath
+}
@@ -0,0 +1,261 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul-net-rpc/go-msgpack/codec"
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/fsm"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// snapshotState holds the records of a snapshot that can be compared, keyed by
+// a human-readable identifier. Raft indexes are stripped from every record so
+// that only meaningful changes are reported.
+type snapshotState struct {
+	KV            map[string]interface{}
+	Nodes         map[string]interface{}
+	Services      map[string]interface{}
+	ACLTokens     map[string]interface{}
+	ACLPolicies   map[string]interface{}
+	ConfigEntries map[string]interface{}
+	Intentions    map[string]interface{}
+}
+
+func newSnapshotState() *snapshotState {
+	return &snapshotState{
+		KV:            make(map[string]interface{}),
+		Nodes:         make(map[string]interface{}),
+		Services:      make(map[string]interface{}),
+		ACLTokens:     make(map[string]interface{}),
+		ACLPolicies:   make(map[string]interface{}),
+		ConfigEntries: make(map[string]interface{}),
+		Intentions:    make(map[string]interface{}),
+	}
+}
+
+// readState decodes the FSM records in the given (already extracted) raft
+// snapshot.
+func readState(r io.Reader) (*snapshotState, error) {
+	s := newSnapshotState()
+
+	handler := func(_ *fsm.SnapshotHeader, msg structs.MessageType, dec *codec.Decoder) error {
+		var err error
+		switch msg {
+		case structs.RegisterRequestType:
+			var req structs.RegisterRequest
+			if err = dec.Decode(&req); err == nil {
+				s.addRegistration(&req)
+			}
+		case structs.KVSRequestType:
+			var entry structs.DirEntry
+			if err = dec.Decode(&entry); err == nil {
+				entry.RaftIndex = structs.RaftIndex{}
+				s.KV[scopedName(&entry.EnterpriseMeta, "", entry.Key)] = &entry
+			}
+		case structs.ACLTokenSetRequestType:
+			var token structs.ACLToken
+			if err = dec.Decode(&token); err == nil {
+				token.RaftIndex = structs.RaftIndex{}
+				s.ACLTokens[scopedName(&token.EnterpriseMeta, "", token.AccessorID)] = &token
+			}
+		case structs.ACLPolicySetRequestType:
+			var policy structs.ACLPolicy
+			if err = dec.Decode(&policy); err == nil {
+				policy.RaftIndex = structs.RaftIndex{}
+				s.ACLPolicies[scopedName(&policy.EnterpriseMeta, "", policy.Name)] = &policy
+			}
+		case structs.ConfigEntryRequestType:
+			var req structs.ConfigEntryRequest
+			if err = dec.Decode(&req); err == nil {
+				s.addConfigEntry(req.Entry)
+			}
+		case structs.IntentionRequestType:
+			var ixn structs.Intention
+			if err = dec.Decode(&ixn); err == nil {
+				ixn.RaftIndex = structs.RaftIndex{}
+				key := fmt.Sprintf("%s/%s => %s/%s", ixn.SourceNS, ixn.SourceName, ixn.DestinationNS, ixn.DestinationName)
+				s.Intentions[key] = &ixn
+			}
+		default:
+			// Skip over records we don't compare.
+			var val interface{}
+			err = dec.Decode(&val)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode msg type %v: %w", msg, err)
+		}
+		return nil
+	}
+
+	if err := fsm.ReadSnapshot(r, handler); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// addRegistration records the node or service described by a register
+// request. Snapshots contain one request for each node, followed by one for
+// each of its services and checks.
+func (s *snapshotState) addRegistration(req *structs.RegisterRequest) {
+	nodeName := scopedName(&req.EnterpriseMeta, req.PeerName, req.Node)
+
+	switch {
+	case req.Service != nil:
+		svc := req.Service
+		svc.RaftIndex = structs.RaftIndex{}
+		s.Services[nodeName+"/"+scopedName(&svc.EnterpriseMeta, "", svc.ID)] = svc
+	case req.Check == nil:
+		s.Nodes[nodeName] = &structs.Node{
+			ID:              req.ID,
+			Node:            req.Node,
+			Address:         req.Address,
+			Datacenter:      req.Datacenter,
+			Partition:       req.PartitionOrEmpty(),
+			PeerName:        req.PeerName,
+			TaggedAddresses: req.TaggedAddresses,
+			Meta:            req.NodeMeta,
+			Locality:        req.Locality,
+		}
+	}
+}
+
+// addConfigEntry records a config entry. The sources of service-intentions
+// entries are also recorded as individual intentions.
+func (s *snapshotState) addConfigEntry(entry structs.ConfigEntry) {
+	if entry == nil {
+		return
+	}
+	if idx := entry.GetRaftIndex(); idx != nil {
+		*idx = structs.RaftIndex{}
+	}
+
+	name := scopedName(entry.GetEnterpriseMeta(), "", entry.GetName())
+	s.ConfigEntries[entry.GetKind()+"/"+name] = entry
+
+	ixns, ok := entry.(*structs.ServiceIntentionsConfigEntry)
+	if !ok {
+		return
+	}
+	for _, src := range ixns.Sources {
+		key := fmt.Sprintf("%s => %s", scopedName(&src.EnterpriseMeta, src.Peer, src.Name), name)
+		s.Intentions[key] = src
+	}
+}
+
+// scopedName qualifies a name with its partition, namespace and peer, when
+// they aren't the defaults.
+func scopedName(entMeta *acl.EnterpriseMeta, peer, name string) string {
+	var parts []string
+	if peer != "" {
+		parts = append(parts, "peer:"+peer)
+	}
+	if entMeta != nil {
+		if p := entMeta.PartitionOrEmpty(); !acl.IsDefaultPartition(p) {
+			parts = append(parts, p)
+		}
+		if ns := entMeta.NamespaceOrEmpty(); ns != "" && ns != acl.DefaultNamespaceName {
+			parts = append(parts, ns)
+		}
+	}
+	return strings.Join(append(parts, name), "/")
+}
+
+// Changes lists the identifiers of records that were added, removed or
+// changed between two snapshots.
+type Changes struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty returns whether there are no changes.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Diff holds the differences between two snapshots.
+type Diff struct {
+	From *MetadataInfo
+	To   *MetadataInfo
+
+	KV            Changes
+	Nodes         Changes
+	Services      Changes
+	ACLTokens     Changes
+	ACLPolicies   Changes
+	ConfigEntries Changes
+	Intentions    Changes
+}
+
+// Empty returns whether the snapshots have no differences.
+func (d *Diff) Empty() bool {
+	for _, s := range d.sections() {
+		if !s.changes.Empty() {
+			return false
+		}
+	}
+	return true
+}
+
+type section struct {
+	name    string
+	changes Changes
+}
+
+func (d *Diff) sections() []section {
+	return []section{
+		{"KV", d.KV},
+		{"Nodes", d.Nodes},
+		{"Services", d.Services},
+		{"ACL Tokens", d.ACLTokens},
+		{"ACL Policies", d.ACLPolicies},
+		{"Config Entries", d.ConfigEntries},
+		{"Intentions", d.Intentions},
+	}
+}
+
+func diffStates(from, to *snapshotState) *Diff {
+	return &Diff{
+		KV:            diffRecords(from.KV, to.KV),
+		Nodes:         diffRecords(from.Nodes, to.Nodes),
+		Services:      diffRecords(from.Services, to.Services),
+		ACLTokens:     diffRecords(from.ACLTokens, to.ACLTokens),
+		ACLPolicies:   diffRecords(from.ACLPolicies, to.ACLPolicies),
+		ConfigEntries: diffRecords(from.ConfigEntries, to.ConfigEntries),
+		Intentions:    diffRecords(from.Intentions, to.Intentions),
+	}
+}
+
+func diffRecords(from, to map[string]interface{}) Changes {
+	c := Changes{
+		Added:   make([]string, 0),
+		Removed: make([]string, 0),
+		Changed: make([]string, 0),
+	}
+	for k, v := range to {
+		old, ok := from[k]
+		switch {
+		case !ok:
+			c.Added = append(c.Added, k)
+		case !reflect.DeepEqual(old, v):
+			c.Changed = append(c.Changed, k)
+		}
+	}
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			c.Removed = append(c.Removed, k)
+		}
+	}
+	sort.Strings(c.Added)
+	sort.Strings(c.Removed)
+	sort.Strings(c.Changed)
+	return c
+}
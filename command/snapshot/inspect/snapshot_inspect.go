@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/consul/agent/consul/fsm"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/snapshot/keys"
 	"github.com/hashicorp/consul/snapshot"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/raft"
@@ -34,6 +35,7 @@ type cmd struct {
 	flags  *flag.FlagSet
 	help   string
 	format string
+	keys   keys.Flags
 
 	// flags
 	kvDetails bool
@@ -54,6 +56,8 @@ func (c *cmd) init() {
 		"format",
 		PrettyFormat,
 		fmt.Sprintf("Output format {%s}", strings.Join(GetSupportedFormats(), "|")))
+	flags.Merge(c.flags, c.keys.EncryptionFlags(false))
+	flags.Merge(c.flags, c.keys.VerifyFlags())
 
 	c.help = flags.Usage(help, c.flags)
 }
@@ -138,7 +142,20 @@ func (c *cmd) Run(args []string) int {
 		}
 		meta = &metaDecoded
 	} else {
-		readFile, meta, err = snapshot.Read(hclog.New(nil), f)
+		encryptionKey, err := c.keys.EncryptionKey(nil)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		verifyKey, err := c.keys.VerifyKey()
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		readFile, meta, err = snapshot.ReadWithKeys(hclog.New(nil), f, &snapshot.Keys{
+			EncryptionKey: encryptionKey,
+			VerifyKey:     verifyKey,
+		})
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error reading snapshot: %s", err))
 			return 1
@@ -367,6 +384,10 @@ Usage: consul snapshot inspect [options] FILE
   To inspect the file "backup.snap":
 
     $ consul snapshot inspect backup.snap
-  
+
+  To inspect an encrypted snapshot:
+
+    $ consul snapshot inspect -encrypt-key-file=snapshot.key backup.snap
+
   For a full list of options and examples, please see the Consul documentation.
 `
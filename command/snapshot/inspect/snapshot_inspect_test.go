@@ -4,6 +4,8 @@
 package inspect
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"flag"
 	"os"
 	"path/filepath"
@@ -12,6 +14,8 @@ import (
 
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/snapshot"
 )
 
 // update allows golden files to be updated based on the current output.
@@ -99,6 +103,45 @@ func TestSnapshotInspectCommand(t *testing.T) {
 	require.Equal(t, want, ui.OutputWriter.String())
 }
 
+func TestSnapshotInspectCommand_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+
+	key := make([]byte, snapshot.EncryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "snapshot.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600))
+
+	in, err := os.Open("./testdata/backup.snap")
+	require.NoError(t, err)
+	defer in.Close()
+	out, err := os.Create(filepath.Join(dir, "backup.snap"))
+	require.NoError(t, err)
+	require.NoError(t, snapshot.Seal(in, out, snapshot.SealOptions{EncryptionKey: key}))
+	require.NoError(t, out.Close())
+
+	t.Run("no key", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{out.Name()})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "snapshot is encrypted")
+	})
+
+	t.Run("key", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-encrypt-key-file=" + keyFile, out.Name()})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		// The output matches that of the plain snapshot.
+		want := golden(t, "TestSnapshotInspectCommand", "")
+		require.Equal(t, want, ui.OutputWriter.String())
+	})
+}
+
 func TestSnapshotInspectKVDetailsCommand(t *testing.T) {
 
 	filepath := "./testdata/backupWithKV.snap"
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package keys holds the flags shared by the snapshot commands for loading the
+// key material used to seal and open snapshot archives.
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/snapshot"
+)
+
+// EncryptKeyEnvName is the environment variable that can hold a base64
+// encoded snapshot encryption key.
+const EncryptKeyEnvName = "CONSUL_SNAPSHOT_ENCRYPT_KEY"
+
+// Flags holds the key flags of a snapshot command.
+type Flags struct {
+	encryptKey            string
+	encryptKeyFile        string
+	encryptKeyFromKeyring bool
+	signingKeyFile        string
+	verifyKeyFile         string
+}
+
+// EncryptionFlags returns the flags used to load an encryption key. The
+// -encrypt-key-from-keyring flag is only included if keyring is true, as it
+// requires access to an agent.
+func (f *Flags) EncryptionFlags(keyring bool) *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.StringVar(&f.encryptKey, "encrypt-key", "",
+		"Base64 encoded 32-byte key used to encrypt and decrypt the snapshot with "+
+			"AES-256-GCM. This can also be specified via the "+EncryptKeyEnvName+
+			" environment variable.")
+	fs.StringVar(&f.encryptKeyFile, "encrypt-key-file", "",
+		"Path to a file containing a base64 encoded 32-byte key used to encrypt "+
+			"and decrypt the snapshot.")
+	if keyring {
+		fs.BoolVar(&f.encryptKeyFromKeyring, "encrypt-key-from-keyring", false,
+			"Derive the snapshot encryption key from the primary gossip encryption "+
+				"key of the LAN keyring. Snapshots encrypted this way can't be read "+
+				"once that gossip key is removed from the keyring.")
+	}
+	return fs
+}
+
+// SigningFlags returns the flags used to load a signing key.
+func (f *Flags) SigningFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.StringVar(&f.signingKeyFile, "signing-key-file", "",
+		"Path to a PEM encoded PKCS #8 Ed25519 private key used to sign the snapshot.")
+	return fs
+}
+
+// VerifyFlags returns the flags used to load a signature verification key.
+func (f *Flags) VerifyFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.StringVar(&f.verifyKeyFile, "verify-key-file", "",
+		"Path to a PEM encoded PKIX Ed25519 public key. If set, the snapshot must "+
+			"carry a valid signature from the matching private key.")
+	return fs
+}
+
+// EncryptionKey returns the encryption key selected by the flags, or nil if
+// none was given. The client is only used to read the gossip keyring, and may
+// be nil if the keyring flag wasn't registered.
+func (f *Flags) EncryptionKey(client *api.Client) ([]byte, error) {
+	encoded := f.encryptKey
+	sources := 0
+	for _, set := range []bool{f.encryptKey != "", f.encryptKeyFile != "", f.encryptKeyFromKeyring} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, fmt.Errorf("Only one of -encrypt-key, -encrypt-key-file or -encrypt-key-from-keyring may be given")
+	}
+
+	switch {
+	case f.encryptKeyFromKeyring:
+		return keyringKey(client)
+	case f.encryptKeyFile != "":
+		data, err := os.ReadFile(f.encryptKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading encryption key file: %s", err)
+		}
+		encoded = string(data)
+	case encoded == "":
+		encoded = os.Getenv(EncryptKeyEnvName)
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding encryption key: %s", err)
+	}
+	if len(key) != snapshot.EncryptionKeySize {
+		return nil, fmt.Errorf("Encryption key must be %d bytes, got %d", snapshot.EncryptionKeySize, len(key))
+	}
+	return key, nil
+}
+
+// keyringKey derives an encryption key from the primary gossip key of the LAN
+// keyring.
+func keyringKey(client *api.Client) ([]byte, error) {
+	if client == nil {
+		return nil, fmt.Errorf("The gossip keyring can't be used without an agent")
+	}
+	responses, err := client.Operator().KeyringList(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing gossip keys: %s", err)
+	}
+
+	var primary []string
+	for _, resp := range responses {
+		if resp.WAN || resp.Segment != "" {
+			continue
+		}
+		keys := resp.PrimaryKeys
+		if len(keys) == 0 {
+			// Older agents don't report primary keys, which is only
+			// unambiguous if there's a single key installed.
+			keys = resp.Keys
+		}
+		for k := range keys {
+			primary = append(primary, k)
+		}
+	}
+	if len(primary) != 1 {
+		return nil, fmt.Errorf("Expected a single primary gossip key in the LAN keyring, found %d", len(primary))
+	}
+
+	gossipKey, err := base64.StdEncoding.DecodeString(primary[0])
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding gossip key: %s", err)
+	}
+	return snapshot.DeriveEncryptionKey(gossipKey)
+}
+
+// SigningKey returns the signing key selected by the flags, or nil if none
+// was given.
+func (f *Flags) SigningKey() (ed25519.PrivateKey, error) {
+	if f.signingKeyFile == "" {
+		return nil, nil
+	}
+	der, err := readPEM(f.signingKeyFile, "PRIVATE KEY")
+	if err != nil {
+		return nil, fmt.Errorf("Error reading signing key: %s", err)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing signing key: %s", err)
+	}
+	ed, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("Signing key must be an Ed25519 key, got %T", key)
+	}
+	return ed, nil
+}
+
+// VerifyKey returns the signature verification key selected by the flags, or
+// nil if none was given.
+func (f *Flags) VerifyKey() (ed25519.PublicKey, error) {
+	if f.verifyKeyFile == "" {
+		return nil, nil
+	}
+	der, err := readPEM(f.verifyKeyFile, "PUBLIC KEY")
+	if err != nil {
+		return nil, fmt.Errorf("Error reading verify key: %s", err)
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing verify key: %s", err)
+	}
+	ed, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("Verify key must be an Ed25519 key, got %T", key)
+	}
+	return ed, nil
+}
+
+func readPEM(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("no %q PEM block found in %s", blockType, path)
+	}
+	return block.Bytes, nil
+}
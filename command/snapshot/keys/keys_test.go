@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keys
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags_EncryptionKey(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	keyFile := filepath.Join(t.TempDir(), "snapshot.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(encoded+"\n"), 0600))
+
+	cases := map[string]struct {
+		args []string
+		env  string
+		key  []byte
+		err  string
+	}{
+		"none": {},
+		"flag": {
+			args: []string{"-encrypt-key=" + encoded},
+			key:  key,
+		},
+		"file": {
+			args: []string{"-encrypt-key-file=" + keyFile},
+			key:  key,
+		},
+		"env": {
+			env: encoded,
+			key: key,
+		},
+		"flag overrides env": {
+			args: []string{"-encrypt-key=" + encoded},
+			env:  "bogus",
+			key:  key,
+		},
+		"conflict": {
+			args: []string{"-encrypt-key=" + encoded, "-encrypt-key-file=" + keyFile},
+			err:  "Only one of",
+		},
+		"bad encoding": {
+			args: []string{"-encrypt-key=not base64!"},
+			err:  "Error decoding encryption key",
+		},
+		"bad size": {
+			args: []string{"-encrypt-key=" + base64.StdEncoding.EncodeToString(key[:16])},
+			err:  "Encryption key must be 32 bytes, got 16",
+		},
+		"keyring without agent": {
+			args: []string{"-encrypt-key-from-keyring"},
+			err:  "can't be used without an agent",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(EncryptKeyEnvName, tc.env)
+
+			var f Flags
+			require.NoError(t, f.EncryptionFlags(true).Parse(tc.args))
+
+			got, err := f.EncryptionKey(nil)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.key, got)
+		})
+	}
+}
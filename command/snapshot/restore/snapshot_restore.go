@@ -6,9 +6,13 @@ package restore
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/snapshot/keys"
+	"github.com/hashicorp/consul/snapshot"
 	"github.com/mitchellh/cli"
 )
 
@@ -22,14 +26,34 @@ type cmd struct {
 	UI    cli.Ui
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
+	keys  keys.Flags
 	help  string
+
+	// flags
+	kvPrefixes    flags.AppendSliceValue
+	configEntries flags.AppendSliceValue
+	acls          bool
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.Var(&c.kvPrefixes, "kv-prefix",
+		"Only restore the KV entries whose keys start with the given prefix, "+
+			"leaving the rest of the cluster's state untouched. An empty prefix "+
+			"restores every key. This flag may be specified multiple times.")
+	c.flags.Var(&c.configEntries, "config-entry",
+		"Only restore the config entries matching the given selector, in the form "+
+			"<kind> or <kind>/<name>, leaving the rest of the cluster's state untouched. "+
+			"A selector of \"*\" restores every config entry. This flag may be specified "+
+			"multiple times.")
+	c.flags.BoolVar(&c.acls, "acls", false,
+		"Only restore ACL tokens and policies, leaving the rest of the cluster's "+
+			"state untouched.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.keys.EncryptionFlags(true))
+	flags.Merge(c.flags, c.keys.VerifyFlags())
 	c.help = flags.Usage(help, c.flags)
 }
 
@@ -59,6 +83,17 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	encryptionKey, err := c.keys.EncryptionKey(client)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	verifyKey, err := c.keys.VerifyKey()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	// Open the file.
 	f, err := os.Open(file)
 	if err != nil {
@@ -67,6 +102,41 @@ func (c *cmd) Run(args []string) int {
 	}
 	defer f.Close()
 
+	// Servers only accept plain archives, so decrypt the snapshot and check
+	// its signature before sending it.
+	if len(encryptionKey) != 0 || verifyKey != nil {
+		opened, err := openFile(f, &snapshot.Keys{
+			EncryptionKey: encryptionKey,
+			VerifyKey:     verifyKey,
+		})
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error opening sealed snapshot: %s", err))
+			return 1
+		}
+		defer func() {
+			opened.Close()
+			os.Remove(opened.Name())
+		}()
+		f = opened
+	}
+
+	filter := &api.SnapshotRestoreFilter{
+		KVPrefixes:    c.kvPrefixes,
+		ConfigEntries: c.configEntries,
+		ACLs:          c.acls,
+	}
+	if len(filter.KVPrefixes) != 0 || len(filter.ConfigEntries) != 0 || filter.ACLs {
+		summary, err := client.Snapshot().RestoreFiltered(nil, f, filter)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error restoring snapshot: %s", err))
+			return 1
+		}
+
+		c.UI.Info(fmt.Sprintf("Restored %d KV entries, %d config entries, %d ACL tokens and %d ACL policies from snapshot",
+			summary.KV, summary.ConfigEntries, summary.ACLTokens, summary.ACLPolicies))
+		return 0
+	}
+
 	// Restore the snapshot.
 	err = client.Snapshot().Restore(nil, f)
 	if err != nil {
@@ -78,6 +148,26 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// openFile writes the plain archive inside a sealed snapshot to a temporary
+// file. The caller is responsible for removing the file.
+func openFile(in io.Reader, keys *snapshot.Keys) (*os.File, error) {
+	f, err := os.CreateTemp("", "snapshot")
+	if err != nil {
+		return nil, err
+	}
+	if err := snapshot.Open(in, f, keys); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return f, nil
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -106,5 +196,20 @@ Usage: consul snapshot restore [options] FILE
 
     $ consul snapshot restore backup.snap
 
+  Records can also be selectively replayed from a snapshot into a running
+  cluster, without replacing the rest of its state. Each selected record is
+  written in the same way as the KV, config entry and ACL APIs would write it.
+
+  To restore only the KV tree under "app/config/" and the service-intentions
+  config entry for "db":
+
+    $ consul snapshot restore -kv-prefix=app/config/ \
+        -config-entry=service-intentions/db backup.snap
+
+  To restore a snapshot that was encrypted and signed by "consul snapshot save":
+
+    $ consul snapshot restore -encrypt-key-file=snapshot.key \
+        -verify-key-file=signing.pub backup.snap
+
   For a full list of options and examples, please see the Consul documentation.
 `
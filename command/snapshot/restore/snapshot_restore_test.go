@@ -4,7 +4,11 @@
 package restore
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +19,7 @@ import (
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/snapshot"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
 )
@@ -110,6 +115,82 @@ func TestSnapshotRestoreCommand(t *testing.T) {
 	}
 }
 
+func TestSnapshotRestoreCommand_Sealed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	dir := testutil.TempDir(t, "snapshot")
+
+	key := make([]byte, snapshot.EncryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	verifyKeyFile := filepath.Join(dir, "signing.pub")
+	require.NoError(t, os.WriteFile(verifyKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	snap, _, err := client.Snapshot().Save(nil)
+	require.NoError(t, err)
+	defer snap.Close()
+
+	file := filepath.Join(dir, "backup.snap")
+	f, err := os.Create(file)
+	require.NoError(t, err)
+	require.NoError(t, snapshot.Seal(snap, f, snapshot.SealOptions{EncryptionKey: key, SigningKey: priv}))
+	require.NoError(t, f.Close())
+
+	t.Run("encrypted", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), file})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "snapshot is encrypted")
+	})
+
+	t.Run("wrong verify key", func(t *testing.T) {
+		other, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(other)
+		require.NoError(t, err)
+		otherFile := filepath.Join(dir, "other.pub")
+		require.NoError(t, os.WriteFile(otherFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+		ui := cli.NewMockUi()
+		c := New(ui)
+		code := c.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-encrypt-key=" + base64.StdEncoding.EncodeToString(key),
+			"-verify-key-file=" + otherFile,
+			file,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "snapshot signature is invalid")
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-encrypt-key=" + base64.StdEncoding.EncodeToString(key),
+			"-verify-key-file=" + verifyKeyFile,
+			file,
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+	})
+}
+
 func TestSnapshotRestoreCommand_TruncatedSnapshot(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -167,3 +248,57 @@ func TestSnapshotRestoreCommand_TruncatedSnapshot(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotRestoreCommand_Filtered(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+	kv := client.KV()
+
+	_, err := kv.Put(&api.KVPair{Key: "app/config", Value: []byte("before")}, nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "other", Value: []byte("before")}, nil)
+	require.NoError(t, err)
+
+	file := filepath.Join(testutil.TempDir(t, "snapshot"), "backup.tgz")
+	{
+		rc, _, err := client.Snapshot().Save(nil)
+		require.NoError(t, err)
+		defer rc.Close()
+
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(file, data, 0600))
+	}
+
+	_, err = kv.Delete("app/config", nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "other", Value: []byte("after")}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	code := c.Run([]string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-kv-prefix=app/",
+		file,
+	})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Restored 1 KV entries, 0 config entries, 0 ACL tokens and 0 ACL policies")
+
+	pair, _, err := kv.Get("app/config", nil)
+	require.NoError(t, err)
+	require.NotNil(t, pair)
+	require.Equal(t, "before", string(pair.Value))
+
+	// Keys outside of the prefix are left alone.
+	pair, _, err = kv.Get("other", nil)
+	require.NoError(t, err)
+	require.Equal(t, "after", string(pair.Value))
+}
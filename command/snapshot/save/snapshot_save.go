@@ -4,6 +4,7 @@
 package save
 
 import (
+	"crypto/ed25519"
 	"flag"
 	"fmt"
 	"os"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/snapshot/keys"
 	"github.com/hashicorp/consul/snapshot"
 )
 
@@ -26,6 +28,7 @@ type cmd struct {
 	UI    cli.Ui
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
+	keys  keys.Flags
 	help  string
 }
 
@@ -34,6 +37,8 @@ func (c *cmd) init() {
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.keys.EncryptionFlags(true))
+	flags.Merge(c.flags, c.keys.SigningFlags())
 	c.help = flags.Usage(help, c.flags)
 }
 
@@ -63,6 +68,17 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	encryptionKey, err := c.keys.EncryptionKey(client)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	signingKey, err := c.keys.SigningKey()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	// Take the snapshot.
 	snap, qm, err := client.Snapshot().Save(&api.QueryOptions{
 		AllowStale: c.http.Stale(),
@@ -97,6 +113,20 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if len(encryptionKey) != 0 || signingKey != nil {
+		opts := snapshot.SealOptions{
+			EncryptionKey: encryptionKey,
+			SigningKey:    signingKey,
+		}
+		sealedFile := file + ".sealed"
+		defer os.Remove(sealedFile)
+		if err := sealFile(unverifiedFile, sealedFile, opts); err != nil {
+			c.UI.Error(fmt.Sprintf("Error sealing snapshot file: %s", err))
+			return 1
+		}
+		unverifiedFile = sealedFile
+	}
+
 	if err := safeio.Rename(unverifiedFile, file); err != nil {
 		c.UI.Error(fmt.Sprintf("Error renaming %q to %q: %v", unverifiedFile, file, err))
 		return 1
@@ -106,6 +136,45 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// sealFile writes a sealed copy of the snapshot archive at src to dst, and
+// reads it back to verify it.
+func sealFile(src, dst string, opts snapshot.SealOptions) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := snapshot.Seal(in, out, opts); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	keys := &snapshot.Keys{EncryptionKey: opts.EncryptionKey}
+	if opts.SigningKey != nil {
+		keys.VerifyKey = opts.SigningKey.Public().(ed25519.PublicKey)
+	}
+	_, err = snapshot.VerifyWithKeys(f, keys)
+	return err
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -134,5 +203,11 @@ Usage: consul snapshot save [options] FILE
 
     $ consul snapshot save -stale backup.snap
 
+  Snapshots contain secrets such as ACL tokens and CA private keys. To encrypt
+  the snapshot with a key from a file, and sign it, before storing it elsewhere:
+
+    $ consul snapshot save -encrypt-key-file=snapshot.key \
+        -signing-key-file=signing.pem backup.snap
+
   For a full list of options and examples, please see the Consul documentation.
 `
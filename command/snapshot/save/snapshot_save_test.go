@@ -4,7 +4,11 @@
 package save
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,6 +26,7 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/snapshot"
 )
 
 func TestSnapshotSaveCommand_noTabs(t *testing.T) {
@@ -112,6 +117,57 @@ func TestSnapshotSaveCommand(t *testing.T) {
 	}
 }
 
+func TestSnapshotSaveCommand_Sealed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	dir := testutil.TempDir(t, "snapshot")
+
+	key := make([]byte, snapshot.EncryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "snapshot.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600))
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	signingKeyFile := filepath.Join(dir, "signing.pem")
+	require.NoError(t, os.WriteFile(signingKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	file := filepath.Join(dir, "backup.snap")
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-encrypt-key-file=" + keyFile,
+		"-signing-key-file=" + signingKeyFile,
+		file,
+	}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = snapshot.Verify(f)
+	require.ErrorIs(t, err, snapshot.ErrEncrypted)
+
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	_, err = snapshot.VerifyWithKeys(f, &snapshot.Keys{EncryptionKey: key, VerifyKey: pub})
+	require.NoError(t, err)
+}
+
 func TestSnapshotSaveCommand_TruncatedStream(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -50,6 +50,10 @@ Usage: consul snapshot <subcommand> [options] [args]
 
       $ consul snapshot inspect backup.snap
 
+  Compare two snapshots:
+
+      $ consul snapshot diff backup-0200.snap backup-0300.snap
+
   Run a daemon process that locally saves a snapshot every hour (available only in
   Consul Enterprise) :
 
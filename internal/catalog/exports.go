@@ -4,7 +4,12 @@
 package catalog
 
 import (
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers"
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/endpoints"
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/nodehealth"
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/workloadhealth"
 	"github.com/hashicorp/consul/internal/catalog/internal/types"
+	"github.com/hashicorp/consul/internal/controller"
 	"github.com/hashicorp/consul/internal/resource"
 )
 
@@ -43,3 +48,20 @@ var (
 func RegisterTypes(r resource.Registry) {
 	types.Register(r)
 }
+
+var (
+	// Controller Statuses
+
+	NodeHealthStatusKey                  = nodehealth.StatusKey
+	NodeHealthStatusConditionHealthy     = nodehealth.StatusConditionHealthy
+	WorkloadHealthStatusKey              = workloadhealth.StatusKey
+	WorkloadHealthStatusConditionHealthy = workloadhealth.StatusConditionHealthy
+	EndpointsStatusKey                   = endpoints.StatusKey
+	EndpointsStatusConditionManaged      = endpoints.StatusConditionEndpointsManaged
+)
+
+// RegisterControllers registers controllers for the catalog types with
+// the given controller Manager.
+func RegisterControllers(mgr *controller.Manager) {
+	controllers.Register(mgr)
+}
@@ -0,0 +1,343 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package endpoints
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/nodehealth"
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/workloadhealth"
+	"github.com/hashicorp/consul/internal/catalog/internal/types"
+	"github.com/hashicorp/consul/internal/controller"
+	"github.com/hashicorp/consul/internal/resource"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+const (
+	// StatusKey is the key under which the endpoints controller writes its
+	// status to Service resources.
+	StatusKey = "consul.io/endpoint-manager"
+
+	StatusConditionEndpointsManaged = "EndpointsManaged"
+
+	StatusReasonSelectorFound    = "SelectorFound"
+	StatusReasonSelectorNotFound = "SelectorNotFound"
+
+	SelectorFoundMessage    = "A valid workload selector is present within the service."
+	SelectorNotFoundMessage = "Either the workload selector was not present or contained no selection criteria."
+)
+
+// ServiceEndpointsController computes the ServiceEndpoints for each Service
+// from the Workloads matched by its selector. The ServiceEndpoints resource
+// has the same name as the Service and is owned by it, so it is deleted by the
+// reaper when the Service is deleted.
+func ServiceEndpointsController() controller.Controller {
+	return controller.ForType(types.ServiceType).
+		WithWatch(types.ServiceEndpointsType, controller.MapOwnerFiltered(types.ServiceType)).
+		WithWatch(types.WorkloadType, mapWorkloadToServices).
+		WithReconciler(&serviceEndpointsReconciler{})
+}
+
+type serviceEndpointsReconciler struct{}
+
+func (r *serviceEndpointsReconciler) Reconcile(ctx context.Context, rt controller.Runtime, req controller.Request) error {
+	rt.Logger.Trace("reconciling service endpoints", "id", req.ID)
+
+	rsp, err := rt.Client.Read(ctx, &pbresource.ReadRequest{Id: req.ID})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		// The owned ServiceEndpoints will be deleted by the reaper.
+		return nil
+	case err != nil:
+		return err
+	}
+	res := rsp.Resource
+
+	var service pbcatalog.Service
+	if err := res.Data.UnmarshalTo(&service); err != nil {
+		return err
+	}
+
+	endpointsID := &pbresource.ID{
+		Type:    types.ServiceEndpointsType,
+		Tenancy: res.Id.Tenancy,
+		Name:    res.Id.Name,
+	}
+	existing, err := readEndpoints(ctx, rt, endpointsID)
+	if err != nil {
+		return err
+	}
+
+	var condition *pbresource.Condition
+	if selectorIsEmpty(service.Workloads) {
+		// Endpoints for services without a selector aren't managed by Consul,
+		// so remove any we previously wrote.
+		if existing != nil && resource.EqualID(existing.Owner, res.Id) {
+			if _, err := rt.Client.Delete(ctx, &pbresource.DeleteRequest{Id: existing.Id}); err != nil {
+				return err
+			}
+		}
+		condition = &pbresource.Condition{
+			Type:    StatusConditionEndpointsManaged,
+			State:   pbresource.Condition_STATE_FALSE,
+			Reason:  StatusReasonSelectorNotFound,
+			Message: SelectorNotFoundMessage,
+		}
+	} else {
+		workloads, err := selectWorkloads(ctx, rt, res.Id.Tenancy, service.Workloads)
+		if err != nil {
+			return err
+		}
+
+		if err := writeEndpoints(ctx, rt, res.Id, existing, endpointsID, buildEndpoints(&service, workloads)); err != nil {
+			return err
+		}
+		condition = &pbresource.Condition{
+			Type:    StatusConditionEndpointsManaged,
+			State:   pbresource.Condition_STATE_TRUE,
+			Reason:  StatusReasonSelectorFound,
+			Message: SelectorFoundMessage,
+		}
+	}
+
+	newStatus := &pbresource.Status{
+		ObservedGeneration: res.Generation,
+		Conditions:         []*pbresource.Condition{condition},
+	}
+	if resource.EqualStatus(res.Status[StatusKey], newStatus) {
+		return nil
+	}
+
+	_, err = rt.Client.WriteStatus(ctx, &pbresource.WriteStatusRequest{
+		Id:     res.Id,
+		Key:    StatusKey,
+		Status: newStatus,
+	})
+	return err
+}
+
+func readEndpoints(ctx context.Context, rt controller.Runtime, id *pbresource.ID) (*pbresource.Resource, error) {
+	rsp, err := rt.Client.Read(ctx, &pbresource.ReadRequest{Id: id})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return rsp.Resource, nil
+}
+
+func writeEndpoints(
+	ctx context.Context,
+	rt controller.Runtime,
+	serviceID *pbresource.ID,
+	existing *pbresource.Resource,
+	id *pbresource.ID,
+	endpoints *pbcatalog.ServiceEndpoints,
+) error {
+	if existing != nil {
+		var current pbcatalog.ServiceEndpoints
+		if err := existing.Data.UnmarshalTo(&current); err == nil && proto.Equal(&current, endpoints) {
+			return nil
+		}
+	}
+
+	data, err := anypb.New(endpoints)
+	if err != nil {
+		return err
+	}
+
+	res := &pbresource.Resource{
+		Id:    id,
+		Owner: serviceID,
+		Data:  data,
+	}
+	if existing != nil {
+		res.Id = existing.Id
+		res.Version = existing.Version
+	}
+
+	_, err = rt.Client.Write(ctx, &pbresource.WriteRequest{Resource: res})
+	return err
+}
+
+// selectWorkloads returns the workloads matched by the selector, sorted by
+// name.
+func selectWorkloads(ctx context.Context, rt controller.Runtime, tenancy *pbresource.Tenancy, sel *pbcatalog.WorkloadSelector) ([]*pbresource.Resource, error) {
+	seen := make(map[string]*pbresource.Resource)
+
+	for _, name := range sel.Names {
+		rsp, err := rt.Client.Read(ctx, &pbresource.ReadRequest{
+			Id: &pbresource.ID{
+				Type:    types.WorkloadType,
+				Tenancy: tenancy,
+				Name:    name,
+			},
+		})
+		switch {
+		case status.Code(err) == codes.NotFound:
+			continue
+		case err != nil:
+			return nil, err
+		}
+		seen[name] = rsp.Resource
+	}
+
+	for _, prefix := range sel.Prefixes {
+		rsp, err := rt.Client.List(ctx, &pbresource.ListRequest{
+			Type:       types.WorkloadType,
+			Tenancy:    tenancy,
+			NamePrefix: prefix,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range rsp.Resources {
+			seen[w.Id.Name] = w
+		}
+	}
+
+	workloads := make([]*pbresource.Resource, 0, len(seen))
+	for _, w := range seen {
+		workloads = append(workloads, w)
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		return workloads[i].Id.Name < workloads[j].Id.Name
+	})
+	return workloads, nil
+}
+
+// buildEndpoints creates an endpoint for every workload. When the service
+// declares ports, only the workload ports they target are included.
+func buildEndpoints(service *pbcatalog.Service, workloads []*pbresource.Resource) *pbcatalog.ServiceEndpoints {
+	targetPorts := make(map[string]struct{})
+	for _, p := range service.Ports {
+		if p.TargetPort != "" {
+			targetPorts[p.TargetPort] = struct{}{}
+		}
+	}
+	includePort := func(name string) bool {
+		if len(targetPorts) == 0 {
+			return true
+		}
+		_, ok := targetPorts[name]
+		return ok
+	}
+
+	endpoints := &pbcatalog.ServiceEndpoints{}
+	for _, res := range workloads {
+		var workload pbcatalog.Workload
+		if err := res.Data.UnmarshalTo(&workload); err != nil {
+			continue
+		}
+
+		ports := make(map[string]*pbcatalog.WorkloadPort)
+		for name, port := range workload.Ports {
+			if includePort(name) {
+				ports[name] = port
+			}
+		}
+		if len(ports) == 0 {
+			// The workload doesn't expose any of the ports the service
+			// routes to.
+			continue
+		}
+
+		var addresses []*pbcatalog.WorkloadAddress
+		for _, addr := range workload.Addresses {
+			// An address without explicit ports serves all of the workload's
+			// ports.
+			if len(addr.Ports) == 0 {
+				addresses = append(addresses, addr)
+				continue
+			}
+
+			var addrPorts []string
+			for _, name := range addr.Ports {
+				if _, ok := ports[name]; ok {
+					addrPorts = append(addrPorts, name)
+				}
+			}
+			if len(addrPorts) == 0 {
+				continue
+			}
+			addresses = append(addresses, &pbcatalog.WorkloadAddress{
+				Host:     addr.Host,
+				Ports:    addrPorts,
+				External: addr.External,
+			})
+		}
+
+		health, err := nodehealth.HealthFromStatus(res, workloadhealth.StatusKey)
+		if err != nil {
+			// Until the workload health controller has run, don't send
+			// traffic to the workload.
+			health = pbcatalog.Health_HEALTH_CRITICAL
+		}
+
+		endpoints.Endpoints = append(endpoints.Endpoints, &pbcatalog.Endpoint{
+			TargetRef:    res.Id,
+			Addresses:    addresses,
+			Ports:        ports,
+			HealthStatus: health,
+		})
+	}
+	return endpoints
+}
+
+// mapWorkloadToServices returns a request for every service whose selector
+// matches the changed workload.
+//
+// TODO: this lists every service in the workload's tenancy. Cache selections
+// if this becomes a bottleneck.
+func mapWorkloadToServices(ctx context.Context, rt controller.Runtime, res *pbresource.Resource) ([]controller.Request, error) {
+	rsp, err := rt.Client.List(ctx, &pbresource.ListRequest{
+		Type:    types.ServiceType,
+		Tenancy: res.Id.Tenancy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var reqs []controller.Request
+	for _, svc := range rsp.Resources {
+		var service pbcatalog.Service
+		if err := svc.Data.UnmarshalTo(&service); err != nil {
+			return nil, err
+		}
+		if selectorMatches(service.Workloads, res.Id.Name) {
+			reqs = append(reqs, controller.Request{ID: svc.Id})
+		}
+	}
+	return reqs, nil
+}
+
+func selectorIsEmpty(sel *pbcatalog.WorkloadSelector) bool {
+	return sel == nil || (len(sel.Names) == 0 && len(sel.Prefixes) == 0)
+}
+
+func selectorMatches(sel *pbcatalog.WorkloadSelector, name string) bool {
+	if sel == nil {
+		return false
+	}
+	for _, n := range sel.Names {
+		if n == name {
+			return true
+		}
+	}
+	for _, p := range sel.Prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
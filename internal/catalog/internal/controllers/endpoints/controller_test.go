@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package endpoints
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	svctest "github.com/hashicorp/consul/agent/grpc-external/services/resource/testing"
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/nodehealth"
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/workloadhealth"
+	"github.com/hashicorp/consul/internal/catalog/internal/types"
+	"github.com/hashicorp/consul/internal/controller"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+	"github.com/hashicorp/consul/proto/private/prototest"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+var defaultTenancy = &pbresource.Tenancy{
+	Partition: "default",
+	Namespace: "default",
+	PeerName:  "local",
+}
+
+func writeResource(t *testing.T, client pbresource.ResourceServiceClient, typ *pbresource.Type, name string, data proto.Message) *pbresource.Resource {
+	t.Helper()
+
+	a, err := anypb.New(data)
+	require.NoError(t, err)
+
+	rsp, err := client.Write(testutil.TestContext(t), &pbresource.WriteRequest{
+		Resource: &pbresource.Resource{
+			Id:   &pbresource.ID{Type: typ, Tenancy: defaultTenancy, Name: name},
+			Data: a,
+		},
+	})
+	require.NoError(t, err)
+	return rsp.Resource
+}
+
+func withWorkloadHealth(res *pbresource.Resource, health pbcatalog.Health) *pbresource.Resource {
+	res = proto.Clone(res).(*pbresource.Resource)
+	res.Status = map[string]*pbresource.Status{
+		workloadhealth.StatusKey: {
+			Conditions: []*pbresource.Condition{
+				nodehealth.Condition(health, workloadhealth.WorkloadHealthyMessage, workloadhealth.WorkloadUnhealthyMessage),
+			},
+		},
+	}
+	return res
+}
+
+func newWorkload(t *testing.T, name string, w *pbcatalog.Workload) *pbresource.Resource {
+	data, err := anypb.New(w)
+	require.NoError(t, err)
+	return &pbresource.Resource{
+		Id:   &pbresource.ID{Type: types.WorkloadType, Tenancy: defaultTenancy, Name: name},
+		Data: data,
+	}
+}
+
+func TestBuildEndpoints(t *testing.T) {
+	api1 := withWorkloadHealth(newWorkload(t, "api-1", &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{
+			{Host: "10.0.0.1"},
+			{Host: "10.0.0.2", Ports: []string{"http", "admin"}},
+			{Host: "10.0.0.3", Ports: []string{"admin"}},
+		},
+		Ports: map[string]*pbcatalog.WorkloadPort{
+			"http":  {Port: 8080, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+			"admin": {Port: 9090, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+		},
+	}), pbcatalog.Health_HEALTH_WARNING)
+
+	// No health has been computed for this workload.
+	api2 := newWorkload(t, "api-2", &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.1.1"}},
+		Ports: map[string]*pbcatalog.WorkloadPort{
+			"http": {Port: 8080, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+		},
+	})
+
+	// Doesn't expose the targeted port.
+	api3 := withWorkloadHealth(newWorkload(t, "api-3", &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.2.1"}},
+		Ports: map[string]*pbcatalog.WorkloadPort{
+			"admin": {Port: 9090, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+		},
+	}), pbcatalog.Health_HEALTH_PASSING)
+
+	service := &pbcatalog.Service{
+		Workloads: &pbcatalog.WorkloadSelector{Prefixes: []string{"api-"}},
+		Ports:     []*pbcatalog.ServicePort{{VirtualPort: 80, TargetPort: "http"}},
+	}
+
+	expected := &pbcatalog.ServiceEndpoints{
+		Endpoints: []*pbcatalog.Endpoint{
+			{
+				TargetRef: api1.Id,
+				Addresses: []*pbcatalog.WorkloadAddress{
+					{Host: "10.0.0.1"},
+					{Host: "10.0.0.2", Ports: []string{"http"}},
+				},
+				Ports: map[string]*pbcatalog.WorkloadPort{
+					"http": {Port: 8080, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+				},
+				HealthStatus: pbcatalog.Health_HEALTH_WARNING,
+			},
+			{
+				TargetRef: api2.Id,
+				Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.1.1"}},
+				Ports: map[string]*pbcatalog.WorkloadPort{
+					"http": {Port: 8080, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+				},
+				HealthStatus: pbcatalog.Health_HEALTH_CRITICAL,
+			},
+		},
+	}
+	prototest.AssertDeepEqual(t, expected, buildEndpoints(service, []*pbresource.Resource{api1, api2, api3}))
+
+	// Without any service ports, all of the workload's ports are included.
+	service.Ports = nil
+	endpoints := buildEndpoints(service, []*pbresource.Resource{api3})
+	require.Len(t, endpoints.Endpoints, 1)
+	require.Contains(t, endpoints.Endpoints[0].Ports, "admin")
+}
+
+func TestServiceEndpointsReconcile(t *testing.T) {
+	client := svctest.RunResourceService(t, types.Register)
+	ctx := testutil.TestContext(t)
+	rt := controller.Runtime{Client: client, Logger: testutil.Logger(t)}
+	rec := &serviceEndpointsReconciler{}
+
+	workload := &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+		Ports: map[string]*pbcatalog.WorkloadPort{
+			"http": {Port: 8080, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+		},
+	}
+	api1 := writeResource(t, client, types.WorkloadType, "api-1", workload)
+	api2 := writeResource(t, client, types.WorkloadType, "api-2", workload)
+	writeResource(t, client, types.WorkloadType, "web-1", workload)
+
+	service := writeResource(t, client, types.ServiceType, "api", &pbcatalog.Service{
+		Workloads: &pbcatalog.WorkloadSelector{
+			Prefixes: []string{"api-"},
+			Names:    []string{"api-1", "missing"},
+		},
+	})
+
+	endpointsID := &pbresource.ID{Type: types.ServiceEndpointsType, Tenancy: defaultTenancy, Name: "api"}
+
+	require.NoError(t, rec.Reconcile(ctx, rt, controller.Request{ID: service.Id}))
+
+	rsp, err := client.Read(ctx, &pbresource.ReadRequest{Id: endpointsID})
+	require.NoError(t, err)
+	prototest.AssertDeepEqual(t, service.Id, rsp.Resource.Owner)
+
+	var endpoints pbcatalog.ServiceEndpoints
+	require.NoError(t, rsp.Resource.Data.UnmarshalTo(&endpoints))
+	require.Len(t, endpoints.Endpoints, 2)
+	prototest.AssertDeepEqual(t, api1.Id, endpoints.Endpoints[0].TargetRef)
+	prototest.AssertDeepEqual(t, api2.Id, endpoints.Endpoints[1].TargetRef)
+
+	svcRsp, err := client.Read(ctx, &pbresource.ReadRequest{Id: service.Id})
+	require.NoError(t, err)
+	cond := svcRsp.Resource.Status[StatusKey].Conditions[0]
+	require.Equal(t, StatusConditionEndpointsManaged, cond.Type)
+	require.Equal(t, pbresource.Condition_STATE_TRUE, cond.State)
+
+	// Reconciling again without changes shouldn't rewrite the endpoints.
+	require.NoError(t, rec.Reconcile(ctx, rt, controller.Request{ID: service.Id}))
+	again, err := client.Read(ctx, &pbresource.ReadRequest{Id: endpointsID})
+	require.NoError(t, err)
+	require.Equal(t, rsp.Resource.Version, again.Resource.Version)
+
+	// Removing the selector removes the endpoints.
+	service = writeResource(t, client, types.ServiceType, "api", &pbcatalog.Service{})
+	require.NoError(t, rec.Reconcile(ctx, rt, controller.Request{ID: service.Id}))
+
+	_, err = client.Read(ctx, &pbresource.ReadRequest{Id: endpointsID})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	svcRsp, err = client.Read(ctx, &pbresource.ReadRequest{Id: service.Id})
+	require.NoError(t, err)
+	cond = svcRsp.Resource.Status[StatusKey].Conditions[0]
+	require.Equal(t, pbresource.Condition_STATE_FALSE, cond.State)
+	require.Equal(t, StatusReasonSelectorNotFound, cond.Reason)
+}
+
+func TestMapWorkloadToServices(t *testing.T) {
+	client := svctest.RunResourceService(t, types.Register)
+	ctx := testutil.TestContext(t)
+
+	byPrefix := writeResource(t, client, types.ServiceType, "by-prefix", &pbcatalog.Service{
+		Workloads: &pbcatalog.WorkloadSelector{Prefixes: []string{"api-"}},
+	})
+	byName := writeResource(t, client, types.ServiceType, "by-name", &pbcatalog.Service{
+		Workloads: &pbcatalog.WorkloadSelector{Names: []string{"api-1"}},
+	})
+	writeResource(t, client, types.ServiceType, "other", &pbcatalog.Service{
+		Workloads: &pbcatalog.WorkloadSelector{Names: []string{"web-1"}},
+	})
+	writeResource(t, client, types.ServiceType, "no-selector", &pbcatalog.Service{})
+
+	reqs, err := mapWorkloadToServices(ctx, controller.Runtime{Client: client}, newWorkload(t, "api-1", &pbcatalog.Workload{}))
+	require.NoError(t, err)
+
+	var ids []*pbresource.ID
+	for _, req := range reqs {
+		ids = append(ids, req.ID)
+	}
+	prototest.AssertElementsMatch(t, []*pbresource.ID{byPrefix.Id, byName.Id}, ids)
+}
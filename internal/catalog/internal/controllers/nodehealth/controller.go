@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nodehealth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/internal/catalog/internal/types"
+	"github.com/hashicorp/consul/internal/controller"
+	"github.com/hashicorp/consul/internal/resource"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+// NodeHealthController rolls up the HealthStatus resources owned by each Node
+// into a single health value, recorded as a condition on the Node's status.
+func NodeHealthController() controller.Controller {
+	return controller.ForType(types.NodeType).
+		WithWatch(types.HealthStatusType, controller.MapOwnerFiltered(types.NodeType)).
+		WithReconciler(&nodeHealthReconciler{})
+}
+
+type nodeHealthReconciler struct{}
+
+func (r *nodeHealthReconciler) Reconcile(ctx context.Context, rt controller.Runtime, req controller.Request) error {
+	rt.Logger.Trace("reconciling node health", "id", req.ID)
+
+	rsp, err := rt.Client.Read(ctx, &pbresource.ReadRequest{Id: req.ID})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		// The HealthStatus resources owned by the node will be cleaned up by
+		// the reaper, so there is nothing to do.
+		return nil
+	case err != nil:
+		return err
+	}
+	res := rsp.Resource
+
+	health, err := OwnedHealth(ctx, rt.Client, res.Id)
+	if err != nil {
+		return err
+	}
+
+	newStatus := &pbresource.Status{
+		ObservedGeneration: res.Generation,
+		Conditions: []*pbresource.Condition{
+			Condition(health, NodeHealthyMessage, NodeUnhealthyMessage),
+		},
+	}
+
+	if resource.EqualStatus(res.Status[StatusKey], newStatus) {
+		return nil
+	}
+
+	_, err = rt.Client.WriteStatus(ctx, &pbresource.WriteStatusRequest{
+		Id:     res.Id,
+		Key:    StatusKey,
+		Status: newStatus,
+	})
+	return err
+}
+
+// OwnedHealth returns the worst health of the HealthStatus resources owned by
+// the resource with the given ID, or passing if it doesn't own any.
+func OwnedHealth(ctx context.Context, client pbresource.ResourceServiceClient, owner *pbresource.ID) (pbcatalog.Health, error) {
+	rsp, err := client.ListByOwner(ctx, &pbresource.ListByOwnerRequest{Owner: owner})
+	if err != nil {
+		return pbcatalog.Health_HEALTH_ANY, err
+	}
+
+	health := pbcatalog.Health_HEALTH_PASSING
+	for _, child := range rsp.Resources {
+		if !resource.EqualType(child.Id.Type, types.HealthStatusType) {
+			continue
+		}
+
+		var hs pbcatalog.HealthStatus
+		if err := child.Data.UnmarshalTo(&hs); err != nil {
+			return pbcatalog.Health_HEALTH_ANY, err
+		}
+		health = WorstHealth(health, hs.Status)
+	}
+	return health, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nodehealth
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	svctest "github.com/hashicorp/consul/agent/grpc-external/services/resource/testing"
+	"github.com/hashicorp/consul/internal/catalog/internal/types"
+	"github.com/hashicorp/consul/internal/controller"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+var defaultTenancy = &pbresource.Tenancy{
+	Partition: "default",
+	Namespace: "default",
+	PeerName:  "local",
+}
+
+func writeResource(t *testing.T, client pbresource.ResourceServiceClient, typ *pbresource.Type, name string, data proto.Message, owner *pbresource.ID) *pbresource.Resource {
+	t.Helper()
+
+	a, err := anypb.New(data)
+	require.NoError(t, err)
+
+	rsp, err := client.Write(testutil.TestContext(t), &pbresource.WriteRequest{
+		Resource: &pbresource.Resource{
+			Id:    &pbresource.ID{Type: typ, Tenancy: defaultTenancy, Name: name},
+			Owner: owner,
+			Data:  a,
+		},
+	})
+	require.NoError(t, err)
+	return rsp.Resource
+}
+
+func TestNodeHealthReconcile(t *testing.T) {
+	cases := map[string]struct {
+		statuses []pbcatalog.Health
+		expected pbcatalog.Health
+	}{
+		"no checks": {
+			expected: pbcatalog.Health_HEALTH_PASSING,
+		},
+		"passing": {
+			statuses: []pbcatalog.Health{pbcatalog.Health_HEALTH_PASSING, pbcatalog.Health_HEALTH_PASSING},
+			expected: pbcatalog.Health_HEALTH_PASSING,
+		},
+		"warning": {
+			statuses: []pbcatalog.Health{pbcatalog.Health_HEALTH_PASSING, pbcatalog.Health_HEALTH_WARNING},
+			expected: pbcatalog.Health_HEALTH_WARNING,
+		},
+		"critical": {
+			statuses: []pbcatalog.Health{pbcatalog.Health_HEALTH_CRITICAL, pbcatalog.Health_HEALTH_WARNING},
+			expected: pbcatalog.Health_HEALTH_CRITICAL,
+		},
+		"maintenance": {
+			statuses: []pbcatalog.Health{pbcatalog.Health_HEALTH_CRITICAL, pbcatalog.Health_HEALTH_MAINTENANCE},
+			expected: pbcatalog.Health_HEALTH_MAINTENANCE,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := svctest.RunResourceService(t, types.Register)
+			ctx := testutil.TestContext(t)
+
+			node := writeResource(t, client, types.NodeType, "node-1", &pbcatalog.Node{
+				Addresses: []*pbcatalog.NodeAddress{{Host: "127.0.0.1"}},
+			}, nil)
+			for i, h := range tc.statuses {
+				writeResource(t, client, types.HealthStatusType, fmt.Sprintf("%s-check-%d", node.Id.Name, i), &pbcatalog.HealthStatus{
+					Type:   "check",
+					Status: h,
+				}, node.Id)
+			}
+
+			rt := controller.Runtime{Client: client, Logger: testutil.Logger(t)}
+			require.NoError(t, (&nodeHealthReconciler{}).Reconcile(ctx, rt, controller.Request{ID: node.Id}))
+
+			rsp, err := client.Read(ctx, &pbresource.ReadRequest{Id: node.Id})
+			require.NoError(t, err)
+
+			health, err := HealthFromStatus(rsp.Resource, StatusKey)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, health)
+
+			cond := rsp.Resource.Status[StatusKey].Conditions[0]
+			if tc.expected == pbcatalog.Health_HEALTH_PASSING {
+				require.Equal(t, pbresource.Condition_STATE_TRUE, cond.State)
+				require.Equal(t, NodeHealthyMessage, cond.Message)
+			} else {
+				require.Equal(t, pbresource.Condition_STATE_FALSE, cond.State)
+				require.Equal(t, NodeUnhealthyMessage, cond.Message)
+			}
+
+			// Reconciling again should be a no-op.
+			version := rsp.Resource.Version
+			require.NoError(t, (&nodeHealthReconciler{}).Reconcile(ctx, rt, controller.Request{ID: node.Id}))
+			rsp, err = client.Read(ctx, &pbresource.ReadRequest{Id: node.Id})
+			require.NoError(t, err)
+			require.Equal(t, version, rsp.Resource.Version)
+		})
+	}
+}
+
+func TestNodeHealthReconcile_NotFound(t *testing.T) {
+	client := svctest.RunResourceService(t, types.Register)
+	rt := controller.Runtime{Client: client, Logger: testutil.Logger(t)}
+
+	err := (&nodeHealthReconciler{}).Reconcile(testutil.TestContext(t), rt, controller.Request{
+		ID: &pbresource.ID{Type: types.NodeType, Tenancy: defaultTenancy, Name: "missing"},
+	})
+	require.NoError(t, err)
+}
+
+func TestHealthFromStatus(t *testing.T) {
+	res := &pbresource.Resource{
+		Id: &pbresource.ID{Name: "node-1"},
+		Status: map[string]*pbresource.Status{
+			StatusKey: {Conditions: []*pbresource.Condition{
+				Condition(pbcatalog.Health_HEALTH_WARNING, NodeHealthyMessage, NodeUnhealthyMessage),
+			}},
+		},
+	}
+	health, err := HealthFromStatus(res, StatusKey)
+	require.NoError(t, err)
+	require.Equal(t, pbcatalog.Health_HEALTH_WARNING, health)
+
+	_, err = HealthFromStatus(res, "other-key")
+	require.Error(t, err)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nodehealth
+
+import (
+	"fmt"
+
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+const (
+	// StatusKey is the key under which the node health status is written to
+	// Node resources.
+	StatusKey = "consul.io/node-health"
+
+	// StatusConditionHealthy is the type of the condition describing the
+	// node's health. Its reason is always the name of a pbcatalog.Health value
+	// (e.g. HEALTH_PASSING), so that other controllers can recover the
+	// aggregated health without recomputing it.
+	StatusConditionHealthy = "healthy"
+
+	NodeHealthyMessage   = "All node health checks are passing"
+	NodeUnhealthyMessage = "One or more node health checks are not passing"
+)
+
+// Condition returns the status condition that reports the given health.
+func Condition(health pbcatalog.Health, healthyMessage, unhealthyMessage string) *pbresource.Condition {
+	state := pbresource.Condition_STATE_FALSE
+	msg := unhealthyMessage
+	if health == pbcatalog.Health_HEALTH_PASSING {
+		state = pbresource.Condition_STATE_TRUE
+		msg = healthyMessage
+	}
+
+	return &pbresource.Condition{
+		Type:    StatusConditionHealthy,
+		State:   state,
+		Reason:  health.String(),
+		Message: msg,
+	}
+}
+
+// HealthFromStatus returns the health recorded in the healthy condition of
+// the status written under key, or an error if it hasn't been computed yet.
+func HealthFromStatus(res *pbresource.Resource, key string) (pbcatalog.Health, error) {
+	if st, ok := res.Status[key]; ok {
+		for _, cond := range st.Conditions {
+			if cond.Type != StatusConditionHealthy {
+				continue
+			}
+			if health, ok := pbcatalog.Health_value[cond.Reason]; ok {
+				return pbcatalog.Health(health), nil
+			}
+		}
+	}
+	return pbcatalog.Health_HEALTH_ANY, fmt.Errorf("%s status has not been computed for %s", key, res.Id.Name)
+}
+
+// WorstHealth returns the more severe of the two health values. Unset (ANY)
+// values are ignored.
+func WorstHealth(a, b pbcatalog.Health) pbcatalog.Health {
+	if b > a {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	svctest "github.com/hashicorp/consul/agent/grpc-external/services/resource/testing"
+	"github.com/hashicorp/consul/internal/catalog/internal/types"
+	"github.com/hashicorp/consul/internal/controller"
+	"github.com/hashicorp/consul/internal/resource/reaper"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+func TestControllers_Integration(t *testing.T) {
+	client := svctest.RunResourceService(t, types.Register)
+	ctx := testutil.TestContext(t)
+
+	mgr := controller.NewManager(client, testutil.Logger(t))
+	Register(mgr)
+	reaper.RegisterControllers(mgr)
+	mgr.SetRaftLeader(true)
+	go mgr.Run(ctx)
+
+	tenancy := &pbresource.Tenancy{Partition: "default", Namespace: "default", PeerName: "local"}
+	write := func(typ *pbresource.Type, name string, data proto.Message, owner *pbresource.ID) *pbresource.Resource {
+		a, err := anypb.New(data)
+		require.NoError(t, err)
+		rsp, err := client.Write(ctx, &pbresource.WriteRequest{
+			Resource: &pbresource.Resource{
+				Id:    &pbresource.ID{Type: typ, Tenancy: tenancy, Name: name},
+				Owner: owner,
+				Data:  a,
+			},
+		})
+		require.NoError(t, err)
+		return rsp.Resource
+	}
+
+	node := write(types.NodeType, "node-1", &pbcatalog.Node{
+		Addresses: []*pbcatalog.NodeAddress{{Host: "127.0.0.1"}},
+	}, nil)
+	workload := write(types.WorkloadType, "api-1", &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+		Ports: map[string]*pbcatalog.WorkloadPort{
+			"http": {Port: 8080, Protocol: pbcatalog.Protocol_PROTOCOL_HTTP},
+		},
+		NodeName: "node-1",
+	}, nil)
+	service := write(types.ServiceType, "api", &pbcatalog.Service{
+		Workloads: &pbcatalog.WorkloadSelector{Prefixes: []string{"api-"}},
+	}, nil)
+
+	endpointsID := &pbresource.ID{Type: types.ServiceEndpointsType, Tenancy: tenancy, Name: "api"}
+	requireEndpointHealth := func(expected pbcatalog.Health) {
+		t.Helper()
+		retry.Run(t, func(r *retry.R) {
+			rsp, err := client.Read(ctx, &pbresource.ReadRequest{Id: endpointsID})
+			require.NoError(r, err)
+
+			var endpoints pbcatalog.ServiceEndpoints
+			require.NoError(r, rsp.Resource.Data.UnmarshalTo(&endpoints))
+			require.Len(r, endpoints.Endpoints, 1)
+			require.Equal(r, workload.Id.Name, endpoints.Endpoints[0].TargetRef.Name)
+			require.Equal(r, expected, endpoints.Endpoints[0].HealthStatus)
+		})
+	}
+
+	// Without any checks the node and workload are passing.
+	requireEndpointHealth(pbcatalog.Health_HEALTH_PASSING)
+
+	// A failing node check makes the workload's endpoint critical.
+	write(types.HealthStatusType, "node-1-serf", &pbcatalog.HealthStatus{
+		Type:   "serf",
+		Status: pbcatalog.Health_HEALTH_CRITICAL,
+	}, node.Id)
+	requireEndpointHealth(pbcatalog.Health_HEALTH_CRITICAL)
+
+	write(types.HealthStatusType, "node-1-serf", &pbcatalog.HealthStatus{
+		Type:   "serf",
+		Status: pbcatalog.Health_HEALTH_PASSING,
+	}, node.Id)
+	requireEndpointHealth(pbcatalog.Health_HEALTH_PASSING)
+
+	// A warning workload check is reflected in the endpoint.
+	write(types.HealthStatusType, "api-1-http", &pbcatalog.HealthStatus{
+		Type:   "http",
+		Status: pbcatalog.Health_HEALTH_WARNING,
+	}, workload.Id)
+	requireEndpointHealth(pbcatalog.Health_HEALTH_WARNING)
+
+	// Deleting the service causes the reaper to delete the endpoints it owns.
+	_, err := client.Delete(ctx, &pbresource.DeleteRequest{Id: service.Id})
+	require.NoError(t, err)
+
+	retry.Run(t, func(r *retry.R) {
+		_, err := client.Read(ctx, &pbresource.ReadRequest{Id: endpointsID})
+		require.Equal(r, codes.NotFound, status.Code(err))
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package workloadhealth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/nodehealth"
+	"github.com/hashicorp/consul/internal/catalog/internal/types"
+	"github.com/hashicorp/consul/internal/controller"
+	"github.com/hashicorp/consul/internal/resource"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+const (
+	// StatusKey is the key under which the workload health status is written
+	// to Workload resources. The healthy condition it contains follows the
+	// same conventions as the node health status.
+	StatusKey = "consul.io/workload-health"
+
+	// StatusConditionHealthy is the type of the condition describing the
+	// health of a workload.
+	StatusConditionHealthy = nodehealth.StatusConditionHealthy
+
+	WorkloadHealthyMessage   = "All workload health checks are passing"
+	WorkloadUnhealthyMessage = "One or more workload health checks are not passing"
+	NodeUnhealthyMessage     = "The workload's node is not healthy"
+	NodeNotFoundMessage      = "The workload's node does not exist"
+)
+
+// WorkloadHealthController rolls up the HealthStatus resources owned by each
+// Workload, along with the health of the Node the workload runs on, into a
+// single health value recorded as a condition on the Workload's status.
+func WorkloadHealthController() controller.Controller {
+	return controller.ForType(types.WorkloadType).
+		WithWatch(types.HealthStatusType, controller.MapOwnerFiltered(types.WorkloadType)).
+		WithWatch(types.NodeType, mapNodeToWorkloads).
+		WithReconciler(&workloadHealthReconciler{})
+}
+
+type workloadHealthReconciler struct{}
+
+func (r *workloadHealthReconciler) Reconcile(ctx context.Context, rt controller.Runtime, req controller.Request) error {
+	rt.Logger.Trace("reconciling workload health", "id", req.ID)
+
+	rsp, err := rt.Client.Read(ctx, &pbresource.ReadRequest{Id: req.ID})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+	res := rsp.Resource
+
+	var workload pbcatalog.Workload
+	if err := res.Data.UnmarshalTo(&workload); err != nil {
+		return err
+	}
+
+	health, err := nodehealth.OwnedHealth(ctx, rt.Client, res.Id)
+	if err != nil {
+		return err
+	}
+	condition := nodehealth.Condition(health, WorkloadHealthyMessage, WorkloadUnhealthyMessage)
+
+	if workload.NodeName != "" {
+		nodeHealth, msg, err := getNodeHealth(ctx, rt, &pbresource.ID{
+			Type:    types.NodeType,
+			Tenancy: res.Id.Tenancy,
+			Name:    workload.NodeName,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The workload's own checks take precedence when they're at least as
+		// severe, otherwise the node's health is reported.
+		if nodeHealth > health {
+			condition = nodehealth.Condition(nodeHealth, WorkloadHealthyMessage, msg)
+		}
+	}
+
+	newStatus := &pbresource.Status{
+		ObservedGeneration: res.Generation,
+		Conditions:         []*pbresource.Condition{condition},
+	}
+
+	if resource.EqualStatus(res.Status[StatusKey], newStatus) {
+		return nil
+	}
+
+	_, err = rt.Client.WriteStatus(ctx, &pbresource.WriteStatusRequest{
+		Id:     res.Id,
+		Key:    StatusKey,
+		Status: newStatus,
+	})
+	return err
+}
+
+// getNodeHealth returns the health of the given node and the message to use
+// if it is the reason the workload is unhealthy. A missing node, or one whose
+// health hasn't been computed yet, is considered critical.
+func getNodeHealth(ctx context.Context, rt controller.Runtime, id *pbresource.ID) (pbcatalog.Health, string, error) {
+	rsp, err := rt.Client.Read(ctx, &pbresource.ReadRequest{Id: id})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		return pbcatalog.Health_HEALTH_CRITICAL, NodeNotFoundMessage, nil
+	case err != nil:
+		return pbcatalog.Health_HEALTH_ANY, "", err
+	}
+
+	health, err := nodehealth.HealthFromStatus(rsp.Resource, nodehealth.StatusKey)
+	if err != nil {
+		// The node health controller will update the node's status shortly,
+		// which in turn will cause this workload to be reconciled again.
+		return pbcatalog.Health_HEALTH_CRITICAL, NodeUnhealthyMessage, nil
+	}
+	return health, NodeUnhealthyMessage, nil
+}
+
+// mapNodeToWorkloads returns a request for every workload placed on the
+// changed node.
+func mapNodeToWorkloads(ctx context.Context, rt controller.Runtime, res *pbresource.Resource) ([]controller.Request, error) {
+	rsp, err := rt.Client.List(ctx, &pbresource.ListRequest{
+		Type:    types.WorkloadType,
+		Tenancy: res.Id.Tenancy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var reqs []controller.Request
+	for _, w := range rsp.Resources {
+		var workload pbcatalog.Workload
+		if err := w.Data.UnmarshalTo(&workload); err != nil {
+			return nil, err
+		}
+		if workload.NodeName == res.Id.Name {
+			reqs = append(reqs, controller.Request{ID: w.Id})
+		}
+	}
+	return reqs, nil
+}
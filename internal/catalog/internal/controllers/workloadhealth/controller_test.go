@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package workloadhealth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	svctest "github.com/hashicorp/consul/agent/grpc-external/services/resource/testing"
+	"github.com/hashicorp/consul/internal/catalog/internal/controllers/nodehealth"
+	"github.com/hashicorp/consul/internal/catalog/internal/types"
+	"github.com/hashicorp/consul/internal/controller"
+	pbcatalog "github.com/hashicorp/consul/proto-public/pbcatalog/v1alpha1"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+	"github.com/hashicorp/consul/proto/private/prototest"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+var defaultTenancy = &pbresource.Tenancy{
+	Partition: "default",
+	Namespace: "default",
+	PeerName:  "local",
+}
+
+func writeResource(t *testing.T, client pbresource.ResourceServiceClient, typ *pbresource.Type, name string, data proto.Message, owner *pbresource.ID) *pbresource.Resource {
+	t.Helper()
+
+	a, err := anypb.New(data)
+	require.NoError(t, err)
+
+	rsp, err := client.Write(testutil.TestContext(t), &pbresource.WriteRequest{
+		Resource: &pbresource.Resource{
+			Id:    &pbresource.ID{Type: typ, Tenancy: defaultTenancy, Name: name},
+			Owner: owner,
+			Data:  a,
+		},
+	})
+	require.NoError(t, err)
+	return rsp.Resource
+}
+
+func writeNodeHealth(t *testing.T, client pbresource.ResourceServiceClient, node *pbresource.Resource, health pbcatalog.Health) {
+	t.Helper()
+
+	_, err := client.WriteStatus(testutil.TestContext(t), &pbresource.WriteStatusRequest{
+		Id:  node.Id,
+		Key: nodehealth.StatusKey,
+		Status: &pbresource.Status{
+			ObservedGeneration: node.Generation,
+			Conditions: []*pbresource.Condition{
+				nodehealth.Condition(health, nodehealth.NodeHealthyMessage, nodehealth.NodeUnhealthyMessage),
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func reconcileWorkload(t *testing.T, ctx context.Context, client pbresource.ResourceServiceClient, id *pbresource.ID) *pbresource.Condition {
+	t.Helper()
+
+	rt := controller.Runtime{Client: client, Logger: testutil.Logger(t)}
+	require.NoError(t, (&workloadHealthReconciler{}).Reconcile(ctx, rt, controller.Request{ID: id}))
+
+	rsp, err := client.Read(ctx, &pbresource.ReadRequest{Id: id})
+	require.NoError(t, err)
+	require.Len(t, rsp.Resource.Status[StatusKey].Conditions, 1)
+	return rsp.Resource.Status[StatusKey].Conditions[0]
+}
+
+func TestWorkloadHealthReconcile(t *testing.T) {
+	cases := map[string]struct {
+		workloadHealth []pbcatalog.Health
+		nodeHealth     pbcatalog.Health
+		noNode         bool
+		expected       pbcatalog.Health
+		message        string
+	}{
+		"no checks": {
+			noNode:   true,
+			expected: pbcatalog.Health_HEALTH_PASSING,
+			message:  WorkloadHealthyMessage,
+		},
+		"workload critical": {
+			workloadHealth: []pbcatalog.Health{pbcatalog.Health_HEALTH_PASSING, pbcatalog.Health_HEALTH_CRITICAL},
+			nodeHealth:     pbcatalog.Health_HEALTH_WARNING,
+			expected:       pbcatalog.Health_HEALTH_CRITICAL,
+			message:        WorkloadUnhealthyMessage,
+		},
+		"node critical": {
+			workloadHealth: []pbcatalog.Health{pbcatalog.Health_HEALTH_WARNING},
+			nodeHealth:     pbcatalog.Health_HEALTH_CRITICAL,
+			expected:       pbcatalog.Health_HEALTH_CRITICAL,
+			message:        NodeUnhealthyMessage,
+		},
+		"node passing": {
+			workloadHealth: []pbcatalog.Health{pbcatalog.Health_HEALTH_PASSING},
+			nodeHealth:     pbcatalog.Health_HEALTH_PASSING,
+			expected:       pbcatalog.Health_HEALTH_PASSING,
+			message:        WorkloadHealthyMessage,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := svctest.RunResourceService(t, types.Register)
+			ctx := testutil.TestContext(t)
+
+			workload := &pbcatalog.Workload{
+				Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+			}
+			if !tc.noNode {
+				node := writeResource(t, client, types.NodeType, "node-1", &pbcatalog.Node{
+					Addresses: []*pbcatalog.NodeAddress{{Host: "127.0.0.1"}},
+				}, nil)
+				writeNodeHealth(t, client, node, tc.nodeHealth)
+				workload.NodeName = node.Id.Name
+			}
+
+			res := writeResource(t, client, types.WorkloadType, "api-1", workload, nil)
+			for i, h := range tc.workloadHealth {
+				writeResource(t, client, types.HealthStatusType, fmt.Sprintf("api-1-check-%d", i), &pbcatalog.HealthStatus{
+					Type:   "check",
+					Status: h,
+				}, res.Id)
+			}
+
+			cond := reconcileWorkload(t, ctx, client, res.Id)
+			require.Equal(t, tc.expected.String(), cond.Reason)
+			require.Equal(t, tc.message, cond.Message)
+		})
+	}
+}
+
+func TestWorkloadHealthReconcile_NodeMissing(t *testing.T) {
+	client := svctest.RunResourceService(t, types.Register)
+	ctx := testutil.TestContext(t)
+
+	res := writeResource(t, client, types.WorkloadType, "api-1", &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+		NodeName:  "missing",
+	}, nil)
+
+	cond := reconcileWorkload(t, ctx, client, res.Id)
+	require.Equal(t, pbresource.Condition_STATE_FALSE, cond.State)
+	require.Equal(t, pbcatalog.Health_HEALTH_CRITICAL.String(), cond.Reason)
+	require.Equal(t, NodeNotFoundMessage, cond.Message)
+}
+
+func TestMapNodeToWorkloads(t *testing.T) {
+	client := svctest.RunResourceService(t, types.Register)
+	ctx := testutil.TestContext(t)
+
+	node := writeResource(t, client, types.NodeType, "node-1", &pbcatalog.Node{
+		Addresses: []*pbcatalog.NodeAddress{{Host: "127.0.0.1"}},
+	}, nil)
+	onNode := writeResource(t, client, types.WorkloadType, "api-1", &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.1"}},
+		NodeName:  "node-1",
+	}, nil)
+	writeResource(t, client, types.WorkloadType, "api-2", &pbcatalog.Workload{
+		Addresses: []*pbcatalog.WorkloadAddress{{Host: "10.0.0.2"}},
+		NodeName:  "node-2",
+	}, nil)
+
+	reqs, err := mapNodeToWorkloads(ctx, controller.Runtime{Client: client}, node)
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	prototest.AssertDeepEqual(t, onNode.Id, reqs[0].ID)
+}
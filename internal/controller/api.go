@@ -169,6 +169,18 @@ func MapOwner(_ context.Context, _ Runtime, res *pbresource.Resource) ([]Request
 	return reqs, nil
 }
 
+// MapOwnerFiltered returns a DependencyMapper that behaves like MapOwner, but
+// only when the owner is of the given type. It is useful when a watched type
+// may be owned by resources of several types.
+func MapOwnerFiltered(filter *pbresource.Type) DependencyMapper {
+	return func(_ context.Context, _ Runtime, res *pbresource.Resource) ([]Request, error) {
+		if res.Owner == nil || !resource.EqualType(res.Owner.Type, filter) {
+			return nil, nil
+		}
+		return []Request{{ID: res.Owner}}, nil
+	}
+}
+
 // Placement determines where and how many replicas of the controller will run.
 type Placement int
 
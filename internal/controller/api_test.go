@@ -205,6 +205,33 @@ func TestController_NoReconciler(t *testing.T) {
 		func() { mgr.Register(ctrl) })
 }
 
+func TestMapOwnerFiltered(t *testing.T) {
+	t.Parallel()
+
+	mapper := controller.MapOwnerFiltered(demo.TypeV2Artist)
+
+	ownerID := &pbresource.ID{
+		Type:    demo.TypeV2Artist,
+		Tenancy: demo.TenancyDefault,
+		Name:    "artist",
+	}
+
+	reqs, err := mapper(testContext(t), controller.Runtime{}, &pbresource.Resource{Owner: ownerID})
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	prototest.AssertDeepEqual(t, ownerID, reqs[0].ID)
+
+	reqs, err = mapper(testContext(t), controller.Runtime{}, &pbresource.Resource{
+		Owner: &pbresource.ID{Type: demo.TypeV2Album, Tenancy: demo.TenancyDefault, Name: "album"},
+	})
+	require.NoError(t, err)
+	require.Empty(t, reqs)
+
+	reqs, err = mapper(testContext(t), controller.Runtime{}, &pbresource.Resource{})
+	require.NoError(t, err)
+	require.Empty(t, reqs)
+}
+
 func newTestReconciler() *testReconciler {
 	return &testReconciler{
 		calls:  make(chan controller.Request),
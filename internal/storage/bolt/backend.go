@@ -0,0 +1,386 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/consul/internal/storage"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+// NewBackend opens (or creates) a bbolt database at the given path and returns
+// a durable, single-node storage backend backed by it.
+//
+// Unlike the inmem backend, resources are read from disk rather than held in
+// memory, and they survive process restarts. It's suitable for development
+// mode and testing, but has no support for replication, so it should NOT be
+// used by a clustered server.
+//
+// You must call Close when you're done with the backend.
+func NewBackend(path string) (*Backend, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{bucketResources, bucketOwners, bucketMetadata} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+
+	return &Backend{
+		db:      db,
+		watches: make(map[*Watch]struct{}),
+	}, nil
+}
+
+// Backend is a storage backend implementation that persists resources in a
+// local bbolt database.
+type Backend struct {
+	db *bbolt.DB
+
+	// mu serializes writes with the dispatching of their events, and with the
+	// creation of watches, so that every watch observes a consistent snapshot
+	// followed by every later change, in order, with no gaps or duplicates.
+	//
+	// Events are dispatched *after* the transaction is committed to provide
+	// monotonic reads between Watch and Read calls.
+	mu      sync.Mutex
+	watches map[*Watch]struct{}
+	closed  bool
+}
+
+// Close closes all open watches and the underlying database.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	for w := range b.watches {
+		w.close()
+	}
+	b.watches = nil
+
+	return b.db.Close()
+}
+
+// Read implements the storage.Backend interface.
+func (b *Backend) Read(_ context.Context, _ storage.ReadConsistency, id *pbresource.ID) (*pbresource.Resource, error) {
+	var res *pbresource.Resource
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		res, err = getResource(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, storage.ErrNotFound
+	}
+
+	// Observe the Uid if it was given.
+	if id.Uid != "" && res.Id.Uid != id.Uid {
+		return nil, storage.ErrNotFound
+	}
+
+	// Let the caller know they need to upgrade/downgrade the schema version.
+	if id.Type.GroupVersion != res.Id.Type.GroupVersion {
+		return nil, storage.GroupVersionMismatchError{
+			RequestedType: id.Type,
+			Stored:        res,
+		}
+	}
+
+	return res, nil
+}
+
+// WriteCAS implements the storage.Backend interface.
+func (b *Backend) WriteCAS(_ context.Context, res *pbresource.Resource) (*pbresource.Resource, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stored := proto.Clone(res).(*pbresource.Resource)
+
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		existing, err := getResource(tx, res.Id)
+		if err != nil {
+			return err
+		}
+
+		// Callers provide an empty version string on initial resource creation.
+		if existing == nil && res.Version != "" {
+			return storage.ErrCASFailure
+		}
+
+		if existing != nil {
+			// Uid is immutable.
+			if existing.Id.Uid != res.Id.Uid {
+				return storage.ErrWrongUid
+			}
+
+			// Ensure CAS semantics.
+			if existing.Version != res.Version {
+				return storage.ErrCASFailure
+			}
+
+			if existing.Owner != nil {
+				if err := tx.Bucket(bucketOwners).Delete(ownerKey(existing.Owner, existing.Id)); err != nil {
+					return err
+				}
+			}
+		}
+
+		vsn, err := nextVersion(tx)
+		if err != nil {
+			return err
+		}
+		stored.Version = strconv.FormatUint(vsn, 10)
+
+		val, err := proto.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketResources).Put(keyFromID(stored.Id, false), val); err != nil {
+			return err
+		}
+
+		if stored.Owner != nil {
+			if err := tx.Bucket(bucketOwners).Put(ownerKey(stored.Owner, stored.Id), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b.publish(pbresource.WatchEvent_OPERATION_UPSERT, stored)
+
+	return stored, nil
+}
+
+// DeleteCAS implements the storage.Backend interface.
+func (b *Backend) DeleteCAS(_ context.Context, id *pbresource.ID, version string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var deleted *pbresource.Resource
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		existing, err := getResource(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Deleting an already deleted resource is a no-op.
+		if existing == nil {
+			return nil
+		}
+
+		// Deleting a resource using a previous Uid is a no-op.
+		if existing.Id.Uid != id.Uid {
+			return nil
+		}
+
+		// Ensure CAS semantics.
+		if existing.Version != version {
+			return storage.ErrCASFailure
+		}
+
+		if err := tx.Bucket(bucketResources).Delete(keyFromID(existing.Id, false)); err != nil {
+			return err
+		}
+
+		if existing.Owner != nil {
+			if err := tx.Bucket(bucketOwners).Delete(ownerKey(existing.Owner, existing.Id)); err != nil {
+				return err
+			}
+		}
+
+		deleted = existing
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted != nil {
+		b.publish(pbresource.WatchEvent_OPERATION_DELETE, deleted)
+	}
+	return nil
+}
+
+// List implements the storage.Backend interface.
+func (b *Backend) List(_ context.Context, _ storage.ReadConsistency, resType storage.UnversionedType, tenancy *pbresource.Tenancy, namePrefix string) ([]*pbresource.Resource, error) {
+	var list []*pbresource.Resource
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		list, err = listTxn(tx, query{resType, tenancy, namePrefix})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func listTxn(tx *bbolt.Tx, q query) ([]*pbresource.Resource, error) {
+	prefix := q.keyPrefix()
+
+	list := make([]*pbresource.Resource, 0)
+	c := tx.Bucket(bucketResources).Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		res, err := unmarshalResource(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if q.matches(res) {
+			list = append(list, res)
+		}
+	}
+	return list, nil
+}
+
+// WatchList implements the storage.Backend interface.
+func (b *Backend) WatchList(_ context.Context, resType storage.UnversionedType, tenancy *pbresource.Tenancy, namePrefix string) (storage.Watch, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, storage.ErrWatchClosed
+	}
+
+	q := query{
+		resourceType: resType,
+		tenancy:      tenancy,
+		namePrefix:   namePrefix,
+	}
+
+	var snapshot []*pbresource.Resource
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		snapshot, err = listTxn(tx, q)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w := newWatch(b, q, snapshot)
+	b.watches[w] = struct{}{}
+
+	return w, nil
+}
+
+// ListByOwner implements the storage.Backend interface.
+func (b *Backend) ListByOwner(_ context.Context, id *pbresource.ID) ([]*pbresource.Resource, error) {
+	var list []*pbresource.Resource
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		prefix := keyFromID(id, true)
+		resources := tx.Bucket(bucketResources)
+
+		c := tx.Bucket(bucketOwners).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			v := resources.Get(k[len(prefix):])
+			if v == nil {
+				continue
+			}
+
+			res, err := unmarshalResource(v)
+			if err != nil {
+				return err
+			}
+			list = append(list, res)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// publish dispatches an event to all of the watches whose query matches the
+// given resource. Watches that have fallen too far behind are closed and
+// removed. Callers must hold b.mu.
+func (b *Backend) publish(op pbresource.WatchEvent_Operation, res *pbresource.Resource) {
+	for w := range b.watches {
+		if !w.query.matches(res) {
+			continue
+		}
+		ok := w.push(&pbresource.WatchEvent{
+			Operation: op,
+			Resource:  res,
+		})
+		if !ok {
+			delete(b.watches, w)
+		}
+	}
+}
+
+func (b *Backend) removeWatch(w *Watch) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.watches, w)
+}
+
+func getResource(tx *bbolt.Tx, id *pbresource.ID) (*pbresource.Resource, error) {
+	v := tx.Bucket(bucketResources).Get(keyFromID(id, false))
+	if v == nil {
+		return nil, nil
+	}
+	return unmarshalResource(v)
+}
+
+func unmarshalResource(v []byte) (*pbresource.Resource, error) {
+	var res pbresource.Resource
+	if err := proto.Unmarshal(v, &res); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+	return &res, nil
+}
+
+// nextVersion increments and returns the persisted version counter, so that
+// versions remain unique across restarts.
+func nextVersion(tx *bbolt.Tx) (uint64, error) {
+	meta := tx.Bucket(bucketMetadata)
+
+	var vsn uint64
+	if v := meta.Get(metaKeyVersion); v != nil {
+		vsn = binary.BigEndian.Uint64(v)
+	}
+	vsn++
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, vsn)
+	if err := meta.Put(metaKeyVersion, buf); err != nil {
+		return 0, err
+	}
+	return vsn, nil
+}
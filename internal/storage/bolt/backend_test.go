@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bolt_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/internal/storage"
+	"github.com/hashicorp/consul/internal/storage/bolt"
+	"github.com/hashicorp/consul/internal/storage/conformance"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+	"github.com/hashicorp/consul/proto/private/prototest"
+)
+
+func TestBackend_Conformance(t *testing.T) {
+	conformance.Test(t, conformance.TestOptions{
+		NewBackend: func(t *testing.T) storage.Backend {
+			backend, err := bolt.NewBackend(filepath.Join(t.TempDir(), "resources.db"))
+			require.NoError(t, err)
+			t.Cleanup(func() { backend.Close() })
+
+			return backend
+		},
+		SupportsStronglyConsistentList: true,
+	})
+}
+
+func TestBackend_Persistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "resources.db")
+
+	typ := &pbresource.Type{Group: "test", GroupVersion: "v1", Kind: "a"}
+	tenancy := &pbresource.Tenancy{Partition: "default", PeerName: "local", Namespace: "default"}
+
+	backend, err := bolt.NewBackend(path)
+	require.NoError(t, err)
+
+	owner, err := backend.WriteCAS(ctx, &pbresource.Resource{
+		Id: &pbresource.ID{Type: typ, Tenancy: tenancy, Name: "owner", Uid: "a"},
+	})
+	require.NoError(t, err)
+
+	child, err := backend.WriteCAS(ctx, &pbresource.Resource{
+		Id:    &pbresource.ID{Type: typ, Tenancy: tenancy, Name: "child", Uid: "b"},
+		Owner: owner.Id,
+	})
+	require.NoError(t, err)
+	require.NoError(t, backend.Close())
+
+	backend, err = bolt.NewBackend(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { backend.Close() })
+
+	res, err := backend.Read(ctx, storage.StrongConsistency, owner.Id)
+	require.NoError(t, err)
+	prototest.AssertDeepEqual(t, owner, res)
+
+	owned, err := backend.ListByOwner(ctx, owner.Id)
+	require.NoError(t, err)
+	prototest.AssertElementsMatch(t, []*pbresource.Resource{child}, owned)
+
+	// Versions must not be reused after a restart.
+	updated, err := backend.WriteCAS(ctx, owner)
+	require.NoError(t, err)
+	require.NotEqual(t, owner.Version, updated.Version)
+	require.NotEqual(t, child.Version, updated.Version)
+}
+
+func TestBackend_WatchClosedOnClose(t *testing.T) {
+	backend, err := bolt.NewBackend(filepath.Join(t.TempDir(), "resources.db"))
+	require.NoError(t, err)
+
+	watch, err := backend.WatchList(context.Background(),
+		storage.UnversionedType{Group: "test", Kind: "a"},
+		&pbresource.Tenancy{Partition: storage.Wildcard, PeerName: storage.Wildcard, Namespace: storage.Wildcard},
+		"",
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, backend.Close())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	_, err = watch.Next(ctx)
+	require.ErrorIs(t, err, storage.ErrWatchClosed)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bolt
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/hashicorp/consul/internal/storage"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+var (
+	// bucketResources holds the resources themselves, keyed by their ID (without
+	// the Uid) and stored as marshaled pbresource.Resource messages.
+	bucketResources = []byte("resources")
+
+	// bucketOwners is an index of resources by their owner. Its keys are the
+	// owner's ID (including the Uid) followed by the owned resource's ID, and
+	// its values are empty.
+	bucketOwners = []byte("owners")
+
+	// bucketMetadata holds internal bookkeeping, such as the version counter.
+	bucketMetadata = []byte("metadata")
+
+	metaKeyVersion = []byte("version")
+)
+
+// keySeparator delimits the segments of our keys.
+const keySeparator = "\x00"
+
+// Our resource keys are structured like so:
+//
+//	<group><kind><partition><peer><namespace><name>
+//
+// Where each segment is followed by a NULL terminator. This mirrors the radix
+// tree keys used by the inmem backend, and means bbolt's byte-ordered cursors
+// can serve list queries with a prefix scan.
+func keyFromType(t storage.UnversionedType) []byte {
+	var b keyBuilder
+	b.String(t.Group)
+	b.String(t.Kind)
+	return b.Bytes()
+}
+
+func keyFromID(id *pbresource.ID, includeUid bool) []byte {
+	var b keyBuilder
+	b.Raw(keyFromType(storage.UnversionedTypeFrom(id.Type)))
+	b.String(id.Tenancy.Partition)
+	b.String(id.Tenancy.PeerName)
+	b.String(id.Tenancy.Namespace)
+	b.String(id.Name)
+	if includeUid {
+		b.String(id.Uid)
+	}
+	return b.Bytes()
+}
+
+func ownerKey(owner, id *pbresource.ID) []byte {
+	var b keyBuilder
+	b.Raw(keyFromID(owner, true))
+	b.Raw(keyFromID(id, false))
+	return b.Bytes()
+}
+
+type keyBuilder bytes.Buffer
+
+func (k *keyBuilder) Raw(v []byte) {
+	(*bytes.Buffer)(k).Write(v)
+}
+
+func (k *keyBuilder) String(s string) {
+	(*bytes.Buffer)(k).WriteString(s)
+	(*bytes.Buffer)(k).WriteString(keySeparator)
+}
+
+func (k *keyBuilder) Bytes() []byte {
+	return (*bytes.Buffer)(k).Bytes()
+}
+
+type query struct {
+	resourceType storage.UnversionedType
+	tenancy      *pbresource.Tenancy
+	namePrefix   string
+}
+
+// keyPrefix returns the key prefix to scan for the given query. Wildcarded
+// tenancy fields end the prefix, and any later segments must be filtered
+// using the matches method.
+func (q query) keyPrefix() []byte {
+	var b keyBuilder
+	b.Raw(keyFromType(q.resourceType))
+
+	for _, v := range []string{q.tenancy.Partition, q.tenancy.PeerName, q.tenancy.Namespace} {
+		if v == storage.Wildcard {
+			return b.Bytes()
+		}
+		b.String(v)
+	}
+
+	if q.namePrefix != "" {
+		b.Raw([]byte(q.namePrefix))
+	}
+	return b.Bytes()
+}
+
+// matches reports whether the given resource satisfies the query.
+func (q query) matches(res *pbresource.Resource) bool {
+	if res.Id.Type.Group != q.resourceType.Group || res.Id.Type.Kind != q.resourceType.Kind {
+		return false
+	}
+
+	if q.tenancy.Partition != storage.Wildcard && res.Id.Tenancy.Partition != q.tenancy.Partition {
+		return false
+	}
+
+	if q.tenancy.PeerName != storage.Wildcard && res.Id.Tenancy.PeerName != q.tenancy.PeerName {
+		return false
+	}
+
+	if q.tenancy.Namespace != storage.Wildcard && res.Id.Tenancy.Namespace != q.tenancy.Namespace {
+		return false
+	}
+
+	if len(q.namePrefix) != 0 && !strings.HasPrefix(res.Id.Name, q.namePrefix) {
+		return false
+	}
+
+	return true
+}
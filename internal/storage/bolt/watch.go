@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bolt
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/consul/internal/storage"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+// watchBufferSize is the maximum number of events buffered for a watch, on
+// top of its initial snapshot. A watcher that falls further behind has its
+// watch closed, and must re-establish it, rather than growing the buffer
+// without limit.
+const watchBufferSize = 1024
+
+// Watch implements the storage.Watch interface. Events are buffered in memory
+// until they are consumed by calling Next.
+type Watch struct {
+	backend *Backend
+	query   query
+
+	mu     sync.Mutex
+	events []*pbresource.WatchEvent
+	closed bool
+
+	// limit is the maximum number of buffered events.
+	limit int
+
+	// notifyCh is signaled (without blocking) whenever an event is pushed or
+	// the watch is closed.
+	notifyCh chan struct{}
+}
+
+func newWatch(backend *Backend, q query, snapshot []*pbresource.Resource) *Watch {
+	w := &Watch{
+		backend:  backend,
+		query:    q,
+		events:   make([]*pbresource.WatchEvent, len(snapshot)),
+		limit:    len(snapshot) + watchBufferSize,
+		notifyCh: make(chan struct{}, 1),
+	}
+	for i, res := range snapshot {
+		w.events[i] = &pbresource.WatchEvent{
+			Operation: pbresource.WatchEvent_OPERATION_UPSERT,
+			Resource:  res,
+		}
+	}
+	return w
+}
+
+// Next returns the next WatchEvent, blocking until one is available.
+func (w *Watch) Next(ctx context.Context) (*pbresource.WatchEvent, error) {
+	for {
+		w.mu.Lock()
+		if len(w.events) != 0 {
+			event := w.events[0]
+			w.events[0] = nil
+			w.events = w.events[1:]
+			w.mu.Unlock()
+			return event, nil
+		}
+		closed := w.closed
+		w.mu.Unlock()
+
+		if closed {
+			return nil, storage.ErrWatchClosed
+		}
+
+		select {
+		case <-w.notifyCh:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Close the watch and free its associated resources.
+func (w *Watch) Close() {
+	w.backend.removeWatch(w)
+	w.close()
+}
+
+// push buffers the given event. It returns false, and closes the watch, if
+// the buffer is full because the watcher isn't keeping up.
+func (w *Watch) push(event *pbresource.WatchEvent) bool {
+	w.mu.Lock()
+	if len(w.events) >= w.limit {
+		w.mu.Unlock()
+		w.close()
+		return false
+	}
+	w.events = append(w.events, event)
+	w.mu.Unlock()
+
+	w.notify()
+	return true
+}
+
+func (w *Watch) close() {
+	w.mu.Lock()
+	w.closed = true
+	w.events = nil
+	w.mu.Unlock()
+
+	w.notify()
+}
+
+func (w *Watch) notify() {
+	select {
+	case w.notifyCh <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bolt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/internal/storage"
+	"github.com/hashicorp/consul/proto-public/pbresource"
+)
+
+func TestWatch_ClosedOnOverflow(t *testing.T) {
+	snapshot := []*pbresource.Resource{{}, {}}
+	w := newWatch(&Backend{}, query{}, snapshot)
+
+	for i := 0; i < watchBufferSize; i++ {
+		require.True(t, w.push(&pbresource.WatchEvent{}))
+	}
+	require.False(t, w.push(&pbresource.WatchEvent{}))
+
+	_, err := w.Next(context.Background())
+	require.ErrorIs(t, err, storage.ErrWatchClosed)
+}
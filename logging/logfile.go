@@ -48,6 +48,31 @@ type LogFile struct {
 	acquire sync.Mutex
 }
 
+// NewLogFile creates a LogFile writing to a new file named after the given
+// path, and prunes the files of the previous rotations beyond maxFiles. The
+// files are rotated every duration, or daily if it is zero, and every maxBytes
+// if it is positive.
+func NewLogFile(path string, duration time.Duration, maxBytes, maxFiles int) (*LogFile, error) {
+	dir, fileName := filepath.Split(path)
+	if duration == 0 {
+		duration = defaultRotateDuration
+	}
+	logFile := &LogFile{
+		fileName: fileName,
+		logPath:  dir,
+		duration: duration,
+		MaxBytes: maxBytes,
+		MaxFiles: maxFiles,
+	}
+	if err := logFile.pruneFiles(); err != nil {
+		return nil, fmt.Errorf("failed to prune log files: %w", err)
+	}
+	if err := logFile.openNew(); err != nil {
+		return nil, err
+	}
+	return logFile, nil
+}
+
 func (l *LogFile) fileNamePattern() string {
 	// Extract the file extension
 	fileExt := filepath.Ext(l.fileName)
@@ -142,3 +167,25 @@ func (l *LogFile) Write(b []byte) (n int, err error) {
 	l.BytesWritten += int64(len(b))
 	return l.FileInfo.Write(b)
 }
+
+// Sync commits the content written to the current file to stable storage.
+func (l *LogFile) Sync() error {
+	l.acquire.Lock()
+	defer l.acquire.Unlock()
+	if l.FileInfo == nil {
+		return nil
+	}
+	return l.FileInfo.Sync()
+}
+
+// Close closes the current file. A later Write opens a new file.
+func (l *LogFile) Close() error {
+	l.acquire.Lock()
+	defer l.acquire.Unlock()
+	if l.FileInfo == nil {
+		return nil
+	}
+	err := l.FileInfo.Close()
+	l.FileInfo = nil
+	return err
+}
@@ -119,6 +119,24 @@ func TestLogFile_FileRotation_Disabled(t *testing.T) {
 	require.Len(t, listDir(t, tempDir), 1)
 }
 
+func TestNewLogFile(t *testing.T) {
+	tempDir := testutil.TempDir(t, t.Name())
+	logFile, err := NewLogFile(filepath.Join(tempDir, "events.log"), 0, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, defaultRotateDuration, logFile.duration)
+
+	_, err = logFile.Write([]byte("Hello World\n"))
+	require.NoError(t, err)
+	require.NoError(t, logFile.Sync())
+	require.NoError(t, logFile.Close())
+
+	files := listDir(t, tempDir)
+	require.Len(t, files, 1)
+	content, err := os.ReadFile(filepath.Join(tempDir, files[0]))
+	require.NoError(t, err)
+	require.Equal(t, "Hello World\n", string(content))
+}
+
 func listDir(t *testing.T, name string) []string {
 	t.Helper()
 	fh, err := os.Open(name)
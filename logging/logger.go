@@ -99,20 +99,9 @@ func Setup(config Config, out io.Writer) (hclog.InterceptLogger, error) {
 		if fileName == "" {
 			fileName = "consul.log"
 		}
-		if config.LogRotateDuration == 0 {
-			config.LogRotateDuration = defaultRotateDuration
-		}
-		logFile := &LogFile{
-			fileName: fileName,
-			logPath:  dir,
-			duration: config.LogRotateDuration,
-			MaxBytes: config.LogRotateBytes,
-			MaxFiles: config.LogRotateMaxFiles,
-		}
-		if err := logFile.pruneFiles(); err != nil {
-			return nil, fmt.Errorf("Failed to prune log files: %w", err)
-		}
-		if err := logFile.openNew(); err != nil {
+		logFile, err := NewLogFile(filepath.Join(dir, fileName), config.LogRotateDuration,
+			config.LogRotateBytes, config.LogRotateMaxFiles)
+		if err != nil {
 			return nil, fmt.Errorf("Failed to setup logging: %w", err)
 		}
 		writers = append(writers, logFile)
@@ -55,6 +55,7 @@ const (
 	Session               string = "session"
 	Sentinel              string = "sentinel"
 	Snapshot              string = "snapshot"
+	SnapshotAgent         string = "snapshot_agent"
 	Partition             string = "partition"
 	Peering               string = "peering"
 	PeeringMetrics        string = "peering_metrics"
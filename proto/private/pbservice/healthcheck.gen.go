@@ -20,6 +20,14 @@ func CheckTypeToStructs(s *CheckType, t *structs.CheckType) {
 	t.Method = s.Method
 	t.Body = s.Body
 	t.DisableRedirects = s.DisableRedirects
+	{
+		t.HTTPAssertions = make([]structs.HTTPAssertion, len(s.HTTPAssertions))
+		for i := range s.HTTPAssertions {
+			if s.HTTPAssertions[i] != nil {
+				HTTPAssertionToStructs(s.HTTPAssertions[i], &t.HTTPAssertions[i])
+			}
+		}
+	}
 	t.TCP = s.TCP
 	t.UDP = s.UDP
 	t.Interval = structs.DurationFromProto(s.Interval)
@@ -30,6 +38,16 @@ func CheckTypeToStructs(s *CheckType, t *structs.CheckType) {
 	t.GRPC = s.GRPC
 	t.GRPCUseTLS = s.GRPCUseTLS
 	t.OSService = s.OSService
+	t.DNS = s.DNS
+	t.DNSQueryName = s.DNSQueryName
+	t.DNSQueryType = s.DNSQueryType
+	t.DNSProtocol = s.DNSProtocol
+	t.DNSExpectedAnswer = s.DNSExpectedAnswer
+	t.DNSExpectedRcode = s.DNSExpectedRcode
+	t.TLS = s.TLS
+	t.TLSCAFile = s.TLSCAFile
+	t.TLSExpiryWarningDays = int(s.TLSExpiryWarningDays)
+	t.TLSExpiryCriticalDays = int(s.TLSExpiryCriticalDays)
 	t.TLSServerName = s.TLSServerName
 	t.TLSSkipVerify = s.TLSSkipVerify
 	t.Timeout = structs.DurationFromProto(s.Timeout)
@@ -37,6 +55,20 @@ func CheckTypeToStructs(s *CheckType, t *structs.CheckType) {
 	t.SuccessBeforePassing = int(s.SuccessBeforePassing)
 	t.FailuresBeforeWarning = int(s.FailuresBeforeWarning)
 	t.FailuresBeforeCritical = int(s.FailuresBeforeCritical)
+	{
+		t.CompositeChecks = make([]structs.CompositeCheckRef, len(s.CompositeChecks))
+		for i := range s.CompositeChecks {
+			if s.CompositeChecks[i] != nil {
+				CompositeCheckRefToStructs(s.CompositeChecks[i], &t.CompositeChecks[i])
+			}
+		}
+	}
+	t.CompositeMode = s.CompositeMode
+	t.CompositeCriticalThreshold = int(s.CompositeCriticalThreshold)
+	t.CompositeWarningThreshold = int(s.CompositeWarningThreshold)
+	t.FlapDetection = s.FlapDetection
+	t.FlapLowThreshold = int(s.FlapLowThreshold)
+	t.FlapHighThreshold = int(s.FlapHighThreshold)
 	t.ProxyHTTP = s.ProxyHTTP
 	t.ProxyGRPC = s.ProxyGRPC
 	t.DeregisterCriticalServiceAfter = structs.DurationFromProto(s.DeregisterCriticalServiceAfter)
@@ -58,6 +90,16 @@ func CheckTypeFromStructs(t *structs.CheckType, s *CheckType) {
 	s.Method = t.Method
 	s.Body = t.Body
 	s.DisableRedirects = t.DisableRedirects
+	{
+		s.HTTPAssertions = make([]*HTTPAssertion, len(t.HTTPAssertions))
+		for i := range t.HTTPAssertions {
+			{
+				var x HTTPAssertion
+				HTTPAssertionFromStructs(&t.HTTPAssertions[i], &x)
+				s.HTTPAssertions[i] = &x
+			}
+		}
+	}
 	s.TCP = t.TCP
 	s.UDP = t.UDP
 	s.Interval = structs.DurationToProto(t.Interval)
@@ -68,6 +110,16 @@ func CheckTypeFromStructs(t *structs.CheckType, s *CheckType) {
 	s.GRPC = t.GRPC
 	s.GRPCUseTLS = t.GRPCUseTLS
 	s.OSService = t.OSService
+	s.DNS = t.DNS
+	s.DNSQueryName = t.DNSQueryName
+	s.DNSQueryType = t.DNSQueryType
+	s.DNSProtocol = t.DNSProtocol
+	s.DNSExpectedAnswer = t.DNSExpectedAnswer
+	s.DNSExpectedRcode = t.DNSExpectedRcode
+	s.TLS = t.TLS
+	s.TLSCAFile = t.TLSCAFile
+	s.TLSExpiryWarningDays = int32(t.TLSExpiryWarningDays)
+	s.TLSExpiryCriticalDays = int32(t.TLSExpiryCriticalDays)
 	s.TLSServerName = t.TLSServerName
 	s.TLSSkipVerify = t.TLSSkipVerify
 	s.Timeout = structs.DurationToProto(t.Timeout)
@@ -75,11 +127,65 @@ func CheckTypeFromStructs(t *structs.CheckType, s *CheckType) {
 	s.SuccessBeforePassing = int32(t.SuccessBeforePassing)
 	s.FailuresBeforeWarning = int32(t.FailuresBeforeWarning)
 	s.FailuresBeforeCritical = int32(t.FailuresBeforeCritical)
+	{
+		s.CompositeChecks = make([]*CompositeCheckRef, len(t.CompositeChecks))
+		for i := range t.CompositeChecks {
+			{
+				var x CompositeCheckRef
+				CompositeCheckRefFromStructs(&t.CompositeChecks[i], &x)
+				s.CompositeChecks[i] = &x
+			}
+		}
+	}
+	s.CompositeMode = t.CompositeMode
+	s.CompositeCriticalThreshold = int32(t.CompositeCriticalThreshold)
+	s.CompositeWarningThreshold = int32(t.CompositeWarningThreshold)
+	s.FlapDetection = t.FlapDetection
+	s.FlapLowThreshold = int32(t.FlapLowThreshold)
+	s.FlapHighThreshold = int32(t.FlapHighThreshold)
 	s.ProxyHTTP = t.ProxyHTTP
 	s.ProxyGRPC = t.ProxyGRPC
 	s.DeregisterCriticalServiceAfter = structs.DurationToProto(t.DeregisterCriticalServiceAfter)
 	s.OutputMaxSize = int32(t.OutputMaxSize)
 }
+func CompositeCheckRefToStructs(s *CompositeCheckRef, t *structs.CompositeCheckRef) {
+	if s == nil {
+		return
+	}
+	t.Node = s.Node
+	t.CheckID = CheckIDType(s.CheckID)
+}
+func CompositeCheckRefFromStructs(t *structs.CompositeCheckRef, s *CompositeCheckRef) {
+	if s == nil {
+		return
+	}
+	s.Node = t.Node
+	s.CheckID = string(t.CheckID)
+}
+func HTTPAssertionToStructs(s *HTTPAssertion, t *structs.HTTPAssertion) {
+	if s == nil {
+		return
+	}
+	t.StatusRange = s.StatusRange
+	t.BodyContains = s.BodyContains
+	t.BodyRegex = s.BodyRegex
+	t.JSONPath = s.JSONPath
+	t.JSONValue = s.JSONValue
+	t.Negate = s.Negate
+	t.Status = s.Status
+}
+func HTTPAssertionFromStructs(t *structs.HTTPAssertion, s *HTTPAssertion) {
+	if s == nil {
+		return
+	}
+	s.StatusRange = t.StatusRange
+	s.BodyContains = t.BodyContains
+	s.BodyRegex = t.BodyRegex
+	s.JSONPath = t.JSONPath
+	s.JSONValue = t.JSONValue
+	s.Negate = t.Negate
+	s.Status = t.Status
+}
 func HealthCheckToStructs(s *HealthCheck, t *structs.HealthCheck) {
 	if s == nil {
 		return
@@ -98,6 +204,7 @@ func HealthCheckToStructs(s *HealthCheck, t *structs.HealthCheck) {
 	t.Timeout = s.Timeout
 	t.ExposedPort = int(s.ExposedPort)
 	t.PeerName = s.PeerName
+	t.Flapping = s.Flapping
 	if s.Definition != nil {
 		HealthCheckDefinitionToStructs(s.Definition, &t.Definition)
 	}
@@ -122,6 +229,7 @@ func HealthCheckFromStructs(t *structs.HealthCheck, s *HealthCheck) {
 	s.Timeout = t.Timeout
 	s.ExposedPort = int32(t.ExposedPort)
 	s.PeerName = t.PeerName
+	s.Flapping = t.Flapping
 	{
 		var x HealthCheckDefinition
 		HealthCheckDefinitionFromStructs(&t.Definition, &x)
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pbsubscribe
+
+import (
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/proto/private/pbcommon"
+)
+
+// NewKVUpdateFromStructs converts a structs.DirEntry into a KVUpdate with the
+// given operation.
+func NewKVUpdateFromStructs(op KVUpdate_UpdateOp, entry *structs.DirEntry) *KVUpdate {
+	update := &KVUpdate{
+		Op:             op,
+		Key:            entry.Key,
+		Flags:          entry.Flags,
+		Value:          entry.Value,
+		Session:        entry.Session,
+		LockIndex:      entry.LockIndex,
+		CreateIndex:    entry.CreateIndex,
+		ModifyIndex:    entry.ModifyIndex,
+		EnterpriseMeta: pbcommon.NewEnterpriseMetaFromStructs(entry.EnterpriseMeta),
+		TTL:            entry.TTL,
+	}
+	if entry.Expires != nil {
+		update.Expires = structs.TimeToProto(*entry.Expires)
+	}
+	return update
+}
+
+// DirEntryToStructs converts the entry carried by a KVUpdate back into a
+// structs.DirEntry.
+func (u *KVUpdate) DirEntryToStructs() *structs.DirEntry {
+	entry := &structs.DirEntry{
+		Key:       u.Key,
+		Flags:     u.Flags,
+		Value:     u.Value,
+		Session:   u.Session,
+		LockIndex: u.LockIndex,
+		TTL:       u.TTL,
+		RaftIndex: structs.RaftIndex{
+			CreateIndex: u.CreateIndex,
+			ModifyIndex: u.ModifyIndex,
+		},
+	}
+	if u.Expires != nil {
+		expires := structs.TimeFromProto(u.Expires)
+		entry.Expires = &expires
+	}
+	pbcommon.EnterpriseMetaToStructs(u.EnterpriseMeta, &entry.EnterpriseMeta)
+	return entry
+}
@@ -76,3 +76,13 @@ func (msg *ServiceListUpdate) MarshalBinary() ([]byte, error) {
 func (msg *ServiceListUpdate) UnmarshalBinary(b []byte) error {
 	return proto.Unmarshal(b, msg)
 }
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (msg *KVUpdate) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (msg *KVUpdate) UnmarshalBinary(b []byte) error {
+	return proto.Unmarshal(b, msg)
+}
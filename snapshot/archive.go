@@ -10,12 +10,16 @@
 //
 // The integrity information is automatically created and checked, and a failure
 // there just looks like an error to the caller.
+//
+// Archives may also contain a SHA256SUMS.sig file with a signature over the
+// SHA256SUMS file, as described in envelope.go.
 package snapshot
 
 import (
 	"archive/tar"
 	"bufio"
 	"bytes"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
@@ -166,10 +170,26 @@ func write(out io.Writer, metadata *raft.SnapshotMeta, snap io.Reader) error {
 	return nil
 }
 
+// writeTarFile adds a file with the given contents to the archive.
+func writeTarFile(archive *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := archive.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return fmt.Errorf("failed to write snapshot %s header: %v", name, err)
+	}
+	if _, err := archive.Write(data); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %v", name, err)
+	}
+	return nil
+}
+
 // read takes a reader and extracts the snapshot metadata and the snapshot
-// itself, and also checks the integrity of the data. You must arrange to call
-// Close() on the returned object or else you will leak a temporary file.
-func read(in io.Reader, metadata *raft.SnapshotMeta, snap io.Writer) error {
+// itself, and also checks the integrity of the data. If verifyKey is given,
+// the archive must also carry a valid signature from the matching private key.
+func read(in io.Reader, metadata *raft.SnapshotMeta, snap io.Writer, verifyKey ed25519.PublicKey) error {
 	// Start a new tar reader.
 	archive := tar.NewReader(in)
 
@@ -185,6 +205,7 @@ func read(in io.Reader, metadata *raft.SnapshotMeta, snap io.Writer) error {
 
 	// Look through the archive for the pieces we care about.
 	var shaBuffer bytes.Buffer
+	var sig []byte
 	for {
 		hdr, err := archive.Next()
 		if err == io.EOF {
@@ -222,15 +243,24 @@ func read(in io.Reader, metadata *raft.SnapshotMeta, snap io.Writer) error {
 				return fmt.Errorf("failed to read snapshot hashes: %v", err)
 			}
 
+		case signatureFile:
+			if sig, err = io.ReadAll(archive); err != nil {
+				return fmt.Errorf("failed to read snapshot signature: %v", err)
+			}
+
 		default:
 			return fmt.Errorf("unexpected file %q in snapshot", hdr.Name)
 		}
 	}
 
-	// Verify all the hashes.
-	if err := hl.DecodeAndVerify(&shaBuffer); err != nil {
+	// Verify all the hashes, and the signature over them.
+	sums := shaBuffer.Bytes()
+	if err := hl.DecodeAndVerify(bytes.NewReader(sums)); err != nil {
 		return fmt.Errorf("failed checking integrity of snapshot: %v", err)
 	}
+	if err := verifySignature(verifyKey, sums, sig); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -48,7 +48,7 @@ func TestArchive(t *testing.T) {
 	// Read the snapshot back.
 	var newMeta raft.SnapshotMeta
 	var newSnap bytes.Buffer
-	if err := read(&archive, &newMeta, &newSnap); err != nil {
+	if err := read(&archive, &newMeta, &newSnap, nil); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
@@ -77,7 +77,7 @@ func TestArchive_GoodData(t *testing.T) {
 		defer f.Close()
 
 		var metadata raft.SnapshotMeta
-		err = read(f, &metadata, io.Discard)
+		err = read(f, &metadata, io.Discard, nil)
 		if err != nil {
 			t.Fatalf("case %d: should've read the snapshot, but didn't: %v", i, err)
 		}
@@ -106,7 +106,7 @@ func TestArchive_BadData(t *testing.T) {
 		defer f.Close()
 
 		var metadata raft.SnapshotMeta
-		err = read(f, &metadata, io.Discard)
+		err = read(f, &metadata, io.Discard, nil)
 		if err == nil || !strings.Contains(err.Error(), c.Error) {
 			t.Fatalf("case %d (%s): %v", i, c.Name, err)
 		}
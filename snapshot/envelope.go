@@ -0,0 +1,472 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Snapshot archives can optionally be sealed for storage outside of the
+// cluster. Sealing happens on the client, so servers only ever deal with plain
+// archives.
+//
+// A signed archive carries an extra SHA256SUMS.sig file, holding an Ed25519
+// signature over the contents of SHA256SUMS. Because SHA256SUMS covers every
+// other file, the signature establishes the provenance of the whole archive.
+//
+// An encrypted archive wraps the compressed archive in an envelope:
+//
+//	magic (8 bytes) | version (1 byte) | nonce prefix (12 bytes) | chunks...
+//
+// where each chunk is:
+//
+//	final flag (1 byte) | ciphertext length (4 bytes) | AES-256-GCM ciphertext
+//
+// Each chunk's nonce is the nonce prefix XOR'd with the chunk's sequence
+// number, and the envelope header and final flag are authenticated as
+// additional data, so chunks cannot be reordered, dropped or truncated
+// without detection.
+const (
+	envelopeMagic   = "CSNAPENC"
+	envelopeVersion = 1
+
+	envelopeChunkSize = 64 * 1024
+
+	// EncryptionKeySize is the size of the AES-256 keys used to encrypt
+	// snapshot archives.
+	EncryptionKeySize = 32
+
+	signatureFile = "SHA256SUMS.sig"
+)
+
+// ErrEncrypted is returned when reading an encrypted snapshot without a key.
+var ErrEncrypted = errors.New("snapshot is encrypted, an encryption key is required to read it")
+
+// SealOptions controls how a snapshot archive is sealed by Seal.
+type SealOptions struct {
+	// EncryptionKey is an AES-256 key used to encrypt the archive. If empty
+	// the archive is not encrypted.
+	EncryptionKey []byte
+
+	// SigningKey is used to sign the archive. If nil the archive is not
+	// signed.
+	SigningKey ed25519.PrivateKey
+}
+
+// Keys holds the key material used to open sealed snapshot archives.
+type Keys struct {
+	// EncryptionKey is the AES-256 key needed to read encrypted archives.
+	EncryptionKey []byte
+
+	// VerifyKey, if set, requires the archive to carry a valid signature from
+	// the matching private key.
+	VerifyKey ed25519.PublicKey
+}
+
+// DeriveEncryptionKey derives a snapshot encryption key from a gossip
+// encryption key, so that operators can reuse key material they already
+// manage. Note that snapshots sealed with a derived key can't be read once the
+// gossip key is removed from the keyring.
+func DeriveEncryptionKey(gossipKey []byte) ([]byte, error) {
+	key := make([]byte, EncryptionKeySize)
+	r := hkdf.New(sha256.New, gossipKey, nil, []byte("consul snapshot encryption"))
+	if _, err := io.ReadFull(r, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Seal reads a plain snapshot archive (as returned by New or the snapshot
+// endpoint) and writes a copy to out that is signed and/or encrypted according
+// to the given options. The archive's integrity is checked along the way.
+func Seal(in io.Reader, out io.Writer, opts SealOptions) error {
+	decomp, err := gzip.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("failed to decompress snapshot: %v", err)
+	}
+	defer decomp.Close()
+
+	w := out
+	var enc io.WriteCloser
+	if len(opts.EncryptionKey) != 0 {
+		enc, err = newEncryptWriter(out, opts.EncryptionKey)
+		if err != nil {
+			return err
+		}
+		w = enc
+	}
+
+	compressor := gzip.NewWriter(w)
+	if err := repack(decomp, compressor, nil, opts.SigningKey); err != nil {
+		return err
+	}
+	if err := concludeGzipRead(decomp); err != nil {
+		return err
+	}
+	if err := compressor.Close(); err != nil {
+		return fmt.Errorf("failed to compress snapshot: %v", err)
+	}
+	if enc != nil {
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("failed to encrypt snapshot: %v", err)
+		}
+	}
+	return nil
+}
+
+// Open reads a sealed snapshot archive, decrypting it and checking its
+// signature as required by the given keys, and writes the plain archive to
+// out. The result can be restored by any server.
+func Open(in io.Reader, out io.Writer, keys *Keys) error {
+	r, err := openEnvelope(in, keys)
+	if err != nil {
+		return err
+	}
+
+	decomp, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to decompress snapshot: %v", err)
+	}
+	defer decomp.Close()
+
+	compressor := gzip.NewWriter(out)
+	if err := repack(decomp, compressor, keys.verifyKey(), nil); err != nil {
+		return err
+	}
+	if err := concludeGzipRead(decomp); err != nil {
+		return err
+	}
+	if err := compressor.Close(); err != nil {
+		return fmt.Errorf("failed to compress snapshot: %v", err)
+	}
+	return nil
+}
+
+func (k *Keys) encryptionKey() []byte {
+	if k == nil {
+		return nil
+	}
+	return k.EncryptionKey
+}
+
+func (k *Keys) verifyKey() ed25519.PublicKey {
+	if k == nil {
+		return nil
+	}
+	return k.VerifyKey
+}
+
+// openEnvelope returns a reader for the compressed archive inside the given
+// stream, decrypting it if it's wrapped in an encryption envelope.
+func openEnvelope(in io.Reader, keys *Keys) (io.Reader, error) {
+	br := bufio.NewReader(in)
+	magic, err := br.Peek(len(envelopeMagic))
+	if err != nil || string(magic) != envelopeMagic {
+		// Not encrypted; let the gzip reader report any problems.
+		return br, nil
+	}
+
+	key := keys.encryptionKey()
+	if len(key) == 0 {
+		return nil, ErrEncrypted
+	}
+	return newDecryptReader(br, key)
+}
+
+// repack copies the tar stream of a snapshot archive from in to out, checking
+// its integrity along the way. If verifyKey is given, the archive must carry a
+// valid signature from the matching private key. Any existing signature is
+// dropped, and a fresh one is added if signingKey is given.
+func repack(in io.Reader, out io.Writer, verifyKey ed25519.PublicKey, signingKey ed25519.PrivateKey) error {
+	archive := tar.NewReader(in)
+	repacked := tar.NewWriter(out)
+
+	hl := newHashList()
+	metaHash := hl.Add("meta.json")
+	snapHash := hl.Add("state.bin")
+
+	var sums, sig []byte
+	for {
+		hdr, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading snapshot: %v", err)
+		}
+
+		switch hdr.Name {
+		case "meta.json", "state.bin":
+			h := metaHash
+			if hdr.Name == "state.bin" {
+				h = snapHash
+			}
+			if err := repacked.WriteHeader(hdr); err != nil {
+				return fmt.Errorf("failed to write snapshot: %v", err)
+			}
+			if _, err := io.Copy(io.MultiWriter(repacked, h), archive); err != nil {
+				return fmt.Errorf("failed to copy snapshot %s: %v", hdr.Name, err)
+			}
+
+		case "SHA256SUMS":
+			if sums, err = io.ReadAll(archive); err != nil {
+				return fmt.Errorf("failed to read snapshot hashes: %v", err)
+			}
+			if err := writeTarFile(repacked, hdr.Name, sums, hdr.ModTime); err != nil {
+				return err
+			}
+
+		case signatureFile:
+			if sig, err = io.ReadAll(archive); err != nil {
+				return fmt.Errorf("failed to read snapshot signature: %v", err)
+			}
+
+		default:
+			return fmt.Errorf("unexpected file %q in snapshot", hdr.Name)
+		}
+	}
+
+	if err := hl.DecodeAndVerify(bytes.NewReader(sums)); err != nil {
+		return fmt.Errorf("failed checking integrity of snapshot: %v", err)
+	}
+	if err := verifySignature(verifyKey, sums, sig); err != nil {
+		return err
+	}
+
+	if signingKey != nil {
+		if err := writeTarFile(repacked, signatureFile, ed25519.Sign(signingKey, sums), time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := repacked.Close(); err != nil {
+		return fmt.Errorf("failed to finalize snapshot: %v", err)
+	}
+	return nil
+}
+
+// verifySignature checks the signature over an archive's SHA256SUMS file. It's
+// a no-op if no verification key was given.
+func verifySignature(key ed25519.PublicKey, sums, sig []byte) error {
+	if key == nil {
+		return nil
+	}
+	if sig == nil {
+		return fmt.Errorf("snapshot is not signed")
+	}
+	if !ed25519.Verify(key, sums, sig) {
+		return fmt.Errorf("snapshot signature is invalid")
+	}
+	return nil
+}
+
+// encryptWriter encrypts everything written to it into an envelope. Close
+// must be called to write the final chunk.
+type encryptWriter struct {
+	out    io.Writer
+	aead   cipher.AEAD
+	header []byte
+	nonce  []byte
+	seq    uint64
+	buf    []byte
+}
+
+func newEncryptWriter(out io.Writer, key []byte) (io.WriteCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(envelopeMagic)+1+aead.NonceSize())
+	header = append(header, envelopeMagic...)
+	header = append(header, envelopeVersion)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	header = append(header, nonce...)
+
+	if _, err := out.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		out:    out,
+		aead:   aead,
+		header: header,
+		nonce:  nonce,
+		buf:    make([]byte, 0, envelopeChunkSize),
+	}, nil
+}
+
+func (w *encryptWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if len(w.buf) == envelopeChunkSize {
+			if err := w.writeChunk(false); err != nil {
+				return n, err
+			}
+		}
+		c := copy(w.buf[len(w.buf):envelopeChunkSize], p)
+		w.buf = w.buf[:len(w.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+func (w *encryptWriter) Close() error {
+	return w.writeChunk(true)
+}
+
+func (w *encryptWriter) writeChunk(final bool) error {
+	flag := chunkFlag(final)
+	ct := w.aead.Seal(nil, chunkNonce(w.nonce, w.seq), w.buf, chunkAAD(w.header, flag))
+	w.seq++
+	w.buf = w.buf[:0]
+
+	prefix := make([]byte, 5)
+	prefix[0] = flag
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(ct)))
+	if _, err := w.out.Write(prefix); err != nil {
+		return err
+	}
+	_, err := w.out.Write(ct)
+	return err
+}
+
+// decryptReader reads the plaintext from an envelope.
+type decryptReader struct {
+	in     io.Reader
+	aead   cipher.AEAD
+	header []byte
+	nonce  []byte
+	seq    uint64
+	buf    []byte
+	done   bool
+}
+
+func newDecryptReader(in io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, len(envelopeMagic)+1+aead.NonceSize())
+	if _, err := io.ReadFull(in, header); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot envelope: %v", err)
+	}
+	if v := header[len(envelopeMagic)]; v != envelopeVersion {
+		return nil, fmt.Errorf("unsupported snapshot envelope version %d", v)
+	}
+
+	return &decryptReader{
+		in:     in,
+		aead:   aead,
+		header: header,
+		nonce:  header[len(envelopeMagic)+1:],
+	}, nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *decryptReader) readChunk() error {
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(r.in, prefix); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("snapshot envelope is truncated")
+		}
+		return err
+	}
+
+	flag := prefix[0]
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if flag > 1 || size > envelopeChunkSize+uint32(r.aead.Overhead()) {
+		return fmt.Errorf("snapshot envelope is corrupt")
+	}
+
+	ct := make([]byte, size)
+	if _, err := io.ReadFull(r.in, ct); err != nil {
+		return fmt.Errorf("snapshot envelope is truncated")
+	}
+
+	pt, err := r.aead.Open(nil, chunkNonce(r.nonce, r.seq), ct, chunkAAD(r.header, flag))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt snapshot, the key may be incorrect: %v", err)
+	}
+	r.seq++
+	r.buf = pt
+
+	if flag == 1 {
+		r.done = true
+		// Make sure nothing was appended after the final chunk.
+		if n, _ := r.in.Read(make([]byte, 1)); n != 0 {
+			return fmt.Errorf("unexpected data after the end of the snapshot envelope")
+		}
+	}
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("snapshot encryption key must be %d bytes, got %d", EncryptionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkFlag(final bool) byte {
+	if final {
+		return 1
+	}
+	return 0
+}
+
+func chunkNonce(prefix []byte, seq uint64) []byte {
+	nonce := make([]byte, len(prefix))
+	copy(nonce, prefix)
+	var ctr [8]byte
+	binary.BigEndian.PutUint64(ctr[:], seq)
+	for i := range ctr {
+		nonce[len(nonce)-8+i] ^= ctr[i]
+	}
+	return nonce
+}
+
+func chunkAAD(header []byte, flag byte) []byte {
+	aad := make([]byte, len(header)+1)
+	copy(aad, header)
+	aad[len(header)] = flag
+	return aad
+}
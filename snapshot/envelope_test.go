@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+// testArchive returns a plain, compressed snapshot archive along with the
+// snapshot data it holds. The data is large enough to span several envelope
+// chunks.
+func testArchive(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	data := make([]byte, 3*envelopeChunkSize+123)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	metadata := raft.SnapshotMeta{ID: "test", Index: 2005, Term: 2011, Size: int64(len(data))}
+
+	var buf bytes.Buffer
+	compressor := gzip.NewWriter(&buf)
+	require.NoError(t, write(compressor, &metadata, bytes.NewReader(data)))
+	require.NoError(t, compressor.Close())
+	return buf.Bytes(), data
+}
+
+func testEncryptionKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, EncryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func seal(t *testing.T, archive []byte, opts SealOptions) []byte {
+	t.Helper()
+	var sealed bytes.Buffer
+	require.NoError(t, Seal(bytes.NewReader(archive), &sealed, opts))
+	return sealed.Bytes()
+}
+
+func readData(t *testing.T, in []byte, keys *Keys) (*raft.SnapshotMeta, []byte, error) {
+	t.Helper()
+	f, meta, err := ReadWithKeys(testutil.Logger(t), bytes.NewReader(in), keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return meta, data, nil
+}
+
+func TestEnvelope_Encrypted(t *testing.T) {
+	archive, data := testArchive(t)
+	key := testEncryptionKey(t)
+
+	sealed := seal(t, archive, SealOptions{EncryptionKey: key})
+	require.Equal(t, envelopeMagic, string(sealed[:len(envelopeMagic)]))
+	require.False(t, bytes.Contains(sealed, data[:64]))
+
+	t.Run("read", func(t *testing.T) {
+		meta, got, err := readData(t, sealed, &Keys{EncryptionKey: key})
+		require.NoError(t, err)
+		require.Equal(t, uint64(2005), meta.Index)
+		require.Equal(t, data, got)
+	})
+
+	t.Run("open", func(t *testing.T) {
+		var opened bytes.Buffer
+		require.NoError(t, Open(bytes.NewReader(sealed), &opened, &Keys{EncryptionKey: key}))
+
+		// The opened archive can be read without any keys.
+		_, got, err := readData(t, opened.Bytes(), nil)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := Verify(bytes.NewReader(sealed))
+		require.ErrorIs(t, err, ErrEncrypted)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := VerifyWithKeys(bytes.NewReader(sealed), &Keys{EncryptionKey: testEncryptionKey(t)})
+		require.ErrorContains(t, err, "failed to decrypt snapshot")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		// Dropping a whole chunk must be caught, not just a partial one.
+		for _, n := range []int{1, 100, envelopeChunkSize + 21} {
+			_, err := VerifyWithKeys(bytes.NewReader(sealed[:len(sealed)-n]), &Keys{EncryptionKey: key})
+			require.Error(t, err, "truncated by %d bytes", n)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := append([]byte{}, sealed...)
+		tampered[len(tampered)/2] ^= 0xff
+		_, err := VerifyWithKeys(bytes.NewReader(tampered), &Keys{EncryptionKey: key})
+		require.ErrorContains(t, err, "failed to decrypt snapshot")
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		extended := append(append([]byte{}, sealed...), 0)
+		_, err := VerifyWithKeys(bytes.NewReader(extended), &Keys{EncryptionKey: key})
+		require.Error(t, err)
+	})
+}
+
+func TestEnvelope_Signed(t *testing.T) {
+	archive, data := testArchive(t)
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sealed := seal(t, archive, SealOptions{SigningKey: priv})
+
+	t.Run("verify", func(t *testing.T) {
+		_, got, err := readData(t, sealed, &Keys{VerifyKey: pub})
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+
+	t.Run("signature ignored without key", func(t *testing.T) {
+		_, err := Verify(bytes.NewReader(sealed))
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		other, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		_, err = VerifyWithKeys(bytes.NewReader(sealed), &Keys{VerifyKey: other})
+		require.ErrorContains(t, err, "snapshot signature is invalid")
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		_, err := VerifyWithKeys(bytes.NewReader(archive), &Keys{VerifyKey: pub})
+		require.ErrorContains(t, err, "snapshot is not signed")
+	})
+
+	t.Run("open strips signature", func(t *testing.T) {
+		var opened bytes.Buffer
+		require.NoError(t, Open(bytes.NewReader(sealed), &opened, &Keys{VerifyKey: pub}))
+
+		_, err := VerifyWithKeys(bytes.NewReader(opened.Bytes()), &Keys{VerifyKey: pub})
+		require.ErrorContains(t, err, "snapshot is not signed")
+	})
+}
+
+func TestEnvelope_EncryptedAndSigned(t *testing.T) {
+	archive, data := testArchive(t)
+	key := testEncryptionKey(t)
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sealed := seal(t, archive, SealOptions{EncryptionKey: key, SigningKey: priv})
+
+	_, got, err := readData(t, sealed, &Keys{EncryptionKey: key, VerifyKey: pub})
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+
+	// Resealing swaps the signature for a new one.
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	var opened bytes.Buffer
+	require.NoError(t, Open(bytes.NewReader(sealed), &opened, &Keys{EncryptionKey: key}))
+	resealed := seal(t, opened.Bytes(), SealOptions{SigningKey: other})
+	_, err = VerifyWithKeys(bytes.NewReader(resealed), &Keys{VerifyKey: pub})
+	require.ErrorContains(t, err, "snapshot signature is invalid")
+}
+
+func TestDeriveEncryptionKey(t *testing.T) {
+	gossipKey := []byte("0123456789abcdef0123456789abcdef")
+
+	key1, err := DeriveEncryptionKey(gossipKey)
+	require.NoError(t, err)
+	require.Len(t, key1, EncryptionKeySize)
+	require.NotEqual(t, gossipKey, key1)
+
+	key2, err := DeriveEncryptionKey(gossipKey)
+	require.NoError(t, err)
+	require.Equal(t, key1, key2)
+}
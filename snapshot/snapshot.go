@@ -128,6 +128,17 @@ func (s *Snapshot) Close() error {
 
 // Verify takes the snapshot from the reader and verifies its contents.
 func Verify(in io.Reader) (*raft.SnapshotMeta, error) {
+	return VerifyWithKeys(in, nil)
+}
+
+// VerifyWithKeys takes a snapshot, which may be encrypted and/or signed, from
+// the reader and verifies its contents. See Keys for details.
+func VerifyWithKeys(in io.Reader, keys *Keys) (*raft.SnapshotMeta, error) {
+	in, err := openEnvelope(in, keys)
+	if err != nil {
+		return nil, err
+	}
+
 	// Wrap the reader in a gzip decompressor.
 	decomp, err := gzip.NewReader(in)
 	if err != nil {
@@ -137,7 +148,7 @@ func Verify(in io.Reader) (*raft.SnapshotMeta, error) {
 
 	// Read the archive, throwing away the snapshot data.
 	var metadata raft.SnapshotMeta
-	if err := read(decomp, &metadata, io.Discard); err != nil {
+	if err := read(decomp, &metadata, io.Discard, keys.verifyKey()); err != nil {
 		return nil, fmt.Errorf("failed to read snapshot file: %v", err)
 	}
 
@@ -165,6 +176,18 @@ func concludeGzipRead(decomp *gzip.Reader) error {
 
 // Read a snapshot into a temporary file. The caller is responsible for removing the file.
 func Read(logger hclog.Logger, in io.Reader) (*os.File, *raft.SnapshotMeta, error) {
+	return ReadWithKeys(logger, in, nil)
+}
+
+// ReadWithKeys reads a snapshot, which may be encrypted and/or signed, into a
+// temporary file. See Keys for details. The caller is responsible for removing
+// the file.
+func ReadWithKeys(logger hclog.Logger, in io.Reader, keys *Keys) (*os.File, *raft.SnapshotMeta, error) {
+	in, err := openEnvelope(in, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Wrap the reader in a gzip decompressor.
 	decomp, err := gzip.NewReader(in)
 	if err != nil {
@@ -185,7 +208,7 @@ func Read(logger hclog.Logger, in io.Reader) (*os.File, *raft.SnapshotMeta, erro
 
 	// Read the archive.
 	var metadata raft.SnapshotMeta
-	if err := read(decomp, &metadata, snap); err != nil {
+	if err := read(decomp, &metadata, snap, keys.verifyKey()); err != nil {
 		return nil, nil, fmt.Errorf("failed to read snapshot file: %v", err)
 	}
 